@@ -80,6 +80,34 @@ type AddonSpec struct {
 
 	// PruneSpec specifies how old objects should be removed (pruned).
 	Prune *PruneSpec `json:"prune,omitempty"`
+
+	// HealthTimeout is how long to wait for the objects of an updated addon to become healthy.
+	// If they do not, the previously applied manifest is applied again and the failure is recorded.
+	// If unset or 0, we do not wait for the addon to become healthy, and do not roll it back.
+	HealthTimeout *metav1.Duration `json:"healthTimeout,omitempty"`
+
+	// DependsOn lists the names of addons in the same channel that must be applied, and healthy, before this addon.
+	DependsOn []string `json:"dependsOn,omitempty"`
+
+	// Chart is a Helm chart that is rendered to produce the manifest, instead of reading Manifest.
+	Chart *HelmChartSpec `json:"chart,omitempty"`
+}
+
+// HelmChartSpec specifies a Helm chart to render as the manifest of an addon.
+type HelmChartSpec struct {
+	// Repository is the chart repository: an OCI registry (oci://), an HTTP(S) chart repository,
+	// or a path holding <name>-<version>.tgz archives. Relative paths are relative to the channel.
+	Repository string `json:"repository,omitempty"`
+	// Name is the name of the chart.
+	Name string `json:"name,omitempty"`
+	// Version is the exact version of the chart.
+	Version string `json:"version,omitempty"`
+	// ReleaseName is the name of the release; defaults to the name of the chart.
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Namespace is the namespace the chart is installed in; defaults to kube-system.
+	Namespace string `json:"namespace,omitempty"`
+	// Values override the default values of the chart.
+	Values map[string]interface{} `json:"values,omitempty"`
 }
 
 // PruneSpec specifies how old objects should be removed (pruned).
@@ -107,6 +135,13 @@ type PruneKindSpec struct {
 }
 
 func (a *Addons) Verify() error {
+	names := make(map[string]bool)
+	for _, addon := range a.Spec.Addons {
+		if addon != nil {
+			names[values.StringValue(addon.Name)] = true
+		}
+	}
+
 	for _, addon := range a.Spec.Addons {
 		if addon == nil {
 			continue
@@ -114,6 +149,17 @@ func (a *Addons) Verify() error {
 		if addon.KubernetesVersion != "" {
 			return fmt.Errorf("bootstrap addon %q has a KubernetesVersion", values.StringValue(addon.Name))
 		}
+		if addon.Chart != nil && values.StringValue(addon.Manifest) != "" {
+			return fmt.Errorf("bootstrap addon %q has both a manifest and a chart", values.StringValue(addon.Name))
+		}
+		for _, dependency := range addon.DependsOn {
+			if dependency == values.StringValue(addon.Name) {
+				return fmt.Errorf("bootstrap addon %q depends on itself", dependency)
+			}
+			if !names[dependency] {
+				return fmt.Errorf("bootstrap addon %q depends on unknown addon %q", values.StringValue(addon.Name), dependency)
+			}
+		}
 	}
 
 	return nil
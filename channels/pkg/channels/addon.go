@@ -22,9 +22,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
 
+	"github.com/blang/semver/v4"
 	"go.uber.org/multierr"
+	"k8s.io/kops/pkg/helm"
 	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/pkg/values"
+	"k8s.io/kops/upup/pkg/fi/utils"
 	"k8s.io/kops/util/pkg/vfs"
 
 	certmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
@@ -45,12 +52,30 @@ type Applier interface {
 	Apply(ctx context.Context, data []byte) error
 }
 
+// HealthChecker is implemented by an Applier that can report the health of the objects in a manifest.
+type HealthChecker interface {
+	// CheckHealth returns a description of each object in the manifest that is not healthy
+	CheckHealth(ctx context.Context, data []byte) ([]string, error)
+	// ApplyWithoutHealthCheck applies the manifest like Apply, but does not require the objects to be healthy,
+	// as the caller waits for them to become healthy with CheckHealth.
+	ApplyWithoutHealthCheck(ctx context.Context, data []byte) error
+}
+
+// healthPollInterval is how often we check the health of an updated addon
+var healthPollInterval = 5 * time.Second
+
+// maxFailureMessageLength limits the length of the message recorded for a failed update
+const maxFailureMessageLength = 1024
+
 // Addon is a wrapper around a single version of an addon
 type Addon struct {
 	Name            string
 	ChannelName     string
 	ChannelLocation url.URL
 	Spec            *api.AddonSpec
+
+	// kubernetesVersion is the version of the cluster, which charts are rendered for
+	kubernetesVersion semver.Version
 }
 
 // AddonUpdate holds data about a proposed update to an addon
@@ -89,11 +114,30 @@ func (a *Addon) ChannelVersion() *ChannelVersion {
 	return &ChannelVersion{
 		Channel:          &a.ChannelName,
 		Id:               a.Spec.Id,
-		ManifestHash:     a.Spec.ManifestHash,
+		ManifestHash:     a.manifestHash(),
 		SystemGeneration: CurrentSystemGeneration,
 	}
 }
 
+// manifestHash returns the hash of the manifest, or of the chart spec if the manifest is rendered from a chart
+// and the channel does not specify its hash.
+func (a *Addon) manifestHash() string {
+	if a.Spec.ManifestHash != "" || a.Spec.Chart == nil {
+		return a.Spec.ManifestHash
+	}
+	b, err := json.Marshal(a.Spec.Chart)
+	if err != nil {
+		klog.Warningf("unable to hash chart of %q: %v", a.Name, err)
+		return ""
+	}
+	hash, err := utils.HashString(string(b))
+	if err != nil {
+		klog.Warningf("unable to hash chart of %q: %v", a.Name, err)
+		return ""
+	}
+	return hash
+}
+
 func (a *Addon) buildChannel() *Channel {
 	channel := &Channel{
 		Namespace: a.GetNamespace(),
@@ -157,6 +201,75 @@ func (a *Addon) GetManifestFullUrl() (*url.URL, error) {
 	return manifestURL, nil
 }
 
+// HasManifest returns true if the addon has objects to apply, from a manifest or a chart
+func (a *Addon) HasManifest() bool {
+	return (a.Spec.Manifest != nil && *a.Spec.Manifest != "") || a.Spec.Chart != nil
+}
+
+// manifestSource describes where the manifest of the addon comes from, for messages
+func (a *Addon) manifestSource() string {
+	if a.Spec.Chart != nil {
+		return fmt.Sprintf("chart %s", a.chartSource())
+	}
+	manifestURL, err := a.GetManifestFullUrl()
+	if err != nil {
+		return fmt.Sprintf("%q", values.StringValue(a.Spec.Manifest))
+	}
+	return fmt.Sprintf("%q", manifestURL)
+}
+
+// chartSource returns the source of the chart, resolving a relative repository against the channel location
+func (a *Addon) chartSource() helm.ChartSource {
+	repository := a.Spec.Chart.Repository
+	if repositoryURL, err := url.Parse(repository); err == nil && !repositoryURL.IsAbs() && !filepath.IsAbs(repository) {
+		repository = a.ChannelLocation.ResolveReference(repositoryURL).String()
+	}
+	return helm.ChartSource{
+		Repository: repository,
+		Name:       a.Spec.Chart.Name,
+		Version:    a.Spec.Chart.Version,
+	}
+}
+
+// readManifest reads the manifest of the addon, rendering it if the addon is a chart
+func (a *Addon) readManifest(ctx context.Context, vfsContext *vfs.VFSContext) ([]byte, error) {
+	if a.Spec.Chart == nil {
+		manifestURL, err := a.GetManifestFullUrl()
+		if err != nil {
+			return nil, err
+		}
+		data, err := vfsContext.ReadFile(manifestURL.String())
+		if err != nil {
+			return nil, fmt.Errorf("error reading manifest: %w", err)
+		}
+		return data, nil
+	}
+
+	source := a.chartSource()
+	chart, err := helm.Load(ctx, vfsContext, source)
+	if err != nil {
+		return nil, err
+	}
+	namespace := a.Spec.Chart.Namespace
+	if namespace == "" {
+		namespace = "kube-system"
+	}
+	options := helm.RenderOptions{
+		ReleaseName:     a.Spec.Chart.ReleaseName,
+		Namespace:       namespace,
+		Values:          a.Spec.Chart.Values,
+		CreateNamespace: true,
+	}
+	if a.kubernetesVersion.Major != 0 || a.kubernetesVersion.Minor != 0 {
+		options.KubeVersion = a.kubernetesVersion.String()
+	}
+	data, err := helm.Render(chart, options)
+	if err != nil {
+		return nil, fmt.Errorf("error rendering chart %s: %w", source, err)
+	}
+	return data, nil
+}
+
 func (a *Addon) EnsureUpdated(ctx context.Context, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, pruner *Pruner, applier Applier, existingVersion *ChannelVersion) (*AddonUpdate, error) {
 	required, err := a.GetRequiredUpdates(ctx, k8sClient, cmClient, existingVersion)
 	if err != nil {
@@ -183,24 +296,74 @@ func (a *Addon) EnsureUpdated(ctx context.Context, vfsContext *vfs.VFSContext, k
 	return required, merr
 }
 
-func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface, vfsContext *vfs.VFSContext, pruner *Pruner, applier Applier, required *AddonUpdate) error {
-	manifestURL, err := a.GetManifestFullUrl()
+// AddonDryRun holds the result of a server-side dry-run of an addon update
+type AddonDryRun struct {
+	Name string
+	// Objects holds the result of applying each object in the manifest
+	Objects []*ObjectDiff
+	// Pruned lists the objects that would be deleted by pruning, as Kind:namespace/name
+	Pruned []string
+}
+
+// DryRun reports the changes that updating the addon would make, without changing the cluster.
+func (a *Addon) DryRun(ctx context.Context, vfsContext *vfs.VFSContext, pruner *Pruner, applier *ClientApplier) (*AddonDryRun, error) {
+	data, err := a.readManifest(ctx, vfsContext)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	objects, err := applier.DryRun(ctx, data)
+	if err != nil {
+		return nil, fmt.Errorf("error in dry-run apply of %s: %w", a.manifestSource(), err)
 	}
 
-	klog.Infof("Applying update from %q", manifestURL)
+	pruned, err := pruner.PlanPrune(ctx, data, a.Spec.Prune)
+	if err != nil {
+		return nil, fmt.Errorf("error in dry-run prune of %s: %w", a.manifestSource(), err)
+	}
+
+	return &AddonDryRun{
+		Name:    a.Name,
+		Objects: objects,
+		Pruned:  pruned,
+	}, nil
+}
+
+func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface, vfsContext *vfs.VFSContext, pruner *Pruner, applier Applier, required *AddonUpdate) error {
+	klog.Infof("Applying update from %s", a.manifestSource())
 
 	// We copy the manifest to a temp file because it is likely e.g. an s3 URL, which kubectl can't read
-	data, err := vfsContext.ReadFile(manifestURL.String())
+	data, err := a.readManifest(ctx, vfsContext)
 	if err != nil {
-		return fmt.Errorf("error reading manifest: %w", err)
+		return err
+	}
+
+	channel := a.buildChannel()
+
+	// We can only roll back an update if we stored the manifest it replaces.
+	// A new install is never rolled back: during cluster bringup addons such as the CNI
+	// must be installed before anything can become healthy.
+	healthChecker, canCheckHealth := applier.(HealthChecker)
+	var previous []byte
+	if required.ExistingVersion != nil && canCheckHealth && a.healthTimeout() > 0 {
+		previous, err = channel.GetAppliedManifest(ctx, k8sClient)
+		if err != nil {
+			klog.Warningf("not waiting for %q to become healthy: %v", a.Name, err)
+		} else if previous == nil {
+			klog.Infof("not waiting for %q to become healthy, as the previously applied manifest was not stored", a.Name)
+		}
+	}
+
+	// The objects of an addon that is not health-gated must be healthy as soon as they are applied
+	apply := applier.Apply
+	if canCheckHealth && a.healthTimeout() > 0 {
+		apply = healthChecker.ApplyWithoutHealthCheck
 	}
 
 	var merr error
 	var applyError, pruneError error
 
-	if applyError = applier.Apply(ctx, data); applyError != nil {
+	if applyError = apply(ctx, data); applyError != nil {
 		merr = multierr.Append(merr, fmt.Errorf("error applying update: %w", applyError))
 	}
 
@@ -210,7 +373,7 @@ func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface,
 
 	if applyError != nil && pruneError == nil {
 		// If we failed to apply, but not prune, we should try to apply again
-		if err := applier.Apply(ctx, data); err != nil {
+		if err := apply(ctx, data); err != nil {
 			merr = multierr.Append(merr, fmt.Errorf("error applying update after prune: %w", err))
 		} else {
 			// If we succeeded to apply after prune, clear the errors
@@ -219,14 +382,30 @@ func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface,
 	}
 
 	if merr != nil {
-		return fmt.Errorf("error updating addon from %q: %w", manifestURL, merr)
+		return fmt.Errorf("error updating addon from %s: %w", a.manifestSource(), merr)
+	}
+
+	if previous != nil {
+		if err := waitForHealthy(ctx, healthChecker, data, a.healthTimeout()); err != nil {
+			return a.rollback(ctx, k8sClient, pruner, healthChecker, channel, required, previous, err)
+		}
 	}
 
 	if err := a.AddNeedsUpdateLabel(ctx, k8sClient, required); err != nil {
 		return fmt.Errorf("error adding needs-update label: %v", err)
 	}
 
-	channel := a.buildChannel()
+	// We only need the applied manifest to roll back the next update, which we only do for addons with a health timeout
+	if a.healthTimeout() > 0 {
+		if err := channel.SetAppliedManifest(ctx, k8sClient, data); err != nil {
+			klog.Warningf("the next update of %q cannot be rolled back: %v", a.Name, err)
+		}
+	} else {
+		if err := channel.DeleteAppliedManifest(ctx, k8sClient); err != nil {
+			klog.Warningf("error deleting the applied manifest of %q: %v", a.Name, err)
+		}
+	}
+
 	err = channel.SetInstalledVersion(ctx, k8sClient, a.ChannelVersion())
 	if err != nil {
 		return fmt.Errorf("error applying annotation to record addon installation: %v", err)
@@ -234,6 +413,77 @@ func (a *Addon) updateAddon(ctx context.Context, k8sClient kubernetes.Interface,
 	return nil
 }
 
+// healthTimeout returns how long to wait for the addon to become healthy after an update.
+// Health gating is opt-in: addons that do not set healthTimeout are not waited for, or rolled back.
+func (a *Addon) healthTimeout() time.Duration {
+	if a.Spec.HealthTimeout == nil {
+		return 0
+	}
+	return a.Spec.HealthTimeout.Duration
+}
+
+// waitForHealthy polls until the objects in the manifest are healthy, or the timeout expires.
+func waitForHealthy(ctx context.Context, healthChecker HealthChecker, data []byte, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	var unhealthy []string
+	for {
+		objects, err := healthChecker.CheckHealth(ctx, data)
+		if err != nil {
+			klog.Warningf("error checking health: %v", err)
+		} else if len(objects) == 0 {
+			return nil
+		} else {
+			unhealthy = objects
+		}
+
+		if time.Now().After(deadline) {
+			if unhealthy == nil {
+				return fmt.Errorf("could not check health within %v: %w", timeout, err)
+			}
+			return fmt.Errorf("objects not healthy after %v: %s", timeout, strings.Join(unhealthy, ", "))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(healthPollInterval):
+		}
+	}
+}
+
+// rollback applies the previous manifest again, after an update did not become healthy,
+// and records the failure in the installed version so that the update is not retried.
+func (a *Addon) rollback(ctx context.Context, k8sClient kubernetes.Interface, pruner *Pruner, applier HealthChecker, channel *Channel, required *AddonUpdate, previous []byte, healthErr error) error {
+	klog.Warningf("update of %q did not become healthy, rolling back to %v: %v", a.Name, required.ExistingVersion, healthErr)
+
+	// The previous version's objects are rolling out again, so they need not be healthy yet
+	if err := applier.ApplyWithoutHealthCheck(ctx, previous); err != nil {
+		// We don't record the failure, so that the whole update is retried
+		return fmt.Errorf("update did not become healthy (%w), and rolling back failed: %w", healthErr, err)
+	}
+	if err := pruner.Prune(ctx, previous, a.Spec.Prune); err != nil {
+		klog.Warningf("error pruning while rolling back %q: %v", a.Name, err)
+	}
+
+	// The message is stored in an annotation, so we keep it short
+	message := healthErr.Error()
+	if len(message) > maxFailureMessageLength {
+		message = message[:maxFailureMessageLength] + "..."
+	}
+	installed := *required.ExistingVersion
+	installed.Failed = &FailedUpdate{
+		Id:           a.Spec.Id,
+		ManifestHash: a.manifestHash(),
+		Time:         time.Now().UTC(),
+		Message:      message,
+	}
+	if err := channel.SetInstalledVersion(ctx, k8sClient, &installed); err != nil {
+		return fmt.Errorf("update did not become healthy and was rolled back (%w), but recording the failure failed: %w", healthErr, err)
+	}
+
+	return fmt.Errorf("update did not become healthy and was rolled back: %w", healthErr)
+}
+
 func (a *Addon) AddNeedsUpdateLabel(ctx context.Context, k8sClient kubernetes.Interface, required *AddonUpdate) error {
 	if required.ExistingVersion != nil {
 		if a.Spec.NeedsRollingUpdate != "" {
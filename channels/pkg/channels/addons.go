@@ -97,6 +97,7 @@ func (a *Addons) GetCurrent(kubernetesVersion semver.Version) (*AddonMenu, error
 		if !addon.matches(kubernetesVersion) {
 			continue
 		}
+		addon.kubernetesVersion = kubernetesVersion
 		name := addon.Name
 
 		existing := menu.Addons[name]
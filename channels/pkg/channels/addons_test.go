@@ -18,9 +18,13 @@ package channels
 
 import (
 	"context"
+	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/blang/semver/v4"
 	fakecertmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned/fake"
@@ -30,6 +34,7 @@ import (
 	fakekubernetes "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kops/channels/pkg/api"
 	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 func TestParseAddons(t *testing.T) {
@@ -327,6 +332,17 @@ func Test_Replacement(t *testing.T) {
 			New:      &ChannelVersion{Id: "a", ManifestHash: hash1, SystemGeneration: 1},
 			Replaces: false,
 		},
+		// Test failed updates
+		{
+			Old:      &ChannelVersion{Id: "a", ManifestHash: hash1, Failed: &FailedUpdate{Id: "a", ManifestHash: hash2}},
+			New:      &ChannelVersion{Id: "a", ManifestHash: hash2},
+			Replaces: false,
+		},
+		{
+			Old:      &ChannelVersion{Id: "a", ManifestHash: hash1, Failed: &FailedUpdate{Id: "a", ManifestHash: hash2}},
+			New:      &ChannelVersion{Id: "b", ManifestHash: hash2},
+			Replaces: true,
+		},
 	}
 	for _, g := range grid {
 		actual := g.New.replaces(t.Name(), g.Old)
@@ -566,3 +582,231 @@ func Test_InstallPKI(t *testing.T) {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
+
+// fakeApplier records the manifests applied, and reports objects as unhealthy while the last applied manifest is unhealthyManifest
+type fakeApplier struct {
+	applied           []string
+	unhealthyManifest string
+}
+
+func (f *fakeApplier) Apply(ctx context.Context, data []byte) error {
+	f.applied = append(f.applied, string(data))
+	if string(data) == f.unhealthyManifest {
+		return fmt.Errorf("not all objects were healthy")
+	}
+	return nil
+}
+
+func (f *fakeApplier) ApplyWithoutHealthCheck(ctx context.Context, data []byte) error {
+	f.applied = append(f.applied, string(data))
+	return nil
+}
+
+func (f *fakeApplier) CheckHealth(ctx context.Context, data []byte) ([]string, error) {
+	if len(f.applied) != 0 && f.applied[len(f.applied)-1] == f.unhealthyManifest {
+		return []string{"Deployment:kube-system/test (rolling out)"}, nil
+	}
+	return nil, nil
+}
+
+func Test_UpdateAddonHealthGating(t *testing.T) {
+	healthPollInterval = time.Millisecond
+
+	ctx := context.Background()
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	for name, contents := range map[string]string{"v1.yaml": "v1", "v2.yaml": "v2", "v3.yaml": "v3"} {
+		p, err := vfsContext.BuildVfsPath("memfs://channel/" + name)
+		if err != nil {
+			t.Fatalf("building path: %v", err)
+		}
+		if err := p.WriteFile(ctx, strings.NewReader(contents), nil); err != nil {
+			t.Fatalf("writing manifest: %v", err)
+		}
+	}
+
+	newAddon := func(version string) *Addon {
+		return &Addon{
+			Name:        "test",
+			ChannelName: "test-channel",
+			Spec: &api.AddonSpec{
+				Name:          new("test"),
+				Manifest:      new("memfs://channel/" + version + ".yaml"),
+				ManifestHash:  version,
+				HealthTimeout: &metav1.Duration{Duration: 50 * time.Millisecond},
+			},
+		}
+	}
+	installedVersion := func(fakek8s *fakekubernetes.Clientset) *ChannelVersion {
+		version, err := newAddon("v1").buildChannel().GetInstalledVersion(ctx, fakek8s)
+		if err != nil {
+			t.Fatalf("getting installed version: %v", err)
+		}
+		return version
+	}
+	appliedManifest := func(fakek8s *fakekubernetes.Clientset) string {
+		manifest, err := newAddon("v1").buildChannel().GetAppliedManifest(ctx, fakek8s)
+		if err != nil {
+			t.Fatalf("getting applied manifest: %v", err)
+		}
+		return string(manifest)
+	}
+
+	fakek8s := fakekubernetes.NewClientset(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "kube-system"}})
+	fakecm := fakecertmanager.NewSimpleClientset()
+	pruner := &Pruner{}
+
+	// A new install is not gated on health
+	applier := &fakeApplier{unhealthyManifest: "v1"}
+	if _, err := newAddon("v1").EnsureUpdated(ctx, vfsContext, fakek8s, fakecm, pruner, applier, nil); err != nil {
+		t.Fatalf("installing v1: %v", err)
+	}
+	if got := installedVersion(fakek8s).ManifestHash; got != "v1" {
+		t.Errorf("expected v1 to be installed, got %q", got)
+	}
+	if got := appliedManifest(fakek8s); got != "v1" {
+		t.Errorf("expected v1 manifest to be stored, got %q", got)
+	}
+
+	// An update that does not become healthy is rolled back
+	applier = &fakeApplier{unhealthyManifest: "v2"}
+	_, err := newAddon("v2").EnsureUpdated(ctx, vfsContext, fakek8s, fakecm, pruner, applier, installedVersion(fakek8s))
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("expected update to be rolled back, got %v", err)
+	}
+	if got := strings.Join(applier.applied, ","); got != "v2,v1" {
+		t.Errorf("expected v2 then v1 to be applied, got %q", got)
+	}
+	installed := installedVersion(fakek8s)
+	if installed.ManifestHash != "v1" || installed.Failed == nil || installed.Failed.ManifestHash != "v2" {
+		t.Errorf("expected v1 to be installed with failed update to v2, got %v", installed)
+	}
+	if got := appliedManifest(fakek8s); got != "v1" {
+		t.Errorf("expected v1 manifest to be stored, got %q", got)
+	}
+
+	// The failed update is not retried
+	required, err := newAddon("v2").GetRequiredUpdates(ctx, fakek8s, fakecm, installed)
+	if err != nil {
+		t.Fatalf("getting required updates: %v", err)
+	}
+	if required != nil {
+		t.Errorf("expected failed update not to be retried, got %v", required.NewVersion)
+	}
+
+	// A healthy update replaces the failure
+	applier = &fakeApplier{}
+	if _, err := newAddon("v2").EnsureUpdated(ctx, vfsContext, fakek8s, fakecm, pruner, applier, &ChannelVersion{ManifestHash: "v1"}); err != nil {
+		t.Fatalf("updating to v2: %v", err)
+	}
+	installed = installedVersion(fakek8s)
+	if installed.ManifestHash != "v2" || installed.Failed != nil {
+		t.Errorf("expected v2 to be installed, got %v", installed)
+	}
+	if got := appliedManifest(fakek8s); got != "v2" {
+		t.Errorf("expected v2 manifest to be stored, got %q", got)
+	}
+
+	// Addons that do not set healthTimeout are neither waited for nor rolled back,
+	// but are not recorded as installed unless they are healthy as soon as they are applied
+	ungated := newAddon("v3")
+	ungated.Spec.HealthTimeout = nil
+	applier = &fakeApplier{unhealthyManifest: "v3"}
+	if _, err := ungated.EnsureUpdated(ctx, vfsContext, fakek8s, fakecm, pruner, applier, installedVersion(fakek8s)); err == nil || !strings.Contains(err.Error(), "not all objects were healthy") {
+		t.Fatalf("expected update to v3 to fail as unhealthy, got %v", err)
+	}
+	if got := installedVersion(fakek8s).ManifestHash; got != "v2" {
+		t.Errorf("expected v2 to still be installed, got %q", got)
+	}
+	applier = &fakeApplier{}
+	if _, err := ungated.EnsureUpdated(ctx, vfsContext, fakek8s, fakecm, pruner, applier, installedVersion(fakek8s)); err != nil {
+		t.Fatalf("updating to v3: %v", err)
+	}
+	if got := strings.Join(applier.applied, ","); got != "v3" {
+		t.Errorf("expected only v3 to be applied, got %q", got)
+	}
+	if got := installedVersion(fakek8s).ManifestHash; got != "v3" {
+		t.Errorf("expected v3 to be installed, got %q", got)
+	}
+	// and their manifest is not stored
+	if got := appliedManifest(fakek8s); got != "" {
+		t.Errorf("expected no manifest to be stored, got %q", got)
+	}
+}
+
+func TestChartAddon(t *testing.T) {
+	dir := t.TempDir()
+	chartDir := filepath.Join(dir, "charts", "hello")
+	if err := os.MkdirAll(filepath.Join(chartDir, "templates"), 0o755); err != nil {
+		t.Fatalf("creating chart directory: %v", err)
+	}
+	files := map[string]string{
+		"Chart.yaml":  "apiVersion: v2\nname: hello\nversion: 0.1.0\n",
+		"values.yaml": "replicas: 1\n",
+		"templates/configmap.yaml": `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Release.Name }}
+data:
+  replicas: {{ .Values.replicas | quote }}
+  kubernetes: {{ .Capabilities.KubeVersion.Version }}
+`,
+	}
+	for name, contents := range files {
+		if err := os.WriteFile(filepath.Join(chartDir, name), []byte(contents), 0o644); err != nil {
+			t.Fatalf("writing %s: %v", name, err)
+		}
+	}
+
+	data := []byte(`
+kind: Addons
+metadata:
+  name: example
+spec:
+  addons:
+  - name: hello.addons.k8s.io
+    selector:
+      k8s-addon: hello.addons.k8s.io
+    chart:
+      repository: charts
+      name: hello
+      version: 0.1.0
+      namespace: hello
+      values:
+        replicas: 3
+`)
+	addons, err := ParseAddons("channel", mustParseURL(t, "file://"+dir+"/channel.yaml"), data)
+	if err != nil {
+		t.Fatalf("ParseAddons returned error: %v", err)
+	}
+	menu, err := addons.GetCurrent(semver.MustParse("1.34.1"))
+	if err != nil {
+		t.Fatalf("GetCurrent returned error: %v", err)
+	}
+	addon := menu.Addons["hello.addons.k8s.io"]
+	if addon == nil {
+		t.Fatalf("addon not found in menu")
+	}
+	if !addon.HasManifest() {
+		t.Errorf("expected chart addon to have a manifest")
+	}
+
+	manifest, err := addon.readManifest(context.Background(), vfs.NewVFSContext())
+	if err != nil {
+		t.Fatalf("readManifest returned error: %v", err)
+	}
+	for _, expected := range []string{"kind: Namespace", "namespace: hello", `replicas: "3"`, "kubernetes: v1.34.1"} {
+		if !strings.Contains(string(manifest), expected) {
+			t.Errorf("expected rendered manifest to contain %q, got:\n%s", expected, manifest)
+		}
+	}
+
+	hash := addon.ChannelVersion().ManifestHash
+	if hash == "" {
+		t.Fatalf("expected chart addon to have a manifest hash")
+	}
+	addon.Spec.Chart.Values["replicas"] = 4
+	if addon.ChannelVersion().ManifestHash == hash {
+		t.Errorf("expected manifest hash to change when the chart values change")
+	}
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// appliedManifestKey is the key in the ConfigMap holding the gzipped manifest
+const appliedManifestKey = "manifest.yaml.gz"
+
+// AppliedManifestName is the name of the ConfigMap, in the addon's namespace, holding the
+// manifest that was last applied for the addon. It is what we roll back to if an update fails.
+func (c *Channel) AppliedManifestName() string {
+	return "kops-addon-" + c.Name
+}
+
+// GetAppliedManifest returns the manifest that was last applied for the addon, or nil if none was stored.
+func (c *Channel) GetAppliedManifest(ctx context.Context, k8sClient kubernetes.Interface) ([]byte, error) {
+	cm, err := k8sClient.CoreV1().ConfigMaps(c.Namespace).Get(ctx, c.AppliedManifestName(), metav1.GetOptions{})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading applied manifest: %w", err)
+	}
+
+	compressed, ok := cm.BinaryData[appliedManifestKey]
+	if !ok {
+		return nil, nil
+	}
+	r, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing applied manifest: %w", err)
+	}
+	manifest, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing applied manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// SetAppliedManifest stores the manifest that was applied for the addon.
+func (c *Channel) SetAppliedManifest(ctx context.Context, k8sClient kubernetes.Interface, manifest []byte) error {
+	var compressed bytes.Buffer
+	w := gzip.NewWriter(&compressed)
+	if _, err := w.Write(manifest); err != nil {
+		return fmt.Errorf("error compressing applied manifest: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("error compressing applied manifest: %w", err)
+	}
+
+	configMaps := k8sClient.CoreV1().ConfigMaps(c.Namespace)
+	cm, err := configMaps.Get(ctx, c.AppliedManifestName(), metav1.GetOptions{})
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("error reading applied manifest: %w", err)
+		}
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.AppliedManifestName(),
+				Namespace: c.Namespace,
+			},
+		}
+		cm.BinaryData = map[string][]byte{appliedManifestKey: compressed.Bytes()}
+		if _, err := configMaps.Create(ctx, cm, metav1.CreateOptions{}); err != nil {
+			return fmt.Errorf("error storing applied manifest: %w", err)
+		}
+		return nil
+	}
+
+	cm.BinaryData = map[string][]byte{appliedManifestKey: compressed.Bytes()}
+	if _, err := configMaps.Update(ctx, cm, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("error storing applied manifest: %w", err)
+	}
+	return nil
+}
+
+// DeleteAppliedManifest deletes the stored manifest of the addon, if there is one.
+func (c *Channel) DeleteAppliedManifest(ctx context.Context, k8sClient kubernetes.Interface) error {
+	err := k8sClient.CoreV1().ConfigMaps(c.Namespace).Delete(ctx, c.AppliedManifestName(), metav1.DeleteOptions{})
+	if err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("error deleting applied manifest: %w", err)
+	}
+	return nil
+}
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	certmanager "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned"
 	v1 "k8s.io/api/core/v1"
@@ -54,6 +55,19 @@ type ChannelVersion struct {
 	// SystemGeneration holds the generation of the channels functionality.
 	// It is used so that we reapply when we introduce new features, such as prune.
 	SystemGeneration int `json:"systemGeneration,omitempty"`
+
+	// Failed records an update of the addon that was rolled back because it did not become healthy.
+	// That update is not applied again.
+	Failed *FailedUpdate `json:"failed,omitempty"`
+}
+
+// FailedUpdate identifies an update of an addon that was rolled back
+type FailedUpdate struct {
+	Id           string    `json:"id,omitempty"`
+	ManifestHash string    `json:"manifestHash,omitempty"`
+	Time         time.Time `json:"time"`
+	// Message describes why the update failed
+	Message string `json:"message,omitempty"`
 }
 
 func stringValue(s *string) string {
@@ -72,6 +86,9 @@ func (c *ChannelVersion) String() string {
 		s += " ManifestHash=" + c.ManifestHash
 	}
 	s += " SystemGeneration=" + strconv.Itoa(c.SystemGeneration)
+	if c.Failed != nil {
+		s += " Failed=" + c.Failed.ManifestHash
+	}
 	return s
 }
 
@@ -118,6 +135,11 @@ func (c *Channel) AnnotationName() string {
 func (c *ChannelVersion) replaces(name string, existing *ChannelVersion) bool {
 	klog.V(6).Infof("Checking existing config for %q: %v compared to new channel: %v", name, existing, c)
 
+	if existing.Failed != nil && existing.Failed.Id == c.Id && existing.Failed.ManifestHash == c.ManifestHash {
+		klog.Warningf("update of %q to %v was rolled back at %s (%s); will not replace", name, c, existing.Failed.Time.Format(time.RFC3339), existing.Failed.Message)
+		return false
+	}
+
 	if c.Id != existing.Id {
 		klog.V(4).Infof("cluster has different ids for %q (%q vs %q); will replace", name, c.Id, existing.Id)
 		return true
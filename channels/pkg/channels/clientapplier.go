@@ -18,13 +18,21 @@ package channels
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"reflect"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/kops/pkg/applylib/applyset"
+	"k8s.io/kops/pkg/diff"
 	"k8s.io/kops/pkg/kubemanifest"
+	"sigs.k8s.io/yaml"
 )
 
 type ClientApplier struct {
@@ -32,11 +40,32 @@ type ClientApplier struct {
 	RESTMapper *restmapper.DeferredDiscoveryRESTMapper
 }
 
-// Apply applies the manifest to the cluster.
+// Apply applies the manifest to the cluster, and requires the objects to be healthy.
 func (p *ClientApplier) Apply(ctx context.Context, manifest []byte) error {
+	results, err := p.apply(ctx, manifest)
+	if err != nil {
+		return err
+	}
+
+	// TODO: Check object health status
+	if !results.AllHealthy() {
+		return fmt.Errorf("not all objects were healthy")
+	}
+
+	return nil
+}
+
+// ApplyWithoutHealthCheck applies the manifest to the cluster, without requiring the objects to be healthy,
+// for callers that wait for the objects to become healthy with CheckHealth.
+func (p *ClientApplier) ApplyWithoutHealthCheck(ctx context.Context, manifest []byte) error {
+	_, err := p.apply(ctx, manifest)
+	return err
+}
+
+func (p *ClientApplier) apply(ctx context.Context, manifest []byte) (*applyset.ApplyResults, error) {
 	objects, err := kubemanifest.LoadObjectsFrom(manifest)
 	if err != nil {
-		return fmt.Errorf("failed to parse objects: %w", err)
+		return nil, fmt.Errorf("failed to parse objects: %w", err)
 	}
 
 	// TODO: Cache applyset for more efficient applying
@@ -58,7 +87,7 @@ func (p *ClientApplier) Apply(ctx context.Context, manifest []byte) error {
 		PatchOptions: patchOptions,
 	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	var applyableObjects []applyset.ApplyableObject
@@ -66,24 +95,220 @@ func (p *ClientApplier) Apply(ctx context.Context, manifest []byte) error {
 		applyableObjects = append(applyableObjects, object)
 	}
 	if err := s.SetDesiredObjects(applyableObjects); err != nil {
-		return err
+		return nil, err
 	}
 
 	results, err := s.ApplyOnce(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to apply objects: %w", err)
+		return nil, fmt.Errorf("failed to apply objects: %w", err)
 	}
 
 	// TODO: Implement pruning
 
 	if !results.AllApplied() {
-		return fmt.Errorf("not all objects were applied")
+		return nil, fmt.Errorf("not all objects were applied")
 	}
 
-	// TODO: Check object health status
-	if !results.AllHealthy() {
-		return fmt.Errorf("not all objects were healthy")
+	return results, nil
+}
+
+// CheckHealth reports the objects in the manifest that are not healthy.
+func (p *ClientApplier) CheckHealth(ctx context.Context, manifest []byte) ([]string, error) {
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse objects: %w", err)
 	}
 
-	return nil
+	s, err := applyset.New(applyset.Options{
+		RESTMapper: p.RESTMapper,
+		Client:     p.Client,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var applyableObjects []applyset.ApplyableObject
+	for _, object := range objects {
+		applyableObjects = append(applyableObjects, object)
+	}
+	if err := s.SetDesiredObjects(applyableObjects); err != nil {
+		return nil, err
+	}
+
+	results, err := s.CheckHealth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check health of objects: %w", err)
+	}
+	return results.Unhealthy(), nil
+}
+
+// Actions reported in an ObjectDiff.
+const (
+	ObjectActionCreate    = "create"
+	ObjectActionUpdate    = "update"
+	ObjectActionUnchanged = "unchanged"
+	ObjectActionError     = "error"
+)
+
+// ObjectDiff is the result of a dry-run apply of a single object.
+type ObjectDiff struct {
+	// Object identifies the object, as Kind:namespace/name.
+	Object string
+	// Action is what applying the object would do, one of the ObjectAction constants.
+	Action string
+	// Diff is the difference between the live object and the result of the apply.
+	Diff string
+	// Error is set if the dry-run failed for this object, for example because its namespace does not exist yet.
+	Error error
+}
+
+// DryRun applies the manifest to the cluster using a server-side dry-run,
+// and reports the difference between each live object and the object the server would persist.
+func (p *ClientApplier) DryRun(ctx context.Context, manifest []byte) ([]*ObjectDiff, error) {
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse objects: %w", err)
+	}
+
+	force := true
+	patchOptions := metav1.PatchOptions{
+		FieldManager: "kops",
+		Force:        &force,
+		DryRun:       []string{metav1.DryRunAll},
+	}
+
+	client := applyset.NewUnstructuredClient(applyset.Options{
+		RESTMapper: p.RESTMapper,
+		Client:     p.Client,
+	})
+
+	var diffs []*ObjectDiff
+	for _, object := range objects {
+		u := object.ToUnstructured()
+		gvk := u.GroupVersionKind()
+		nn := types.NamespacedName{Namespace: u.GetNamespace(), Name: u.GetName()}
+
+		result := &ObjectDiff{Object: gvk.Kind + ":" + u.GetName()}
+		if nn.Namespace != "" {
+			result.Object = gvk.Kind + ":" + nn.String()
+		}
+		diffs = append(diffs, result)
+
+		live, err := client.Get(ctx, gvk, nn)
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				result.Action = ObjectActionError
+				result.Error = err
+				continue
+			}
+		}
+
+		j, err := json.Marshal(object)
+		if err != nil {
+			result.Action = ObjectActionError
+			result.Error = fmt.Errorf("failed to marshal object to JSON: %w", err)
+			continue
+		}
+
+		applied, err := client.Patch(ctx, gvk, nn, types.ApplyPatchType, j, patchOptions)
+		if err != nil {
+			result.Action = ObjectActionError
+			result.Error = fmt.Errorf("error from dry-run apply: %w", err)
+			continue
+		}
+
+		live, applied = maskSecretData(live, applied)
+
+		liveYAML, err := comparableYAML(live)
+		if err != nil {
+			return nil, err
+		}
+		appliedYAML, err := comparableYAML(applied)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case live == nil:
+			result.Action = ObjectActionCreate
+		case liveYAML == appliedYAML:
+			result.Action = ObjectActionUnchanged
+			continue
+		default:
+			result.Action = ObjectActionUpdate
+		}
+		result.Diff = diff.FormatDiff(liveYAML, appliedYAML)
+	}
+
+	return diffs, nil
+}
+
+// maskSecretData returns copies of the live and applied objects in which the values of a Secret
+// are replaced with placeholders, so that diffs do not print credentials. As with kubectl diff,
+// values that change are masked differently before and after, so that the change is still shown.
+func maskSecretData(live, applied *unstructured.Unstructured) (*unstructured.Unstructured, *unstructured.Unstructured) {
+	isSecret := func(obj *unstructured.Unstructured) bool {
+		return obj != nil && obj.GroupVersionKind().GroupKind() == schema.GroupKind{Kind: "Secret"}
+	}
+	if !isSecret(live) && !isSecret(applied) {
+		return live, applied
+	}
+
+	if live != nil {
+		live = live.DeepCopy()
+	}
+	if applied != nil {
+		applied = applied.DeepCopy()
+	}
+
+	for _, field := range []string{"data", "stringData"} {
+		var before, after map[string]interface{}
+		if live != nil {
+			before, _, _ = unstructured.NestedMap(live.Object, field)
+		}
+		if applied != nil {
+			after, _, _ = unstructured.NestedMap(applied.Object, field)
+		}
+
+		for k, v := range before {
+			if w, found := after[k]; !found || reflect.DeepEqual(v, w) {
+				before[k] = "***"
+			} else {
+				before[k] = "*** (before)"
+			}
+		}
+		for k := range after {
+			if v, found := before[k]; !found || v == "***" {
+				after[k] = "***"
+			} else {
+				after[k] = "*** (after)"
+			}
+		}
+
+		if before != nil {
+			unstructured.SetNestedMap(live.Object, before, field)
+		}
+		if after != nil {
+			unstructured.SetNestedMap(applied.Object, after, field)
+		}
+	}
+	return live, applied
+}
+
+// comparableYAML renders the object as YAML, without the fields the server changes on every write.
+func comparableYAML(obj *unstructured.Unstructured) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+	obj = obj.DeepCopy()
+	for _, field := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"} {
+		unstructured.RemoveNestedField(obj.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	b, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal %s to YAML: %w", obj.GetName(), err)
+	}
+	return string(b), nil
 }
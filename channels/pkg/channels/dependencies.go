@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// SortedAddons returns the addons in the menu, ordered so that each addon comes after the addons it depends on.
+// Addons are applied in groups: first those without dependencies, then those that depend only on the first group, and so on.
+// Within a group, addons are ordered by name.
+// Dependencies that are not in the menu are ignored, as they may be managed by another channel.
+func (m *AddonMenu) SortedAddons() ([]*Addon, error) {
+	depths := make(map[string]int)
+	for name := range m.Addons {
+		if _, err := m.depth(name, depths, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	var addons []*Addon
+	for _, addon := range m.Addons {
+		addons = append(addons, addon)
+	}
+	sort.Slice(addons, func(i, j int) bool {
+		if depths[addons[i].Name] != depths[addons[j].Name] {
+			return depths[addons[i].Name] < depths[addons[j].Name]
+		}
+		return addons[i].Name < addons[j].Name
+	})
+	return addons, nil
+}
+
+// depth returns the length of the longest chain of dependencies from the named addon.
+// path holds the addons we are computing the depth of, to detect cycles.
+func (m *AddonMenu) depth(name string, depths map[string]int, path []string) (int, error) {
+	if depth, found := depths[name]; found {
+		return depth, nil
+	}
+	for i, p := range path {
+		if p == name {
+			return 0, fmt.Errorf("addons have a dependency cycle: %s", strings.Join(append(path[i:], name), " -> "))
+		}
+	}
+
+	depth := 0
+	for _, dependency := range m.Addons[name].Spec.DependsOn {
+		if _, found := m.Addons[dependency]; !found {
+			klog.V(2).Infof("ignoring dependency of %q on %q, which is not in the channel", name, dependency)
+			continue
+		}
+		d, err := m.depth(dependency, depths, append(path, name))
+		if err != nil {
+			return 0, err
+		}
+		depth = max(depth, d+1)
+	}
+	depths[name] = depth
+	return depth, nil
+}
+
+// WaitForDependencies waits for each addon in the menu that the addon depends on to become healthy,
+// for up to the healthTimeout of the dependency. Dependencies that do not set healthTimeout are not waited for.
+func (m *AddonMenu) WaitForDependencies(ctx context.Context, vfsContext *vfs.VFSContext, healthChecker HealthChecker, addon *Addon) error {
+	for _, name := range addon.Spec.DependsOn {
+		dependency := m.Addons[name]
+		if dependency == nil || !dependency.HasManifest() {
+			continue
+		}
+		timeout := dependency.healthTimeout()
+		if timeout <= 0 {
+			continue
+		}
+
+		data, err := dependency.readManifest(ctx, vfsContext)
+		if err != nil {
+			return fmt.Errorf("error reading manifest of dependency %q: %w", name, err)
+		}
+
+		klog.Infof("waiting for %q to become healthy before applying %q", name, addon.Name)
+		if err := waitForHealthy(ctx, healthChecker, data, timeout); err != nil {
+			return fmt.Errorf("dependency %q: %w", name, err)
+		}
+	}
+	return nil
+}
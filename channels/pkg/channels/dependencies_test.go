@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func menuOf(specs ...*api.AddonSpec) *AddonMenu {
+	menu := NewAddonMenu()
+	for _, spec := range specs {
+		menu.Addons[*spec.Name] = &Addon{Name: *spec.Name, Spec: spec}
+	}
+	return menu
+}
+
+func TestSortedAddons(t *testing.T) {
+	grid := []struct {
+		name     string
+		menu     *AddonMenu
+		expected string
+		err      string
+	}{
+		{
+			name: "no dependencies",
+			menu: menuOf(
+				&api.AddonSpec{Name: new("c")},
+				&api.AddonSpec{Name: new("a")},
+				&api.AddonSpec{Name: new("b")},
+			),
+			expected: "a,b,c",
+		},
+		{
+			name: "dependencies come first",
+			menu: menuOf(
+				&api.AddonSpec{Name: new("a"), DependsOn: []string{"certmanager"}},
+				&api.AddonSpec{Name: new("certmanager"), DependsOn: []string{"z"}},
+				&api.AddonSpec{Name: new("b")},
+				&api.AddonSpec{Name: new("z")},
+			),
+			expected: "b,z,certmanager,a",
+		},
+		{
+			name: "dependencies outside the channel are ignored",
+			menu: menuOf(
+				&api.AddonSpec{Name: new("a"), DependsOn: []string{"other"}},
+				&api.AddonSpec{Name: new("b")},
+			),
+			expected: "a,b",
+		},
+		{
+			name: "cycle",
+			menu: menuOf(
+				&api.AddonSpec{Name: new("a"), DependsOn: []string{"b"}},
+				&api.AddonSpec{Name: new("b"), DependsOn: []string{"c"}},
+				&api.AddonSpec{Name: new("c"), DependsOn: []string{"a"}},
+			),
+			err: "dependency cycle",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			addons, err := g.menu.SortedAddons()
+			if g.err != "" {
+				if err == nil || !strings.Contains(err.Error(), g.err) {
+					t.Fatalf("expected error containing %q, got %v", g.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var names []string
+			for _, addon := range addons {
+				names = append(names, addon.Name)
+			}
+			if actual := strings.Join(names, ","); actual != g.expected {
+				t.Errorf("unexpected order; got %q, want %q", actual, g.expected)
+			}
+		})
+	}
+}
+
+func TestWaitForDependencies(t *testing.T) {
+	healthPollInterval = time.Millisecond
+
+	ctx := context.Background()
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	p, err := vfsContext.BuildVfsPath("memfs://channel/dependency.yaml")
+	if err != nil {
+		t.Fatalf("building path: %v", err)
+	}
+	if err := p.WriteFile(ctx, strings.NewReader("dependency"), nil); err != nil {
+		t.Fatalf("writing manifest: %v", err)
+	}
+
+	menu := menuOf(
+		&api.AddonSpec{Name: new("dependency"), Manifest: new("dependency.yaml"), HealthTimeout: &metav1.Duration{Duration: 10 * time.Millisecond}},
+		&api.AddonSpec{Name: new("dependent"), DependsOn: []string{"dependency"}},
+	)
+	menu.Addons["dependency"].ChannelLocation = *mustParseURL(t, "memfs://channel/addons.yaml")
+
+	healthy := &fakeApplier{}
+	if err := menu.WaitForDependencies(ctx, vfsContext, healthy, menu.Addons["dependent"]); err != nil {
+		t.Errorf("expected healthy dependency not to block, got %v", err)
+	}
+
+	unhealthy := &fakeApplier{applied: []string{"dependency"}, unhealthyManifest: "dependency"}
+	err = menu.WaitForDependencies(ctx, vfsContext, unhealthy, menu.Addons["dependent"])
+	if err == nil || !strings.Contains(err.Error(), `dependency "dependency"`) {
+		t.Errorf("expected unhealthy dependency to be reported, got %v", err)
+	}
+}
@@ -37,26 +37,37 @@ type Pruner struct {
 
 // Prune prunes objects not in the manifest, according to PruneSpec.
 func (p *Pruner) Prune(ctx context.Context, manifest []byte, spec *api.PruneSpec) error {
+	_, err := p.prune(ctx, manifest, spec, false)
+	return err
+}
+
+// PlanPrune returns the objects that Prune would delete, as Kind:namespace/name.
+// The deletions are sent to the server as a dry-run.
+func (p *Pruner) PlanPrune(ctx context.Context, manifest []byte, spec *api.PruneSpec) ([]string, error) {
+	return p.prune(ctx, manifest, spec, true)
+}
+
+func (p *Pruner) prune(ctx context.Context, manifest []byte, spec *api.PruneSpec, dryRun bool) ([]string, error) {
 	klog.Infof("Prune spec: %v", spec)
 
 	if spec == nil {
-		return nil
+		return nil, nil
 	}
 
 	objects, err := kubemanifest.LoadObjectsFrom(manifest)
 	if err != nil {
-		return fmt.Errorf("failed to parse objects: %w", err)
+		return nil, fmt.Errorf("failed to parse objects: %w", err)
 	}
 
 	objectsByKind := make(map[schema.GroupKind][]*kubemanifest.Object)
 	for _, object := range objects {
 		gv, err := schema.ParseGroupVersion(object.APIVersion())
 		if err != nil || gv.Version == "" {
-			return fmt.Errorf("failed to parse apiVersion %q", object.APIVersion())
+			return nil, fmt.Errorf("failed to parse apiVersion %q", object.APIVersion())
 		}
 		kind := object.Kind()
 		if kind == "" {
-			return fmt.Errorf("failed to find kind in object")
+			return nil, fmt.Errorf("failed to find kind in object")
 		}
 
 		gvk := gv.WithKind(kind)
@@ -64,18 +75,19 @@ func (p *Pruner) Prune(ctx context.Context, manifest []byte, spec *api.PruneSpec
 		objectsByKind[gk] = append(objectsByKind[gk], object)
 	}
 
+	var pruned []string
 	for i := range spec.Kinds {
 		pruneKind := &spec.Kinds[i]
 		gk := schema.GroupKind{Group: pruneKind.Group, Kind: pruneKind.Kind}
-		if err := p.pruneObjectsOfKind(ctx, gk, pruneKind, objectsByKind[gk]); err != nil {
-			return fmt.Errorf("failed to prune objects of kind %s: %w", gk, err)
+		if err := p.pruneObjectsOfKind(ctx, gk, pruneKind, objectsByKind[gk], dryRun, &pruned); err != nil {
+			return nil, fmt.Errorf("failed to prune objects of kind %s: %w", gk, err)
 		}
 	}
 
-	return nil
+	return pruned, nil
 }
 
-func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, spec *api.PruneKindSpec, keepObjects []*kubemanifest.Object) error {
+func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, spec *api.PruneKindSpec, keepObjects []*kubemanifest.Object, dryRun bool, pruned *[]string) error {
 	klog.Infof("pruning objects of kind: %v", gk)
 
 	restMapping, err := p.RESTMapper.RESTMapping(gk)
@@ -95,7 +107,7 @@ func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, sp
 		if err != nil {
 			return fmt.Errorf("error listing objects: %w", err)
 		}
-		if err := p.pruneObjects(ctx, gvr, objects, keepObjects); err != nil {
+		if err := p.pruneObjects(ctx, gvr, gk, objects, keepObjects, dryRun, pruned); err != nil {
 			return err
 		}
 	} else {
@@ -105,7 +117,7 @@ func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, sp
 			if err != nil {
 				return fmt.Errorf("error listing objects in namespace %s: %w", namespace, err)
 			}
-			if err := p.pruneObjects(ctx, gvr, actualObjects, keepObjects); err != nil {
+			if err := p.pruneObjects(ctx, gvr, gk, actualObjects, keepObjects, dryRun, pruned); err != nil {
 				return err
 			}
 		}
@@ -114,7 +126,7 @@ func (p *Pruner) pruneObjectsOfKind(ctx context.Context, gk schema.GroupKind, sp
 	return nil
 }
 
-func (p *Pruner) pruneObjects(ctx context.Context, gvr schema.GroupVersionResource, actualObjects *unstructured.UnstructuredList, keepObjects []*kubemanifest.Object) error {
+func (p *Pruner) pruneObjects(ctx context.Context, gvr schema.GroupVersionResource, gk schema.GroupKind, actualObjects *unstructured.UnstructuredList, keepObjects []*kubemanifest.Object, dryRun bool, pruned *[]string) error {
 	keepMap := make(map[string]*kubemanifest.Object)
 	for _, keepObject := range keepObjects {
 		key := keepObject.GetNamespace() + "/" + keepObject.GetName()
@@ -130,7 +142,11 @@ func (p *Pruner) pruneObjects(ctx context.Context, gvr schema.GroupVersionResour
 			continue
 		}
 
-		klog.Infof("pruning %s %s", gvr, key)
+		if dryRun {
+			klog.Infof("would prune %s %s", gvr, key)
+		} else {
+			klog.Infof("pruning %s %s", gvr, key)
+		}
 
 		var resource dynamic.ResourceInterface
 		if namespace != "" {
@@ -140,9 +156,17 @@ func (p *Pruner) pruneObjects(ctx context.Context, gvr schema.GroupVersionResour
 		}
 
 		var opts v1.DeleteOptions
+		if dryRun {
+			opts.DryRun = []string{v1.DryRunAll}
+		}
 		if err := resource.Delete(ctx, name, opts); err != nil {
 			return fmt.Errorf("failed to delete %s: %w", key, err)
 		}
+		if namespace != "" {
+			*pruned = append(*pruned, gk.Kind+":"+key)
+		} else {
+			*pruned = append(*pruned, gk.Kind+":"+name)
+		}
 	}
 
 	return nil
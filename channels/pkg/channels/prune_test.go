@@ -0,0 +1,251 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package channels
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/kops/channels/pkg/api"
+)
+
+// fakeDynamicClient implements just enough of dynamic.Interface for pruning.
+type fakeDynamicClient struct {
+	dynamic.Interface
+	objects []unstructured.Unstructured
+	deletes []fakeDelete
+}
+
+type fakeDelete struct {
+	namespace string
+	name      string
+	options   metav1.DeleteOptions
+}
+
+func (c *fakeDynamicClient) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return &fakeResource{client: c}
+}
+
+type fakeResource struct {
+	dynamic.NamespaceableResourceInterface
+	client    *fakeDynamicClient
+	namespace string
+}
+
+func (r *fakeResource) Namespace(namespace string) dynamic.ResourceInterface {
+	return &fakeResource{client: r.client, namespace: namespace}
+}
+
+func (r *fakeResource) List(ctx context.Context, opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	for _, obj := range r.client.objects {
+		if r.namespace == "" || obj.GetNamespace() == r.namespace {
+			list.Items = append(list.Items, obj)
+		}
+	}
+	return list, nil
+}
+
+func (r *fakeResource) Delete(ctx context.Context, name string, options metav1.DeleteOptions, subresources ...string) error {
+	r.client.deletes = append(r.client.deletes, fakeDelete{namespace: r.namespace, name: name, options: options})
+	return nil
+}
+
+func configMap(namespace, name string) unstructured.Unstructured {
+	u := unstructured.Unstructured{}
+	u.SetAPIVersion("v1")
+	u.SetKind("ConfigMap")
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	return u
+}
+
+func TestPlanPrune(t *testing.T) {
+	discovery := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{}}
+	discovery.Resources = []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"},
+			},
+		},
+	}
+	client := &fakeDynamicClient{
+		objects: []unstructured.Unstructured{
+			configMap("kube-system", "keep"),
+			configMap("kube-system", "old"),
+			configMap("default", "other"),
+		},
+	}
+	pruner := &Pruner{
+		Client:     client,
+		RESTMapper: restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(discovery)),
+	}
+
+	manifest := []byte(`
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: keep
+  namespace: kube-system
+`)
+	spec := &api.PruneSpec{
+		Kinds: []api.PruneKindSpec{
+			{Kind: "ConfigMap", Namespaces: []string{"kube-system"}},
+		},
+	}
+
+	pruned, err := pruner.PlanPrune(context.Background(), manifest, spec)
+	if err != nil {
+		t.Fatalf("PlanPrune failed: %v", err)
+	}
+	if want := []string{"ConfigMap:kube-system/old"}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("unexpected pruned objects; got %v, want %v", pruned, want)
+	}
+	if len(client.deletes) != 1 {
+		t.Fatalf("expected 1 delete, got %d", len(client.deletes))
+	}
+	if got := client.deletes[0].options.DryRun; !reflect.DeepEqual(got, []string{metav1.DryRunAll}) {
+		t.Errorf("expected delete to be a dry-run, got DryRun=%v", got)
+	}
+
+	client.deletes = nil
+	if err := pruner.Prune(context.Background(), manifest, spec); err != nil {
+		t.Fatalf("Prune failed: %v", err)
+	}
+	if len(client.deletes) != 1 {
+		t.Fatalf("expected 1 delete, got %d", len(client.deletes))
+	}
+	if got := client.deletes[0].options.DryRun; len(got) != 0 {
+		t.Errorf("expected delete not to be a dry-run, got DryRun=%v", got)
+	}
+}
+
+func TestComparableYAML(t *testing.T) {
+	live := configMap("kube-system", "example")
+	live.SetResourceVersion("123")
+	live.SetUID("abc")
+	live.SetGeneration(2)
+	live.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kops"}})
+	unstructured.SetNestedField(live.Object, "value", "data", "key")
+
+	applied := live.DeepCopy()
+	applied.SetResourceVersion("124")
+	applied.SetGeneration(3)
+	unstructured.SetNestedField(applied.Object, "changed", "status", "phase")
+
+	liveYAML, err := comparableYAML(&live)
+	if err != nil {
+		t.Fatalf("comparableYAML failed: %v", err)
+	}
+	appliedYAML, err := comparableYAML(applied)
+	if err != nil {
+		t.Fatalf("comparableYAML failed: %v", err)
+	}
+	if liveYAML != appliedYAML {
+		t.Errorf("expected server-managed fields to be ignored, got\n%s\nand\n%s", liveYAML, appliedYAML)
+	}
+	if !strings.Contains(liveYAML, "key: value") {
+		t.Errorf("expected data to be kept, got\n%s", liveYAML)
+	}
+
+	empty, err := comparableYAML(nil)
+	if err != nil || empty != "" {
+		t.Errorf("expected nil object to render as empty, got %q, %v", empty, err)
+	}
+}
+
+func TestMaskSecretData(t *testing.T) {
+	secret := func(data map[string]interface{}) *unstructured.Unstructured {
+		u := &unstructured.Unstructured{}
+		u.SetAPIVersion("v1")
+		u.SetKind("Secret")
+		u.SetNamespace("kube-system")
+		u.SetName("cloud-credentials")
+		unstructured.SetNestedMap(u.Object, data, "data")
+		return u
+	}
+
+	live := secret(map[string]interface{}{
+		"unchanged": "c2VjcmV0",
+		"changed":   "b2xk",
+		"removed":   "Z29uZQ==",
+	})
+	applied := secret(map[string]interface{}{
+		"unchanged": "c2VjcmV0",
+		"changed":   "bmV3",
+		"added":     "YWRkZWQ=",
+	})
+
+	maskedLive, maskedApplied := maskSecretData(live, applied)
+	liveYAML, err := comparableYAML(maskedLive)
+	if err != nil {
+		t.Fatalf("comparableYAML failed: %v", err)
+	}
+	appliedYAML, err := comparableYAML(maskedApplied)
+	if err != nil {
+		t.Fatalf("comparableYAML failed: %v", err)
+	}
+
+	for _, value := range []string{"c2VjcmV0", "b2xk", "Z29uZQ==", "bmV3", "YWRkZWQ="} {
+		if strings.Contains(liveYAML, value) || strings.Contains(appliedYAML, value) {
+			t.Errorf("expected secret value %q to be masked, got\n%s\nand\n%s", value, liveYAML, appliedYAML)
+		}
+	}
+	for _, expected := range []string{"unchanged: '***'", "changed: '*** (before)'", "removed: '***'"} {
+		if !strings.Contains(liveYAML, expected) {
+			t.Errorf("expected %q in live object, got\n%s", expected, liveYAML)
+		}
+	}
+	for _, expected := range []string{"unchanged: '***'", "changed: '*** (after)'", "added: '***'"} {
+		if !strings.Contains(appliedYAML, expected) {
+			t.Errorf("expected %q in applied object, got\n%s", expected, appliedYAML)
+		}
+	}
+
+	if got, _, _ := unstructured.NestedString(live.Object, "data", "changed"); got != "b2xk" {
+		t.Errorf("expected the live object not to be modified, got %q", got)
+	}
+
+	// A new Secret is masked too
+	_, maskedApplied = maskSecretData(nil, applied)
+	appliedYAML, err = comparableYAML(maskedApplied)
+	if err != nil {
+		t.Fatalf("comparableYAML failed: %v", err)
+	}
+	if strings.Contains(appliedYAML, "bmV3") {
+		t.Errorf("expected secret value to be masked, got\n%s", appliedYAML)
+	}
+
+	// Other kinds are not masked
+	cm := configMap("kube-system", "example")
+	unstructured.SetNestedField(cm.Object, "value", "data", "key")
+	_, maskedCM := maskSecretData(nil, &cm)
+	if got, _, _ := unstructured.NestedString(maskedCM.Object, "data", "key"); got != "value" {
+		t.Errorf("expected ConfigMap data not to be masked, got %q", got)
+	}
+}
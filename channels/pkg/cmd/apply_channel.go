@@ -21,7 +21,6 @@ import (
 	"fmt"
 	"io"
 	"net/url"
-	"os"
 	"os/signal"
 	"strings"
 	"syscall"
@@ -43,23 +42,37 @@ import (
 	"k8s.io/kops/util/pkg/vfs"
 )
 
+// Values for ApplyChannelOptions.DryRun
+const (
+	DryRunNone   = "none"
+	DryRunServer = "server"
+)
+
 type ApplyChannelOptions struct {
 	Yes      bool
 	Interval time.Duration
 	NodeName string
 
+	// DryRun is DryRunServer to show the changes each addon update would make, using a server-side dry-run
+	DryRun string
+
 	// Comma delimited label,value pairs to add to the node. Eg "kops.k8s.io/cloud-controller-manager,foo=bar"
 	NodeLabels map[string]string
 }
 
 func NewCmdApplyChannel(f *ChannelsFactory, out io.Writer) *cobra.Command {
-	var options ApplyChannelOptions
+	options := ApplyChannelOptions{
+		DryRun: DryRunNone,
+	}
 	var rawLabels string
 
 	cmd := &cobra.Command{
 		Use:   "channel CHANNEL...",
 		Short: "Applies updates from the given channel(s)",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := options.validateDryRun(); err != nil {
+				return err
+			}
 			var err error
 			options.NodeLabels, err = parseLabels(rawLabels)
 			if err != nil {
@@ -75,6 +88,7 @@ func NewCmdApplyChannel(f *ChannelsFactory, out io.Writer) *cobra.Command {
 	}
 
 	cmd.Flags().BoolVar(&options.Yes, "yes", false, "Apply update")
+	cmd.Flags().StringVar(&options.DryRun, "dry-run", options.DryRun, "Set to \"server\" to show the changes to each object using a server-side dry-run, without applying them")
 	cmd.Flags().DurationVar(&options.Interval, "interval", 0, "If non-zero, re-apply the channel on this interval until interrupted (e.g. 60s)")
 	cmd.Flags().StringVar(&options.NodeName, "node-name", "", "If set, patch the named node with the mandatory control-plane labels each iteration; typically supplied via the downward API.")
 	cmd.Flags().StringVar(&rawLabels, "node-labels", "", "If set, patch the named node with each of the label,value pairs each iteration; typically supplied via the downward API.")
@@ -82,6 +96,23 @@ func NewCmdApplyChannel(f *ChannelsFactory, out io.Writer) *cobra.Command {
 	return cmd
 }
 
+func (o *ApplyChannelOptions) validateDryRun() error {
+	switch o.DryRun {
+	case "", DryRunNone:
+		return nil
+	case DryRunServer:
+		if o.Yes {
+			return fmt.Errorf("--yes cannot be combined with --dry-run=%s", o.DryRun)
+		}
+		if o.Interval > 0 {
+			return fmt.Errorf("--interval cannot be combined with --dry-run=%s", o.DryRun)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown value %q for --dry-run, expected %q or %q", o.DryRun, DryRunNone, DryRunServer)
+	}
+}
+
 func parseLabels(rawLabels string) (map[string]string, error) {
 	labels := make(map[string]string)
 	pairs := strings.Split(rawLabels, ",")
@@ -156,6 +187,10 @@ func runApplyChannelLoop(ctx context.Context, out io.Writer, options *ApplyChann
 }
 
 func RunApplyChannel(ctx context.Context, f *ChannelsFactory, out io.Writer, options *ApplyChannelOptions, args []string) error {
+	if err := options.validateDryRun(); err != nil {
+		return err
+	}
+
 	restConfig, err := f.RESTConfig()
 	if err != nil {
 		return err
@@ -209,14 +244,14 @@ func RunApplyChannel(ctx context.Context, f *ChannelsFactory, out io.Writer, opt
 			merr = multierr.Append(merr, fmt.Errorf("building menu for %q: %w", channelLocation, err))
 			continue
 		}
-		if err := applyMenu(ctx, menu, f.VFSContext(), k8sClient, cmClient, dynamicClient, restMapper, options.Yes); err != nil {
+		if err := applyMenu(ctx, out, channelLocation, menu, f.VFSContext(), k8sClient, cmClient, dynamicClient, restMapper, options); err != nil {
 			merr = multierr.Append(merr, fmt.Errorf("applying %q: %w", channelLocation, err))
 		}
 	}
 	return merr
 }
 
-func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, dynamicClient dynamic.Interface, restMapper *restmapper.DeferredDiscoveryRESTMapper, apply bool) error {
+func applyMenu(ctx context.Context, out io.Writer, channelLocation string, menu *channels.AddonMenu, vfsContext *vfs.VFSContext, k8sClient kubernetes.Interface, cmClient certmanager.Interface, dynamicClient dynamic.Interface, restMapper *restmapper.DeferredDiscoveryRESTMapper, options *ApplyChannelOptions) error {
 	// channelVersions is the list of installed addons in the cluster.
 	// It is keyed by <namespace>:<addon name>.
 	channelVersions, err := getChannelVersions(ctx, k8sClient)
@@ -229,8 +264,14 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 		return fmt.Errorf("failed to get updates: %w", err)
 	}
 
+	if options.Yes {
+		if err := deleteRemovedAppliedManifests(ctx, k8sClient, channelLocation, menu, channelVersions); err != nil {
+			klog.Warningf("error cleaning up addons removed from %q: %v", channelLocation, err)
+		}
+	}
+
 	if len(updates) == 0 {
-		fmt.Printf("No update required\n")
+		fmt.Fprintf(out, "No update required\n")
 		return nil
 	}
 
@@ -259,17 +300,12 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 		})
 
 		columns := []string{"NAME", "CURRENT", "UPDATE", "PKI"}
-		err := t.Render(updates, os.Stdout, columns...)
+		err := t.Render(updates, out, columns...)
 		if err != nil {
 			return err
 		}
 	}
 
-	if !apply {
-		fmt.Printf("\nMust specify --yes to update\n")
-		return nil
-	}
-
 	pruner := &channels.Pruner{
 		Client:     dynamicClient,
 		RESTMapper: restMapper,
@@ -280,24 +316,121 @@ func applyMenu(ctx context.Context, menu *channels.AddonMenu, vfsContext *vfs.VF
 		RESTMapper: restMapper,
 	}
 
+	if options.DryRun == DryRunServer {
+		return dryRunUpdates(ctx, out, vfsContext, pruner, applier, needUpdates)
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to update\n")
+		return nil
+	}
+
 	var merr error
 
+	// needUpdates is ordered so that dependencies come first; we don't update an addon if a dependency failed
+	failed := make(map[string]bool)
 	for _, needUpdate := range needUpdates {
+		if dependency := failedDependency(needUpdate, failed); dependency != "" {
+			failed[needUpdate.Name] = true
+			merr = multierr.Append(merr, fmt.Errorf("not updating %q, as dependency %q was not updated", needUpdate.Name, dependency))
+			continue
+		}
+		if err := menu.WaitForDependencies(ctx, vfsContext, applier, needUpdate); err != nil {
+			failed[needUpdate.Name] = true
+			merr = multierr.Append(merr, fmt.Errorf("not updating %q: %w", needUpdate.Name, err))
+			continue
+		}
+
 		update, err := needUpdate.EnsureUpdated(ctx, vfsContext, k8sClient, cmClient, pruner, applier, channelVersions[needUpdate.GetNamespace()+":"+needUpdate.Name])
 		if err != nil {
+			failed[needUpdate.Name] = true
 			merr = multierr.Append(merr, fmt.Errorf("updating %q: %w", needUpdate.Name, err))
 		} else if update != nil {
-			fmt.Printf("Updated %q\n", update.Name)
+			fmt.Fprintf(out, "Updated %q\n", update.Name)
 		}
 	}
 
 	return merr
 }
 
+// deleteRemovedAppliedManifests deletes the stored manifests of addons that were installed from the channel,
+// but are no longer in it.
+func deleteRemovedAppliedManifests(ctx context.Context, k8sClient kubernetes.Interface, channelLocation string, menu *channels.AddonMenu, channelVersions map[string]*channels.ChannelVersion) error {
+	var merr error
+	for key, version := range channelVersions {
+		if version.Channel == nil || *version.Channel != channelLocation {
+			continue
+		}
+		namespace, name, _ := strings.Cut(key, ":")
+		if addon := menu.Addons[name]; addon != nil && addon.GetNamespace() == namespace {
+			continue
+		}
+		channel := &channels.Channel{Namespace: namespace, Name: name}
+		if err := channel.DeleteAppliedManifest(ctx, k8sClient); err != nil {
+			merr = multierr.Append(merr, fmt.Errorf("addon %q: %w", name, err))
+		}
+	}
+	return merr
+}
+
+// failedDependency returns the name of a dependency of the addon that failed to update, or "" if there is none.
+func failedDependency(addon *channels.Addon, failed map[string]bool) string {
+	for _, dependency := range addon.Spec.DependsOn {
+		if failed[dependency] {
+			return dependency
+		}
+	}
+	return ""
+}
+
+// dryRunUpdates prints the changes that updating each addon would make to the objects in the cluster.
+func dryRunUpdates(ctx context.Context, out io.Writer, vfsContext *vfs.VFSContext, pruner *channels.Pruner, applier *channels.ClientApplier, needUpdates []*channels.Addon) error {
+	var merr error
+	for _, needUpdate := range needUpdates {
+		if !needUpdate.HasManifest() {
+			// Only PKI needs to be installed
+			continue
+		}
+		result, err := needUpdate.DryRun(ctx, vfsContext, pruner, applier)
+		if err != nil {
+			merr = multierr.Append(merr, fmt.Errorf("dry-run of %q: %w", needUpdate.Name, err))
+			continue
+		}
+
+		fmt.Fprintf(out, "\nAddon %q:\n", result.Name)
+		unchanged := 0
+		for _, object := range result.Objects {
+			switch object.Action {
+			case channels.ObjectActionUnchanged:
+				unchanged++
+			case channels.ObjectActionError:
+				fmt.Fprintf(out, "  %s: unable to dry-run: %v\n", object.Object, object.Error)
+			default:
+				fmt.Fprintf(out, "  %s: %s\n", object.Object, object.Action)
+				for _, line := range strings.Split(strings.TrimRight(object.Diff, "\n"), "\n") {
+					fmt.Fprintf(out, "    %s\n", line)
+				}
+			}
+		}
+		if unchanged != 0 {
+			fmt.Fprintf(out, "  %d objects unchanged\n", unchanged)
+		}
+		for _, pruned := range result.Pruned {
+			fmt.Fprintf(out, "  %s: delete (pruned)\n", pruned)
+		}
+	}
+	return merr
+}
+
 func getUpdates(ctx context.Context, menu *channels.AddonMenu, k8sClient kubernetes.Interface, cmClient certmanager.Interface, channelVersions map[string]*channels.ChannelVersion) ([]*channels.AddonUpdate, []*channels.Addon, error) {
+	addons, err := menu.SortedAddons()
+	if err != nil {
+		return nil, nil, err
+	}
+
 	var updates []*channels.AddonUpdate
 	var needUpdates []*channels.Addon
-	for _, addon := range menu.Addons {
+	for _, addon := range addons {
 		update, err := addon.GetRequiredUpdates(ctx, k8sClient, cmClient, channelVersions[addon.GetNamespace()+":"+addon.Name])
 		if err != nil {
 			return nil, nil, fmt.Errorf("error checking for required update: %v", err)
@@ -18,11 +18,14 @@ package cmd
 
 import (
 	"context"
+	"sort"
+	"strings"
 	"testing"
 
 	cmfake "github.com/cert-manager/cert-manager/pkg/client/clientset/versioned/fake"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	fakek8s "k8s.io/client-go/kubernetes/fake"
 	"k8s.io/kops/channels/pkg/api"
 	"k8s.io/kops/channels/pkg/channels"
@@ -76,3 +79,58 @@ func TestGetUpdates(t *testing.T) {
 		t.Errorf("expected update in kube-system, but update applied to %q", needUpdates[0].GetNamespace())
 	}
 }
+
+func TestDeleteRemovedAppliedManifests(t *testing.T) {
+	// This test checks that the stored manifests of addons removed from the channel are deleted,
+	// and that those of addons still in the channel, or installed from other channels, are kept.
+
+	channelLocation := "s3://mystatestore/cluster.example.com/addons/bootstrap-channel.yaml"
+	kubeSystemNS := corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "kube-system",
+			Annotations: map[string]string{
+				"addons.k8s.io/current": "{\"channel\":\"" + channelLocation + "\",\"manifestHash\":\"abc\",\"systemGeneration\":1}",
+				"addons.k8s.io/removed": "{\"channel\":\"" + channelLocation + "\",\"manifestHash\":\"abc\",\"systemGeneration\":1}",
+				"addons.k8s.io/other":   "{\"channel\":\"s3://mystatestore/other-channel.yaml\",\"manifestHash\":\"abc\",\"systemGeneration\":1}",
+			},
+		},
+	}
+	var objects []runtime.Object
+	objects = append(objects, &kubeSystemNS)
+	for _, name := range []string{"current", "removed", "other"} {
+		objects = append(objects, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "kops-addon-" + name, Namespace: "kube-system"}})
+	}
+	k8sClient := fakek8s.NewClientset(objects...)
+	ctx := context.Background()
+
+	channelVersions, err := getChannelVersions(ctx, k8sClient)
+	if err != nil {
+		t.Fatalf("failed to get channel versions: %v", err)
+	}
+
+	menu := channels.NewAddonMenu()
+	menu.Addons = map[string]*channels.Addon{
+		"current": {
+			Name: "current",
+			Spec: &api.AddonSpec{
+				Name: new("current"),
+			},
+		},
+	}
+	if err := deleteRemovedAppliedManifests(ctx, k8sClient, channelLocation, menu, channelVersions); err != nil {
+		t.Fatalf("failed to delete applied manifests: %v", err)
+	}
+
+	configMaps, err := k8sClient.CoreV1().ConfigMaps("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		t.Fatalf("failed to list configmaps: %v", err)
+	}
+	var names []string
+	for _, cm := range configMaps.Items {
+		names = append(names, cm.Name)
+	}
+	sort.Strings(names)
+	if got := strings.Join(names, ","); got != "kops-addon-current,kops-addon-other" {
+		t.Errorf("expected the removed addon's manifest to be deleted, got configmaps %q", got)
+	}
+}
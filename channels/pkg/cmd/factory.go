@@ -21,6 +21,8 @@ import (
 	"net/http"
 
 	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
@@ -33,6 +35,8 @@ import (
 type ChannelsFactory struct {
 	configFlags      genericclioptions.ConfigFlags
 	cachedRESTConfig *rest.Config
+	// configProvided is true if the RESTConfig was provided, instead of being loaded from the kubeconfig
+	configProvided   bool
 	cachedHTTPClient *http.Client
 	vfsContext       *vfs.VFSContext
 	restMapper       *restmapper.DeferredDiscoveryRESTMapper
@@ -44,6 +48,16 @@ func NewChannelsFactory() *ChannelsFactory {
 	return &ChannelsFactory{}
 }
 
+// NewChannelsFactoryForConfig builds a ChannelsFactory that connects using restConfig,
+// rather than the kubeconfig, and reads channels from vfsContext.
+func NewChannelsFactoryForConfig(restConfig *rest.Config, vfsContext *vfs.VFSContext) *ChannelsFactory {
+	return &ChannelsFactory{
+		cachedRESTConfig: restConfig,
+		configProvided:   true,
+		vfsContext:       vfsContext,
+	}
+}
+
 func (f *ChannelsFactory) RESTConfig() (*rest.Config, error) {
 	if f.cachedRESTConfig == nil {
 		clientGetter := genericclioptions.NewConfigFlags(true)
@@ -78,9 +92,23 @@ func (f *ChannelsFactory) HTTPClient() (*http.Client, error) {
 
 func (f *ChannelsFactory) RESTMapper() (*restmapper.DeferredDiscoveryRESTMapper, error) {
 	if f.restMapper == nil {
-		discoveryClient, err := f.configFlags.ToDiscoveryClient()
-		if err != nil {
-			return nil, err
+		var discoveryClient discovery.CachedDiscoveryInterface
+		if f.configProvided {
+			httpClient, err := f.HTTPClient()
+			if err != nil {
+				return nil, err
+			}
+			client, err := discovery.NewDiscoveryClientForConfigAndClient(f.cachedRESTConfig, httpClient)
+			if err != nil {
+				return nil, fmt.Errorf("building discovery client: %w", err)
+			}
+			discoveryClient = memory.NewMemCacheClient(client)
+		} else {
+			client, err := f.configFlags.ToDiscoveryClient()
+			if err != nil {
+				return nil, err
+			}
+			discoveryClient = client
 		}
 
 		restMapper := restmapper.NewDeferredDiscoveryRESTMapper(discoveryClient)
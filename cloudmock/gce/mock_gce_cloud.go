@@ -20,9 +20,11 @@ import (
 	"fmt"
 
 	"cloud.google.com/go/storage"
+	"google.golang.org/api/cloudkms/v1"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	compute "google.golang.org/api/compute/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/kops/cloudmock/gce/mockcloudkms"
 	"k8s.io/kops/cloudmock/gce/mockcloudresourcemanager"
 	mockcompute "k8s.io/kops/cloudmock/gce/mockcompute"
 	"k8s.io/kops/cloudmock/gce/mockdns"
@@ -47,6 +49,7 @@ type MockGCECloud struct {
 	iamClient                  *mockiam.MockClient
 	storageClient              *storage.Client
 	cloudResourceManagerClient *cloudresourcemanager.Service
+	cloudKMSClient             *cloudkms.Service
 }
 
 var _ gce.GCECloud = &MockGCECloud{}
@@ -61,6 +64,7 @@ func InstallMockGCECloud(region string, project string) *MockGCECloud {
 		iamClient:                  mockiam.NewMockClient(project),
 		storageClient:              mockstorage.New(),
 		cloudResourceManagerClient: mockcloudresourcemanager.New(),
+		cloudKMSClient:             mockcloudkms.New(),
 	}
 	gce.CacheGCECloudInstance(region, project, c)
 	return c
@@ -123,6 +127,11 @@ func (c *MockGCECloud) CloudResourceManager() *cloudresourcemanager.Service {
 	return c.cloudResourceManagerClient
 }
 
+// CloudKMS returns the client for the Cloud KMS API
+func (c *MockGCECloud) CloudKMS() *cloudkms.Service {
+	return c.cloudKMSClient
+}
+
 // CloudDNS returns the DNS client
 func (c *MockGCECloud) CloudDNS() gce.DNSClient {
 	return c.dnsClient
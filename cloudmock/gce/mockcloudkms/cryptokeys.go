@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mockcloudkms
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"sync"
+
+	"google.golang.org/api/cloudkms/v1"
+	"k8s.io/kops/cloudmock/gce/gcphttp"
+)
+
+type cryptoKeys struct {
+	mutex sync.Mutex
+
+	policies map[string]*cloudkms.Policy
+}
+
+func (s *cryptoKeys) Init() {
+	s.policies = make(map[string]*cloudkms.Policy)
+}
+
+func (s *cryptoKeys) getIAMPolicy(name string, request *http.Request) (*http.Response, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	policy := s.policies[name]
+	if policy == nil {
+		policy = &cloudkms.Policy{
+			Etag: nextEtag(""),
+		}
+	}
+
+	return gcphttp.OKResponse(policy)
+}
+
+func nextEtag(etag string) string {
+	hash := sha256.Sum256([]byte(etag))
+	nextEtag := hex.EncodeToString(hash[:])
+	return nextEtag
+}
+
+func (s *cryptoKeys) setIAMPolicy(name string, request *http.Request) (*http.Response, error) {
+	b, err := io.ReadAll(request.Body)
+	if err != nil {
+		return gcphttp.ErrorBadRequest("")
+	}
+
+	req := &cloudkms.SetIamPolicyRequest{}
+	if err := json.Unmarshal(b, &req); err != nil {
+		return gcphttp.ErrorBadRequest("")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	oldPolicy := s.policies[name]
+	if oldPolicy == nil {
+		oldPolicy = &cloudkms.Policy{
+			Etag: nextEtag(""),
+		}
+	}
+
+	newPolicy := req.Policy
+
+	if oldPolicy.Etag != newPolicy.Etag {
+		return gcphttp.ErrorNotFound("etag")
+	}
+
+	newPolicy.Etag = nextEtag(oldPolicy.Etag)
+	s.policies[name] = newPolicy
+
+	return gcphttp.OKResponse(newPolicy)
+}
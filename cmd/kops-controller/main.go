@@ -35,10 +35,12 @@ import (
 	"k8s.io/kops/cmd/kops-controller/pkg/server"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
 	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap/attestverifier"
 	"k8s.io/kops/pkg/bootstrap/awsbootstrap"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap/pkiverifier"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/controllers/clusterapi"
+	"k8s.io/kops/pkg/externalsigner"
 	"k8s.io/kops/pkg/nodeidentity"
 	nodeidentityaws "k8s.io/kops/pkg/nodeidentity/aws"
 	nodeidentityazure "k8s.io/kops/pkg/nodeidentity/azure"
@@ -61,6 +63,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/yaml"
 	// +kubebuilder:scaffold:imports
@@ -84,9 +87,8 @@ func main() {
 	flag.Set("legacy_stderr_threshold_behavior", "false") //nolint:errcheck
 	flag.Set("stderrthreshold", "INFO")                   //nolint:errcheck
 
-	// Disable metrics by default (avoid port conflicts, also risky because we are host network)
-	metricsAddress := ":0"
-	// flag.StringVar(&metricsAddr, "metrics-addr", metricsAddress, "The address the metric endpoint binds to.")
+	// The signing CAs can be held in an HSM or cloud KMS
+	externalsigner.Register()
 
 	configPath := "/etc/kubernetes/kops-controller/config.yaml"
 	flag.StringVar(&configPath, "conf", configPath, "Location of yaml configuration file")
@@ -123,9 +125,16 @@ func main() {
 	kubeConfig.Burst = 300
 	kubeConfig.QPS = 150
 
+	metricsAddress, err := opt.MetricsBindAddress(os.Getenv("HOST_IP"))
+	if err != nil {
+		setupLog.Error(err, "error building metrics address")
+		os.Exit(1)
+	}
+
 	mgr, err := ctrl.NewManager(kubeConfig, ctrl.Options{
 		Scheme: scheme,
 		Metrics: metricsserver.Options{
+			// We are host network, so the default (see config.Options) binds to the loopback interface
 			BindAddress: metricsAddress,
 		},
 		LeaderElection:   true,
@@ -213,6 +222,15 @@ func main() {
 			verifiers = append(verifiers, verifier)
 		}
 
+		if opt.Server.Provider.Attestation != nil {
+			verifier, err := attestverifier.NewVerifier(opt.Server.Provider.Attestation, mgr.GetClient())
+			if err != nil {
+				setupLog.Error(err, "unable to create verifier")
+				os.Exit(1)
+			}
+			verifiers = append(verifiers, verifier)
+		}
+
 		if opt.Server.PKI != nil {
 			verifier, err := pkiverifier.NewVerifier(opt.Server.PKI, mgr.GetClient())
 			if err != nil {
@@ -244,6 +262,11 @@ func main() {
 		}
 		clientset = srv.GetClientset()
 		mgr.Add(srv)
+
+		if err := ctrlmetrics.Registry.Register(srv.CertificateCollector()); err != nil {
+			setupLog.Error(err, "unable to register certificate metrics")
+			os.Exit(1)
+		}
 	}
 
 	if opt.EnableCloudIPAM {
@@ -17,8 +17,15 @@ limitations under the License.
 package config
 
 import (
+	"fmt"
+	"net"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
 	"k8s.io/kops/pkg/bootstrap/awsbootstrap"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi/cloudup/azure"
 	"k8s.io/kops/upup/pkg/fi/cloudup/do"
 	gcetpm "k8s.io/kops/upup/pkg/fi/cloudup/gce/tpm"
@@ -41,9 +48,37 @@ type Options struct {
 
 	// CAPI configures Cluster API (CAPI) support.
 	CAPI *CAPIOptions `json:"capi,omitempty"`
+
+	// MetricsAddress is the address the metrics endpoint binds to, or "0" to disable it.
+	MetricsAddress string `json:"metricsAddress,omitempty"`
+
+	// MetricsOnHostIP binds the metrics endpoint to the IP address of the host, from the HOST_IP env var,
+	// at the port of MetricsAddress, so that the metrics can be scraped from other nodes.
+	MetricsOnHostIP bool `json:"metricsOnHostIP,omitempty"`
+
+	// CertificateExpiryHorizon is how far ahead the metrics flag certificates as expiring.
+	CertificateExpiryHorizon *metav1.Duration `json:"certificateExpiryHorizon,omitempty"`
 }
 
 func (o *Options) PopulateDefaults() {
+	// We run with host networking, so only serve metrics on the loopback interface
+	o.MetricsAddress = fmt.Sprintf("127.0.0.1:%d", wellknownports.KopsControllerMetricsPort)
+	o.CertificateExpiryHorizon = &metav1.Duration{Duration: 30 * 24 * time.Hour}
+}
+
+// MetricsBindAddress returns the address the metrics endpoint binds to, given the IP address of the host.
+func (o *Options) MetricsBindAddress(hostIP string) (string, error) {
+	if !o.MetricsOnHostIP || o.MetricsAddress == "0" {
+		return o.MetricsAddress, nil
+	}
+	if hostIP == "" {
+		return "", fmt.Errorf("metricsOnHostIP is set, but HOST_IP is not")
+	}
+	_, port, err := net.SplitHostPort(o.MetricsAddress)
+	if err != nil {
+		return "", fmt.Errorf("invalid metricsAddress %q: %w", o.MetricsAddress, err)
+	}
+	return net.JoinHostPort(hostIP, port), nil
 }
 
 type CAPIOptions struct {
@@ -80,6 +115,36 @@ type ServerOptions struct {
 	SigningCAs []string `json:"signingCAs"`
 	// CertNames is the list of active certificate names.
 	CertNames []string `json:"certNames"`
+
+	// Bootstrap limits the credentials we issue to bootstrapping nodes.
+	Bootstrap *BootstrapLimitsOptions `json:"bootstrap,omitempty"`
+}
+
+const (
+	// DefaultBootstrapRequestsPerMinute is the default number of bootstrap requests we accept per minute, from all nodes together.
+	DefaultBootstrapRequestsPerMinute = 600
+	// DefaultBootstrapBurst is the default number of bootstrap requests we accept at once, from all nodes together.
+	DefaultBootstrapBurst = 200
+	// DefaultBootstrapNodeRequestsPerMinute is the default number of bootstrap requests we accept per minute for each node name.
+	// nodeup makes two requests when it boots, and retries failed requests.
+	DefaultBootstrapNodeRequestsPerMinute = 6
+	// DefaultBootstrapNodeBurst is the default number of bootstrap requests we accept at once for each node name.
+	DefaultBootstrapNodeBurst = 10
+)
+
+// BootstrapLimitsOptions limits the credentials we issue to bootstrapping nodes.
+// Unset limits take their default; a rate of 0 disables the limit.
+type BootstrapLimitsOptions struct {
+	// RequestsPerMinute is the number of bootstrap requests we accept per minute, from all nodes together.
+	RequestsPerMinute *int32 `json:"requestsPerMinute,omitempty"`
+	// Burst is the number of bootstrap requests we accept at once, from all nodes together.
+	Burst *int32 `json:"burst,omitempty"`
+	// NodeRequestsPerMinute is the number of bootstrap requests we accept per minute for each node name.
+	NodeRequestsPerMinute *int32 `json:"nodeRequestsPerMinute,omitempty"`
+	// NodeBurst is the number of bootstrap requests we accept at once for each node name.
+	NodeBurst *int32 `json:"nodeBurst,omitempty"`
+	// MinReissueInterval, if set, rejects requests for certificates for a node name that we issued certificates to more recently than this.
+	MinReissueInterval *metav1.Duration `json:"minReissueInterval,omitempty"`
 }
 
 type ServerProviderOptions struct {
@@ -91,4 +156,5 @@ type ServerProviderOptions struct {
 	Scaleway     *scaleway.ScalewayVerifierOptions   `json:"scaleway,omitempty"`
 	Azure        *azure.AzureVerifierOptions         `json:"azure,omitempty"`
 	Linode       *linode.LinodeVerifierOptions       `json:"linode,omitempty"`
+	Attestation  *attestbootstrap.Options            `json:"attestation,omitempty"`
 }
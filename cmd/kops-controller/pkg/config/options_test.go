@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+)
+
+func TestMetricsBindAddress(t *testing.T) {
+	grid := []struct {
+		name            string
+		metricsAddress  string
+		metricsOnHostIP bool
+		hostIP          string
+		expected        string
+		expectError     bool
+	}{
+		{name: "loopback", metricsAddress: "127.0.0.1:4007", hostIP: "10.0.1.2", expected: "127.0.0.1:4007"},
+		{name: "host ip", metricsAddress: "127.0.0.1:4007", metricsOnHostIP: true, hostIP: "10.0.1.2", expected: "10.0.1.2:4007"},
+		{name: "ipv6 host ip", metricsAddress: "127.0.0.1:4007", metricsOnHostIP: true, hostIP: "2001:db8::1", expected: "[2001:db8::1]:4007"},
+		{name: "disabled", metricsAddress: "0", metricsOnHostIP: true, hostIP: "10.0.1.2", expected: "0"},
+		{name: "no host ip", metricsAddress: "127.0.0.1:4007", metricsOnHostIP: true, expectError: true},
+		{name: "invalid address", metricsAddress: "4007", metricsOnHostIP: true, hostIP: "10.0.1.2", expectError: true},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			o := &Options{MetricsAddress: g.metricsAddress, MetricsOnHostIP: g.metricsOnHostIP}
+			actual, err := o.MetricsBindAddress(g.hostIP)
+			if g.expectError {
+				if err == nil {
+					t.Errorf("expected an error, got %q", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if actual != g.expected {
+				t.Errorf("expected %q, got %q", g.expected, actual)
+			}
+		})
+	}
+}
@@ -124,6 +124,11 @@ func (c *client) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return newAddonsClient(basePath, cluster)
 }
 
+// ClusterHistoryFor returns the client for the revision history of a particular Cluster
+func (c *client) ClusterHistoryFor(cluster *kops.Cluster) (simple.ClusterHistoryClient, error) {
+	return nil, fmt.Errorf("method ClusterHistoryFor not supported in server-side client")
+}
+
 // SecretStore builds the secret store for the specified cluster
 func (c *client) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	clusterName := cluster.Name
@@ -165,6 +170,6 @@ func (c *client) SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredentialStor
 }
 
 // DeleteCluster deletes all the state for the specified cluster
-func (c *client) DeleteCluster(ctx context.Context, cluster *kops.Cluster) error {
+func (c *client) DeleteCluster(ctx context.Context, cluster *kops.Cluster, options simple.DeleteClusterOptions) error {
 	return fmt.Errorf("method DeleteCluster not supported in server-side client")
 }
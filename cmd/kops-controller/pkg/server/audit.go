@@ -0,0 +1,147 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// auditRecord is the audit log entry of a bootstrap request.
+type auditRecord struct {
+	// Time is when we received the request.
+	Time time.Time `json:"time"`
+	// SourceIP is the address the request came from.
+	SourceIP string `json:"sourceIP,omitempty"`
+	// Verifier is the authentication scheme of the request, which selects the verifier.
+	Verifier string `json:"verifier,omitempty"`
+	// NodeName is the verified name of the node.
+	NodeName string `json:"nodeName,omitempty"`
+	// InstanceGroup is the verified instance group of the node.
+	InstanceGroup string `json:"instanceGroup,omitempty"`
+	// IncludeNodeConfig is true if the node requested its configuration.
+	IncludeNodeConfig bool `json:"includeNodeConfig,omitempty"`
+	// Certificates are the certificates we issued.
+	Certificates []auditCertificate `json:"certificates,omitempty"`
+	// Status is the HTTP status of the response.
+	Status int `json:"status"`
+	// Result is "success", "rejected" or "error".
+	Result string `json:"result"`
+	// Reason is why the request was not successful.
+	Reason string `json:"reason,omitempty"`
+}
+
+// auditCertificate is the audit log entry of an issued certificate.
+type auditCertificate struct {
+	// Name is the name of the certificate requested by the node.
+	Name string `json:"name"`
+	// SerialNumber is the serial number of the certificate.
+	SerialNumber string `json:"serialNumber"`
+	// Subject is the subject of the certificate.
+	Subject string `json:"subject"`
+	// Issuer is the name of the keyset that signed the certificate.
+	Issuer string `json:"issuer"`
+	// IssuerID is the ID of the keypair that signed the certificate.
+	IssuerID string `json:"issuerID,omitempty"`
+	// NotAfter is when the certificate expires.
+	NotAfter time.Time `json:"notAfter"`
+}
+
+// newAuditRecord starts the audit log entry of a request.
+func newAuditRecord(r *http.Request) *auditRecord {
+	record := &auditRecord{
+		Time:     time.Now().UTC(),
+		SourceIP: r.RemoteAddr,
+		Verifier: authenticationScheme(r.Header.Get("Authorization")),
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		record.SourceIP = host
+	}
+	return record
+}
+
+// finish records the outcome of the request.
+func (a *auditRecord) finish(status int) {
+	a.Status = status
+	switch {
+	case status < 300:
+		a.Result = "success"
+	case status < 500:
+		a.Result = "rejected"
+	default:
+		a.Result = "error"
+	}
+}
+
+// authenticationScheme returns the scheme of an Authorization header, e.g. x-aws-sts.
+// We never return the credentials themselves.
+func authenticationScheme(authorization string) string {
+	scheme, _, found := strings.Cut(authorization, " ")
+	if !found || len(scheme) > 64 {
+		return ""
+	}
+	return scheme
+}
+
+// auditLog writes the audit log of bootstrap requests, as one JSON object per line.
+type auditLog struct {
+	mutex sync.Mutex
+	out   io.Writer
+}
+
+func (l *auditLog) write(record *auditRecord) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		klog.Warningf("failed to serialize audit record: %v", err)
+		return
+	}
+	b = append(b, '\n')
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if _, err := l.out.Write(b); err != nil {
+		klog.Warningf("failed to write audit record: %v", err)
+	}
+}
+
+// statusRecorder records the status of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthenticationScheme(t *testing.T) {
+	grid := []struct {
+		Authorization string
+		Expected      string
+	}{
+		{Authorization: "x-aws-sts c2VjcmV0", Expected: "x-aws-sts"},
+		{Authorization: "x-kops-attest eyJkYXRhIjoi", Expected: "x-kops-attest"},
+		{Authorization: "c2VjcmV0", Expected: ""},
+		{Authorization: "", Expected: ""},
+	}
+	for _, g := range grid {
+		t.Run(g.Expected, func(t *testing.T) {
+			assert.Equal(t, g.Expected, authenticationScheme(g.Authorization))
+		})
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/bootstrap", nil)
+	r.RemoteAddr = "192.0.2.10:41234"
+	r.Header.Set("Authorization", "x-pki-tpm secret")
+
+	record := newAuditRecord(r)
+	record.NodeName = "node-a"
+	record.InstanceGroup = "nodes"
+	record.Certificates = []auditCertificate{{Name: "kubelet", SerialNumber: "1234", Issuer: "kubernetes-ca", IssuerID: "5678"}}
+	record.finish(http.StatusOK)
+
+	var out bytes.Buffer
+	l := &auditLog{out: &out}
+	l.write(record)
+
+	rejected := newAuditRecord(r)
+	rejected.Reason = "node already registered"
+	rejected.finish(http.StatusConflict)
+	l.write(rejected)
+
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.NotContains(t, out.String(), "secret")
+
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(lines[0], &got))
+	assert.Equal(t, "192.0.2.10", got["sourceIP"])
+	assert.Equal(t, "x-pki-tpm", got["verifier"])
+	assert.Equal(t, "node-a", got["nodeName"])
+	assert.Equal(t, "nodes", got["instanceGroup"])
+	assert.Equal(t, "success", got["result"])
+	assert.Equal(t, float64(http.StatusOK), got["status"])
+	assert.Len(t, got["certificates"], 1)
+
+	require.NoError(t, json.Unmarshal(lines[1], &got))
+	assert.Equal(t, "rejected", got["result"])
+	assert.Equal(t, "node already registered", got["reason"])
+}
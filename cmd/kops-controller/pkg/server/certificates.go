@@ -0,0 +1,182 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/certinventory"
+)
+
+var (
+	certificateExpiryDesc = prometheus.NewDesc(
+		"kops_controller_certificate_expiration_timestamp_seconds",
+		"Time when a certificate expires, in seconds since the Unix epoch. Estimated certificates expire at or after the reported time.",
+		[]string{"source", "name", "id", "node", "subject", "issuer", "issuer_id", "estimated"},
+		nil,
+	)
+	certificatesExpiringDesc = prometheus.NewDesc(
+		"kops_controller_certificates_expiring",
+		"Number of certificates that have expired or expire within the configured horizon.",
+		[]string{"horizon"},
+		nil,
+	)
+)
+
+// inventoryCacheDuration is how long we reuse the certificate inventory between scrapes;
+// certificates change only when nodes join, so there is no need to list the nodes on every scrape.
+const inventoryCacheDuration = 5 * time.Minute
+
+// issuedCertificates records the certificates issued to bootstrapping nodes.
+// It holds the exact expiry of the certificates issued by this kops-controller; certificates issued before it started
+// are estimated from when the node registered.
+type issuedCertificates struct {
+	mutex        sync.Mutex
+	certificates map[string]*certinventory.Certificate
+}
+
+func (i *issuedCertificates) record(c *certinventory.Certificate) {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	if i.certificates == nil {
+		i.certificates = make(map[string]*certinventory.Certificate)
+	}
+	i.certificates[c.Key()] = c
+}
+
+// list returns the recorded certificates of the given nodes, forgetting those of nodes that no longer exist.
+func (i *issuedCertificates) list(nodes map[string]bool) []*certinventory.Certificate {
+	i.mutex.Lock()
+	defer i.mutex.Unlock()
+
+	var certificates []*certinventory.Certificate
+	for key, c := range i.certificates {
+		if !nodes[c.Node] {
+			delete(i.certificates, key)
+			continue
+		}
+		certificates = append(certificates, c)
+	}
+	return certificates
+}
+
+// certificateCollector exposes the certificate inventory as Prometheus metrics.
+type certificateCollector struct {
+	server  *Server
+	horizon time.Duration
+
+	mutex     sync.Mutex
+	cached    []*certinventory.Certificate
+	cachedAt  time.Time
+	cacheTime time.Duration
+}
+
+var _ prometheus.Collector = &certificateCollector{}
+
+// CertificateCollector returns a collector for the expiry of the certificates kops-controller knows about:
+// its signing CAs, and the certificates issued to the nodes.
+func (s *Server) CertificateCollector() prometheus.Collector {
+	var horizon time.Duration
+	if s.opt.CertificateExpiryHorizon != nil {
+		horizon = s.opt.CertificateExpiryHorizon.Duration
+	}
+	return &certificateCollector{
+		server:    s,
+		horizon:   horizon,
+		cacheTime: inventoryCacheDuration,
+	}
+}
+
+func (c *certificateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- certificateExpiryDesc
+	ch <- certificatesExpiringDesc
+}
+
+func (c *certificateCollector) Collect(ch chan<- prometheus.Metric) {
+	certificates, err := c.inventory()
+	if err != nil {
+		klog.Warningf("building certificate inventory: %v", err)
+		ch <- prometheus.NewInvalidMetric(certificateExpiryDesc, err)
+		return
+	}
+
+	for _, cert := range certificates {
+		ch <- prometheus.MustNewConstMetric(certificateExpiryDesc, prometheus.GaugeValue, float64(cert.NotAfter.Unix()),
+			string(cert.Source), cert.Name, cert.ID, cert.Node, cert.Subject, cert.Issuer, cert.IssuerID, strconv.FormatBool(cert.Estimated))
+	}
+
+	expiring := certinventory.Expiring(certificates, time.Now(), c.horizon)
+	ch <- prometheus.MustNewConstMetric(certificatesExpiringDesc, prometheus.GaugeValue, float64(len(expiring)), c.horizon.String())
+}
+
+func (c *certificateCollector) inventory() ([]*certinventory.Certificate, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.cached != nil && time.Since(c.cachedAt) < c.cacheTime {
+		return c.cached, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	certificates, err := c.server.certificateInventory(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.cached = certificates
+	c.cachedAt = time.Now()
+	return certificates, nil
+}
+
+// certificateInventory lists the certificates kops-controller knows about.
+func (s *Server) certificateInventory(ctx context.Context) ([]*certinventory.Certificate, error) {
+	keysets, err := s.keystore.ListKeysets()
+	if err != nil {
+		return nil, err
+	}
+	certificates := certinventory.FromKeysets(keysets)
+
+	nodes := &corev1.NodeList{}
+	if err := s.uncachedClient.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	nodeNames := make(map[string]bool)
+	for _, node := range nodes.Items {
+		nodeNames[node.Name] = true
+	}
+
+	nodeCertificates := certinventory.FromNodes(nodes.Items, certinventory.NodeOptions{
+		BootstrapCertNames:  s.opt.Server.CertNames,
+		BootstrapCertSigner: model.KopsControllerCertSigner,
+		Primaries:           s.keypairIDs,
+	})
+	nodeCertificates = certinventory.Merge(nodeCertificates, s.issued.list(nodeNames))
+
+	certificates = append(certificates, nodeCertificates...)
+	certinventory.Sort(certificates)
+	return certificates, nil
+}
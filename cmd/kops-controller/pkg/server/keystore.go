@@ -19,6 +19,7 @@ package server
 import (
 	"context"
 	"fmt"
+	"maps"
 	"os"
 	"path"
 
@@ -71,8 +72,11 @@ func (k *keystore) MirrorTo(ctx context.Context, basedir vfs.Path) error {
 }
 
 // ListKeysets will return all the KeySets.
+// The server-side keystore only holds the keysets of the signing CAs.
 func (k *keystore) ListKeysets() (map[string]*fi.Keyset, error) {
-	return nil, fmt.Errorf("server-side client does not support ListKeysets")
+	keysets := make(map[string]*fi.Keyset, len(k.keySets))
+	maps.Copy(keysets, k.keySets)
+	return keysets, nil
 }
 
 func newKeystore(basePath string, cas []string) (*keystore, map[string]string, error) {
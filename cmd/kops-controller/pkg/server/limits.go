@@ -0,0 +1,151 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+)
+
+// pruneInterval is how often we forget the state of nodes whose limits have been replenished.
+const pruneInterval = 10 * time.Minute
+
+// bootstrapLimiter limits how quickly we issue credentials to bootstrapping nodes,
+// containing the damage a compromised verifier can do.
+// Its state is held in memory, so each kops-controller replica applies the limits independently.
+// The minimum reissue interval is enforced separately, by reissueTracker, across all replicas.
+type bootstrapLimiter struct {
+	mutex sync.Mutex
+
+	global    *rate.Limiter
+	nodeLimit rate.Limit
+	nodeBurst int
+
+	nodes     map[string]*nodeLimits
+	lastPrune time.Time
+}
+
+// nodeLimits is the limiter state of a node name.
+type nodeLimits struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimitError describes the limit a bootstrap request exceeded.
+type rateLimitError struct {
+	reason     string
+	retryAfter time.Duration
+}
+
+func (e *rateLimitError) Error() string {
+	return fmt.Sprintf("%s; retry after %v", e.reason, e.retryAfter)
+}
+
+func newBootstrapLimiter(opt *config.BootstrapLimitsOptions) *bootstrapLimiter {
+	if opt == nil {
+		opt = &config.BootstrapLimitsOptions{}
+	}
+
+	l := &bootstrapLimiter{
+		global:    rate.NewLimiter(perMinute(opt.RequestsPerMinute, config.DefaultBootstrapRequestsPerMinute), burst(opt.Burst, config.DefaultBootstrapBurst)),
+		nodeLimit: perMinute(opt.NodeRequestsPerMinute, config.DefaultBootstrapNodeRequestsPerMinute),
+		nodeBurst: burst(opt.NodeBurst, config.DefaultBootstrapNodeBurst),
+		nodes:     make(map[string]*nodeLimits),
+	}
+	return l
+}
+
+// perMinute returns the rate.Limit for a number of events per minute, where 0 means unlimited.
+func perMinute(n *int32, defaultValue int32) rate.Limit {
+	v := defaultValue
+	if n != nil {
+		v = *n
+	}
+	if v <= 0 {
+		return rate.Inf
+	}
+	return rate.Limit(float64(v) / 60)
+}
+
+func burst(n *int32, defaultValue int32) int {
+	v := defaultValue
+	if n != nil {
+		v = *n
+	}
+	return max(int(v), 1)
+}
+
+// allow checks whether we accept a bootstrap request from the node at the given time,
+// returning the limit it exceeds if not.
+func (l *bootstrapLimiter) allow(now time.Time, nodeName string) *rateLimitError {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.prune(now)
+
+	node := l.node(nodeName)
+	node.lastSeen = now
+
+	nodeReservation := node.limiter.ReserveN(now, 1)
+	if wait := nodeReservation.DelayFrom(now); wait > 0 {
+		nodeReservation.CancelAt(now)
+		return &rateLimitError{reason: fmt.Sprintf("node %q exceeded its request rate", nodeName), retryAfter: wait}
+	}
+
+	globalReservation := l.global.ReserveN(now, 1)
+	if wait := globalReservation.DelayFrom(now); wait > 0 {
+		globalReservation.CancelAt(now)
+		nodeReservation.CancelAt(now)
+		return &rateLimitError{reason: "exceeded the request rate for all nodes", retryAfter: wait}
+	}
+
+	return nil
+}
+
+// node returns the state of the node, creating it if needed.
+func (l *bootstrapLimiter) node(nodeName string) *nodeLimits {
+	node := l.nodes[nodeName]
+	if node == nil {
+		node = &nodeLimits{
+			limiter: rate.NewLimiter(l.nodeLimit, l.nodeBurst),
+		}
+		l.nodes[nodeName] = node
+	}
+	return node
+}
+
+// prune forgets the nodes whose limiter is replenished.
+func (l *bootstrapLimiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < pruneInterval {
+		return
+	}
+	l.lastPrune = now
+
+	var replenish time.Duration
+	if l.nodeLimit != rate.Inf {
+		replenish = time.Duration(float64(l.nodeBurst) / float64(l.nodeLimit) * float64(time.Second))
+	}
+	for name, node := range l.nodes {
+		if now.Sub(node.lastSeen) >= replenish {
+			delete(l.nodes, name)
+		}
+	}
+}
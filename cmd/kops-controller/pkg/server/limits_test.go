@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"k8s.io/kops/cmd/kops-controller/pkg/config"
+	"k8s.io/utils/ptr"
+)
+
+func TestBootstrapLimiterNode(t *testing.T) {
+	l := newBootstrapLimiter(&config.BootstrapLimitsOptions{
+		NodeRequestsPerMinute: ptr.To(int32(6)),
+		NodeBurst:             ptr.To(int32(2)),
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, l.allow(now, "node-a"))
+	assert.Nil(t, l.allow(now, "node-a"))
+	limited := l.allow(now, "node-a")
+	if assert.NotNil(t, limited) {
+		assert.Equal(t, 10*time.Second, limited.retryAfter)
+	}
+
+	// Other nodes have their own limit.
+	assert.Nil(t, l.allow(now, "node-b"))
+
+	// A denied request consumes nothing, so the node's limit replenishes at the configured rate.
+	assert.Nil(t, l.allow(now.Add(10*time.Second), "node-a"))
+	assert.NotNil(t, l.allow(now.Add(10*time.Second), "node-a"))
+}
+
+func TestBootstrapLimiterGlobal(t *testing.T) {
+	l := newBootstrapLimiter(&config.BootstrapLimitsOptions{
+		RequestsPerMinute: ptr.To(int32(60)),
+		Burst:             ptr.To(int32(2)),
+		NodeBurst:         ptr.To(int32(1)),
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, l.allow(now, "node-a"))
+	assert.Nil(t, l.allow(now, "node-b"))
+	limited := l.allow(now, "node-c")
+	if assert.NotNil(t, limited) {
+		assert.Equal(t, time.Second, limited.retryAfter)
+	}
+
+	// Exceeding the global limit must not consume the node's limit.
+	assert.Nil(t, l.allow(now.Add(time.Second), "node-c"))
+}
+
+func TestBootstrapLimiterUnlimited(t *testing.T) {
+	l := newBootstrapLimiter(&config.BootstrapLimitsOptions{
+		RequestsPerMinute:     ptr.To(int32(0)),
+		NodeRequestsPerMinute: ptr.To(int32(0)),
+	})
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for range 1000 {
+		assert.Nil(t, l.allow(now, "node-a"))
+	}
+}
+
+func TestBootstrapLimiterPrune(t *testing.T) {
+	l := newBootstrapLimiter(nil)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Nil(t, l.allow(now, "node-a"))
+	assert.Nil(t, l.allow(now.Add(pruneInterval), "node-b"))
+	assert.NotContains(t, l.nodes, "node-a")
+	assert.Contains(t, l.nodes, "node-b")
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// reissueConfigMapNamespace and reissueConfigMapName identify the ConfigMap in which we record
+	// when we last issued certificates to each node name.
+	reissueConfigMapNamespace = "kube-system"
+	reissueConfigMapName      = "kops-controller-bootstrap"
+
+	// reissueClaimAttempts is how many times we try to record a claim when other replicas are updating the record concurrently.
+	reissueClaimAttempts = 5
+)
+
+// reissueTracker enforces the minimum interval between issuing certificates to a node name.
+// The times are recorded in a ConfigMap, so that the interval holds across kops-controller replicas and restarts.
+type reissueTracker struct {
+	client   client.Client
+	interval time.Duration
+}
+
+// claim records that we are issuing certificates to the node at the given time,
+// returning the limit it exceeds if we issued certificates to it within the interval.
+// If issuing the certificates fails, the caller must call the returned function to withdraw the claim,
+// so that the node can retry.
+func (t *reissueTracker) claim(ctx context.Context, nodeName string, now time.Time) (func(context.Context), *rateLimitError, error) {
+	key := types.NamespacedName{Namespace: reissueConfigMapNamespace, Name: reissueConfigMapName}
+	issuedAt := now.UTC().Format(time.RFC3339)
+
+	for attempt := 0; attempt < reissueClaimAttempts; attempt++ {
+		cm := &corev1.ConfigMap{}
+		if err := t.client.Get(ctx, key, cm); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, nil, fmt.Errorf("reading %v: %w", key, err)
+			}
+			cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name}}
+		}
+
+		previous, found := cm.Data[nodeName]
+		if found {
+			if lastIssued, err := time.Parse(time.RFC3339, previous); err == nil {
+				if wait := lastIssued.Add(t.interval).Sub(now); wait > 0 {
+					return nil, &rateLimitError{reason: fmt.Sprintf("node %q was issued certificates within %v", nodeName, t.interval), retryAfter: wait}, nil
+				}
+			}
+		}
+
+		// Forget the nodes whose interval has passed, so that the record holds only the recently bootstrapped nodes.
+		data := map[string]string{nodeName: issuedAt}
+		for name, value := range cm.Data {
+			lastIssued, err := time.Parse(time.RFC3339, value)
+			if name != nodeName && err == nil && now.Sub(lastIssued) < t.interval {
+				data[name] = value
+			}
+		}
+		cm.Data = data
+
+		var err error
+		if cm.ResourceVersion == "" {
+			err = t.client.Create(ctx, cm)
+		} else {
+			err = t.client.Update(ctx, cm)
+		}
+		if apierrors.IsConflict(err) || apierrors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("recording certificate issue for %q in %v: %w", nodeName, key, err)
+		}
+
+		release := func(ctx context.Context) {
+			if err := t.release(ctx, key, nodeName, issuedAt, previous, found); err != nil {
+				klog.Warningf("failed to withdraw certificate issue for %q from %v: %v", nodeName, key, err)
+			}
+		}
+		return release, nil, nil
+	}
+	return nil, nil, fmt.Errorf("recording certificate issue for %q in %v: too many concurrent updates", nodeName, key)
+}
+
+// release withdraws a claim, restoring the previous record for the node unless it has been claimed again since.
+func (t *reissueTracker) release(ctx context.Context, key types.NamespacedName, nodeName string, issuedAt string, previous string, found bool) error {
+	for attempt := 0; attempt < reissueClaimAttempts; attempt++ {
+		cm := &corev1.ConfigMap{}
+		if err := t.client.Get(ctx, key, cm); err != nil {
+			return err
+		}
+		if cm.Data[nodeName] != issuedAt {
+			return nil
+		}
+		if found {
+			cm.Data[nodeName] = previous
+		} else {
+			delete(cm.Data, nodeName)
+		}
+		err := t.client.Update(ctx, cm)
+		if !apierrors.IsConflict(err) {
+			return err
+		}
+	}
+	return fmt.Errorf("too many concurrent updates")
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package server
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// fakeConfigMapClient stores a single ConfigMap, with optimistic concurrency.
+type fakeConfigMapClient struct {
+	client.Client
+	configMap *corev1.ConfigMap
+}
+
+var configMapsResource = schema.GroupResource{Resource: "configmaps"}
+
+func (c *fakeConfigMapClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if c.configMap == nil {
+		return apierrors.NewNotFound(configMapsResource, key.Name)
+	}
+	c.configMap.DeepCopyInto(obj.(*corev1.ConfigMap))
+	return nil
+}
+
+func (c *fakeConfigMapClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if c.configMap != nil {
+		return apierrors.NewAlreadyExists(configMapsResource, obj.GetName())
+	}
+	obj.SetResourceVersion("1")
+	c.configMap = obj.(*corev1.ConfigMap).DeepCopy()
+	return nil
+}
+
+func (c *fakeConfigMapClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if c.configMap == nil || obj.GetResourceVersion() != c.configMap.ResourceVersion {
+		return apierrors.NewConflict(configMapsResource, obj.GetName(), nil)
+	}
+	version, _ := strconv.Atoi(obj.GetResourceVersion())
+	obj.SetResourceVersion(strconv.Itoa(version + 1))
+	c.configMap = obj.(*corev1.ConfigMap).DeepCopy()
+	return nil
+}
+
+func TestReissueTracker(t *testing.T) {
+	ctx := context.Background()
+	kube := &fakeConfigMapClient{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Each replica has its own tracker, sharing the ConfigMap.
+	replica1 := &reissueTracker{client: kube, interval: 15 * time.Minute}
+	replica2 := &reissueTracker{client: kube, interval: 15 * time.Minute}
+
+	_, limited, err := replica1.claim(ctx, "node-a", now)
+	require.NoError(t, err)
+	assert.Nil(t, limited)
+
+	_, limited, err = replica2.claim(ctx, "node-a", now.Add(time.Minute))
+	require.NoError(t, err)
+	if assert.NotNil(t, limited) {
+		assert.Equal(t, 14*time.Minute, limited.retryAfter)
+	}
+
+	_, limited, err = replica2.claim(ctx, "node-b", now.Add(time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, limited)
+
+	// Once the interval has passed, the node can be issued certificates again, and expired records are pruned.
+	_, limited, err = replica2.claim(ctx, "node-a", now.Add(15*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, limited)
+	assert.Contains(t, kube.configMap.Data, "node-b")
+
+	_, limited, err = replica1.claim(ctx, "node-c", now.Add(20*time.Minute))
+	require.NoError(t, err)
+	assert.Nil(t, limited)
+	assert.NotContains(t, kube.configMap.Data, "node-b")
+}
+
+func TestReissueTrackerRelease(t *testing.T) {
+	ctx := context.Background()
+	kube := &fakeConfigMapClient{}
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracker := &reissueTracker{client: kube, interval: 15 * time.Minute}
+
+	// A node whose request failed can retry immediately.
+	release, limited, err := tracker.claim(ctx, "node-a", now)
+	require.NoError(t, err)
+	require.Nil(t, limited)
+	release(ctx)
+	assert.NotContains(t, kube.configMap.Data, "node-a")
+
+	release, limited, err = tracker.claim(ctx, "node-a", now.Add(time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, limited)
+
+	// A failed request after the interval restores the previous record.
+	release2, limited, err := tracker.claim(ctx, "node-a", now.Add(20*time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, limited)
+	release2(ctx)
+	assert.Equal(t, "2026-01-01T00:01:00Z", kube.configMap.Data["node-a"])
+
+	// Releasing a claim that has since been superseded does nothing.
+	_, limited, err = tracker.claim(ctx, "node-a", now.Add(30*time.Minute))
+	require.NoError(t, err)
+	require.Nil(t, limited)
+	release(ctx)
+	assert.Equal(t, "2026-01-01T00:30:00Z", kube.configMap.Data["node-a"])
+}
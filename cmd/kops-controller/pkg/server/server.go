@@ -24,10 +24,12 @@ import (
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
-	"hash/fnv"
 	"io"
+	"math"
 	"net/http"
+	"os"
 	"runtime/debug"
+	"strconv"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -41,6 +43,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/certinventory"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/pkg/rbac"
@@ -70,6 +73,18 @@ type Server struct {
 
 	// challengeClient performs our callback-challenge into the node
 	challengeClient *bootstrap.ChallengeClient
+
+	// issued records the certificates issued to bootstrapping nodes
+	issued issuedCertificates
+
+	// limiter limits how quickly we issue credentials to bootstrapping nodes
+	limiter *bootstrapLimiter
+
+	// reissue, if set, enforces the minimum interval between issuing certificates to a node
+	reissue *reissueTracker
+
+	// audit is the audit log of bootstrap requests
+	audit *auditLog
 }
 
 var _ manager.LeaderElectionRunnable = &Server{}
@@ -88,6 +103,14 @@ func NewServer(vfsContext *vfs.VFSContext, opt *config.Options, verifier bootstr
 		server:         server,
 		verifier:       verifier,
 		uncachedClient: uncachedClient,
+		limiter:        newBootstrapLimiter(opt.Server.Bootstrap),
+		audit:          &auditLog{out: os.Stdout},
+	}
+	if opt.Server.Bootstrap != nil && opt.Server.Bootstrap.MinReissueInterval != nil && opt.Server.Bootstrap.MinReissueInterval.Duration > 0 {
+		s.reissue = &reissueTracker{
+			client:   uncachedClient,
+			interval: opt.Server.Bootstrap.MinReissueInterval.Duration,
+		}
 	}
 
 	configBase, err := vfsContext.BuildVfsPath(opt.ConfigBase)
@@ -161,8 +184,21 @@ func healthCheck(w http.ResponseWriter, r *http.Request) {
 }
 
 func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
+	audit := newAuditRecord(r)
+	recorder := &statusRecorder{ResponseWriter: w}
+	w = recorder
+	defer func() {
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		audit.finish(status)
+		s.audit.write(audit)
+	}()
+
 	if r.Body == nil {
 		klog.Infof("bootstrap %s no body", r.RemoteAddr)
+		audit.Reason = "no body"
 		w.WriteHeader(http.StatusBadRequest)
 		return
 	}
@@ -170,6 +206,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		klog.Infof("bootstrap %s read err: %v", r.RemoteAddr, err)
+		audit.Reason = fmt.Sprintf("reading body: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = fmt.Fprintf(w, "bootstrap %s failed to read body: %v", r.RemoteAddr, err)
 		return
@@ -180,6 +217,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	id, err := s.verifier.VerifyToken(ctx, r, r.Header.Get("Authorization"), body)
 	if err != nil {
 		// means that we should exit nodeup gracefully
+		audit.Reason = fmt.Sprintf("verifying token: %v", err)
 		if err == bootstrap.ErrAlreadyExists {
 			w.WriteHeader(http.StatusConflict)
 			klog.Infof("%s: %v", r.RemoteAddr, err)
@@ -191,6 +229,8 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("failed to verify token"))
 		return
 	}
+	audit.NodeName = id.NodeName
+	audit.InstanceGroup = id.InstanceGroupName
 
 	// Once the node is registered, we don't allow further registrations, this protects against a pod or escaped workload attempting to impersonate the node.
 	{
@@ -200,6 +240,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 			for _, condition := range node.Status.Conditions {
 				if condition.Type == corev1.NodeReady && condition.Status == corev1.ConditionTrue {
 					klog.Infof("bootstrap %s node %q already exists; denying to avoid node-impersonation attacks", r.RemoteAddr, id.NodeName)
+					audit.Reason = "node already registered"
 					w.WriteHeader(http.StatusConflict)
 					_, _ = w.Write([]byte("node already registered"))
 					return
@@ -208,6 +249,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		}
 		if err != nil && !errors.IsNotFound(err) {
 			klog.Infof("bootstrap %s error querying for node %q: %v", r.RemoteAddr, id.NodeName, err)
+			audit.Reason = fmt.Sprintf("querying for node: %v", err)
 			w.WriteHeader(http.StatusInternalServerError)
 			_, _ = w.Write([]byte("internal error"))
 			return
@@ -217,6 +259,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	req := &nodeup.BootstrapRequest{}
 	if err := json.Unmarshal(body, req); err != nil {
 		klog.Infof("bootstrap %s decode err: %v", r.RemoteAddr, err)
+		audit.Reason = fmt.Sprintf("decoding request: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = fmt.Fprintf(w, "failed to decode: %v", err)
 		return
@@ -224,20 +267,24 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 
 	if req.APIVersion != nodeup.BootstrapAPIVersion {
 		klog.Infof("bootstrap %s wrong APIVersion", r.RemoteAddr)
+		audit.Reason = fmt.Sprintf("unexpected APIVersion %q", req.APIVersion)
 		w.WriteHeader(http.StatusBadRequest)
 		_, _ = w.Write([]byte("unexpected APIVersion"))
 		return
 	}
+	audit.IncludeNodeConfig = req.IncludeNodeConfig
 
 	if model.UseChallengeCallback(kops.CloudProviderID(s.opt.Cloud)) {
 		if id.ChallengeEndpoint == "" {
 			klog.Infof("cannot determine endpoint for bootstrap callback challenge from %q", r.RemoteAddr)
+			audit.Reason = "cannot determine endpoint for callback challenge"
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte("callback failed"))
 			return
 		}
 		if err := s.challengeClient.DoCallbackChallenge(ctx, s.opt.ClusterName, id.ChallengeEndpoint, req); err != nil {
 			klog.Infof("bootstrap %s callback challenge failed: %v", r.RemoteAddr, err)
+			audit.Reason = fmt.Sprintf("callback challenge: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte("callback failed"))
 			return
@@ -246,6 +293,13 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		klog.Infof("performed successful callback challenge with %s; identified as %s", id.ChallengeEndpoint, id.NodeName)
 	}
 
+	// We check the limits after verifying the request, so that they apply to the verified node name
+	// and unauthenticated requests cannot exhaust them.
+	if limited := s.limiter.allow(time.Now(), id.NodeName); limited != nil {
+		s.rateLimited(w, r, audit, limited)
+		return
+	}
+
 	resp := &nodeup.BootstrapResponse{
 		Certs: map[string]string{},
 	}
@@ -255,6 +309,7 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 		nodeConfig, err := s.getNodeConfig(r.Context(), req, id)
 		if err != nil {
 			klog.Infof("bootstrap failed to build node config: %v", err)
+			audit.Reason = fmt.Sprintf("building node config: %v", err)
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = w.Write([]byte("failed to build node config"))
 			return
@@ -267,14 +322,38 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	// expire at different times, but all certificates on a given node expire around the same time.
 	// We salt on the verified NodeName so nodes sharing a NAT/load balancer (same RemoteAddr) still
 	// get independent skews.
-	hash := fnv.New32()
-	_, _ = hash.Write([]byte(id.NodeName))
-	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
+	validity := certinventory.BootstrapValidity(id.NodeName)
+
+	if s.reissue != nil && len(req.Certs) > 0 {
+		release, limited, err := s.reissue.claim(ctx, id.NodeName, time.Now())
+		if err != nil {
+			klog.Infof("bootstrap %s failed to check reissue interval: %v", r.RemoteAddr, err)
+			audit.Reason = fmt.Sprintf("checking reissue interval: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte("internal error"))
+			return
+		}
+		if limited != nil {
+			s.rateLimited(w, r, audit, limited)
+			return
+		}
+		// We only count the request against the interval once all its certificates have been issued,
+		// so that a node whose request failed can retry.
+		defer func() {
+			if len(resp.Certs) != len(req.Certs) {
+				release(context.WithoutCancel(ctx))
+			}
+		}()
+	}
 
 	for name, pubKey := range req.Certs {
-		cert, err := s.issueCert(ctx, name, pubKey, id, validHours, req.KeypairIDs)
+		cert, issued, err := s.issueCert(ctx, name, pubKey, id, validity, req.KeypairIDs)
+		if issued != nil {
+			audit.Certificates = append(audit.Certificates, *issued)
+		}
 		if err != nil {
 			klog.Infof("bootstrap %s cert %q issue err: %v", r.RemoteAddr, name, err)
+			audit.Reason = fmt.Sprintf("issuing %q: %v", name, err)
 			w.WriteHeader(http.StatusBadRequest)
 			_, _ = fmt.Fprintf(w, "failed to issue %q: %v", name, err)
 			return
@@ -287,32 +366,41 @@ func (s *Server) bootstrap(w http.ResponseWriter, r *http.Request) {
 	klog.Infof("bootstrap %s (req.includeNodeConfig: %t, req.certs.#: %d, req.keypairs.#: %d) success", r.RemoteAddr, req.IncludeNodeConfig, len(req.Certs), len(req.KeypairIDs))
 }
 
-func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *bootstrap.VerifyResult, validHours uint32, keypairIDs map[string]string) (string, error) {
+// rateLimited rejects a bootstrap request that exceeded a limit, telling the node when to retry.
+func (s *Server) rateLimited(w http.ResponseWriter, r *http.Request, audit *auditRecord, limited *rateLimitError) {
+	klog.Infof("bootstrap %s rate limited: %v", r.RemoteAddr, limited)
+	audit.Reason = limited.reason
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.retryAfter.Seconds()))))
+	w.WriteHeader(http.StatusTooManyRequests)
+	_, _ = w.Write([]byte("rate limited"))
+}
+
+// issueCert issues a certificate to a bootstrapping node, returning it in PEM form along with its audit log entry.
+func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *bootstrap.VerifyResult, validity time.Duration, keypairIDs map[string]string) (string, *auditCertificate, error) {
 	block, _ := pem.Decode([]byte(pubKey))
 	if block == nil {
-		return "", fmt.Errorf("decoding pem public key")
+		return "", nil, fmt.Errorf("decoding pem public key")
 	}
 	if block.Type != "RSA PUBLIC KEY" {
-		return "", fmt.Errorf("unexpected key type %q", block.Type)
+		return "", nil, fmt.Errorf("unexpected key type %q", block.Type)
 	}
 	key, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
-		return "", fmt.Errorf("parsing key: %v", err)
+		return "", nil, fmt.Errorf("parsing key: %v", err)
 	}
 
 	issueReq := &pki.IssueCertRequest{
-		Signer:    fi.CertificateIDCA,
+		Signer:    model.KopsControllerCertSigner(name),
 		Type:      "client",
 		PublicKey: key,
-		Validity:  time.Hour * time.Duration(validHours),
+		Validity:  validity,
 	}
 
 	if !s.certNames.Has(name) {
-		return "", fmt.Errorf("key name not enabled")
+		return "", nil, fmt.Errorf("key name not enabled")
 	}
 	switch name {
 	case "etcd-client-cilium":
-		issueReq.Signer = "etcd-clients-ca-cilium"
 		issueReq.Subject = pkix.Name{
 			CommonName: "cilium",
 		}
@@ -336,22 +424,32 @@ func (s *Server) issueCert(ctx context.Context, name string, pubKey string, id *
 			CommonName: rbac.KubeRouter,
 		}
 	default:
-		return "", fmt.Errorf("unexpected key name")
+		return "", nil, fmt.Errorf("unexpected key name")
 	}
 
 	// This field was added to the protocol in kOps 1.22.
 	if len(keypairIDs) > 0 {
 		if keypairIDs[issueReq.Signer] != s.keypairIDs[issueReq.Signer] {
-			return "", fmt.Errorf("request's keypair ID %q for %s didn't match server's %q", keypairIDs[issueReq.Signer], issueReq.Signer, s.keypairIDs[issueReq.Signer])
+			return "", nil, fmt.Errorf("request's keypair ID %q for %s didn't match server's %q", keypairIDs[issueReq.Signer], issueReq.Signer, s.keypairIDs[issueReq.Signer])
 		}
 	}
 
 	cert, _, _, err := pki.IssueCert(ctx, issueReq, s.keystore)
 	if err != nil {
-		return "", fmt.Errorf("issuing certificate: %v", err)
+		return "", nil, fmt.Errorf("issuing certificate: %v", err)
+	}
+	s.issued.record(certinventory.ForIssuedCertificate(certinventory.SourceBootstrap, id.NodeName, name, cert, issueReq.Signer, s.keypairIDs[issueReq.Signer]))
+	issued := &auditCertificate{
+		Name:         name,
+		SerialNumber: cert.Certificate.SerialNumber.String(),
+		Subject:      cert.Subject.String(),
+		Issuer:       issueReq.Signer,
+		IssuerID:     s.keypairIDs[issueReq.Signer],
+		NotAfter:     cert.Certificate.NotAfter.UTC(),
 	}
 
-	return cert.AsString()
+	certPEM, err := cert.AsString()
+	return certPEM, issued, err
 }
 
 // recovery is responsible for ensuring we don't exit on a panic.
@@ -177,6 +177,15 @@ func RunCreateInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer,
 		return err
 	}
 
+	if !options.DryRun {
+		lockedCtx, unlock, err := lockClusterState(ctx, clientset, cluster, "create instancegroup")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		ctx = lockedCtx
+	}
+
 	channel, err := cloudup.ChannelForCluster(clientset.VFSContext(), cluster)
 	if err != nil {
 		klog.Warningf("%v", err)
@@ -18,10 +18,12 @@ package main
 
 import (
 	"context"
+	"crypto"
 	"crypto/x509/pkix"
 	"fmt"
 	"io"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -59,6 +61,14 @@ var (
 
 	If the keyset is specified as "all", a newly generated secondary
 	certificate and private key will be added to each rotatable keyset.
+
+	The private keys of the "kubernetes-ca" and "service-account" keysets can
+	be held in an HSM or a cloud KMS instead of the state store: --key-uri
+	takes the URI of the key, such as awskms://<key ARN>,
+	gcpkms://<key version name> or an RFC 7512 pkcs11: URI. The control plane
+	signs with the "kubernetes-ca" key, so it must be in a cloud KMS.
+	kube-apiserver signs service account tokens through the plugin set in
+	spec.kubeAPIServer.serviceAccountSigningEndpoint.
 	`))
 
 	createKeypairExample = templates.Examples(i18n.T(`
@@ -67,6 +77,16 @@ var (
 		--cert ~/ca.pem --key ~/ca-key.pem \
 		--name k8s-cluster.example.com --state s3://my-state-store
 
+	# Add a CA whose private key is held in AWS KMS, with a self-signed certificate.
+	kops create keypair kubernetes-ca --primary \
+		--key-uri awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
+	# Add a service-account keypair whose private key is held in an HSM.
+	kops create keypair service-account --primary \
+		--key-uri "pkcs11:token=kops;object=service-account?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=file:/etc/kops/pin" \
+		--name k8s-cluster.example.com --state s3://my-state-store
+
 	# Add a newly generated certificate and private key to each rotatable keyset.
 	kops create keypair all \
 		--name k8s-cluster.example.com --state s3://my-state-store
@@ -79,6 +99,7 @@ type CreateKeypairOptions struct {
 	ClusterName    string
 	Keyset         string
 	PrivateKeyPath string
+	KeyURI         string
 	CertPath       string
 	Primary        bool
 }
@@ -87,6 +108,10 @@ func rotatableKeysetFilter(name string, _ *fi.Keyset) bool {
 	return name == "all" || name == "service-account" || strings.Contains(name, "-ca")
 }
 
+// externalSignerKeysets are the keysets whose users can sign through an external signer.
+// The other keysets are used by components that read their private keys from disk.
+var externalSignerKeysets = []string{fi.CertificateIDCA, "service-account"}
+
 // NewCmdCreateKeypair returns a create keypair command.
 func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 	options := &CreateKeypairOptions{}
@@ -120,11 +145,27 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 				if options.PrivateKeyPath != "" {
 					return fmt.Errorf("cannot specify --key with \"all\"")
 				}
+				if options.KeyURI != "" {
+					return fmt.Errorf("cannot specify --key-uri with \"all\"")
+				}
 				if options.Primary {
 					return fmt.Errorf("cannot specify --primary with \"all\"")
 				}
 			}
 
+			if options.KeyURI != "" {
+				if options.PrivateKeyPath != "" {
+					return fmt.Errorf("cannot specify both --key and --key-uri")
+				}
+				if !slices.Contains(externalSignerKeysets, options.Keyset) {
+					return fmt.Errorf("--key-uri is only supported for keysets %s", strings.Join(externalSignerKeysets, ", "))
+				}
+				// Only the kops CLI signs through a PKCS#11 token, but nodeup and kops-controller also sign with the CA key
+				if options.Keyset == fi.CertificateIDCA && pki.ExternalKeyScheme(options.KeyURI) == "pkcs11" {
+					return fmt.Errorf("the control plane signs with the %s key and can't reach a PKCS#11 token; use an awskms:// or gcpkms:// key", fi.CertificateIDCA)
+				}
+			}
+
 			return nil
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -137,6 +178,7 @@ func NewCmdCreateKeypair(f *util.Factory, out io.Writer) *cobra.Command {
 
 	cmd.Flags().StringVar(&options.CertPath, "cert", options.CertPath, "Path to CA certificate")
 	cmd.Flags().StringVar(&options.PrivateKeyPath, "key", options.PrivateKeyPath, "Path to CA private key")
+	cmd.Flags().StringVar(&options.KeyURI, "key-uri", options.KeyURI, "URI of a CA private key held in an HSM or cloud KMS")
 	cmd.Flags().BoolVar(&options.Primary, "primary", options.Primary, "Make the keypair the one used to issue certificates")
 
 	return cmd
@@ -153,11 +195,22 @@ func RunCreateKeypair(ctx context.Context, f *util.Factory, out io.Writer, optio
 		return fmt.Errorf("error getting cluster: %q: %v", options.ClusterName, err)
 	}
 
+	if options.KeyURI != "" && options.Primary && options.Keyset == "service-account" &&
+		(cluster.Spec.KubeAPIServer == nil || cluster.Spec.KubeAPIServer.ServiceAccountSigningEndpoint == nil) {
+		return fmt.Errorf("kube-apiserver can't sign with an external service-account key; set spec.kubeAPIServer.serviceAccountSigningEndpoint to the socket of a service account token signer plugin first")
+	}
+
 	clientSet, err := f.KopsClient()
 	if err != nil {
 		return fmt.Errorf("error getting clientset: %v", err)
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientSet, cluster, "create keypair")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	keyStore, err := clientSet.KeyStore(cluster)
 	if err != nil {
 		return fmt.Errorf("error getting keystore: %v", err)
@@ -198,6 +251,12 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 			return fmt.Errorf("error loading private key %q: %v", privateKeyBytes, err)
 		}
 	}
+	if options.KeyURI != "" {
+		privateKey, err = pki.NewExternalPrivateKey(ctx, options.KeyURI)
+		if err != nil {
+			return err
+		}
+	}
 
 	var cert *pki.Certificate
 	if options.CertPath == "" {
@@ -230,6 +289,12 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 		if err != nil {
 			return fmt.Errorf("error loading certificate %q: %v", options.CertPath, err)
 		}
+
+		if options.KeyURI != "" {
+			if public, ok := cert.PublicKey.(interface{ Equal(crypto.PublicKey) bool }); !ok || !public.Equal(privateKey.Key.Public()) {
+				return fmt.Errorf("certificate %q does not match the key %q", options.CertPath, options.KeyURI)
+			}
+		}
 	}
 
 	keyset, err := keyStore.FindKeyset(ctx, name)
@@ -263,6 +328,14 @@ func createKeypair(ctx context.Context, out io.Writer, options *CreateKeypairOpt
 	if options.PrivateKeyPath != "" {
 		fmt.Fprintf(out, "using user provided private key: %v\n", options.PrivateKeyPath)
 	}
+	if options.KeyURI != "" {
+		fmt.Fprintf(out, "using external private key: %v\n", options.KeyURI)
+		if name == fi.CertificateIDCA {
+			fmt.Fprintf(out, "warning: kube-controller-manager will not sign CertificateSigningRequests while this keypair is primary\n")
+		} else {
+			fmt.Fprintf(out, "warning: kube-controller-manager will not sign legacy service account token secrets while this keypair is primary\n")
+		}
+	}
 	fmt.Fprintf(out, "Created %s %s\n", name, item.Id)
 	return nil
 }
@@ -327,10 +400,13 @@ func completeCreateKeypair(ctx context.Context, f commandutils.Factory, options
 	if options.CertPath == "" {
 		flags = append(flags, "--cert")
 	}
-	if options.PrivateKeyPath == "" {
+	if options.PrivateKeyPath == "" && options.KeyURI == "" {
 		flags = append(flags, "--key")
+		if slices.Contains(externalSignerKeysets, args[0]) {
+			flags = append(flags, "--key-uri")
+		}
 	}
-	if !options.Primary && (options.CertPath == "" || options.PrivateKeyPath != "") {
+	if !options.Primary && (options.CertPath == "" || options.PrivateKeyPath != "" || options.KeyURI != "") {
 		flags = append(flags, "--primary")
 	}
 	return flags, cobra.ShellCompDirectiveNoFileComp
@@ -96,6 +96,12 @@ func RunCreateSecretCiliumEncryptionConfig(ctx context.Context, f commandutils.F
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "create secret ciliumpassword")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	secretStore, err := clientset.SecretStore(cluster)
 	if err != nil {
 		return err
@@ -101,6 +101,12 @@ func RunCreateSecretDockerConfig(ctx context.Context, f commandutils.Factory, ou
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "create secret dockerconfig")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	secretStore, err := clientset.SecretStore(cluster)
 	if err != nil {
 		return err
@@ -95,6 +95,12 @@ func RunCreateSecretEncryptionConfig(ctx context.Context, f commandutils.Factory
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "create secret encryptionconfig")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	secretStore, err := clientset.SecretStore(cluster)
 	if err != nil {
 		return err
@@ -84,6 +84,12 @@ func RunCreateSSHPublicKey(ctx context.Context, f *util.Factory, out io.Writer,
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "create sshpublickey")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
 	if err != nil {
 		return err
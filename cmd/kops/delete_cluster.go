@@ -27,6 +27,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/resources"
@@ -44,6 +45,7 @@ type DeleteClusterOptions struct {
 	Region      string
 	External    bool
 	Unregister  bool
+	KeepBackups bool
 	ClusterName string
 	wait        time.Duration
 	count       int
@@ -67,6 +69,9 @@ var (
 	# The --yes option runs the command immediately.
 	kops delete cluster --name=k8s.cluster.site --yes
 
+	# Delete a cluster, keeping its backups so that it can be restored with "kops toolbox backup restore".
+	kops delete cluster --name=k8s.cluster.site --keep-backups --yes
+
 	`))
 
 	deleteClusterShort = i18n.T("Delete a cluster.")
@@ -91,6 +96,7 @@ func NewCmdDeleteCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Specify --yes to delete the cluster")
 	cmd.Flags().BoolVar(&options.Unregister, "unregister", options.Unregister, "Don't delete cloud resources, just unregister the cluster")
 	cmd.Flags().BoolVar(&options.External, "external", options.External, "Delete an external cluster")
+	cmd.Flags().BoolVar(&options.KeepBackups, "keep-backups", options.KeepBackups, "Keep the backups in the state store, so that the cluster can be restored from them")
 
 	cmd.Flags().StringVar(&options.Region, "region", options.Region, "External cluster's cloud region")
 	cmd.RegisterFlagCompletionFunc("region", completeRegion)
@@ -128,6 +134,19 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
+
+		if options.Yes {
+			clientset, err := f.KopsClient()
+			if err != nil {
+				return err
+			}
+			lockedCtx, unlock, err := lockClusterState(ctx, clientset, cluster, "delete cluster")
+			if err != nil {
+				return err
+			}
+			defer unlock()
+			ctx = lockedCtx
+		}
 	}
 
 	wouldDeleteCloudResources := false
@@ -217,7 +236,7 @@ func RunDeleteCluster(ctx context.Context, f *util.Factory, out io.Writer, optio
 		if err != nil {
 			return err
 		}
-		err = clientset.DeleteCluster(ctx, cluster)
+		err = clientset.DeleteCluster(ctx, cluster, simple.DeleteClusterOptions{KeepBackups: options.KeepBackups})
 		if err != nil {
 			return fmt.Errorf("error removing cluster from state store: %v", err)
 		}
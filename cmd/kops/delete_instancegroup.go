@@ -171,6 +171,12 @@ func RunDeleteInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer,
 		return nil
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "delete instancegroup")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	cloud, err := cloudup.BuildCloud(cluster)
 	if err != nil {
 		return err
@@ -103,6 +103,12 @@ func RunDeleteSecret(ctx context.Context, f *util.Factory, out io.Writer, option
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "delete secret")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	secretStore, err := clientset.SecretStore(cluster)
 	if err != nil {
 		return err
@@ -72,6 +72,12 @@ func RunDeleteSSHPublicKey(ctx context.Context, f *util.Factory, out io.Writer,
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "delete sshpublickey")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
 	if err != nil {
 		return err
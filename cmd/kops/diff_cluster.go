@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	diffClusterLong = templates.LongDesc(i18n.T(`
+	Show the changes to the cluster and instance group configuration since a recorded revision.
+
+	A revision is recorded each time the cluster or one of its instance groups is changed.
+	Use "kops get cluster --history" to list the revisions.`))
+
+	diffClusterExample = templates.Examples(i18n.T(`
+	# Show the changes made since revision 3
+	kops diff cluster k8s-cluster.example.com --revision 3
+	`))
+
+	diffClusterShort = i18n.T(`Show the changes to a cluster since a recorded revision.`)
+)
+
+type DiffClusterOptions struct {
+	ClusterName string
+
+	// Revision is the recorded revision to compare the current configuration with
+	Revision int
+}
+
+func NewCmdDiffCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &DiffClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             diffClusterShort,
+		Long:              diffClusterLong,
+		Example:           diffClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunDiffCluster(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.Revision, "revision", options.Revision, "Revision to compare the current configuration with")
+	cmd.MarkFlagRequired("revision")
+
+	return cmd
+}
+
+func RunDiffCluster(ctx context.Context, f *util.Factory, out io.Writer, options *DiffClusterOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	current, revision, err := loadClusterRevision(ctx, clientset, cluster, options.Revision)
+	if err != nil {
+		return err
+	}
+
+	if current == revision {
+		_, err := fmt.Fprintf(out, "No changes since revision %d\n", options.Revision)
+		return err
+	}
+	_, err = fmt.Fprint(out, diff.FormatDiff(revision, current))
+	return err
+}
+
+// loadClusterRevision returns the current configuration of the cluster and the configuration at the specified revision, rendered as YAML
+func loadClusterRevision(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster, revision int) (string, string, error) {
+	history, err := clientset.ClusterHistoryFor(cluster)
+	if err != nil {
+		return "", "", err
+	}
+	revisionCluster, revisionInstanceGroups, err := history.Get(ctx, revision)
+	if err != nil {
+		return "", "", err
+	}
+	revisionYAML, err := renderClusterConfiguration(revisionCluster, revisionInstanceGroups)
+	if err != nil {
+		return "", "", err
+	}
+
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", "", err
+	}
+	var instanceGroups []*kopsapi.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+	currentYAML, err := renderClusterConfiguration(cluster, instanceGroups)
+	if err != nil {
+		return "", "", err
+	}
+
+	return currentYAML, revisionYAML, nil
+}
+
+// renderClusterConfiguration renders the cluster and its instance groups (sorted by name) as YAML documents.
+// The generation is omitted, as it changes on every write, including a rollback.
+func renderClusterConfiguration(cluster *kopsapi.Cluster, instanceGroups []*kopsapi.InstanceGroup) (string, error) {
+	var sorted []*kopsapi.InstanceGroup
+	for _, ig := range instanceGroups {
+		ig = ig.DeepCopy()
+		ig.SetGeneration(0)
+		sorted = append(sorted, ig)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].ObjectMeta.Name < sorted[j].ObjectMeta.Name
+	})
+
+	cluster = cluster.DeepCopy()
+	cluster.SetGeneration(0)
+
+	var b bytes.Buffer
+	if err := marshalToWriter(cluster, marshalYaml, &b); err != nil {
+		return "", err
+	}
+	for _, ig := range sorted {
+		b.WriteString("---\n")
+		if err := marshalToWriter(ig, marshalYaml, &b); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
@@ -119,6 +119,12 @@ func RunDistrustKeypair(ctx context.Context, f *util.Factory, out io.Writer, opt
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "distrust keypair")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return err
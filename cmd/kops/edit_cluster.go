@@ -115,6 +115,12 @@ func RunEditCluster(ctx context.Context, f *util.Factory, out io.Writer, options
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, oldCluster, "edit cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	instanceGroups, err := commands.ReadAllInstanceGroups(ctx, clientset, oldCluster)
 	if err != nil {
 		return err
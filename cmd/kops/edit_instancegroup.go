@@ -132,6 +132,12 @@ func RunEditInstanceGroup(ctx context.Context, f *util.Factory, out io.Writer, o
 		return err
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "edit instancegroup")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	channel, err := cloudup.ChannelForCluster(clientset.VFSContext(), cluster)
 	if err != nil {
 		klog.Warningf("%v", err)
@@ -64,7 +64,9 @@ func NewCmdGet(f *util.Factory, out io.Writer) *cobra.Command {
 	// create subcommands
 	cmd.AddCommand(NewCmdGetAll(f, out, options))
 	cmd.AddCommand(NewCmdGetAssets(f, out, options))
+	cmd.AddCommand(NewCmdGetCertificates(f, out, options))
 	cmd.AddCommand(NewCmdGetCluster(f, out, options))
+	cmd.AddCommand(NewCmdGetDrift(f, out, options))
 	cmd.AddCommand(NewCmdGetInstanceGroups(f, out, options))
 	cmd.AddCommand(NewCmdGetInstances(f, out, options))
 	cmd.AddCommand(NewCmdGetKeypairs(f, out, options))
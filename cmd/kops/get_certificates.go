@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops/model"
+	"k8s.io/kops/pkg/certinventory"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	getCertificatesLong = templates.LongDesc(i18n.T(`
+	Display the certificates of a cluster and when they expire.
+
+	This lists the keypairs in the cluster's keystore and the certificates issued to each node,
+	either by kops-controller when the node bootstrapped or by nodeup.
+	Node certificates never leave the nodes, so their expiry is estimated from when the node
+	registered with the cluster; these entries are marked with "~".
+
+	The command exits with a non-zero status if any certificate expires within the horizon.`))
+
+	getCertificatesExample = templates.Examples(i18n.T(`
+	# Display the certificates of a cluster.
+	kops get certificates --name k8s-cluster.example.com
+
+	# Fail if any keystore certificate expires within 90 days.
+	kops get certificates --name k8s-cluster.example.com --horizon 2160h --nodes=false
+	`))
+
+	getCertificatesShort = i18n.T(`Display cluster certificates and their expiry.`)
+)
+
+type GetCertificatesOptions struct {
+	*GetOptions
+	kubeconfig.CreateKubecfgOptions
+
+	// Horizon is how far ahead a certificate expiry is reported as a failure.
+	Horizon time.Duration
+	// Nodes includes the certificates issued to the nodes, which requires access to the Kubernetes API.
+	Nodes bool
+}
+
+type renderableCertificate struct {
+	*certinventory.Certificate
+	Remaining string `json:"remaining"`
+	Expiring  bool   `json:"expiring,omitempty"`
+}
+
+func NewCmdGetCertificates(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetCertificatesOptions{
+		GetOptions: getOptions,
+		Horizon:    30 * 24 * time.Hour,
+		Nodes:      true,
+	}
+	cmd := &cobra.Command{
+		Use:               "certificates [CLUSTER]",
+		Aliases:           []string{"certificate", "certs"},
+		Short:             getCertificatesShort,
+		Long:              getCertificatesLong,
+		Example:           getCertificatesExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetCertificates(cmd.Context(), f, out, &options)
+		},
+	}
+
+	cmd.Flags().DurationVar(&options.Horizon, "horizon", options.Horizon, "Report certificates that expire within this duration")
+	cmd.Flags().BoolVar(&options.Nodes, "nodes", options.Nodes, "Include the certificates issued to nodes")
+	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
+
+	return cmd
+}
+
+func RunGetCertificates(ctx context.Context, f *util.Factory, out io.Writer, options *GetCertificatesOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return err
+	}
+	if cluster == nil {
+		return fmt.Errorf("cluster not found %q", options.ClusterName)
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return fmt.Errorf("listing keysets: %w", err)
+	}
+	certificates := certinventory.FromKeysets(keysets)
+
+	if options.Nodes {
+		restConfig, err := f.RESTConfig(ctx, cluster, options.CreateKubecfgOptions)
+		if err != nil {
+			return err
+		}
+		httpClient, err := f.HTTPClient(restConfig)
+		if err != nil {
+			return err
+		}
+		k8sClient, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
+		if err != nil {
+			return fmt.Errorf("building kubernetes client: %w", err)
+		}
+		nodes, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return fmt.Errorf("listing nodes: %w", err)
+		}
+
+		primaries := make(map[string]string)
+		for name, keyset := range keysets {
+			if keyset.Primary != nil {
+				primaries[name] = keyset.Primary.Id
+			}
+		}
+		certificates = append(certificates, certinventory.FromNodes(nodes.Items, certinventory.NodeOptions{
+			BootstrapCertNames:  model.KopsControllerCertNames(cluster),
+			BootstrapCertSigner: model.KopsControllerCertSigner,
+			Primaries:           primaries,
+		})...)
+		certinventory.Sort(certificates)
+	}
+
+	now := time.Now()
+	expiring := certinventory.Expiring(certificates, now, options.Horizon)
+
+	switch options.Output {
+	case OutputTable:
+		if err := certificateOutputTable(certificates, now, options.Horizon, out); err != nil {
+			return err
+		}
+	case OutputYaml:
+		y, err := yaml.Marshal(asRenderableCertificates(certificates, now, options.Horizon))
+		if err != nil {
+			return fmt.Errorf("unable to marshal YAML: %v", err)
+		}
+		if _, err := out.Write(y); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	case OutputJSON:
+		j, err := json.MarshalIndent(asRenderableCertificates(certificates, now, options.Horizon), "", "  ")
+		if err != nil {
+			return fmt.Errorf("unable to marshal JSON: %v", err)
+		}
+		if _, err := out.Write(j); err != nil {
+			return fmt.Errorf("error writing to output: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	if len(expiring) > 0 {
+		return fmt.Errorf("%d certificate(s) expire within %s", len(expiring), options.Horizon)
+	}
+	return nil
+}
+
+func certificateOutputTable(certificates []*certinventory.Certificate, now time.Time, horizon time.Duration, out io.Writer) error {
+	t := &tables.Table{}
+	t.AddColumn("SOURCE", func(c *certinventory.Certificate) string {
+		return string(c.Source)
+	})
+	t.AddColumn("NAME", func(c *certinventory.Certificate) string {
+		return c.Name
+	})
+	t.AddColumn("ID", func(c *certinventory.Certificate) string {
+		return c.ID
+	})
+	t.AddColumn("NODE", func(c *certinventory.Certificate) string {
+		return c.Node
+	})
+	t.AddColumn("SUBJECT", func(c *certinventory.Certificate) string {
+		return c.Subject
+	})
+	t.AddColumn("ISSUER-ID", func(c *certinventory.Certificate) string {
+		if c.IssuerID == "" {
+			return c.Issuer
+		}
+		return c.Issuer + "/" + c.IssuerID
+	})
+	t.AddColumn("EXPIRES", func(c *certinventory.Certificate) string {
+		expires := c.NotAfter.Format(time.RFC3339)
+		if c.Estimated {
+			expires = "~" + expires
+		}
+		return expires
+	})
+	t.AddColumn("REMAINING", func(c *certinventory.Certificate) string {
+		return formatRemaining(c.Remaining(now))
+	})
+	t.AddColumn("STATUS", func(c *certinventory.Certificate) string {
+		switch remaining := c.Remaining(now); {
+		case remaining <= 0:
+			return "EXPIRED"
+		case remaining < horizon:
+			return "EXPIRING"
+		default:
+			return "OK"
+		}
+	})
+	return t.Render(certificates, out, "SOURCE", "NAME", "ID", "NODE", "SUBJECT", "ISSUER-ID", "EXPIRES", "REMAINING", "STATUS")
+}
+
+func formatRemaining(remaining time.Duration) string {
+	if remaining <= 0 {
+		return "expired"
+	}
+	return duration.HumanDuration(remaining)
+}
+
+func asRenderableCertificates(certificates []*certinventory.Certificate, now time.Time, horizon time.Duration) []*renderableCertificate {
+	renderable := make([]*renderableCertificate, len(certificates))
+	for i, c := range certificates {
+		remaining := c.Remaining(now)
+		renderable[i] = &renderableCertificate{
+			Certificate: c,
+			Remaining:   formatRemaining(remaining),
+			Expiring:    remaining < horizon,
+		}
+	}
+	return renderable
+}
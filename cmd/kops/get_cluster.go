@@ -18,9 +18,12 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,12 +33,14 @@ import (
 	"k8s.io/kops/cmd/kops/util"
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/util/pkg/tables"
 	"k8s.io/kops/util/pkg/vfs"
 	"k8s.io/kubectl/pkg/util/i18n"
 	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
 )
 
 var (
@@ -54,6 +59,9 @@ var (
 
 	# Save a cluster desired configuration to YAML file
 	kops get cluster k8s-cluster.example.com -o yaml > cluster-desired-config.yaml
+
+	# List the recorded revisions of a cluster's configuration
+	kops get cluster k8s-cluster.example.com --history
 	`))
 
 	getClusterShort = i18n.T(`Get one or many clusters.`)
@@ -80,6 +88,9 @@ type GetClusterOptions struct {
 	// FullSpec determines if we should output the completed (fully populated) spec
 	FullSpec bool
 
+	// History lists the recorded revisions of the cluster and instance group specs, instead of the cluster
+	History bool
+
 	// ClusterNames is a list of cluster names to show; if not specified all clusters will be shown
 	ClusterNames []string
 }
@@ -114,6 +125,7 @@ func NewCmdGetCluster(f *util.Factory, out io.Writer, getOptions *GetOptions) *c
 	}
 
 	cmd.Flags().BoolVar(&options.FullSpec, "full", options.FullSpec, "Show fully populated configuration")
+	cmd.Flags().BoolVar(&options.History, "history", options.History, "List the recorded revisions of the cluster configuration")
 
 	return cmd
 }
@@ -156,6 +168,13 @@ func RunGetClusters(ctx context.Context, f commandutils.Factory, out io.Writer,
 		return fmt.Errorf("no clusters found")
 	}
 
+	if options.History {
+		if !singleClusterSelected {
+			return fmt.Errorf("--history requires a single cluster to be specified")
+		}
+		return getClusterHistory(ctx, client, out, clusters[0], options.Output)
+	}
+
 	if options.FullSpec {
 		var err error
 		clusters, err = fullClusterSpecs(ctx, client.VFSContext(), clusters)
@@ -234,6 +253,59 @@ func clusterOutputTable(clusters []*kopsapi.Cluster, out io.Writer) error {
 	return t.Render(clusters, out, "NAME", "CLOUD", "ZONES")
 }
 
+// getClusterHistory outputs the recorded revisions of a cluster's configuration
+func getClusterHistory(ctx context.Context, client simple.Clientset, out io.Writer, cluster *kopsapi.Cluster, output string) error {
+	history, err := client.ClusterHistoryFor(cluster)
+	if err != nil {
+		return err
+	}
+	revisions, err := history.List(ctx)
+	if err != nil {
+		return fmt.Errorf("error reading history of cluster %q: %w", cluster.ObjectMeta.Name, err)
+	}
+
+	switch output {
+	case OutputTable:
+		if len(revisions) == 0 {
+			_, err := fmt.Fprintf(out, "No revisions recorded for cluster %q\n", cluster.ObjectMeta.Name)
+			return err
+		}
+		t := &tables.Table{}
+		t.AddColumn("REVISION", func(r *simple.ClusterRevision) string {
+			return strconv.Itoa(r.Revision)
+		})
+		t.AddColumn("TIMESTAMP", func(r *simple.ClusterRevision) string {
+			return r.Timestamp.Format(time.RFC3339)
+		})
+		t.AddColumn("AUTHOR", func(r *simple.ClusterRevision) string {
+			return r.Author
+		})
+		t.AddColumn("KOPS VERSION", func(r *simple.ClusterRevision) string {
+			return r.KopsVersion
+		})
+		t.AddColumn("CHANGE", func(r *simple.ClusterRevision) string {
+			return r.Change
+		})
+		return t.Render(revisions, out, "REVISION", "TIMESTAMP", "AUTHOR", "KOPS VERSION", "CHANGE")
+	case OutputYaml:
+		b, err := yaml.Marshal(revisions)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %w", err)
+		}
+		_, err = out.Write(b)
+		return err
+	case OutputJSON:
+		b, err := json.MarshalIndent(revisions, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %w", err)
+		}
+		_, err = out.Write(append(b, '\n'))
+		return err
+	default:
+		return fmt.Errorf("unknown output format: %q", output)
+	}
+}
+
 // fullOutputJSON outputs the marshalled JSON of a list of clusters and instance groups.  It will handle
 // nils for clusters and instanceGroups slices.
 func fullOutputJSON(out io.Writer, singleObject bool, args ...runtime.Object) error {
@@ -0,0 +1,225 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+	"sigs.k8s.io/yaml"
+)
+
+// OutputPrometheus is the Prometheus text exposition format, suitable for the node_exporter textfile collector
+const OutputPrometheus = "prometheus"
+
+var (
+	getDriftLong = templates.LongDesc(i18n.T(`
+	Report cloud resources whose live state differs from the cluster and instance group specs,
+	for example security group rules or launch templates that were changed outside of kOps.
+
+	No changes are made. The command exits with a non-zero status if drift is found.
+	In addition to the table, yaml and json output formats, prometheus writes metrics
+	suitable for the node_exporter textfile collector.`))
+
+	getDriftExample = templates.Examples(i18n.T(`
+	# Report drift for a cluster.
+	kops get drift k8s-cluster.example.com
+
+	# Write drift metrics for the node_exporter textfile collector.
+	kops get drift k8s-cluster.example.com -o prometheus > /var/lib/node_exporter/kops_drift.prom
+	`))
+
+	getDriftShort = i18n.T(`Report cloud resources that differ from the cluster spec.`)
+)
+
+type GetDriftOptions struct {
+	*GetOptions
+}
+
+// DriftResult is the machine-readable result of a drift check
+type DriftResult struct {
+	// Cluster is the name of the cluster that was checked
+	Cluster string `json:"cluster"`
+	// Resources lists the resources that have drifted, with the changes needed to correct them
+	Resources []fi.PlanTask `json:"resources"`
+}
+
+func NewCmdGetDrift(f *util.Factory, out io.Writer, getOptions *GetOptions) *cobra.Command {
+	options := GetDriftOptions{
+		GetOptions: getOptions,
+	}
+
+	cmd := &cobra.Command{
+		Use:               "drift [CLUSTER]",
+		Short:             getDriftShort,
+		Long:              getDriftLong,
+		Example:           getDriftExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunGetDrift(cmd.Context(), f, out, &options)
+		},
+	}
+
+	return cmd
+}
+
+func RunGetDrift(ctx context.Context, f *util.Factory, out io.Writer, options *GetDriftOptions) error {
+	switch options.Output {
+	case OutputTable, OutputJSON, OutputYaml, OutputPrometheus:
+	default:
+		return fmt.Errorf("unsupported output format: %q", options.Output)
+	}
+
+	// Run a dry-run update with the same defaults as kops update cluster.
+	// Requesting the plan in a machine-readable format suppresses the usual report of changes.
+	updateClusterOptions := &UpdateClusterOptions{}
+	updateClusterOptions.InitDefaults()
+	updateClusterOptions.Target = cloudup.TargetDryRun
+	updateClusterOptions.ClusterName = options.ClusterName
+	updateClusterOptions.Output = OutputJSON
+	updateClusterResults, err := RunUpdateCluster(ctx, f, io.Discard, updateClusterOptions)
+	if err != nil {
+		return err
+	}
+
+	target, ok := updateClusterResults.Target.(*fi.CloudupDryRunTarget)
+	if !ok {
+		return fmt.Errorf("unexpected target type %T", updateClusterResults.Target)
+	}
+	plan, err := target.Plan(updateClusterResults.TaskMap)
+	if err != nil {
+		return err
+	}
+
+	result := &DriftResult{
+		Cluster:   updateClusterResults.Cluster.ObjectMeta.Name,
+		Resources: plan.Tasks,
+	}
+	if result.Resources == nil {
+		result.Resources = []fi.PlanTask{}
+	}
+
+	switch options.Output {
+	case OutputTable:
+		err = driftOutputTable(result, out)
+	case OutputYaml:
+		var b []byte
+		b, err = yaml.Marshal(result)
+		if err == nil {
+			_, err = out.Write(b)
+		}
+	case OutputJSON:
+		var b []byte
+		b, err = json.MarshalIndent(result, "", "  ")
+		if err == nil {
+			_, err = out.Write(append(b, '\n'))
+		}
+	case OutputPrometheus:
+		err = driftOutputPrometheus(result, time.Now(), out)
+	}
+	if err != nil {
+		return fmt.Errorf("error writing drift report: %w", err)
+	}
+
+	if len(result.Resources) != 0 {
+		return fmt.Errorf("found drift in %d resources", len(result.Resources))
+	}
+	return nil
+}
+
+func driftOutputTable(result *DriftResult, out io.Writer) error {
+	if len(result.Resources) == 0 {
+		_, err := fmt.Fprintf(out, "No drift found\n")
+		return err
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("RESOURCE", func(r fi.PlanTask) string {
+		return r.Key
+	})
+	t.AddColumn("ACTION", func(r fi.PlanTask) string {
+		if r.Deferred {
+			return string(r.Action) + " (deferred)"
+		}
+		return string(r.Action)
+	})
+	t.AddColumn("FIELDS", func(r fi.PlanTask) string {
+		var fields []string
+		for _, c := range r.Changes {
+			fields = append(fields, c.Field)
+		}
+		return strings.Join(fields, ",")
+	})
+	return t.Render(result.Resources, out, "RESOURCE", "ACTION", "FIELDS")
+}
+
+// driftOutputPrometheus writes the drift result in the Prometheus text exposition format
+func driftOutputPrometheus(result *DriftResult, now time.Time, out io.Writer) error {
+	b := &strings.Builder{}
+	cluster := escapePrometheusLabelValue(result.Cluster)
+
+	counts := map[fi.PlanAction]int{
+		fi.PlanActionCreate: 0,
+		fi.PlanActionUpdate: 0,
+		fi.PlanActionDelete: 0,
+	}
+	for _, r := range result.Resources {
+		counts[r.Action]++
+	}
+	var actions []string
+	for action := range counts {
+		actions = append(actions, string(action))
+	}
+	sort.Strings(actions)
+
+	fmt.Fprintf(b, "# HELP kops_drift_resources Number of cloud resources that differ from the cluster spec, by the action needed to correct them.\n")
+	fmt.Fprintf(b, "# TYPE kops_drift_resources gauge\n")
+	for _, action := range actions {
+		fmt.Fprintf(b, "kops_drift_resources{cluster=\"%s\",action=\"%s\"} %d\n", cluster, action, counts[fi.PlanAction(action)])
+	}
+
+	fmt.Fprintf(b, "# HELP kops_drift_resource Set for each cloud resource that differs from the cluster spec.\n")
+	fmt.Fprintf(b, "# TYPE kops_drift_resource gauge\n")
+	for _, r := range result.Resources {
+		fmt.Fprintf(b, "kops_drift_resource{cluster=\"%s\",resource=\"%s\",action=\"%s\"} 1\n", cluster, escapePrometheusLabelValue(r.Key), r.Action)
+	}
+
+	fmt.Fprintf(b, "# HELP kops_drift_last_check_timestamp_seconds Time of the last drift check.\n")
+	fmt.Fprintf(b, "# TYPE kops_drift_last_check_timestamp_seconds gauge\n")
+	fmt.Fprintf(b, "kops_drift_last_check_timestamp_seconds{cluster=\"%s\"} %d\n", cluster, now.Unix())
+
+	_, err := io.WriteString(out, b.String())
+	return err
+}
+
+func escapePrometheusLabelValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s)
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+func TestDriftOutputPrometheus(t *testing.T) {
+	result := &DriftResult{
+		Cluster: "minimal.example.com",
+		Resources: []fi.PlanTask{
+			{Key: "SecurityGroupRule/from-0.0.0.0/0-ingress-tcp-22", Action: fi.PlanActionUpdate},
+			{Key: "LaunchTemplate/nodes.minimal.example.com", Action: fi.PlanActionUpdate},
+			{Key: "IAMRolePolicy/\"quoted\"", Action: fi.PlanActionCreate},
+		},
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, driftOutputPrometheus(result, time.Unix(1700000000, 0), &out))
+
+	expected := `# HELP kops_drift_resources Number of cloud resources that differ from the cluster spec, by the action needed to correct them.
+# TYPE kops_drift_resources gauge
+kops_drift_resources{cluster="minimal.example.com",action="create"} 1
+kops_drift_resources{cluster="minimal.example.com",action="delete"} 0
+kops_drift_resources{cluster="minimal.example.com",action="update"} 2
+# HELP kops_drift_resource Set for each cloud resource that differs from the cluster spec.
+# TYPE kops_drift_resource gauge
+kops_drift_resource{cluster="minimal.example.com",resource="SecurityGroupRule/from-0.0.0.0/0-ingress-tcp-22",action="update"} 1
+kops_drift_resource{cluster="minimal.example.com",resource="LaunchTemplate/nodes.minimal.example.com",action="update"} 1
+kops_drift_resource{cluster="minimal.example.com",resource="IAMRolePolicy/\"quoted\"",action="create"} 1
+# HELP kops_drift_last_check_timestamp_seconds Time of the last drift check.
+# TYPE kops_drift_last_check_timestamp_seconds gauge
+kops_drift_last_check_timestamp_seconds{cluster="minimal.example.com"} 1700000000
+`
+	assert.Equal(t, expected, out.String())
+}
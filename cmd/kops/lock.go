@@ -0,0 +1,66 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kubectl/pkg/util/i18n"
+)
+
+var lockShort = i18n.T("Inspect and manage the state store lock.")
+
+func NewCmdLock(f *util.Factory, out io.Writer) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lock",
+		Short: lockShort,
+	}
+
+	// create subcommands
+	cmd.AddCommand(NewCmdLockStatus(f, out))
+	cmd.AddCommand(NewCmdLockBreak(f, out))
+
+	return cmd
+}
+
+// lockClusterState takes the state store lock for the cluster, for the duration of a mutating command.
+// The command must use the returned context, which is cancelled if another process takes the lock.
+// The returned function releases the lock.
+func lockClusterState(ctx context.Context, clientset simple.Clientset, cluster *kopsapi.Cluster, command string) (context.Context, func(), error) {
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	lease, err := statelock.Acquire(ctx, configBase, command, statelock.DefaultLeaseDuration)
+	if err != nil {
+		return nil, nil, err
+	}
+	leaseCtx, cancel := lease.Context(ctx)
+	return leaseCtx, func() {
+		cancel()
+		if err := lease.Release(context.WithoutCancel(ctx)); err != nil {
+			klog.Warningf("%v", err)
+		}
+	}, nil
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	lockBreakLong = templates.LongDesc(i18n.T(`
+	Forcibly release the state store lock of a cluster.
+
+	Only break the lock if the holder shown by "kops lock status" is no longer running,
+	for example because it was killed. A holder that is still running loses the lock
+	and fails when it next renews its lease.`))
+
+	lockBreakExample = templates.Examples(i18n.T(`
+	kops lock break k8s-cluster.example.com
+	`))
+
+	lockBreakShort = i18n.T(`Forcibly release the state store lock.`)
+)
+
+type LockBreakOptions struct {
+	ClusterName string
+}
+
+func NewCmdLockBreak(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &LockBreakOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "break [CLUSTER]",
+		Short:             lockBreakShort,
+		Long:              lockBreakLong,
+		Example:           lockBreakExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunLockBreak(cmd.Context(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunLockBreak(ctx context.Context, f *util.Factory, out io.Writer, options *LockBreakOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	record, err := statelock.Break(ctx, configBase)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		fmt.Fprintf(out, "Cluster %q is not locked\n", cluster.ObjectMeta.Name)
+		return nil
+	}
+	fmt.Fprintf(out, "Broke the lock held by %s\n", record)
+	return nil
+}
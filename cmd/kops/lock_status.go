@@ -0,0 +1,106 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	lockStatusLong = templates.LongDesc(i18n.T(`
+	Show who holds the state store lock of a cluster.
+
+	Commands that change the cluster, such as "kops update cluster --yes", hold the lock
+	while they run, so that two operators can't change the same cluster at the same time.`))
+
+	lockStatusExample = templates.Examples(i18n.T(`
+	kops lock status k8s-cluster.example.com
+	`))
+
+	lockStatusShort = i18n.T(`Show the holder of the state store lock.`)
+)
+
+type LockStatusOptions struct {
+	ClusterName string
+}
+
+func NewCmdLockStatus(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &LockStatusOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "status [CLUSTER]",
+		Short:             lockStatusShort,
+		Long:              lockStatusLong,
+		Example:           lockStatusExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunLockStatus(cmd.Context(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunLockStatus(ctx context.Context, f *util.Factory, out io.Writer, options *LockStatusOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	record, err := statelock.Status(ctx, configBase)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		fmt.Fprintf(out, "Cluster %q is not locked\n", cluster.ObjectMeta.Name)
+		return nil
+	}
+
+	fmt.Fprintf(out, "Holder:\t\t%s\n", record.Holder)
+	fmt.Fprintf(out, "PID:\t\t%d\n", record.PID)
+	fmt.Fprintf(out, "Command:\t%s\n", record.Command)
+	fmt.Fprintf(out, "kOps version:\t%s\n", record.KopsVersion)
+	fmt.Fprintf(out, "Acquired:\t%s\n", record.AcquiredAt.Format(time.RFC3339))
+	fmt.Fprintf(out, "Renewed:\t%s\n", record.RenewedAt.Format(time.RFC3339))
+	if record.Expired(time.Now()) {
+		fmt.Fprintf(out, "Expired:\t%s\n", record.ExpiresAt.Format(time.RFC3339))
+	} else {
+		fmt.Fprintf(out, "Expires:\t%s\n", record.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/google/go-containerregistry/pkg/crane"
 	"k8s.io/kops"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/externalsigner"
 )
 
 func main() {
@@ -41,6 +42,9 @@ func run(ctx context.Context) error {
 		return crane.Digest(image, crane.WithAuthFromKeychain(authn.DefaultKeychain))
 	})
 
+	// Keysets can hold keys in an HSM or cloud KMS
+	externalsigner.Register()
+
 	// Set up OpenTelemetry.
 	serviceName := "kops"
 	serviceVersion := kops.Version
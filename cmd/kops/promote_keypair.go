@@ -128,6 +128,12 @@ func RunPromoteKeypair(ctx context.Context, f *util.Factory, out io.Writer, opti
 		return fmt.Errorf("getting clientset: %v", err)
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientSet, cluster, "promote keypair")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	keyStore, err := clientSet.KeyStore(cluster)
 	if err != nil {
 		return fmt.Errorf("getting keystore: %v", err)
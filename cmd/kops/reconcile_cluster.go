@@ -50,6 +50,9 @@ type ReconcileClusterOptions struct {
 	CoreUpdateClusterOptions
 
 	kubeconfig.CreateKubecfgOptions
+
+	// Resume continues an interrupted rolling update from the progress recorded in the state store.
+	Resume bool
 }
 
 func NewCmdReconcileCluster(f *util.Factory, out io.Writer) *cobra.Command {
@@ -79,6 +82,8 @@ func NewCmdReconcileCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	// cmd.Flags().StringVar(&options.OutDir, "out", options.OutDir, "Path to write any local output")
 	// cmd.MarkFlagDirname("out")
 
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Resume an interrupted rolling update from the progress recorded in the state store")
+
 	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
 
 	// These flags from the update command are specified to kubeconfig creation
@@ -184,6 +189,7 @@ func RunReconcileCluster(ctx context.Context, f *util.Factory, out io.Writer, op
 			string(kops.InstanceGroupRoleControlPlane),
 		}
 		opt.Yes = c.Yes
+		opt.Resume = options.Resume
 		if err := RunRollingUpdateCluster(ctx, f, out, opt); err != nil {
 			return err
 		}
@@ -209,6 +215,7 @@ func RunReconcileCluster(ctx context.Context, f *util.Factory, out io.Writer, op
 		// Do all roles this time, though we only expect changes to node & bastion roles
 		opt.InstanceGroupRoles = nil
 		opt.Yes = c.Yes
+		opt.Resume = options.Resume
 		if err := RunRollingUpdateCluster(ctx, f, out, opt); err != nil {
 			return err
 		}
@@ -150,6 +150,12 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 							return fmt.Errorf("error creating cluster: %v", err)
 						}
 					} else {
+						ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "replace")
+						if err != nil {
+							return err
+						}
+						defer unlock()
+
 						_, err = clientset.UpdateCluster(ctx, v, status)
 						if err != nil {
 							return fmt.Errorf("error replacing cluster: %v", err)
@@ -169,6 +175,12 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 					}
 					return fmt.Errorf("error fetching cluster %q: %v", clusterName, err)
 				}
+				ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "replace")
+				if err != nil {
+					return err
+				}
+				defer unlock()
+
 				// check if the instancegroup exists already
 				igName := v.ObjectMeta.Name
 				ig, err := clientset.InstanceGroupsFor(cluster).Get(ctx, igName, metav1.GetOptions{})
@@ -207,6 +219,11 @@ func RunReplace(ctx context.Context, f *util.Factory, out io.Writer, c *ReplaceO
 				if err != nil {
 					return err
 				}
+				ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "replace")
+				if err != nil {
+					return err
+				}
+				defer unlock()
 
 				sshCredentialStore, err := clientset.SSHCredentialStore(cluster)
 				if err != nil {
@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/diff"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	rollbackClusterLong = templates.LongDesc(i18n.T(`
+	Restore the cluster and instance group configuration recorded in a revision.
+
+	Instance groups that did not exist at the revision are deleted, and instance groups
+	that have since been deleted are recreated. The rollback is itself recorded in the history.
+
+	Only the configuration in the state store is changed; run "kops update cluster" to apply it
+	to the cloud resources. Use "kops get cluster --history" to list the revisions.`))
+
+	rollbackClusterExample = templates.Examples(i18n.T(`
+	# Preview rolling back to revision 3
+	kops rollback cluster k8s-cluster.example.com --to 3
+
+	# Roll back to revision 3 and apply the configuration
+	kops rollback cluster k8s-cluster.example.com --to 3 --yes
+	kops update cluster k8s-cluster.example.com --yes
+	`))
+
+	rollbackClusterShort = i18n.T(`Roll back a cluster to a recorded revision.`)
+)
+
+type RollbackClusterOptions struct {
+	ClusterName string
+
+	// To is the revision to roll back to
+	To int
+
+	// Yes must be set to make the changes; otherwise they are only previewed
+	Yes bool
+}
+
+func NewCmdRollbackCluster(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &RollbackClusterOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "cluster [CLUSTER]",
+		Short:             rollbackClusterShort,
+		Long:              rollbackClusterLong,
+		Example:           rollbackClusterExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunRollbackCluster(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().IntVar(&options.To, "to", options.To, "Revision to roll back to")
+	cmd.MarkFlagRequired("to")
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Roll back the configuration, without --yes the changes are only previewed")
+
+	return cmd
+}
+
+func RunRollbackCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollbackClusterOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	current, revision, err := loadClusterRevision(ctx, clientset, cluster, options.To)
+	if err != nil {
+		return err
+	}
+	if current == revision {
+		_, err := fmt.Fprintf(out, "Cluster configuration already matches revision %d\n", options.To)
+		return err
+	}
+
+	if !options.Yes {
+		fmt.Fprint(out, diff.FormatDiff(current, revision))
+		fmt.Fprintf(out, "\nMust specify --yes to roll back to revision %d\n", options.To)
+		return nil
+	}
+
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "rollback cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	history, err := clientset.ClusterHistoryFor(cluster)
+	if err != nil {
+		return err
+	}
+	revisionCluster, revisionInstanceGroups, err := history.Get(ctx, options.To)
+	if err != nil {
+		return err
+	}
+
+	revisionCluster.SetGeneration(cluster.GetGeneration())
+	if _, err := clientset.UpdateCluster(ctx, revisionCluster, nil); err != nil {
+		return fmt.Errorf("error rolling back cluster: %w", err)
+	}
+
+	igClient := clientset.InstanceGroupsFor(revisionCluster)
+	list, err := igClient.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]*kopsapi.InstanceGroup)
+	for i := range list.Items {
+		existing[list.Items[i].ObjectMeta.Name] = &list.Items[i]
+	}
+
+	for _, ig := range revisionInstanceGroups {
+		if current := existing[ig.ObjectMeta.Name]; current != nil {
+			delete(existing, ig.ObjectMeta.Name)
+			ig.SetGeneration(current.GetGeneration())
+			if _, err := igClient.Update(ctx, ig, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("error rolling back instance group %q: %w", ig.ObjectMeta.Name, err)
+			}
+		} else {
+			if _, err := igClient.Create(ctx, ig, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("error recreating instance group %q: %w", ig.ObjectMeta.Name, err)
+			}
+		}
+	}
+	for name := range existing {
+		if err := igClient.Delete(ctx, name, metav1.DeleteOptions{}); err != nil {
+			return fmt.Errorf("error deleting instance group %q: %w", name, err)
+		}
+	}
+
+	fmt.Fprintf(out, "Rolled back the configuration of cluster %q to revision %d\n", cluster.ObjectMeta.Name, options.To)
+	fmt.Fprintf(out, "Run \"kops update cluster %s --yes\" to apply the changes\n", cluster.ObjectMeta.Name)
+	return nil
+}
@@ -89,6 +89,16 @@ var (
 		# Update only the "nodes-1a" instance group of the k8s-cluster.example.com kOps cluster.
 		kops rolling-update cluster k8s-cluster.example.com --yes \
 		  --instance-group nodes-1a
+
+		# Continue an interrupted rolling update of the k8s-cluster.example.com kOps cluster,
+		# skipping the instances that were already replaced.
+		kops rolling-update cluster k8s-cluster.example.com --yes --resume
+
+		# Discard the progress of an interrupted rolling update, and start a new one.
+		kops rolling-update cluster k8s-cluster.example.com --yes --discard-state
+
+		# Stop the rolling update in progress before it disrupts another instance.
+		kops rolling-update cluster k8s-cluster.example.com --pause
 		`))
 
 	rollingupdateShort = i18n.T(`Rolling update a cluster.`)
@@ -134,6 +144,18 @@ type RollingUpdateOptions struct {
 	// Interactive rolling-update prompts user to continue after each instances is updated.
 	Interactive bool
 
+	// Resume continues an interrupted rolling-update from the progress recorded in the state store.
+	Resume bool
+
+	// DiscardState discards the progress recorded for an interrupted rolling-update, and starts a new one.
+	DiscardState bool
+
+	// WaitForWindow waits for closed maintenance windows to open, instead of stopping.
+	WaitForWindow bool
+
+	// Pause asks an in-progress rolling-update to stop at the next safe point.
+	Pause bool
+
 	ClusterName string
 
 	// InstanceGroups is the list of instance groups to rolling-update;
@@ -161,6 +183,10 @@ func (o *RollingUpdateOptions) InitDefaults() {
 	o.NodeInterval = 15 * time.Second
 	o.BastionInterval = 15 * time.Second
 	o.Interactive = false
+	o.Resume = false
+	o.DiscardState = false
+	o.WaitForWindow = false
+	o.Pause = false
 
 	o.PostDrainDelay = 5 * time.Second
 	o.ValidationTimeout = 15 * time.Minute
@@ -209,6 +235,10 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().DurationVar(&options.BastionInterval, "bastion-interval", options.BastionInterval, "Time to wait between restarting bastions")
 	cmd.Flags().DurationVar(&options.PostDrainDelay, "post-drain-delay", options.PostDrainDelay, "Time to wait after draining each node")
 	cmd.Flags().BoolVarP(&options.Interactive, "interactive", "i", options.Interactive, "Prompt to continue after each instance is updated")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Resume an interrupted rolling update from the progress recorded in the state store")
+	cmd.Flags().BoolVar(&options.DiscardState, "discard-state", options.DiscardState, "Discard the progress recorded for an interrupted rolling update, and start a new rolling update")
+	cmd.Flags().BoolVar(&options.WaitForWindow, "wait-for-window", options.WaitForWindow, "Wait for closed maintenance windows to open, holding the state store lock while waiting, instead of stopping")
+	cmd.Flags().BoolVar(&options.Pause, "pause", options.Pause, "Ask the rolling update in progress to stop before it disrupts another instance")
 	cmd.Flags().StringSliceVar(&options.InstanceGroups, "instance-group", options.InstanceGroups, "Instance groups to update (defaults to all if not specified)")
 	cmd.RegisterFlagCompletionFunc("instance-group", completeInstanceGroup(f, &options.InstanceGroups, &options.InstanceGroupRoles))
 	cmd.Flags().StringSliceVar(&options.InstanceGroupRoles, "instance-group-roles", options.InstanceGroupRoles, "Instance group roles to update ("+strings.Join(allRoles, ",")+")")
@@ -235,6 +265,10 @@ func NewCmdRollingUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 }
 
 func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, options *RollingUpdateOptions) error {
+	if options.Resume && options.DiscardState {
+		return fmt.Errorf("--resume and --discard-state cannot be used together")
+	}
+
 	clientset, err := f.KopsClient()
 	if err != nil {
 		return err
@@ -245,6 +279,24 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return err
 	}
 
+	if options.Pause {
+		configBase, err := clientset.ConfigBaseFor(cluster)
+		if err != nil {
+			return err
+		}
+		paused, err := instancegroups.PauseRollingUpdate(ctx, configBase)
+		if err != nil {
+			return err
+		}
+		if !paused {
+			fmt.Fprintf(out, "No rolling-update in progress.\n")
+			return nil
+		}
+		fmt.Fprintf(out, "Requested that the rolling-update in progress stop before it disrupts another instance.\n")
+		fmt.Fprintf(out, "Use --resume to continue it.\n")
+		return nil
+	}
+
 	var nodes []v1.Node
 	var k8sClient kubernetes.Interface
 	if !options.CloudOnly {
@@ -360,6 +412,9 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		NodeInterval:      options.NodeInterval,
 		BastionInterval:   options.BastionInterval,
 		Interactive:       options.Interactive,
+		Resume:            options.Resume,
+		DiscardState:      options.DiscardState,
+		WaitForWindow:     options.WaitForWindow,
 		Force:             options.Force,
 		Cloud:             cloud,
 		K8sClient:         k8sClient,
@@ -453,6 +508,12 @@ func RunRollingUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer
 		return nil
 	}
 
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "rolling-update cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	var clusterValidator validation.ClusterValidator
 	if !options.CloudOnly {
 		restConfig, err := f.RESTConfig(ctx, cluster, options.CreateKubecfgOptions)
@@ -0,0 +1,143 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/pkg/backup"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBackupCreateLong = templates.LongDesc(i18n.T(`
+	Create a backup of the state of a cluster, from which the control plane can be rebuilt.
+
+	A backup records three things together: a copy of the files in the state store, the latest
+	etcd-manager backups of each etcd cluster, and the versions of the addons installed in the cluster.
+	etcd-manager takes the etcd backups periodically; they are referenced by the backup, not copied.
+
+	Backups are stored in the state store, under backups/kops in the cluster's directory.`))
+
+	toolboxBackupCreateExample = templates.Examples(i18n.T(`
+	# Back up the cluster before an upgrade
+	kops toolbox backup create --name k8s-cluster.example.com --backup-name before-upgrade
+	`))
+
+	toolboxBackupCreateShort = i18n.T(`Create a backup of the state of a cluster.`)
+)
+
+type ToolboxBackupCreateOptions struct {
+	ClusterName string
+
+	// BackupName is the name of the backup; it defaults to the current time
+	BackupName string
+
+	kubeconfig.CreateKubecfgOptions
+}
+
+func NewCmdToolboxBackupCreate(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBackupCreateOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "create [CLUSTER]",
+		Short:             toolboxBackupCreateShort,
+		Long:              toolboxBackupCreateLong,
+		Example:           toolboxBackupCreateExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxBackupCreate(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.BackupName, "backup-name", options.BackupName, "Name of the backup (default: the current time)")
+	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
+
+	return cmd
+}
+
+func RunToolboxBackupCreate(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxBackupCreateOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := f.RESTConfig(ctx, cluster, options.CreateKubecfgOptions)
+	if err != nil {
+		return fmt.Errorf("getting rest config: %w", err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return fmt.Errorf("cannot build kube client: %w", err)
+	}
+
+	namespaces, err := k8sClient.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing namespaces: %w", err)
+	}
+	channelVersions := make(map[string]map[string]*channels.ChannelVersion)
+	for i := range namespaces.Items {
+		ns := &namespaces.Items[i]
+		if versions := channels.FindChannelVersions(ns); len(versions) != 0 {
+			channelVersions[ns.Name] = versions
+		}
+	}
+
+	name := options.BackupName
+	if name == "" {
+		name = backup.NewName(time.Now())
+	}
+
+	// Hold the lock so that the state store is not changed while we copy it
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "toolbox backup create")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	b, err := backup.Create(ctx, f.VFSContext(), configBase, cluster, name, channelVersions)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Created backup %q of %d files in the state store\n", b.Name, len(b.Files))
+	for _, etcd := range b.Etcd {
+		fmt.Fprintf(out, "  etcd cluster %q: backup %s\n", etcd.Cluster, etcd.Backup)
+	}
+	return nil
+}
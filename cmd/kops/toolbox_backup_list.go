@@ -0,0 +1,117 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/backup"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/util/pkg/tables"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBackupListExample = templates.Examples(i18n.T(`
+	# List the backups of a cluster
+	kops toolbox backup list --name k8s-cluster.example.com
+	`))
+
+	toolboxBackupListShort = i18n.T(`List the backups of a cluster.`)
+)
+
+type ToolboxBackupListOptions struct {
+	ClusterName string
+}
+
+func NewCmdToolboxBackupList(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBackupListOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "list [CLUSTER]",
+		Short:             toolboxBackupListShort,
+		Example:           toolboxBackupListExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxBackupList(cmd.Context(), f, out, options)
+		},
+	}
+
+	return cmd
+}
+
+func RunToolboxBackupList(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxBackupListOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	backups, err := backup.List(ctx, configBase)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		fmt.Fprintf(out, "No backups found\n")
+		return nil
+	}
+
+	t := &tables.Table{}
+	t.AddColumn("NAME", func(b *backup.Backup) string {
+		return b.Name
+	})
+	t.AddColumn("CREATED", func(b *backup.Backup) string {
+		return b.Timestamp.Format(time.RFC3339)
+	})
+	t.AddColumn("FILES", func(b *backup.Backup) string {
+		return fmt.Sprintf("%d", len(b.Files))
+	})
+	t.AddColumn("ETCD", func(b *backup.Backup) string {
+		var s []string
+		for _, etcd := range b.Etcd {
+			s = append(s, etcd.Cluster+"="+etcd.Backup)
+		}
+		return strings.Join(s, ",")
+	})
+	t.AddColumn("ADDONS", func(b *backup.Backup) string {
+		n := 0
+		for _, addons := range b.ChannelVersions {
+			n += len(addons)
+		}
+		return fmt.Sprintf("%d", n)
+	})
+	t.AddColumn("KOPS VERSION", func(b *backup.Backup) string {
+		return b.KopsVersion
+	})
+	return t.Render(backups, out, "NAME", "CREATED", "FILES", "ETCD", "ADDONS", "KOPS VERSION")
+}
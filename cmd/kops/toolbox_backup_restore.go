@@ -0,0 +1,242 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/backup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxBackupRestoreLong = templates.LongDesc(i18n.T(`
+	Restore the state of a cluster from a backup.
+
+	The files in the state store are replaced with the copies in the backup, and files created since
+	the backup are deleted. etcd-manager is asked to restore each etcd cluster from the etcd backup
+	referenced by the backup; the restore starts when etcd-manager is restarted. The addons recorded in
+	the etcd backup then match the addon manifests in the restored state store.
+
+	With --yes, the restored configuration is then applied with "kops update cluster", and the control
+	plane nodes are replaced without validation, which restarts etcd-manager. If the cluster had been
+	deleted, the control plane is created from the restored configuration instead.
+
+	This can be used after the cluster has been deleted with "kops delete cluster --keep-backups".
+	Restoring involves downtime of the control plane, and objects created in the cluster since the
+	backup are lost.`))
+
+	toolboxBackupRestoreExample = templates.Examples(i18n.T(`
+	# Preview restoring a backup
+	kops toolbox backup restore --name k8s-cluster.example.com before-upgrade
+
+	# Restore the backup and rebuild the control plane
+	kops toolbox backup restore --name k8s-cluster.example.com before-upgrade --yes
+	`))
+
+	toolboxBackupRestoreShort = i18n.T(`Restore the state of a cluster from a backup.`)
+)
+
+type ToolboxBackupRestoreOptions struct {
+	ClusterName string
+
+	// BackupName is the name of the backup to restore
+	BackupName string
+
+	// Yes must be set to restore the backup; otherwise the changes are only listed
+	Yes bool
+}
+
+func NewCmdToolboxBackupRestore(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxBackupRestoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:     "restore BACKUP",
+		Short:   toolboxBackupRestoreShort,
+		Long:    toolboxBackupRestoreLong,
+		Example: toolboxBackupRestoreExample,
+		Args: func(cmd *cobra.Command, args []string) error {
+			options.ClusterName = rootCommand.ClusterName(true)
+			if options.ClusterName == "" {
+				return fmt.Errorf("--name is required")
+			}
+
+			if len(args) != 1 {
+				return fmt.Errorf("must specify the name of the backup to restore")
+			}
+			options.BackupName = args[0]
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxBackupRestore(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Restore the backup and rebuild the control plane, without --yes the changes are only listed")
+
+	return cmd
+}
+
+func RunToolboxBackupRestore(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxBackupRestoreOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := clientset.GetCluster(ctx, options.ClusterName)
+	if err != nil {
+		return fmt.Errorf("error reading cluster configuration: %v", err)
+	}
+	deleted := cluster == nil
+	if deleted {
+		// The cluster has been deleted with --keep-backups, but its backups are still in the state store
+		cluster = &kopsapi.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: options.ClusterName},
+		}
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	b, err := backup.Get(ctx, configBase, options.BackupName)
+	if err != nil {
+		return err
+	}
+
+	restoreCtx := ctx
+	unlock := func() {}
+	if options.Yes {
+		// Plan the restore under the lock, so that the files we restore and delete are the ones we print
+		restoreCtx, unlock, err = lockClusterState(ctx, clientset, cluster, "toolbox backup restore")
+		if err != nil {
+			return err
+		}
+	}
+	defer func() {
+		unlock()
+	}()
+
+	restore, err := backup.PlanRestore(restoreCtx, f.VFSContext(), configBase, b)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Backup %q was created at %s by kOps %s\n", b.Name, b.Timestamp.Format(time.RFC3339), b.KopsVersion)
+	if len(restore.Write) == 0 && len(restore.Delete) == 0 {
+		fmt.Fprintf(out, "\nThe state store matches the backup\n")
+	}
+	if len(restore.Write) != 0 {
+		fmt.Fprintf(out, "\nWill restore:\n")
+		for _, file := range restore.Write {
+			fmt.Fprintf(out, "  %s\n", file)
+		}
+	}
+	if len(restore.Delete) != 0 {
+		fmt.Fprintf(out, "\nWill delete:\n")
+		for _, file := range restore.Delete {
+			fmt.Fprintf(out, "  %s\n", file)
+		}
+	}
+	if len(b.Etcd) != 0 {
+		fmt.Fprintf(out, "\nWill restore etcd clusters from:\n")
+		for _, etcd := range b.Etcd {
+			fmt.Fprintf(out, "  %s: %s/%s\n", etcd.Cluster, etcd.BackupStore, etcd.Backup)
+		}
+	}
+	if len(b.ChannelVersions) != 0 {
+		fmt.Fprintf(out, "\nAddons installed at the time of the backup:\n")
+		var namespaces []string
+		for ns := range b.ChannelVersions {
+			namespaces = append(namespaces, ns)
+		}
+		sort.Strings(namespaces)
+		for _, ns := range namespaces {
+			var names []string
+			for name := range b.ChannelVersions[ns] {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			for _, name := range names {
+				fmt.Fprintf(out, "  %s/%s: %s\n", ns, name, b.ChannelVersions[ns][name])
+			}
+		}
+	}
+
+	if !options.Yes {
+		fmt.Fprintf(out, "\nMust specify --yes to restore the backup\n")
+		return nil
+	}
+
+	if err := restore.Apply(restoreCtx, f.VFSContext(), cluster); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "\nRestored backup %q.\n", b.Name)
+
+	// update cluster and rolling-update take the lock themselves
+	unlock()
+	unlock = func() {}
+
+	// The restored keys may differ from those in the current kubeconfig.
+	f.ResetClusterClients(cluster)
+
+	fmt.Fprintf(out, "\nApplying the restored configuration\n")
+	{
+		opt := &UpdateClusterOptions{}
+		opt.InitDefaults()
+		opt.ClusterName = options.ClusterName
+		opt.Yes = true
+		if _, err := RunUpdateCluster(ctx, f, out, opt); err != nil {
+			return err
+		}
+	}
+
+	// The control plane of a deleted cluster has just been created, so etcd-manager starts with the restore command.
+	if deleted {
+		return nil
+	}
+
+	// The cluster state in etcd is being replaced, so the control plane can't be validated while it is rolled.
+	fmt.Fprintf(out, "\nReplacing the control plane nodes, so that etcd-manager restores the etcd backups\n")
+	{
+		opt := &RollingUpdateOptions{}
+		opt.InitDefaults()
+		opt.ClusterName = options.ClusterName
+		opt.InstanceGroupRoles = []string{
+			string(kopsapi.InstanceGroupRoleAPIServer),
+			string(kopsapi.InstanceGroupRoleControlPlane),
+		}
+		opt.CloudOnly = true
+		opt.Force = true
+		opt.Yes = true
+		if err := RunRollingUpdateCluster(ctx, f, out, opt); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -52,6 +52,7 @@ func NewCmdToolboxEnroll(f commandutils.Factory, out io.Writer) *cobra.Command {
 	cmd.Flags().IntVar(&options.SSHPort, "ssh-port", options.SSHPort, "port for ssh")
 
 	cmd.Flags().BoolVar(&options.BuildHost, "build-host", options.BuildHost, "only build the host resource, don't apply it or enroll the node")
+	cmd.Flags().BoolVar(&options.TPM, "tpm", options.TPM, "enroll the machine with an attestation key in its TPM instead of a software machine key; requires spec.nodeAttestation.tpm on the cluster and tpm2-tools on the machine")
 
 	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
 
@@ -0,0 +1,161 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxRekeyStateStoreLong = templates.LongDesc(i18n.T(`
+	Rewrite the secrets and private keys of a cluster in the state store, encrypting them with
+	the key set in the cluster's spec.configStore.encryptionKey.
+
+	Run this after setting or changing the encryption key, so that objects written before the change
+	are encrypted with the new key. If the encryption key has been removed, the objects are rewritten
+	in plaintext. The previous key must still be usable to decrypt the existing objects.
+
+	Afterwards, run "kops update cluster --yes" to rewrite the copies of the keys that are read by the cluster.`))
+
+	toolboxRekeyStateStoreExample = templates.Examples(i18n.T(`
+	# Encrypt the secrets and private keys with an AWS KMS key
+	kops edit cluster k8s-cluster.example.com --set spec.configStore.encryptionKey=awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+	kops toolbox rekey-state-store k8s-cluster.example.com --yes
+	kops update cluster k8s-cluster.example.com --yes
+	`))
+
+	toolboxRekeyStateStoreShort = i18n.T(`Re-encrypt the secrets and private keys in the state store.`)
+)
+
+type ToolboxRekeyStateStoreOptions struct {
+	ClusterName string
+
+	// Yes must be set to rewrite the objects; otherwise they are only listed
+	Yes bool
+}
+
+func NewCmdToolboxRekeyStateStore(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxRekeyStateStoreOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "rekey-state-store [CLUSTER]",
+		Short:             toolboxRekeyStateStoreShort,
+		Long:              toolboxRekeyStateStoreLong,
+		Example:           toolboxRekeyStateStoreExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxRekeyStateStore(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Rewrite the secrets and private keys, without --yes they are only listed")
+
+	return cmd
+}
+
+func RunToolboxRekeyStateStore(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxRekeyStateStoreOptions) error {
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+	secretStore, err := clientset.SecretStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return fmt.Errorf("listing keysets: %w", err)
+	}
+	var keysetNames []string
+	for name := range keysets {
+		keysetNames = append(keysetNames, name)
+	}
+	sort.Strings(keysetNames)
+
+	secretNames, err := secretStore.ListSecrets()
+	if err != nil {
+		return fmt.Errorf("listing secrets: %w", err)
+	}
+	sort.Strings(secretNames)
+
+	encryptionKey := cluster.Spec.ConfigStore.EncryptionKey
+	if !options.Yes {
+		if encryptionKey != "" {
+			fmt.Fprintf(out, "Will encrypt with %s:\n", encryptionKey)
+		} else {
+			fmt.Fprintf(out, "No encryption key is set; will write in plaintext:\n")
+		}
+		for _, name := range keysetNames {
+			fmt.Fprintf(out, "  keyset %s\n", name)
+		}
+		for _, name := range secretNames {
+			fmt.Fprintf(out, "  secret %s\n", name)
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to rekey the state store\n")
+		return nil
+	}
+
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "toolbox rekey-state-store")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	for _, name := range keysetNames {
+		if err := keyStore.StoreKeyset(ctx, name, keysets[name]); err != nil {
+			return fmt.Errorf("rewriting keyset %q: %w", name, err)
+		}
+		fmt.Fprintf(out, "Rewrote keyset %s\n", name)
+	}
+
+	for _, name := range secretNames {
+		secret, err := secretStore.FindSecret(name)
+		if err != nil {
+			return fmt.Errorf("reading secret %q: %w", name, err)
+		}
+		if secret == nil {
+			continue
+		}
+		if _, err := secretStore.ReplaceSecret(name, secret); err != nil {
+			return fmt.Errorf("rewriting secret %q: %w", name, err)
+		}
+		fmt.Fprintf(out, "Rewrote secret %s\n", name)
+	}
+
+	fmt.Fprintf(out, "\nRun \"kops update cluster %s --yes\" to rewrite the copies read by the cluster\n", cluster.ObjectMeta.Name)
+	return nil
+}
@@ -0,0 +1,370 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/kops/cmd/kops/util"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/pkg/rotateca"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxRotateCALong = templates.LongDesc(i18n.T(`
+	Rotate the keypairs of one or more keysets, updating and rolling the cluster between each phase.
+
+	The rotation has three phases. The stage phase adds a new keypair to each keyset, which is trusted
+	but not yet used for signing. The promote phase makes the new keypair the primary, used for signing.
+	The distrust phase distrusts the keypairs that are older than the new primary.
+
+	After each phase the cluster is updated and rolled, control plane first, and the rotation
+	verifies that every instance is running the new configuration, that every node is Ready and,
+	when rotating kubernetes-ca, that the cluster's CA bundle trusts exactly the trusted keypairs.
+	The next phase is only started once verification succeeds.
+
+	Progress is recorded in the state store. An interrupted or failed rotation can be continued with --resume.
+
+	Once the kubernetes-ca keypair is promoted, kubeconfig files that were exported before the stage
+	phase no longer work. Use --stop-after=stage to stop after the stage phase, distribute new kubeconfig
+	files with "kops export kubecfg", then continue with --resume.`))
+
+	toolboxRotateCAExample = templates.Examples(i18n.T(`
+	# Rotate all rotatable keysets
+	kops toolbox rotate-ca k8s-cluster.example.com --keyset all --yes
+
+	# Rotate kubernetes-ca, stopping once the new keypair is trusted
+	kops toolbox rotate-ca k8s-cluster.example.com --keyset kubernetes-ca --stop-after stage --yes
+	kops export kubecfg k8s-cluster.example.com
+	kops toolbox rotate-ca k8s-cluster.example.com --resume --yes
+	`))
+
+	toolboxRotateCAShort = i18n.T(`Rotate the keypairs of keysets, rolling the cluster between each phase.`)
+)
+
+type ToolboxRotateCAOptions struct {
+	ClusterName string
+
+	// Keysets is the list of keysets to rotate, or "all" for every rotatable keyset
+	Keysets []string
+
+	// Yes must be set to perform the rotation; otherwise the plan is only printed
+	Yes bool
+
+	// Resume continues a rotation from the progress recorded in the state store.
+	Resume bool
+
+	// StopAfter stops the rotation once the named phase has been verified.
+	StopAfter string
+
+	// VerifyTimeout is how long to wait for the cluster to verify after each rolling update.
+	VerifyTimeout time.Duration
+
+	kubeconfig.CreateKubecfgOptions
+}
+
+func (o *ToolboxRotateCAOptions) InitDefaults() {
+	o.VerifyTimeout = 15 * time.Minute
+	o.Admin = kubeconfig.DefaultKubecfgAdminLifetime
+}
+
+func NewCmdToolboxRotateCA(f *util.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxRotateCAOptions{}
+	options.InitDefaults()
+
+	cmd := &cobra.Command{
+		Use:               "rotate-ca [CLUSTER]",
+		Short:             toolboxRotateCAShort,
+		Long:              toolboxRotateCALong,
+		Example:           toolboxRotateCAExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxRotateCA(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&options.Keysets, "keyset", options.Keysets, "Keysets to rotate, or \"all\" for every rotatable keyset")
+	cmd.RegisterFlagCompletionFunc("keyset", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return completeRotateCAKeyset(cmd.Context(), f, options, args)
+	})
+	cmd.Flags().BoolVarP(&options.Yes, "yes", "y", options.Yes, "Perform the rotation; without --yes the keysets to rotate are only listed")
+	cmd.Flags().BoolVar(&options.Resume, "resume", options.Resume, "Resume the rotation recorded in the state store")
+	cmd.Flags().StringVar(&options.StopAfter, "stop-after", options.StopAfter, "Stop once the given phase (stage or promote) has been verified")
+	cmd.RegisterFlagCompletionFunc("stop-after", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{string(rotateca.PhaseStage), string(rotateca.PhasePromote)}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().DurationVar(&options.VerifyTimeout, "verify-timeout", options.VerifyTimeout, "Maximum time to wait for the cluster to verify after each rolling update")
+
+	options.CreateKubecfgOptions.AddCommonFlags(cmd.Flags())
+
+	return cmd
+}
+
+func RunToolboxRotateCA(ctx context.Context, f *util.Factory, out io.Writer, options *ToolboxRotateCAOptions) error {
+	var stopAfter rotateca.Phase
+	if options.StopAfter != "" {
+		phase, err := rotateca.ParsePhase(options.StopAfter)
+		if err != nil {
+			return err
+		}
+		if phase == rotateca.PhaseDistrust {
+			return fmt.Errorf("cannot stop after the last phase %q", phase)
+		}
+		stopAfter = phase
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	configBase, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	keyStore, err := clientset.KeyStore(cluster)
+	if err != nil {
+		return err
+	}
+
+	keysets, err := resolveRotateCAKeysets(keyStore, options.Keysets)
+	if err != nil {
+		return err
+	}
+
+	state, err := rotateca.ReadRotationState(ctx, configBase)
+	if err != nil {
+		return err
+	}
+
+	if !options.Yes {
+		if state != nil {
+			fmt.Fprintf(out, "CA rotation of %s in progress since %s, in the %s phase\n", strings.Join(state.KeysetNames(), ","), state.StartedAt.Format(time.RFC3339), state.Phase)
+			if state.Error != "" {
+				fmt.Fprintf(out, "Stopped with error: %s\n", state.Error)
+			}
+		} else if len(keysets) != 0 {
+			fmt.Fprintf(out, "Will rotate:\n")
+			for _, name := range keysets {
+				fmt.Fprintf(out, "  keyset %s\n", name)
+			}
+		} else {
+			fmt.Fprintf(out, "No CA rotation in progress; use --keyset to choose the keysets to rotate\n")
+			return nil
+		}
+		fmt.Fprintf(out, "\nMust specify --yes to rotate\n")
+		return nil
+	}
+
+	if !options.Resume && len(keysets) == 0 {
+		return fmt.Errorf("must specify --keyset, or --resume to continue the rotation in progress")
+	}
+
+	rotator := &rotateca.Rotator{
+		ConfigBase: configBase,
+		KeyStore:   keyStore,
+		Cluster: &rotateCACluster{
+			f:       f,
+			out:     out,
+			cluster: cluster,
+			options: options,
+		},
+		Lock: func(ctx context.Context) (context.Context, func(), error) {
+			return lockClusterState(ctx, clientset, cluster, "toolbox rotate-ca")
+		},
+		Out:            out,
+		StopAfter:      stopAfter,
+		VerifyTimeout:  options.VerifyTimeout,
+		VerifyInterval: 30 * time.Second,
+	}
+
+	if options.Resume {
+		err = rotator.Resume(ctx, keysets)
+	} else {
+		err = rotator.Start(ctx, keysets)
+	}
+	if err != nil && !errors.Is(err, rotateca.ErrRotationInProgress) {
+		if state, _ := rotateca.ReadRotationState(ctx, configBase); state != nil && state.Error != "" {
+			return fmt.Errorf("%w\nAfter fixing the problem, use --resume to continue the rotation", err)
+		}
+	}
+	return err
+}
+
+// resolveRotateCAKeysets expands "all" to every rotatable keyset, and checks the named keysets can be rotated.
+func resolveRotateCAKeysets(keyStore fi.CAStore, names []string) ([]string, error) {
+	if !slices.Contains(names, "all") {
+		for _, name := range names {
+			if !rotatableKeysetFilter(name, nil) {
+				return nil, fmt.Errorf("rotating keypairs for %q is not supported", name)
+			}
+		}
+		return names, nil
+	}
+	if len(names) != 1 {
+		return nil, fmt.Errorf("cannot combine \"all\" with other keysets")
+	}
+
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return nil, fmt.Errorf("listing keysets: %w", err)
+	}
+	var resolved []string
+	for name, keyset := range keysets {
+		if rotatableKeysetFilter(name, keyset) {
+			resolved = append(resolved, name)
+		}
+	}
+	sort.Strings(resolved)
+	return resolved, nil
+}
+
+// rotateCACluster updates, rolls and verifies the cluster between the phases of a CA rotation.
+type rotateCACluster struct {
+	f       *util.Factory
+	out     io.Writer
+	cluster *kopsapi.Cluster
+	options *ToolboxRotateCAOptions
+}
+
+var _ rotateca.Cluster = &rotateCACluster{}
+
+func (c *rotateCACluster) Reconcile(ctx context.Context) error {
+	// Each phase changes the CA bundle, so the kubeconfig built for the previous phase is stale.
+	c.f.ResetClusterClients(c.cluster)
+
+	opt := &ReconcileClusterOptions{}
+	opt.InitDefaults()
+	opt.ClusterName = c.cluster.ObjectMeta.Name
+	opt.CreateKubecfgOptions = c.options.CreateKubecfgOptions
+	opt.Yes = true
+	opt.Resume = c.options.Resume
+	return RunReconcileCluster(ctx, c.f, c.out, opt)
+}
+
+func (c *rotateCACluster) Verify(ctx context.Context, keysets map[string]*fi.Keyset) error {
+	clientset, err := c.f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	restConfig, err := c.f.RESTConfig(ctx, c.cluster, c.options.CreateKubecfgOptions)
+	if err != nil {
+		return fmt.Errorf("getting rest config: %w", err)
+	}
+	httpClient, err := c.f.HTTPClient(restConfig)
+	if err != nil {
+		return fmt.Errorf("getting http client: %w", err)
+	}
+	k8sClient, err := kubernetes.NewForConfigAndClient(restConfig, httpClient)
+	if err != nil {
+		return fmt.Errorf("getting kubernetes client: %w", err)
+	}
+
+	nodeList, err := k8sClient.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing nodes: %w", err)
+	}
+
+	list, err := clientset.InstanceGroupsFor(c.cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+	var instanceGroups []*kopsapi.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+
+	cloud, err := cloudup.BuildCloud(c.cluster)
+	if err != nil {
+		return err
+	}
+	groups, err := cloud.GetCloudGroups(c.cluster, instanceGroups, false, nodeList.Items)
+	if err != nil {
+		return err
+	}
+	if err := rotateca.VerifyCloudGroups(groups); err != nil {
+		return err
+	}
+
+	// kube-controller-manager publishes the kubernetes-ca bundle it reads from its node
+	if keyset := keysets[fi.CertificateIDCA]; keyset != nil {
+		configMap, err := k8sClient.CoreV1().ConfigMaps("kube-system").Get(ctx, "kube-root-ca.crt", metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return fmt.Errorf("configmap kube-system/kube-root-ca.crt not found")
+			}
+			return fmt.Errorf("getting configmap kube-system/kube-root-ca.crt: %w", err)
+		}
+		if err := rotateca.VerifyTrustBundle(fi.CertificateIDCA, []byte(configMap.Data["ca.crt"]), keyset); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func completeRotateCAKeyset(ctx context.Context, f commandutils.Factory, options *ToolboxRotateCAOptions, args []string) ([]string, cobra.ShellCompDirective) {
+	commandutils.ConfigureKlogForCompletion()
+
+	cluster, clientSet, completions, directive := GetClusterForCompletion(ctx, f, args)
+	if cluster == nil {
+		return completions, directive
+	}
+
+	keyStore, err := clientSet.KeyStore(cluster)
+	if err != nil {
+		return commandutils.CompletionError("getting keystore", err)
+	}
+	keysets, err := keyStore.ListKeysets()
+	if err != nil {
+		return commandutils.CompletionError("listing keysets", err)
+	}
+
+	completions = []string{"all"}
+	for name, keyset := range keysets {
+		if rotatableKeysetFilter(name, keyset) && !slices.Contains(options.Keysets, name) {
+			completions = append(completions, name)
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
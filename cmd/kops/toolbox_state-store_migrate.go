@@ -0,0 +1,160 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/commands/commandutils"
+	"k8s.io/kubectl/pkg/util/i18n"
+	"k8s.io/kubectl/pkg/util/templates"
+)
+
+var (
+	toolboxStateStoreMigrateLong = templates.LongDesc(i18n.T(`
+	Copy the state of a cluster to another state store, which can use a different backend.
+
+	Everything in the cluster's directory in the state store is copied, including the instance groups,
+	keypairs, secrets, SSH public keys and addons. Each file is read back from the destination and its
+	hash compared with the source. The cluster spec is rewritten so that spec.configStore and the etcd
+	backup stores point to the destination.
+
+	The source state store is not changed. Afterwards, run "kops update cluster --yes" against the
+	destination state store, so that the cluster reads its configuration from there.`))
+
+	toolboxStateStoreMigrateExample = templates.Examples(i18n.T(`
+	# Preview copying a cluster from S3 to GCS
+	kops toolbox state-store migrate k8s-cluster.example.com --state s3://kops-state --to gs://kops-state --dry-run
+
+	# Copy the cluster, then apply it from the new state store
+	kops toolbox state-store migrate k8s-cluster.example.com --state s3://kops-state --to gs://kops-state
+	kops update cluster k8s-cluster.example.com --state gs://kops-state --yes
+	`))
+
+	toolboxStateStoreMigrateShort = i18n.T(`Copy the state of a cluster to another state store.`)
+)
+
+type ToolboxStateStoreMigrateOptions struct {
+	ClusterName string
+
+	// To is the destination state store
+	To string
+
+	// DryRun lists the files that would be copied, without copying them
+	DryRun bool
+}
+
+func NewCmdToolboxStateStoreMigrate(f commandutils.Factory, out io.Writer) *cobra.Command {
+	options := &ToolboxStateStoreMigrateOptions{}
+
+	cmd := &cobra.Command{
+		Use:               "migrate [CLUSTER]",
+		Short:             toolboxStateStoreMigrateShort,
+		Long:              toolboxStateStoreMigrateLong,
+		Example:           toolboxStateStoreMigrateExample,
+		Args:              rootCommand.clusterNameArgs(&options.ClusterName),
+		ValidArgsFunction: commandutils.CompleteClusterName(f, true, false),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return RunToolboxStateStoreMigrate(cmd.Context(), f, out, options)
+		},
+	}
+
+	cmd.Flags().StringVar(&options.To, "to", options.To, "State store to copy the cluster to")
+	cmd.MarkFlagRequired("to")
+	cmd.Flags().BoolVar(&options.DryRun, "dry-run", options.DryRun, "List the files that would be copied, without copying them")
+
+	return cmd
+}
+
+func RunToolboxStateStoreMigrate(ctx context.Context, f commandutils.Factory, out io.Writer, options *ToolboxStateStoreMigrateOptions) error {
+	if options.To == "" {
+		return fmt.Errorf("--to is required")
+	}
+
+	clientset, err := f.KopsClient()
+	if err != nil {
+		return err
+	}
+
+	cluster, err := GetCluster(ctx, f, options.ClusterName)
+	if err != nil {
+		return err
+	}
+
+	from, err := clientset.ConfigBaseFor(cluster)
+	if err != nil {
+		return err
+	}
+
+	to, err := f.VFSContext().BuildVfsPath(options.To)
+	if err != nil {
+		return fmt.Errorf("error parsing state store %q: %w", options.To, err)
+	}
+
+	if !options.DryRun {
+		// Hold the lock so that the cluster isn't changed while we copy it
+		lockedCtx, unlock, err := lockClusterState(ctx, clientset, cluster, "toolbox state-store migrate")
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		ctx = lockedCtx
+	}
+
+	migration, err := vfsclientset.PlanStateMigration(ctx, cluster, from, to)
+	if err != nil {
+		return err
+	}
+
+	if options.DryRun {
+		fmt.Fprintf(out, "Will copy %d files from %s to %s:\n", len(migration.Files), migration.From, migration.To)
+		for _, file := range migration.Files {
+			fmt.Fprintf(out, "  %s\n", file)
+		}
+	}
+	if len(migration.Rewrites) != 0 {
+		fmt.Fprintf(out, "\nCluster spec changes:\n")
+		for _, rewrite := range migration.Rewrites {
+			fmt.Fprintf(out, "  %s\n", rewrite)
+		}
+	}
+	if len(migration.External) != 0 {
+		fmt.Fprintf(out, "\nNot copying paths outside the state store directory of the cluster:\n")
+		for _, external := range migration.External {
+			fmt.Fprintf(out, "  %s\n", external)
+		}
+	}
+	if options.DryRun {
+		return nil
+	}
+
+	fmt.Fprintf(out, "\n")
+	err = migration.Apply(ctx, f.VFSContext(), func(relativePath string) {
+		fmt.Fprintf(out, "Copied %s\n", relativePath)
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "\nCopied and verified %d files from %s to %s\n", len(migration.Files), migration.From, migration.To)
+	fmt.Fprintf(out, "Run \"kops update cluster %s --state %s --yes\" so that the cluster uses the new state store\n", cluster.ObjectMeta.Name, options.To)
+	return nil
+}
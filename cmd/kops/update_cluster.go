@@ -19,9 +19,13 @@ package main
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -31,10 +35,12 @@ import (
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
 	"k8s.io/kops/cmd/kops/util"
 	"k8s.io/kops/pkg/apis/kops"
 	apisutil "k8s.io/kops/pkg/apis/kops/util"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/pkg/predicates"
@@ -57,6 +63,19 @@ var (
 	updateClusterExample = templates.Examples(i18n.T(`
 	# After the cluster has been edited or upgraded, update the cloud resources with:
 	kops update cluster k8s-cluster.example.com --state=s3://my-state-store --yes
+
+	# Print the plan of changes as JSON, with secrets redacted and file contents hashed:
+	kops update cluster k8s-cluster.example.com -o json
+
+	# Save a plan for review, then apply exactly the planned changes:
+	kops update cluster k8s-cluster.example.com --plan-out plan.json
+	kops update cluster k8s-cluster.example.com --yes --plan-in plan.json
+
+	# Only change the security group rules for nodes; other resources are checked but not changed:
+	kops update cluster k8s-cluster.example.com --yes --select 'type=SecurityGroupRule,name=.*nodes.*'
+
+	# Record the execution of each task as newline-delimited JSON:
+	kops update cluster k8s-cluster.example.com --yes --events-output events.ndjson
 	`))
 
 	updateClusterShort = i18n.T("Update a cluster.")
@@ -68,6 +87,9 @@ type UpdateClusterOptions struct {
 	// Reconcile is true if we should reconcile the cluster by rolling the control plane and nodes sequentially
 	Reconcile bool
 
+	// DiffAddons is true if a dry-run should also show the changes to addons in the running cluster
+	DiffAddons bool
+
 	kubeconfig.CreateKubecfgOptions
 	CoreUpdateClusterOptions
 }
@@ -108,6 +130,21 @@ type CoreUpdateClusterOptions struct {
 	// to populate the LifecycleOverrides struct member in ApplyClusterCmd struct.
 	LifecycleOverrides []string
 
+	// Output is the format in which a dry-run prints the plan of changes: json or yaml.
+	// If not set, a human-readable report is printed.
+	Output string
+
+	// PlanOut is the path to which a dry-run saves the plan of changes, for later use with PlanIn.
+	PlanOut string
+	// PlanIn is the path of a plan saved by a dry-run with PlanOut; only the changes in that plan will be applied.
+	PlanIn string
+
+	// Selectors limits the changes to the tasks matching any of the selectors, such as type=SecurityGroupRule,name=nodes.*
+	Selectors []string
+
+	// EventsOutput is the path to which we write a newline-delimited JSON record of each task execution; "-" means stdout.
+	EventsOutput string
+
 	// Prune is true if we should clean up any old revisions of objects.
 	// Typically this is done in after we have rolling-updated the cluster.
 	// The goal is that the cluster can keep running even during more disruptive
@@ -190,8 +227,21 @@ func NewCmdUpdateCluster(f *util.Factory, out io.Writer) *cobra.Command {
 	viper.BindEnv("lifecycle-overrides", "KOPS_LIFECYCLE_OVERRIDES")
 	cmd.RegisterFlagCompletionFunc("lifecycle-overrides", completeLifecycleOverrides)
 
+	cmd.Flags().StringVarP(&options.Output, "output", "o", options.Output, "Output format of the plan of changes when not applying them. One of json or yaml")
+	cmd.RegisterFlagCompletionFunc("output", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{OutputJSON, OutputYaml}, cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringVar(&options.PlanOut, "plan-out", options.PlanOut, "Path to save the plan of changes to, when not applying them")
+	cmd.MarkFlagFilename("plan-out")
+	cmd.Flags().StringVar(&options.PlanIn, "plan-in", options.PlanIn, "Path of a plan saved with --plan-out; the update fails if the changes no longer match the plan")
+	cmd.MarkFlagFilename("plan-in")
+	cmd.Flags().StringArrayVar(&options.Selectors, "select", options.Selectors, "Only change tasks matching the selector, e.g. type=SecurityGroupRule,name=nodes.* or instancegroup=gpu-a; may be repeated")
+	cmd.Flags().StringVar(&options.EventsOutput, "events-output", options.EventsOutput, "Path to write a newline-delimited JSON record of each task execution, or - for stdout, in which case the report of changes is written to stderr")
+	cmd.MarkFlagFilename("events-output")
+
 	cmd.Flags().BoolVar(&options.Prune, "prune", options.Prune, "Delete old revisions of cloud resources that were needed during an upgrade")
 	cmd.Flags().BoolVar(&options.IgnoreKubeletVersionSkew, "ignore-kubelet-version-skew", options.IgnoreKubeletVersionSkew, "Setting this to true will force updating the kubernetes version on all instance groups, regardles of which control plane version is running")
+	cmd.Flags().BoolVar(&options.DiffAddons, "diff-addons", options.DiffAddons, "When not applying changes, also show the changes to addons in the running cluster, using a server-side dry-run")
 
 	return cmd
 }
@@ -256,6 +306,29 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		targetName = cloudup.TargetDryRun
 	}
 
+	switch c.Output {
+	case "", OutputJSON, OutputYaml:
+	default:
+		return nil, fmt.Errorf("unsupported output format: %q", c.Output)
+	}
+	if c.Output != "" && !isDryrun {
+		return nil, fmt.Errorf("--output can only be used when not applying changes")
+	}
+	if c.Output != "" && c.EventsOutput == "-" {
+		return nil, fmt.Errorf("--events-output - cannot be used with --output, as both are written to stdout")
+	}
+	// Only the events are written to stdout, so that they can be parsed; the report of changes goes to stderr
+	eventsOutput := out
+	if c.EventsOutput == "-" {
+		out = os.Stderr
+	}
+	if c.PlanOut != "" && !isDryrun {
+		return nil, fmt.Errorf("--plan-out can only be used when not applying changes")
+	}
+	if c.PlanIn != "" && (isDryrun || c.Target != cloudup.TargetDirect) {
+		return nil, fmt.Errorf("--plan-in can only be used when applying changes with --yes")
+	}
+
 	if c.OutDir == "" {
 		if c.Target == cloudup.TargetTerraform {
 			c.OutDir = "out/terraform"
@@ -274,6 +347,15 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		return results, err
 	}
 
+	if !isDryrun {
+		lockedCtx, unlock, err := lockClusterState(ctx, clientset, cluster, "update cluster")
+		if err != nil {
+			return results, err
+		}
+		defer unlock()
+		ctx = lockedCtx
+	}
+
 	keyStore, err := clientset.KeyStore(cluster)
 	if err != nil {
 		return results, err
@@ -343,6 +425,15 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 		lifecycleOverrideMap[taskName] = lifecycleOverride
 	}
 
+	var taskSelectors []*cloudup.TaskSelector
+	for _, s := range c.Selectors {
+		selector, err := cloudup.ParseTaskSelector(s)
+		if err != nil {
+			return results, err
+		}
+		taskSelectors = append(taskSelectors, selector)
+	}
+
 	var instanceGroupFilters []predicates.Predicate[*kops.InstanceGroup]
 	if len(c.InstanceGroups) != 0 {
 		instanceGroupFilters = append(instanceGroupFilters, matchInstanceGroupNames(c.InstanceGroups))
@@ -364,21 +455,64 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 			klog.V(2).Infof("found control plane running version: %v", minControlPlaneRunningVersion)
 		}
 	}
+	runTasksOptions := c.RunTasksOptions
+	if c.EventsOutput != "" {
+		if c.EventsOutput == "-" {
+			runTasksOptions.EventSink = fi.NewJSONTaskEventSink(eventsOutput)
+		} else {
+			eventsFile, err := os.Create(c.EventsOutput)
+			if err != nil {
+				return results, fmt.Errorf("error creating events output %q: %w", c.EventsOutput, err)
+			}
+			defer eventsFile.Close()
+			runTasksOptions.EventSink = fi.NewJSONTaskEventSink(eventsFile)
+		}
+	}
+
+	var specHash string
+	if c.PlanOut != "" || c.PlanIn != "" {
+		specHash, err = planSpecHash(ctx, clientset, cluster, &c.CoreUpdateClusterOptions)
+		if err != nil {
+			return results, err
+		}
+	}
+
+	var planned *fi.SavedPlan
+	if c.PlanIn != "" {
+		planned, err = readSavedPlan(c.PlanIn)
+		if err != nil {
+			return results, err
+		}
+		if planned.KopsVersion != kopsbase.Version {
+			return results, fmt.Errorf("plan %q was created by kOps %s, but this is kOps %s", c.PlanIn, planned.KopsVersion, kopsbase.Version)
+		}
+		if planned.SpecHash != specHash {
+			return results, fmt.Errorf("cluster has been changed since plan %q was created; please create a new plan", c.PlanIn)
+		}
+	}
+
 	applyCmd := &cloudup.ApplyClusterCmd{
 		Cloud:                      cloud,
 		Clientset:                  clientset,
 		Cluster:                    cluster,
 		DryRun:                     isDryrun,
 		AllowKopsDowngrade:         c.AllowKopsDowngrade,
-		RunTasksOptions:            &c.RunTasksOptions,
+		RunTasksOptions:            &runTasksOptions,
 		OutDir:                     c.OutDir,
 		InstanceGroupFilter:        predicates.AllOf(instanceGroupFilters...),
 		Phase:                      phase,
 		TargetName:                 targetName,
 		LifecycleOverrides:         lifecycleOverrideMap,
+		TaskSelectors:              taskSelectors,
 		GetAssets:                  c.GetAssets,
 		DeletionProcessing:         deletionProcessing,
 		ControlPlaneRunningVersion: minControlPlaneRunningVersion,
+		Plan:                       planned,
+	}
+	if c.Output != "" {
+		applyCmd.DryRunOutput = io.Discard
+	} else if c.EventsOutput == "-" {
+		applyCmd.DryRunOutput = out
 	}
 
 	applyResults, err := applyCmd.Run(ctx)
@@ -394,6 +528,32 @@ func RunUpdateCluster(ctx context.Context, f *util.Factory, out io.Writer, c *Up
 
 	if isDryrun && !c.GetAssets {
 		target := applyCmd.Target.(*fi.CloudupDryRunTarget)
+		if c.PlanOut != "" {
+			saved, err := target.SavedPlan(applyCmd.TaskMap)
+			if err != nil {
+				return results, err
+			}
+			saved.KopsVersion = kopsbase.Version
+			saved.SpecHash = specHash
+			if err := writeSavedPlan(c.PlanOut, saved); err != nil {
+				return results, err
+			}
+			if c.Output == "" {
+				fmt.Fprintf(out, "Plan saved to %q; apply it with --yes --plan-in %s\n", c.PlanOut, c.PlanOut)
+			}
+		}
+		if c.Output != "" {
+			plan, err := target.Plan(applyCmd.TaskMap)
+			if err != nil {
+				return results, err
+			}
+			return results, writePlan(out, c.Output, plan)
+		}
+		if c.DiffAddons {
+			if err := diffAddons(ctx, f, out, cluster, c.CreateKubecfgOptions, applyCmd.TaskMap); err != nil {
+				return results, err
+			}
+		}
 		if target.HasChanges() {
 			fmt.Fprintf(out, "Must specify --yes to apply changes\n")
 		} else {
@@ -664,3 +824,88 @@ func checkControlPlaneRunningVersion(ctx context.Context, clusterName string, ve
 	}
 	return strings.TrimPrefix(version, "v"), nil
 }
+
+// writePlan prints the plan of changes in the requested format
+func writePlan(out io.Writer, format string, plan *fi.Plan) error {
+	var b []byte
+	var err error
+	switch format {
+	case OutputJSON:
+		b, err = json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return fmt.Errorf("error marshaling json: %w", err)
+		}
+		b = append(b, '\n')
+	case OutputYaml:
+		b, err = kops.ToRawYaml(plan)
+		if err != nil {
+			return fmt.Errorf("error marshaling yaml: %w", err)
+		}
+	default:
+		return fmt.Errorf("unsupported output format: %q", format)
+	}
+	if _, err := out.Write(b); err != nil {
+		return fmt.Errorf("error writing plan: %w", err)
+	}
+	return nil
+}
+
+// planSpecHash hashes the cluster and instance group specs, along with the options that determine which changes are made
+func planSpecHash(ctx context.Context, clientset simple.Clientset, cluster *kops.Cluster, c *CoreUpdateClusterOptions) (string, error) {
+	list, err := clientset.InstanceGroupsFor(cluster).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	h := sha256.New()
+	clusterYAML, err := kops.ToRawYaml(cluster)
+	if err != nil {
+		return "", err
+	}
+	h.Write(clusterYAML)
+
+	var instanceGroups []*kops.InstanceGroup
+	for i := range list.Items {
+		instanceGroups = append(instanceGroups, &list.Items[i])
+	}
+	sort.Slice(instanceGroups, func(i, j int) bool {
+		return instanceGroups[i].Name < instanceGroups[j].Name
+	})
+	for _, ig := range instanceGroups {
+		igYAML, err := kops.ToRawYaml(ig)
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte("---\n"))
+		h.Write(igYAML)
+	}
+
+	fmt.Fprintf(h, "phase=%s\nprune=%t\ninstanceGroups=%s\ninstanceGroupRoles=%s\nlifecycleOverrides=%s\nselectors=%s\n",
+		c.Phase, c.Prune, strings.Join(c.InstanceGroups, ","), strings.Join(c.InstanceGroupRoles, ","), strings.Join(c.LifecycleOverrides, ","), strings.Join(c.Selectors, "\x00"))
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readSavedPlan(p string) (*fi.SavedPlan, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return nil, fmt.Errorf("error reading plan %q: %w", p, err)
+	}
+	plan := &fi.SavedPlan{}
+	if err := json.Unmarshal(b, plan); err != nil {
+		return nil, fmt.Errorf("error parsing plan %q: %w", p, err)
+	}
+	return plan, nil
+}
+
+func writeSavedPlan(p string, plan *fi.SavedPlan) error {
+	b, err := json.MarshalIndent(plan, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error marshaling plan: %w", err)
+	}
+	b = append(b, '\n')
+	if err := os.WriteFile(p, b, 0o600); err != nil {
+		return fmt.Errorf("error writing plan %q: %w", p, err)
+	}
+	return nil
+}
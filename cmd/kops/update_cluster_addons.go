@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	channelscmd "k8s.io/kops/channels/pkg/cmd"
+	"k8s.io/kops/cmd/kops/util"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubeconfig"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/bootstrapchannelbuilder"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// addonsDryRunBase is where the rendered addons are placed, so the channels tool can read them without writing to the state store.
+const addonsDryRunBase = "memfs://addons-dry-run/"
+
+// diffAddons shows the changes that applying the rendered addons would make to the running cluster,
+// using the same server-side dry-run as `channels apply channel --dry-run=server`.
+func diffAddons(ctx context.Context, f *util.Factory, out io.Writer, cluster *kops.Cluster, kubecfgOptions kubeconfig.CreateKubecfgOptions, taskMap map[string]fi.CloudupTask) error {
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+
+	channelLocation := ""
+	for _, task := range taskMap {
+		var location *string
+		var contents fi.Resource
+		switch task := task.(type) {
+		case *bootstrapchannelbuilder.AddonManifest:
+			location, contents = task.Location, task.Contents
+		case *bootstrapchannelbuilder.BootstrapChannel:
+			location, contents = task.Location, task.Contents
+			channelLocation = addonsDryRunBase + fi.ValueOf(location)
+		default:
+			continue
+		}
+		if contents == nil {
+			return fmt.Errorf("addon %q was not rendered", fi.ValueOf(location))
+		}
+
+		data, err := fi.ResourceAsBytes(contents)
+		if err != nil {
+			return fmt.Errorf("reading addon %q: %w", fi.ValueOf(location), err)
+		}
+		p, err := vfsContext.BuildVfsPath(addonsDryRunBase + fi.ValueOf(location))
+		if err != nil {
+			return err
+		}
+		if err := p.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+			return fmt.Errorf("writing addon %q: %w", fi.ValueOf(location), err)
+		}
+	}
+	if channelLocation == "" {
+		fmt.Fprintf(out, "\nAddons were not rendered, so addon changes cannot be shown\n")
+		return nil
+	}
+
+	restConfig, err := f.RESTConfig(ctx, cluster, kubecfgOptions)
+	if err != nil {
+		return fmt.Errorf("getting rest config: %w", err)
+	}
+
+	fmt.Fprintf(out, "\nAddon changes:\n")
+	options := &channelscmd.ApplyChannelOptions{
+		DryRun: channelscmd.DryRunServer,
+	}
+	factory := channelscmd.NewChannelsFactoryForConfig(restConfig, vfsContext)
+	if err := channelscmd.RunApplyChannel(ctx, factory, out, options, []string{channelLocation}); err != nil {
+		return fmt.Errorf("showing addon changes: %w", err)
+	}
+	fmt.Fprintf(out, "\n")
+	return nil
+}
@@ -294,6 +294,12 @@ func RunUpgradeCluster(ctx context.Context, f *util.Factory, out io.Writer, opti
 		fmt.Printf("\nMust specify --yes to perform upgrade\n")
 		return nil
 	}
+
+	ctx, unlock, err := lockClusterState(ctx, clientset, cluster, "upgrade cluster")
+	if err != nil {
+		return err
+	}
+	defer unlock()
 	for _, action := range actions {
 		action.apply()
 	}
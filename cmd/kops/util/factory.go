@@ -40,6 +40,7 @@ import (
 	"k8s.io/kops/pkg/kubeconfig"
 	"k8s.io/kops/upup/pkg/fi/cloudup"
 	"k8s.io/kops/util/pkg/vfs"
+	fsacls "k8s.io/kops/util/pkg/vfs/acls/fs"
 	gceacls "k8s.io/kops/util/pkg/vfs/acls/gce"
 )
 
@@ -71,6 +72,7 @@ type clusterInfo struct {
 }
 
 func NewFactory(options *FactoryOptions) *Factory {
+	fsacls.Register()
 	gceacls.Register()
 
 	if options == nil {
@@ -149,7 +151,7 @@ func (f *Factory) KopsClient() (simple.Clientset, error) {
 			f.clientset = vfsclientset.NewVFSClientset(f.VFSContext(), basePath)
 		}
 		if strings.HasPrefix(registryPath, "file://") {
-			klog.Warning("The local filesystem state store is not functional for running clusters")
+			klog.Warning("A local filesystem state store must be mounted at the same path on the control plane hosts of running clusters")
 		}
 	}
 
@@ -178,6 +180,15 @@ func (f *Factory) getClusterInfo(cluster *kops.Cluster, options kubeconfig.Creat
 	return clusterInfo
 }
 
+// ResetClusterClients discards the cached REST config and clients for the cluster,
+// so that they are rebuilt from the current state of the keystore on next use.
+func (f *Factory) ResetClusterClients(cluster *kops.Cluster) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	delete(f.clusters, cluster.ObjectMeta.Name)
+}
+
 func (f *Factory) RESTConfig(ctx context.Context, cluster *kops.Cluster, options kubeconfig.CreateKubecfgOptions) (*rest.Config, error) {
 	clusterInfo := f.getClusterInfo(cluster, options)
 	return clusterInfo.RESTConfig(ctx)
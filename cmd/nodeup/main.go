@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops"
 	"k8s.io/kops/nodeup/pkg/bootstrap"
+	"k8s.io/kops/pkg/externalsigner"
 	"k8s.io/kops/upup/pkg/fi/nodeup"
 )
 
@@ -60,6 +61,9 @@ func main() {
 	flag.Set("logtostderr", "true")
 	flag.Parse()
 
+	// Control plane nodes issue certificates with CAs that can be held in an HSM or cloud KMS
+	externalsigner.Register()
+
 	if dryrun {
 		target = "dryrun"
 	}
@@ -110,6 +110,24 @@ func (i *Installation) buildEnvFile() *nodetasks.InstallFile {
 		}
 	}
 
+	// Pass in the Vault settings of the control plane when the keypairs or secrets are stored in Vault;
+	// the secret ID stays in the file named by VAULT_SECRET_ID_FILE
+	if os.Getenv("VAULT_ADDR") != "" {
+		for _, envVar := range []string{
+			"VAULT_ADDR",
+			"VAULT_NAMESPACE",
+			"VAULT_ROLE_ID",
+			"VAULT_SECRET_ID_FILE",
+			"VAULT_APPROLE_MOUNT",
+			"VAULT_CACERT_BASE64",
+			"VAULT_SKIP_VERIFY",
+		} {
+			if v := os.Getenv(envVar); v != "" {
+				envVars[envVar] = v
+			}
+		}
+	}
+
 	if os.Getenv("OSS_REGION") != "" {
 		envVars["OSS_REGION"] = os.Getenv("OSS_REGION")
 	}
@@ -123,7 +141,7 @@ func (i *Installation) buildEnvFile() *nodetasks.InstallFile {
 		Path:     "/etc/sysconfig/kops-configuration",
 		Contents: fi.NewStringResource(sysconfig),
 		Type:     nodetasks.FileType_File,
-		// The file may contain state store and OpenStack credentials.
+		// The file may contain state store, OpenStack and Vault credentials.
 		Mode: new("0600"),
 	}}
 
@@ -24,8 +24,9 @@ import (
 
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap/attestsigner"
 	"k8s.io/kops/pkg/bootstrap/awsbootstrap"
-	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
 	"k8s.io/kops/pkg/kopscontrollerclient"
 	"k8s.io/kops/pkg/wellknownports"
 	"k8s.io/kops/upup/pkg/fi"
@@ -102,7 +103,7 @@ func (b BootstrapClientBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		authenticator = a
 
 	case kops.CloudProviderMetal:
-		a, err := pkibootstrap.NewAuthenticatorFromFile("/etc/kubernetes/kops/pki/machine/private.pem")
+		a, err := attestsigner.NewMachineAuthenticator(attestbootstrap.MachineDir)
 		if err != nil {
 			return err
 		}
@@ -374,6 +374,29 @@ func (c *NodeupModelContext) BuildPrivateKeyTask(ctx *fi.NodeupModelBuilderConte
 	return c.buildCertificatePairTask(ctx, name, path, filename, owner, beforeServices, false)
 }
 
+// HasExternalPrivateKey returns true if the private key of the keypair used by the node is held by an external signer,
+// such as an HSM or a cloud KMS. The key file then only holds a reference to the key,
+// which can't be used by components other than kOps.
+func (c *NodeupModelContext) HasExternalPrivateKey(ctx *fi.NodeupModelBuilderContext, name string) (bool, error) {
+	keypairID := c.NodeupConfig.KeypairIDs[name]
+	if keypairID == "" {
+		return false, fmt.Errorf("no keypair ID for %q", name)
+	}
+
+	keyset, err := c.KeyStore.FindKeyset(ctx.Context(), name)
+	if err != nil {
+		return false, err
+	}
+	if keyset == nil {
+		return false, fmt.Errorf("keyset %q not found", name)
+	}
+	item := keyset.Items[keypairID]
+	if item == nil {
+		return false, fmt.Errorf("did not find keypair %s for %s", keypairID, name)
+	}
+	return item.PrivateKey.ExternalKeyURI() != "", nil
+}
+
 func (c *NodeupModelContext) buildCertificatePairTask(ctx *fi.NodeupModelBuilderContext, name, path, filename string, owner *string, beforeServices []string, includeCert bool) error {
 	p := filepath.Join(path, filename)
 	if !filepath.IsAbs(p) {
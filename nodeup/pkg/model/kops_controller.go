@@ -17,11 +17,13 @@ limitations under the License.
 package model
 
 import (
+	"os"
 	"path/filepath"
 
 	"k8s.io/kops/pkg/wellknownusers"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/nodeup/nodetasks"
+	"k8s.io/kops/util/pkg/vfs"
 	"sigs.k8s.io/yaml"
 )
 
@@ -106,5 +108,15 @@ func (b *KopsControllerBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		Owner:    s(wellknownusers.KopsControllerName),
 	})
 
+	// The Vault AppRole secret ID is provisioned on the host by the cluster operator; only root and kops-controller can read it
+	if _, err := os.Stat(vfs.VaultSecretIDFile); err == nil {
+		c.AddTask(&nodetasks.File{
+			Path:  vfs.VaultSecretIDFile,
+			Type:  nodetasks.FileType_File,
+			Mode:  s("0400"),
+			Owner: s(wellknownusers.KopsControllerName),
+		})
+	}
+
 	return nil
 }
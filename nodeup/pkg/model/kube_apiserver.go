@@ -154,16 +154,28 @@ func (b *KubeAPIServerBuilder) Build(c *fi.NodeupModelBuilderContext) error {
 		}
 	}
 
-	kubeAPIServer.ServiceAccountKeyFile = append(kubeAPIServer.ServiceAccountKeyFile, filepath.Join(pathSrvKAPI, "service-account.pub"))
-	c.AddTask(&nodetasks.File{
-		Path:     filepath.Join(pathSrvKAPI, "service-account.pub"),
-		Contents: fi.NewStringResource(b.NodeupConfig.APIServerConfig.ServiceAccountPublicKeys),
-		Type:     nodetasks.FileType_File,
-		Mode:     s("0600"),
-	})
+	// kube-apiserver can't sign with a service-account key held by an external signer;
+	// it signs through a plugin instead, which also serves the public keys.
+	externalServiceAccountKey, err := b.HasExternalPrivateKey(c, "service-account")
+	if err != nil {
+		return err
+	}
+	if externalServiceAccountKey && kubeAPIServer.ServiceAccountSigningEndpoint == nil {
+		return fmt.Errorf("the service-account keypair is held by an external signer; set spec.kubeAPIServer.serviceAccountSigningEndpoint to the socket of a service account token signer plugin")
+	}
+
+	if kubeAPIServer.ServiceAccountSigningEndpoint == nil {
+		kubeAPIServer.ServiceAccountKeyFile = append(kubeAPIServer.ServiceAccountKeyFile, filepath.Join(pathSrvKAPI, "service-account.pub"))
+		c.AddTask(&nodetasks.File{
+			Path:     filepath.Join(pathSrvKAPI, "service-account.pub"),
+			Contents: fi.NewStringResource(b.NodeupConfig.APIServerConfig.ServiceAccountPublicKeys),
+			Type:     nodetasks.FileType_File,
+			Mode:     s("0600"),
+		})
+	}
 
 	// Set the signing key if we're using Service Account Token VolumeProjection
-	if kubeAPIServer.ServiceAccountSigningKeyFile == nil {
+	if kubeAPIServer.ServiceAccountSigningKeyFile == nil && kubeAPIServer.ServiceAccountSigningEndpoint == nil {
 		s := filepath.Join(pathSrvKAPI, "service-account.key")
 		kubeAPIServer.ServiceAccountSigningKeyFile = &s
 		if err := b.BuildPrivateKeyTask(c, "service-account", pathSrvKAPI, "service-account", nil, nil); err != nil {
@@ -811,6 +823,11 @@ func (b *KubeAPIServerBuilder) buildPod(ctx context.Context, kubeAPIServer *kops
 		}
 	}
 
+	if kubeAPIServer.ServiceAccountSigningEndpoint != nil {
+		// Mount the directory of the socket, as the plugin may recreate it
+		kubemanifest.AddHostPathMapping(pod, container, "serviceaccountsigner", filepath.Dir(*kubeAPIServer.ServiceAccountSigningEndpoint), kubemanifest.WithReadWrite())
+	}
+
 	if b.NodeupConfig.APIServerConfig.Authentication != nil {
 		if b.NodeupConfig.APIServerConfig.Authentication.Kopeio != nil || b.NodeupConfig.APIServerConfig.Authentication.AWS != nil {
 			kubemanifest.AddHostPathMapping(pod, container, "authn-config", PathAuthnConfig)
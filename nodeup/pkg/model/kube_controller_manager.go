@@ -35,6 +35,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
 )
 
 // KubeControllerManagerBuilder install kube-controller-manager (just the manifest at the moment)
@@ -55,23 +56,38 @@ func (b *KubeControllerManagerBuilder) Build(c *fi.NodeupModelBuilderContext) er
 	kcm := b.NodeupConfig.ControlPlaneConfig.KubeControllerManager
 	kcm.RootCAFile = filepath.Join(b.PathSrvKubernetes(), "ca.crt")
 
-	// Include the CA Key
-	// @TODO: use a per-machine key?  use KMS?
-	if err := b.BuildCertificatePairTask(c, fi.CertificateIDCA, pathSrvKCM, "ca", nil, nil); err != nil {
+	// Include the CA Key, unless it is held by an external signer.
+	// kube-controller-manager can't sign through it, so then doesn't sign CertificateSigningRequests.
+	externalCA, err := b.HasExternalPrivateKey(c, fi.CertificateIDCA)
+	if err != nil {
 		return err
 	}
+	if externalCA {
+		klog.Warningf("the %s keypair is held by an external signer; kube-controller-manager will not sign CertificateSigningRequests", fi.CertificateIDCA)
+	} else {
+		if err := b.BuildCertificatePairTask(c, fi.CertificateIDCA, pathSrvKCM, "ca", nil, nil); err != nil {
+			return err
+		}
+	}
 
-	if err := b.BuildPrivateKeyTask(c, "service-account", pathSrvKCM, "service-account", nil, nil); err != nil {
+	// The service-account key is only used to sign legacy service account token secrets
+	externalServiceAccountKey, err := b.HasExternalPrivateKey(c, "service-account")
+	if err != nil {
 		return err
 	}
-	kcm.ServiceAccountPrivateKeyFile = filepath.Join(pathSrvKCM, "service-account.key")
+	if !externalServiceAccountKey {
+		if err := b.BuildPrivateKeyTask(c, "service-account", pathSrvKCM, "service-account", nil, nil); err != nil {
+			return err
+		}
+		kcm.ServiceAccountPrivateKeyFile = filepath.Join(pathSrvKCM, "service-account.key")
+	}
 
 	if err := b.writeServerCertificate(c, &kcm); err != nil {
 		return err
 	}
 
 	{
-		pod, err := b.buildPod(&kcm)
+		pod, err := b.buildPod(&kcm, !externalCA)
 		if err != nil {
 			return fmt.Errorf("error building kube-controller-manager pod: %v", err)
 		}
@@ -143,7 +159,7 @@ func (b *KubeControllerManagerBuilder) writeServerCertificate(c *fi.NodeupModelB
 }
 
 // buildPod is responsible for building the kubernetes manifest for the controller-manager
-func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerConfig) (*v1.Pod, error) {
+func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerConfig, clusterSigning bool) (*v1.Pod, error) {
 	pathSrvKCM := filepath.Join(b.PathSrvKubernetes(), "kube-controller-manager")
 
 	flags, err := flagbuilder.BuildFlagsList(kcm)
@@ -157,10 +173,12 @@ func (b *KubeControllerManagerBuilder) buildPod(kcm *kops.KubeControllerManagerC
 	}
 
 	// Configure CA certificate to be used to sign keys
-	flags = append(flags, []string{
-		"--cluster-signing-cert-file=" + filepath.Join(pathSrvKCM, "ca.crt"),
-		"--cluster-signing-key-file=" + filepath.Join(pathSrvKCM, "ca.key"),
-	}...)
+	if clusterSigning {
+		flags = append(flags, []string{
+			"--cluster-signing-cert-file=" + filepath.Join(pathSrvKCM, "ca.crt"),
+			"--cluster-signing-key-file=" + filepath.Join(pathSrvKCM, "ca.key"),
+		}...)
+	}
 
 	pod := &v1.Pod{
 		TypeMeta: metav1.TypeMeta{
@@ -17,8 +17,15 @@ limitations under the License.
 package model
 
 import (
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/architectures"
 )
@@ -44,3 +51,44 @@ func TestKubeControllerManagerBuilderARM64(t *testing.T) {
 		return builder.Build(target)
 	})
 }
+
+func TestKubeControllerManagerBuilderExternalCA(t *testing.T) {
+	publicKey, err := x509.MarshalPKIXPublicKey(mustParseCertificate(dummyCertificate).PublicKey)
+	require.NoError(t, err)
+	externalKey := pem.EncodeToMemory(&pem.Block{
+		Type:    "KOPS EXTERNAL PRIVATE KEY",
+		Headers: map[string]string{"URI": "awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"},
+		Bytes:   publicKey,
+	})
+
+	builder := &KubeControllerManagerBuilder{NodeupModelContext: &NodeupModelContext{
+		NodeupConfig: &nodeup.Config{
+			KeypairIDs: map[string]string{
+				fi.CertificateIDCA: "3",
+				"service-account":  "3",
+			},
+		},
+		KeyStore: &fakeKeystore{
+			T: t,
+			privateKeysets: map[string]*kops.Keyset{
+				fi.CertificateIDCA: simplePrivateKeyset(dummyCertificate, string(externalKey)),
+				"service-account":  simplePrivateKeyset(dummyCertificate, dummyKey),
+			},
+		},
+	}}
+	c := &fi.NodeupModelBuilderContext{Tasks: make(map[string]fi.NodeupTask)}
+
+	external, err := builder.HasExternalPrivateKey(c, fi.CertificateIDCA)
+	require.NoError(t, err)
+	assert.True(t, external)
+	external, err = builder.HasExternalPrivateKey(c, "service-account")
+	require.NoError(t, err)
+	assert.False(t, external)
+
+	for _, clusterSigning := range []bool{true, false} {
+		pod, err := builder.buildPod(&kops.KubeControllerManagerConfig{}, clusterSigning)
+		require.NoError(t, err)
+		args := strings.Join(pod.Spec.Containers[0].Command, " ") + strings.Join(pod.Spec.Containers[0].Args, " ")
+		assert.Equal(t, clusterSigning, strings.Contains(args, "--cluster-signing-key-file="))
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// MaintenanceWindow is a parsed MaintenanceWindowSpec
+// +k8s:deepcopy-gen=false
+type MaintenanceWindow struct {
+	// days holds the days of the week on which the window opens
+	days [7]bool
+	// start and end are the offsets into the day at which the window opens and closes
+	start time.Duration
+	end   time.Duration
+	// location is the time zone of start and end
+	location *time.Location
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseMaintenanceWindow parses a MaintenanceWindowSpec
+func ParseMaintenanceWindow(spec *MaintenanceWindowSpec) (*MaintenanceWindow, error) {
+	w := &MaintenanceWindow{
+		location: time.UTC,
+	}
+
+	if spec.TimeZone != "" {
+		location, err := time.LoadLocation(spec.TimeZone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time zone %q: %w", spec.TimeZone, err)
+		}
+		w.location = location
+	}
+
+	if len(spec.Days) == 0 {
+		for i := range w.days {
+			w.days[i] = true
+		}
+	}
+	for _, days := range spec.Days {
+		if err := w.parseDays(days); err != nil {
+			return nil, err
+		}
+	}
+
+	var err error
+	if w.start, err = parseTimeOfDay(spec.StartTime); err != nil {
+		return nil, fmt.Errorf("invalid start time: %w", err)
+	}
+	if w.end, err = parseTimeOfDay(spec.EndTime); err != nil {
+		return nil, fmt.Errorf("invalid end time: %w", err)
+	}
+
+	return w, nil
+}
+
+// parseDays parses a single day ("Mon") or a range of days ("Mon-Fri")
+func (w *MaintenanceWindow) parseDays(s string) error {
+	first, last, isRange := strings.Cut(s, "-")
+	from, ok := weekdays[strings.ToLower(strings.TrimSpace(first))]
+	if !ok {
+		return fmt.Errorf("invalid day %q", s)
+	}
+	to := from
+	if isRange {
+		to, ok = weekdays[strings.ToLower(strings.TrimSpace(last))]
+		if !ok {
+			return fmt.Errorf("invalid day %q", s)
+		}
+	}
+	for d := from; ; d = (d + 1) % 7 {
+		w.days[d] = true
+		if d == to {
+			break
+		}
+	}
+	return nil
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, fmt.Errorf("must be specified")
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not in HH:MM format", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// IsOpen returns true if the window is open at time t
+func (w *MaintenanceWindow) IsOpen(t time.Time) bool {
+	t = t.In(w.location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.location)
+
+	// The window may have opened today, or (if it spans midnight) yesterday
+	for _, day := range []time.Time{midnight, midnight.AddDate(0, 0, -1)} {
+		if !w.days[day.Weekday()] {
+			continue
+		}
+		opens, closes := w.boundsFrom(day)
+		if !t.Before(opens) && t.Before(closes) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns the earliest time at or after t at which the window is open
+func (w *MaintenanceWindow) NextOpen(t time.Time) time.Time {
+	if w.IsOpen(t) {
+		return t
+	}
+	t = t.In(w.location)
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, w.location)
+	for i := 0; i <= 7; i++ {
+		day := midnight.AddDate(0, 0, i)
+		if !w.days[day.Weekday()] {
+			continue
+		}
+		opens, _ := w.boundsFrom(day)
+		if opens.After(t) {
+			return opens
+		}
+	}
+	// Not reached if at least one day is set
+	return t
+}
+
+// boundsFrom returns the times at which the window opens and closes, for the window opening on the given day
+func (w *MaintenanceWindow) boundsFrom(midnight time.Time) (time.Time, time.Time) {
+	// We use time.Date rather than adding durations, so that daylight saving transitions are respected
+	y, m, d := midnight.Date()
+	opens := time.Date(y, m, d, 0, int(w.start/time.Minute), 0, 0, w.location)
+	if w.end <= w.start {
+		d++
+	}
+	closes := time.Date(y, m, d, 0, int(w.end/time.Minute), 0, 0, w.location)
+	return opens, closes
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kops
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseMaintenanceWindowErrors(t *testing.T) {
+	grid := []struct {
+		name string
+		spec MaintenanceWindowSpec
+	}{
+		{
+			name: "missing times",
+			spec: MaintenanceWindowSpec{},
+		},
+		{
+			name: "bad time",
+			spec: MaintenanceWindowSpec{StartTime: "25:00", EndTime: "06:00"},
+		},
+		{
+			name: "bad day",
+			spec: MaintenanceWindowSpec{Days: []string{"Mon-Someday"}, StartTime: "22:00", EndTime: "06:00"},
+		},
+		{
+			name: "bad time zone",
+			spec: MaintenanceWindowSpec{StartTime: "22:00", EndTime: "06:00", TimeZone: "Nowhere/Special"},
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			if _, err := ParseMaintenanceWindow(&g.spec); err == nil {
+				t.Errorf("expected error parsing %+v", g.spec)
+			}
+		})
+	}
+}
+
+func TestMaintenanceWindow(t *testing.T) {
+	// 2026-10-16 is a Friday
+	friday := func(hour, minute int) time.Time {
+		return time.Date(2026, 10, 16, hour, minute, 0, 0, time.UTC)
+	}
+
+	grid := []struct {
+		name     string
+		spec     MaintenanceWindowSpec
+		at       time.Time
+		open     bool
+		nextOpen time.Time
+	}{
+		{
+			name: "every day, inside",
+			spec: MaintenanceWindowSpec{StartTime: "09:00", EndTime: "17:00"},
+			at:   friday(12, 0),
+			open: true,
+		},
+		{
+			name:     "every day, before",
+			spec:     MaintenanceWindowSpec{StartTime: "09:00", EndTime: "17:00"},
+			at:       friday(8, 30),
+			nextOpen: friday(9, 0),
+		},
+		{
+			name:     "every day, at close",
+			spec:     MaintenanceWindowSpec{StartTime: "09:00", EndTime: "17:00"},
+			at:       friday(17, 0),
+			nextOpen: friday(9, 0).AddDate(0, 0, 1),
+		},
+		{
+			name: "spanning midnight, after midnight",
+			spec: MaintenanceWindowSpec{Days: []string{"Thu"}, StartTime: "22:00", EndTime: "06:00"},
+			at:   friday(3, 0),
+			open: true,
+		},
+		{
+			name:     "spanning midnight, on a day the window does not open",
+			spec:     MaintenanceWindowSpec{Days: []string{"Thu"}, StartTime: "22:00", EndTime: "06:00"},
+			at:       friday(23, 0),
+			nextOpen: time.Date(2026, 10, 22, 22, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "weekend range",
+			spec:     MaintenanceWindowSpec{Days: []string{"Sat-Sun"}, StartTime: "00:00", EndTime: "00:00"},
+			at:       friday(12, 0),
+			nextOpen: time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "range wrapping the end of the week",
+			spec: MaintenanceWindowSpec{Days: []string{"Fri-Mon"}, StartTime: "00:00", EndTime: "00:00"},
+			at:   friday(12, 0),
+			open: true,
+		},
+		{
+			name: "time zone",
+			spec: MaintenanceWindowSpec{StartTime: "09:00", EndTime: "10:00", TimeZone: "Asia/Tokyo"},
+			at:   time.Date(2026, 10, 16, 0, 30, 0, 0, time.UTC),
+			open: true,
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			w, err := ParseMaintenanceWindow(&g.spec)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if open := w.IsOpen(g.at); open != g.open {
+				t.Errorf("IsOpen(%v) = %v, expected %v", g.at, open, g.open)
+			}
+			expectedNextOpen := g.nextOpen
+			if g.open {
+				expectedNextOpen = g.at
+			}
+			if nextOpen := w.NextOpen(g.at); !nextOpen.Equal(expectedNextOpen) {
+				t.Errorf("NextOpen(%v) = %v, expected %v", g.at, nextOpen, expectedNextOpen)
+			}
+		})
+	}
+}
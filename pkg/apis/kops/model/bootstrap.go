@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package model
+
+import (
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// kubernetesCA is the keyset of the cluster's general CA (kubernetesCA, which we cannot import here).
+const kubernetesCA = "kubernetes-ca"
+
+// KopsControllerCertNames returns the names of the certificates that nodes request from kops-controller when they bootstrap.
+func KopsControllerCertNames(cluster *kops.Cluster) []string {
+	certNames := []string{"kubelet", "kubelet-server"}
+	if UseCiliumEtcd(cluster) {
+		certNames = append(certNames, "etcd-client-cilium")
+	}
+	if cluster.Spec.KubeProxy.Enabled == nil || *cluster.Spec.KubeProxy.Enabled {
+		certNames = append(certNames, "kube-proxy")
+	}
+	if cluster.Spec.Networking.KubeRouter != nil {
+		certNames = append(certNames, "kube-router")
+	}
+	return certNames
+}
+
+// KopsControllerSigningCAs returns the keysets that kops-controller signs the certificates of bootstrapping nodes with.
+func KopsControllerSigningCAs(cluster *kops.Cluster) []string {
+	signingCAs := []string{kubernetesCA}
+	if UseCiliumEtcd(cluster) {
+		signingCAs = append(signingCAs, "etcd-clients-ca-cilium")
+	}
+	return signingCAs
+}
+
+// KopsControllerCertSigner returns the keyset that kops-controller signs the named certificate with.
+func KopsControllerCertSigner(certName string) string {
+	switch certName {
+	case "etcd-client-cilium":
+		return "etcd-clients-ca-cilium"
+	default:
+		return kubernetesCA
+	}
+}
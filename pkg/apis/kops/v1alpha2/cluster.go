@@ -49,8 +49,10 @@ type ClusterSpec struct {
 	// The Channel we are following
 	Channel string `json:"channel,omitempty"`
 	// Additional addons that should be installed on the cluster
-	Addons      []AddonSpec          `json:"addons,omitempty"`
-	ConfigStore kops.ConfigStoreSpec `json:"-"`
+	Addons []AddonSpec `json:"addons,omitempty"`
+	// AddonPatches patch the manifests of the addons managed by kOps
+	AddonPatches []AddonPatchSpec     `json:"addonPatches,omitempty"`
+	ConfigStore  kops.ConfigStoreSpec `json:"-"`
 	// ConfigBase is the path where we store configuration for the cluster
 	// This might be different that the location when the cluster spec itself is stored,
 	// both because this must be accessible to the cluster,
@@ -104,6 +106,11 @@ type ClusterSpec struct {
 	// KeyStore is the VFS path to where SSL keys and certificates are stored
 	// +k8s:conversion-gen=false
 	KeyStore string `json:"keyStore,omitempty"`
+	// StateEncryptionKey is the URI of the key used to encrypt secrets and private keys written to the state store,
+	// awskms://<key ARN> on AWS, gcpkms://<key name> on GCE, or vaulttransit://<mount>/<key> for a Vault transit key.
+	// Objects written before the key was set remain in plaintext until "kops toolbox rekey-state-store" is run.
+	// +k8s:conversion-gen=false
+	StateEncryptionKey string `json:"stateEncryptionKey,omitempty"`
 	// ConfigStore is unused.
 	// +k8s:conversion-gen=false
 	LegacyConfigStore string `json:"configStore,omitempty"`
@@ -218,6 +225,10 @@ type ClusterSpec struct {
 	Authorization *AuthorizationSpec `json:"authorization,omitempty"`
 	// NodeAuthorization defined the custom node authorization configuration
 	NodeAuthorization *NodeAuthorizationSpec `json:"nodeAuthorization,omitempty"`
+	// NodeAttestation configures hardware-rooted authentication of enrolled machines to kops-controller.
+	NodeAttestation *NodeAttestationSpec `json:"nodeAttestation,omitempty"`
+	// KopsController configures kops-controller.
+	KopsController *KopsControllerSpec `json:"kopsController,omitempty"`
 	// CloudLabels defines additional tags or labels on cloud provider resources
 	CloudLabels map[string]string `json:"cloudLabels,omitempty"`
 	// Hooks for custom actions e.g. on first installation
@@ -337,10 +348,118 @@ type NodeAuthorizerSpec struct {
 	TokenTTL *metav1.Duration `json:"tokenTTL,omitempty"`
 }
 
+// NodeAttestationSpec configures how machines enrolled with `kops toolbox enroll` authenticate to kops-controller
+// using hardware-rooted or workload identities, rather than a machine key on disk.
+type NodeAttestationSpec struct {
+	// TPM accepts TPM 2.0 quotes signed by an attestation key that was registered when the host was enrolled.
+	TPM *TPMAttestationSpec `json:"tpm,omitempty"`
+	// SPIFFE accepts SPIFFE X.509-SVIDs issued by a trusted SPIFFE trust domain, for example by a SPIRE agent.
+	SPIFFE *SPIFFEAttestationSpec `json:"spiffe,omitempty"`
+}
+
+// TPMAttestationSpec configures TPM 2.0 quote verification.
+type TPMAttestationSpec struct {
+	// PCRs maps the index of a SHA-256 PCR to its expected hex-encoded value.
+	// Quotes with any other value for a listed PCR are rejected.
+	PCRs map[string]string `json:"pcrs,omitempty"`
+}
+
+// SPIFFEAttestationSpec configures SPIFFE X.509-SVID verification.
+type SPIFFEAttestationSpec struct {
+	// TrustDomain is the SPIFFE trust domain that node SVIDs must belong to, e.g. example.org.
+	TrustDomain string `json:"trustDomain,omitempty"`
+	// TrustBundle holds the PEM-encoded X.509 authorities of the trust domain.
+	TrustBundle string `json:"trustBundle,omitempty"`
+	// PathPrefix is the path under which node SPIFFE IDs are issued; it defaults to /kops.
+	// Node SVIDs must have the SPIFFE ID spiffe://<trustDomain><pathPrefix>/<instanceGroup>/<nodeName>.
+	PathPrefix string `json:"pathPrefix,omitempty"`
+}
+
+// KopsControllerSpec configures kops-controller.
+type KopsControllerSpec struct {
+	// Bootstrap limits the credentials kops-controller issues to bootstrapping nodes.
+	Bootstrap *KopsControllerBootstrapSpec `json:"bootstrap,omitempty"`
+	// Metrics configures the Prometheus metrics of kops-controller.
+	Metrics *KopsControllerMetricsSpec `json:"metrics,omitempty"`
+}
+
+// KopsControllerMetricsSpec configures the Prometheus metrics of kops-controller.
+type KopsControllerMetricsSpec struct {
+	// Expose serves the metrics on port 4007 of the IP address of the control plane node, so that Prometheus can scrape them.
+	// By default the metrics are only served on the loopback interface of the control plane node.
+	Expose *bool `json:"expose,omitempty"`
+	// CertificateExpiryHorizon is how far ahead the certificate expiry metrics flag certificates as expiring. (default 720h)
+	CertificateExpiryHorizon *metav1.Duration `json:"certificateExpiryHorizon,omitempty"`
+}
+
+// KopsControllerBootstrapSpec limits the credentials kops-controller issues to bootstrapping nodes,
+// containing the damage a compromised node verifier can do. Each kops-controller replica applies the limits independently.
+type KopsControllerBootstrapSpec struct {
+	// RequestsPerMinute is the number of bootstrap requests accepted per minute, from all nodes together.
+	// 0 disables the limit. (default 600)
+	RequestsPerMinute *int32 `json:"requestsPerMinute,omitempty"`
+	// Burst is the number of bootstrap requests accepted at once, from all nodes together. (default 200)
+	Burst *int32 `json:"burst,omitempty"`
+	// NodeRequestsPerMinute is the number of bootstrap requests accepted per minute for each node name.
+	// 0 disables the limit. (default 6)
+	NodeRequestsPerMinute *int32 `json:"nodeRequestsPerMinute,omitempty"`
+	// NodeBurst is the number of bootstrap requests accepted at once for each node name. (default 10)
+	NodeBurst *int32 `json:"nodeBurst,omitempty"`
+	// MinReissueInterval, if set, rejects requests for certificates for a node name that was issued certificates more recently than this.
+	MinReissueInterval *metav1.Duration `json:"minReissueInterval,omitempty"`
+}
+
 // AddonSpec defines an addon that we want to install in the cluster
 type AddonSpec struct {
 	// Manifest is a path to the manifest that defines the addon
 	Manifest string `json:"manifest,omitempty"`
+	// Chart is a Helm chart that is rendered to define the addon, instead of a manifest
+	Chart *HelmChartSpec `json:"chart,omitempty"`
+}
+
+// HelmChartSpec defines a Helm chart that is installed as an addon
+type HelmChartSpec struct {
+	// Repository is the location of the chart repository: an OCI registry (oci://), an HTTP(S) chart repository,
+	// a VFS path holding <name>-<version>.tgz archives, or a local directory holding the chart in <name>/
+	Repository string `json:"repository,omitempty"`
+	// Name is the name of the chart
+	Name string `json:"name,omitempty"`
+	// Version is the exact version of the chart
+	Version string `json:"version,omitempty"`
+	// ReleaseName is the name of the release; it defaults to the name of the chart
+	ReleaseName string `json:"releaseName,omitempty"`
+	// Namespace is the namespace the chart is installed in; it defaults to kube-system
+	Namespace string `json:"namespace,omitempty"`
+	// Values is a YAML document overriding the default values of the chart
+	Values string `json:"values,omitempty"`
+}
+
+// AddonPatchSpec patches objects in the manifest of an addon managed by kOps.
+// Patches are applied when the manifest is built, so they are reapplied whenever the addon is updated.
+type AddonPatchSpec struct {
+	// Addon is the name of the addon, such as coredns.addons.k8s.io
+	Addon string `json:"addon,omitempty"`
+	// Target selects the object in the manifest to patch
+	Target AddonPatchTarget `json:"target,omitempty"`
+	// StrategicMerge is a strategic-merge patch, as YAML.
+	// Kinds that are not built into Kubernetes are patched with a JSON merge patch instead.
+	StrategicMerge string `json:"strategicMerge,omitempty"`
+	// JSON6902 is a JSON patch (RFC 6902), as a YAML or JSON list of operations
+	JSON6902 string `json:"json6902,omitempty"`
+}
+
+// AddonPatchTarget selects an object in an addon manifest
+type AddonPatchTarget struct {
+	// Group is the API group of the object; empty for the core group
+	Group string `json:"group,omitempty"`
+	// Version is the API version of the object; if empty, any version matches
+	Version string `json:"version,omitempty"`
+	// Kind is the kind of the object
+	Kind string `json:"kind,omitempty"`
+	// Namespace is the namespace of the object; if empty, any namespace matches
+	Namespace string `json:"namespace,omitempty"`
+	// Name is the name of the object
+	Name string `json:"name,omitempty"`
 }
 
 // FileAssetSpec defines the structure for a file asset
@@ -890,6 +1009,75 @@ type RollingUpdate struct {
 	// nodes.
 	// +optional
 	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+	// MaintenanceWindow restricts node disruption to the specified days and hours.
+	// When the window is closed, the rolling update stops between instances, to be resumed
+	// once the window opens again.
+	// +optional
+	MaintenanceWindow *MaintenanceWindowSpec `json:"maintenanceWindow,omitempty"`
+	// ValidationGates are additional checks that must pass for the cluster to validate.
+	// Gates in an InstanceGroup's spec are attributed to that InstanceGroup, so they
+	// must pass between node replacements when it is updated.
+	// +optional
+	ValidationGates []ValidationGateSpec `json:"validationGates,omitempty"`
+}
+
+// ValidationGateSpec is an additional check that must pass for the cluster to validate.
+// Exactly one of Prometheus, HTTP or Workload must be set.
+type ValidationGateSpec struct {
+	// Name identifies the gate in validation failures.
+	Name string `json:"name"`
+	// Prometheus passes when a PromQL query returns a true value.
+	Prometheus *PrometheusValidationGateSpec `json:"prometheus,omitempty"`
+	// HTTP passes when an HTTP endpoint responds with a 2xx status code.
+	HTTP *HTTPValidationGateSpec `json:"http,omitempty"`
+	// Workload passes when a workload has enough available replicas.
+	Workload *WorkloadValidationGateSpec `json:"workload,omitempty"`
+}
+
+// PrometheusValidationGateSpec is a validation gate backed by a PromQL query.
+type PrometheusValidationGateSpec struct {
+	// URL is the base URL of the Prometheus API, for example "https://prometheus.example.com".
+	URL string `json:"url"`
+	// Query is the PromQL query to evaluate.
+	// The gate passes when the query returns at least one sample and every sample is non-zero.
+	Query string `json:"query"`
+}
+
+// HTTPValidationGateSpec is a validation gate backed by an HTTP endpoint.
+type HTTPValidationGateSpec struct {
+	// URL is the URL to send a GET request to.
+	URL string `json:"url"`
+	// Timeout is the maximum time to wait for a response. Defaults to 10s.
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// WorkloadValidationGateSpec is a validation gate backed by the available replicas of a workload.
+type WorkloadValidationGateSpec struct {
+	// Kind is the kind of the workload: Deployment, StatefulSet or DaemonSet.
+	Kind string `json:"kind"`
+	// Namespace is the namespace of the workload.
+	Namespace string `json:"namespace"`
+	// Name is the name of the workload.
+	Name string `json:"name"`
+	// MinAvailable is the minimum number of available replicas.
+	// The value can be an absolute number (for example 5) or a percentage of
+	// desired replicas (for example 90%), which is rounded up.
+	MinAvailable *intstr.IntOrString `json:"minAvailable"`
+}
+
+// MaintenanceWindowSpec is a recurring period of time during which nodes may be disrupted.
+type MaintenanceWindowSpec struct {
+	// Days is the list of days of the week on which the window opens,
+	// for example "Sat", "Sun" or "Mon-Fri". Defaults to every day.
+	Days []string `json:"days,omitempty"`
+	// StartTime is the time of day at which the window opens, in "HH:MM" format.
+	StartTime string `json:"startTime,omitempty"`
+	// EndTime is the time of day at which the window closes, in "HH:MM" format.
+	// If EndTime is not after StartTime, the window closes on the following day.
+	EndTime string `json:"endTime,omitempty"`
+	// TimeZone is the IANA name of the time zone of StartTime and EndTime,
+	// for example "Europe/Berlin". Defaults to UTC.
+	TimeZone string `json:"timeZone,omitempty"`
 }
 
 type PackagesConfig struct {
@@ -100,6 +100,7 @@ func Convert_v1alpha2_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *kops
 	}
 	out.ConfigStore.Secrets = in.SecretStore
 	out.ConfigStore.Keypairs = in.KeyStore
+	out.ConfigStore.EncryptionKey = in.StateEncryptionKey
 	if in.KubeAPIServer != nil {
 		kube := in.KubeAPIServer
 		if kube.OIDCClientID != nil ||
@@ -412,6 +413,7 @@ func Convert_kops_ClusterSpec_To_v1alpha2_ClusterSpec(in *kops.ClusterSpec, out
 	}
 	out.ConfigBase = in.ConfigStore.Base
 	out.KeyStore = in.ConfigStore.Keypairs
+	out.StateEncryptionKey = in.ConfigStore.EncryptionKey
 	out.SecretStore = in.ConfigStore.Secrets
 	if in.ExternalPolicies != nil {
 		out.ExternalPolicies = make(map[string][]string, len(in.ExternalPolicies))
@@ -530,6 +530,10 @@ type KubeAPIServerConfig struct {
 	// The issuer will sign issued ID tokens with this private key. (Requires the 'TokenRequest' feature gate.)
 	ServiceAccountSigningKeyFile *string `json:"serviceAccountSigningKeyFile,omitempty" flag:"service-account-signing-key-file"`
 
+	// ServiceAccountSigningEndpoint is the path to the unix socket of an external service account token signer plugin.
+	// It is required when the private key of the service-account keyset is held by an external signer.
+	ServiceAccountSigningEndpoint *string `json:"serviceAccountSigningEndpoint,omitempty" flag:"service-account-signing-endpoint"`
+
 	// Identifier of the service account token issuer. The issuer will assert this identifier
 	// in "iss" claim of issued tokens. This value is a string or URI.
 	ServiceAccountIssuer *string `json:"serviceAccountIssuer,omitempty" flag:"service-account-issuer"`
@@ -104,6 +104,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*AddonPatchSpec)(nil), (*kops.AddonPatchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_AddonPatchSpec_To_kops_AddonPatchSpec(a.(*AddonPatchSpec), b.(*kops.AddonPatchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AddonPatchSpec)(nil), (*AddonPatchSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AddonPatchSpec_To_v1alpha3_AddonPatchSpec(a.(*kops.AddonPatchSpec), b.(*AddonPatchSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*AddonPatchTarget)(nil), (*kops.AddonPatchTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_AddonPatchTarget_To_kops_AddonPatchTarget(a.(*AddonPatchTarget), b.(*kops.AddonPatchTarget), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.AddonPatchTarget)(nil), (*AddonPatchTarget)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_AddonPatchTarget_To_v1alpha3_AddonPatchTarget(a.(*kops.AddonPatchTarget), b.(*AddonPatchTarget), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*AddonSpec)(nil), (*kops.AddonSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_AddonSpec_To_kops_AddonSpec(a.(*AddonSpec), b.(*kops.AddonSpec), scope)
 	}); err != nil {
@@ -614,6 +634,26 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*HTTPValidationGateSpec)(nil), (*kops.HTTPValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_HTTPValidationGateSpec_To_kops_HTTPValidationGateSpec(a.(*HTTPValidationGateSpec), b.(*kops.HTTPValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HTTPValidationGateSpec)(nil), (*HTTPValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HTTPValidationGateSpec_To_v1alpha3_HTTPValidationGateSpec(a.(*kops.HTTPValidationGateSpec), b.(*HTTPValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*HelmChartSpec)(nil), (*kops.HelmChartSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_HelmChartSpec_To_kops_HelmChartSpec(a.(*HelmChartSpec), b.(*kops.HelmChartSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.HelmChartSpec)(nil), (*HelmChartSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_HelmChartSpec_To_v1alpha3_HelmChartSpec(a.(*kops.HelmChartSpec), b.(*HelmChartSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*HetznerSpec)(nil), (*kops.HetznerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_HetznerSpec_To_kops_HetznerSpec(a.(*HetznerSpec), b.(*kops.HetznerSpec), scope)
 	}); err != nil {
@@ -844,6 +884,36 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*KopsControllerBootstrapSpec)(nil), (*kops.KopsControllerBootstrapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_KopsControllerBootstrapSpec_To_kops_KopsControllerBootstrapSpec(a.(*KopsControllerBootstrapSpec), b.(*kops.KopsControllerBootstrapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KopsControllerBootstrapSpec)(nil), (*KopsControllerBootstrapSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KopsControllerBootstrapSpec_To_v1alpha3_KopsControllerBootstrapSpec(a.(*kops.KopsControllerBootstrapSpec), b.(*KopsControllerBootstrapSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KopsControllerMetricsSpec)(nil), (*kops.KopsControllerMetricsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_KopsControllerMetricsSpec_To_kops_KopsControllerMetricsSpec(a.(*KopsControllerMetricsSpec), b.(*kops.KopsControllerMetricsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KopsControllerMetricsSpec)(nil), (*KopsControllerMetricsSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KopsControllerMetricsSpec_To_v1alpha3_KopsControllerMetricsSpec(a.(*kops.KopsControllerMetricsSpec), b.(*KopsControllerMetricsSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*KopsControllerSpec)(nil), (*kops.KopsControllerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_KopsControllerSpec_To_kops_KopsControllerSpec(a.(*KopsControllerSpec), b.(*kops.KopsControllerSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.KopsControllerSpec)(nil), (*KopsControllerSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_KopsControllerSpec_To_v1alpha3_KopsControllerSpec(a.(*kops.KopsControllerSpec), b.(*KopsControllerSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*KubeAPIServerConfig)(nil), (*kops.KubeAPIServerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(a.(*KubeAPIServerConfig), b.(*kops.KubeAPIServerConfig), scope)
 	}); err != nil {
@@ -984,6 +1054,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*MaintenanceWindowSpec)(nil), (*kops.MaintenanceWindowSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(a.(*MaintenanceWindowSpec), b.(*kops.MaintenanceWindowSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.MaintenanceWindowSpec)(nil), (*MaintenanceWindowSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_MaintenanceWindowSpec_To_v1alpha3_MaintenanceWindowSpec(a.(*kops.MaintenanceWindowSpec), b.(*MaintenanceWindowSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*MetricsServerConfig)(nil), (*kops.MetricsServerConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_MetricsServerConfig_To_kops_MetricsServerConfig(a.(*MetricsServerConfig), b.(*kops.MetricsServerConfig), scope)
 	}); err != nil {
@@ -1044,6 +1124,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*NodeAttestationSpec)(nil), (*kops.NodeAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_NodeAttestationSpec_To_kops_NodeAttestationSpec(a.(*NodeAttestationSpec), b.(*kops.NodeAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.NodeAttestationSpec)(nil), (*NodeAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_NodeAttestationSpec_To_v1alpha3_NodeAttestationSpec(a.(*kops.NodeAttestationSpec), b.(*NodeAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*NodeLocalDNSConfig)(nil), (*kops.NodeLocalDNSConfig)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(a.(*NodeLocalDNSConfig), b.(*kops.NodeLocalDNSConfig), scope)
 	}); err != nil {
@@ -1194,6 +1284,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*PrometheusValidationGateSpec)(nil), (*kops.PrometheusValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_PrometheusValidationGateSpec_To_kops_PrometheusValidationGateSpec(a.(*PrometheusValidationGateSpec), b.(*kops.PrometheusValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.PrometheusValidationGateSpec)(nil), (*PrometheusValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_PrometheusValidationGateSpec_To_v1alpha3_PrometheusValidationGateSpec(a.(*kops.PrometheusValidationGateSpec), b.(*PrometheusValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*RBACAuthorizationSpec)(nil), (*kops.RBACAuthorizationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(a.(*RBACAuthorizationSpec), b.(*kops.RBACAuthorizationSpec), scope)
 	}); err != nil {
@@ -1234,6 +1334,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*SPIFFEAttestationSpec)(nil), (*kops.SPIFFEAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_SPIFFEAttestationSpec_To_kops_SPIFFEAttestationSpec(a.(*SPIFFEAttestationSpec), b.(*kops.SPIFFEAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.SPIFFEAttestationSpec)(nil), (*SPIFFEAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_SPIFFEAttestationSpec_To_v1alpha3_SPIFFEAttestationSpec(a.(*kops.SPIFFEAttestationSpec), b.(*SPIFFEAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*SSHCredential)(nil), (*kops.SSHCredential)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_SSHCredential_To_kops_SSHCredential(a.(*SSHCredential), b.(*kops.SSHCredential), scope)
 	}); err != nil {
@@ -1304,6 +1414,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*TPMAttestationSpec)(nil), (*kops.TPMAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(a.(*TPMAttestationSpec), b.(*kops.TPMAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.TPMAttestationSpec)(nil), (*TPMAttestationSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(a.(*kops.TPMAttestationSpec), b.(*TPMAttestationSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*TargetSpec)(nil), (*kops.TargetSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_TargetSpec_To_kops_TargetSpec(a.(*TargetSpec), b.(*kops.TargetSpec), scope)
 	}); err != nil {
@@ -1344,6 +1464,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*ValidationGateSpec)(nil), (*kops.ValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_ValidationGateSpec_To_kops_ValidationGateSpec(a.(*ValidationGateSpec), b.(*kops.ValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.ValidationGateSpec)(nil), (*ValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_ValidationGateSpec_To_v1alpha3_ValidationGateSpec(a.(*kops.ValidationGateSpec), b.(*ValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*VolumeMountSpec)(nil), (*kops.VolumeMountSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha3_VolumeMountSpec_To_kops_VolumeMountSpec(a.(*VolumeMountSpec), b.(*kops.VolumeMountSpec), scope)
 	}); err != nil {
@@ -1384,6 +1514,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*WorkloadValidationGateSpec)(nil), (*kops.WorkloadValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha3_WorkloadValidationGateSpec_To_kops_WorkloadValidationGateSpec(a.(*WorkloadValidationGateSpec), b.(*kops.WorkloadValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*kops.WorkloadValidationGateSpec)(nil), (*WorkloadValidationGateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_kops_WorkloadValidationGateSpec_To_v1alpha3_WorkloadValidationGateSpec(a.(*kops.WorkloadValidationGateSpec), b.(*WorkloadValidationGateSpec), scope)
+	}); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -1715,8 +1855,75 @@ func Convert_kops_AccessLogSpec_To_v1alpha3_AccessLogSpec(in *kops.AccessLogSpec
 	return autoConvert_kops_AccessLogSpec_To_v1alpha3_AccessLogSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_AddonPatchSpec_To_kops_AddonPatchSpec(in *AddonPatchSpec, out *kops.AddonPatchSpec, s conversion.Scope) error {
+	out.Addon = in.Addon
+	if err := Convert_v1alpha3_AddonPatchTarget_To_kops_AddonPatchTarget(&in.Target, &out.Target, s); err != nil {
+		return err
+	}
+	out.StrategicMerge = in.StrategicMerge
+	out.JSON6902 = in.JSON6902
+	return nil
+}
+
+// Convert_v1alpha3_AddonPatchSpec_To_kops_AddonPatchSpec is an autogenerated conversion function.
+func Convert_v1alpha3_AddonPatchSpec_To_kops_AddonPatchSpec(in *AddonPatchSpec, out *kops.AddonPatchSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_AddonPatchSpec_To_kops_AddonPatchSpec(in, out, s)
+}
+
+func autoConvert_kops_AddonPatchSpec_To_v1alpha3_AddonPatchSpec(in *kops.AddonPatchSpec, out *AddonPatchSpec, s conversion.Scope) error {
+	out.Addon = in.Addon
+	if err := Convert_kops_AddonPatchTarget_To_v1alpha3_AddonPatchTarget(&in.Target, &out.Target, s); err != nil {
+		return err
+	}
+	out.StrategicMerge = in.StrategicMerge
+	out.JSON6902 = in.JSON6902
+	return nil
+}
+
+// Convert_kops_AddonPatchSpec_To_v1alpha3_AddonPatchSpec is an autogenerated conversion function.
+func Convert_kops_AddonPatchSpec_To_v1alpha3_AddonPatchSpec(in *kops.AddonPatchSpec, out *AddonPatchSpec, s conversion.Scope) error {
+	return autoConvert_kops_AddonPatchSpec_To_v1alpha3_AddonPatchSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_AddonPatchTarget_To_kops_AddonPatchTarget(in *AddonPatchTarget, out *kops.AddonPatchTarget, s conversion.Scope) error {
+	out.Group = in.Group
+	out.Version = in.Version
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1alpha3_AddonPatchTarget_To_kops_AddonPatchTarget is an autogenerated conversion function.
+func Convert_v1alpha3_AddonPatchTarget_To_kops_AddonPatchTarget(in *AddonPatchTarget, out *kops.AddonPatchTarget, s conversion.Scope) error {
+	return autoConvert_v1alpha3_AddonPatchTarget_To_kops_AddonPatchTarget(in, out, s)
+}
+
+func autoConvert_kops_AddonPatchTarget_To_v1alpha3_AddonPatchTarget(in *kops.AddonPatchTarget, out *AddonPatchTarget, s conversion.Scope) error {
+	out.Group = in.Group
+	out.Version = in.Version
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_kops_AddonPatchTarget_To_v1alpha3_AddonPatchTarget is an autogenerated conversion function.
+func Convert_kops_AddonPatchTarget_To_v1alpha3_AddonPatchTarget(in *kops.AddonPatchTarget, out *AddonPatchTarget, s conversion.Scope) error {
+	return autoConvert_kops_AddonPatchTarget_To_v1alpha3_AddonPatchTarget(in, out, s)
+}
+
 func autoConvert_v1alpha3_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(kops.HelmChartSpec)
+		if err := Convert_v1alpha3_HelmChartSpec_To_kops_HelmChartSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -1727,6 +1934,15 @@ func Convert_v1alpha3_AddonSpec_To_kops_AddonSpec(in *AddonSpec, out *kops.Addon
 
 func autoConvert_kops_AddonSpec_To_v1alpha3_AddonSpec(in *kops.AddonSpec, out *AddonSpec, s conversion.Scope) error {
 	out.Manifest = in.Manifest
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(HelmChartSpec)
+		if err := Convert_kops_HelmChartSpec_To_v1alpha3_HelmChartSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Chart = nil
+	}
 	return nil
 }
 
@@ -2838,6 +3054,17 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 	} else {
 		out.Addons = nil
 	}
+	if in.AddonPatches != nil {
+		in, out := &in.AddonPatches, &out.AddonPatches
+		*out = make([]kops.AddonPatchSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_AddonPatchSpec_To_kops_AddonPatchSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AddonPatches = nil
+	}
 	if err := Convert_v1alpha3_ConfigStoreSpec_To_kops_ConfigStoreSpec(&in.ConfigStore, &out.ConfigStore, s); err != nil {
 		return err
 	}
@@ -3064,6 +3291,24 @@ func autoConvert_v1alpha3_ClusterSpec_To_kops_ClusterSpec(in *ClusterSpec, out *
 		out.Authorization = nil
 	}
 	out.NodeAuthorization = in.NodeAuthorization
+	if in.NodeAttestation != nil {
+		in, out := &in.NodeAttestation, &out.NodeAttestation
+		*out = new(kops.NodeAttestationSpec)
+		if err := Convert_v1alpha3_NodeAttestationSpec_To_kops_NodeAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeAttestation = nil
+	}
+	if in.KopsController != nil {
+		in, out := &in.KopsController, &out.KopsController
+		*out = new(kops.KopsControllerSpec)
+		if err := Convert_v1alpha3_KopsControllerSpec_To_kops_KopsControllerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KopsController = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -3172,6 +3417,17 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 	} else {
 		out.Addons = nil
 	}
+	if in.AddonPatches != nil {
+		in, out := &in.AddonPatches, &out.AddonPatches
+		*out = make([]AddonPatchSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_AddonPatchSpec_To_v1alpha3_AddonPatchSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.AddonPatches = nil
+	}
 	if err := Convert_kops_ConfigStoreSpec_To_v1alpha3_ConfigStoreSpec(&in.ConfigStore, &out.ConfigStore, s); err != nil {
 		return err
 	}
@@ -3398,6 +3654,24 @@ func autoConvert_kops_ClusterSpec_To_v1alpha3_ClusterSpec(in *kops.ClusterSpec,
 		out.Authorization = nil
 	}
 	out.NodeAuthorization = in.NodeAuthorization
+	if in.NodeAttestation != nil {
+		in, out := &in.NodeAttestation, &out.NodeAttestation
+		*out = new(NodeAttestationSpec)
+		if err := Convert_kops_NodeAttestationSpec_To_v1alpha3_NodeAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.NodeAttestation = nil
+	}
+	if in.KopsController != nil {
+		in, out := &in.KopsController, &out.KopsController
+		*out = new(KopsControllerSpec)
+		if err := Convert_kops_KopsControllerSpec_To_v1alpha3_KopsControllerSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.KopsController = nil
+	}
 	out.CloudLabels = in.CloudLabels
 	if in.Hooks != nil {
 		in, out := &in.Hooks, &out.Hooks
@@ -3555,6 +3829,7 @@ func autoConvert_v1alpha3_ConfigStoreSpec_To_kops_ConfigStoreSpec(in *ConfigStor
 	out.Base = in.Base
 	out.Keypairs = in.Keypairs
 	out.Secrets = in.Secrets
+	out.EncryptionKey = in.EncryptionKey
 	return nil
 }
 
@@ -3567,6 +3842,7 @@ func autoConvert_kops_ConfigStoreSpec_To_v1alpha3_ConfigStoreSpec(in *kops.Confi
 	out.Base = in.Base
 	out.Keypairs = in.Keypairs
 	out.Secrets = in.Secrets
+	out.EncryptionKey = in.EncryptionKey
 	return nil
 }
 
@@ -4557,6 +4833,58 @@ func Convert_kops_HTTPProxy_To_v1alpha3_HTTPProxy(in *kops.HTTPProxy, out *HTTPP
 	return autoConvert_kops_HTTPProxy_To_v1alpha3_HTTPProxy(in, out, s)
 }
 
+func autoConvert_v1alpha3_HTTPValidationGateSpec_To_kops_HTTPValidationGateSpec(in *HTTPValidationGateSpec, out *kops.HTTPValidationGateSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert_v1alpha3_HTTPValidationGateSpec_To_kops_HTTPValidationGateSpec is an autogenerated conversion function.
+func Convert_v1alpha3_HTTPValidationGateSpec_To_kops_HTTPValidationGateSpec(in *HTTPValidationGateSpec, out *kops.HTTPValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_HTTPValidationGateSpec_To_kops_HTTPValidationGateSpec(in, out, s)
+}
+
+func autoConvert_kops_HTTPValidationGateSpec_To_v1alpha3_HTTPValidationGateSpec(in *kops.HTTPValidationGateSpec, out *HTTPValidationGateSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Timeout = in.Timeout
+	return nil
+}
+
+// Convert_kops_HTTPValidationGateSpec_To_v1alpha3_HTTPValidationGateSpec is an autogenerated conversion function.
+func Convert_kops_HTTPValidationGateSpec_To_v1alpha3_HTTPValidationGateSpec(in *kops.HTTPValidationGateSpec, out *HTTPValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_kops_HTTPValidationGateSpec_To_v1alpha3_HTTPValidationGateSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_HelmChartSpec_To_kops_HelmChartSpec(in *HelmChartSpec, out *kops.HelmChartSpec, s conversion.Scope) error {
+	out.Repository = in.Repository
+	out.Name = in.Name
+	out.Version = in.Version
+	out.ReleaseName = in.ReleaseName
+	out.Namespace = in.Namespace
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_v1alpha3_HelmChartSpec_To_kops_HelmChartSpec is an autogenerated conversion function.
+func Convert_v1alpha3_HelmChartSpec_To_kops_HelmChartSpec(in *HelmChartSpec, out *kops.HelmChartSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_HelmChartSpec_To_kops_HelmChartSpec(in, out, s)
+}
+
+func autoConvert_kops_HelmChartSpec_To_v1alpha3_HelmChartSpec(in *kops.HelmChartSpec, out *HelmChartSpec, s conversion.Scope) error {
+	out.Repository = in.Repository
+	out.Name = in.Name
+	out.Version = in.Version
+	out.ReleaseName = in.ReleaseName
+	out.Namespace = in.Namespace
+	out.Values = in.Values
+	return nil
+}
+
+// Convert_kops_HelmChartSpec_To_v1alpha3_HelmChartSpec is an autogenerated conversion function.
+func Convert_kops_HelmChartSpec_To_v1alpha3_HelmChartSpec(in *kops.HelmChartSpec, out *HelmChartSpec, s conversion.Scope) error {
+	return autoConvert_kops_HelmChartSpec_To_v1alpha3_HelmChartSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_HetznerSpec_To_kops_HetznerSpec(in *HetznerSpec, out *kops.HetznerSpec, s conversion.Scope) error {
 	return nil
 }
@@ -5669,6 +5997,110 @@ func Convert_kops_KopeioNetworkingSpec_To_v1alpha3_KopeioNetworkingSpec(in *kops
 	return autoConvert_kops_KopeioNetworkingSpec_To_v1alpha3_KopeioNetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_KopsControllerBootstrapSpec_To_kops_KopsControllerBootstrapSpec(in *KopsControllerBootstrapSpec, out *kops.KopsControllerBootstrapSpec, s conversion.Scope) error {
+	out.RequestsPerMinute = in.RequestsPerMinute
+	out.Burst = in.Burst
+	out.NodeRequestsPerMinute = in.NodeRequestsPerMinute
+	out.NodeBurst = in.NodeBurst
+	out.MinReissueInterval = in.MinReissueInterval
+	return nil
+}
+
+// Convert_v1alpha3_KopsControllerBootstrapSpec_To_kops_KopsControllerBootstrapSpec is an autogenerated conversion function.
+func Convert_v1alpha3_KopsControllerBootstrapSpec_To_kops_KopsControllerBootstrapSpec(in *KopsControllerBootstrapSpec, out *kops.KopsControllerBootstrapSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_KopsControllerBootstrapSpec_To_kops_KopsControllerBootstrapSpec(in, out, s)
+}
+
+func autoConvert_kops_KopsControllerBootstrapSpec_To_v1alpha3_KopsControllerBootstrapSpec(in *kops.KopsControllerBootstrapSpec, out *KopsControllerBootstrapSpec, s conversion.Scope) error {
+	out.RequestsPerMinute = in.RequestsPerMinute
+	out.Burst = in.Burst
+	out.NodeRequestsPerMinute = in.NodeRequestsPerMinute
+	out.NodeBurst = in.NodeBurst
+	out.MinReissueInterval = in.MinReissueInterval
+	return nil
+}
+
+// Convert_kops_KopsControllerBootstrapSpec_To_v1alpha3_KopsControllerBootstrapSpec is an autogenerated conversion function.
+func Convert_kops_KopsControllerBootstrapSpec_To_v1alpha3_KopsControllerBootstrapSpec(in *kops.KopsControllerBootstrapSpec, out *KopsControllerBootstrapSpec, s conversion.Scope) error {
+	return autoConvert_kops_KopsControllerBootstrapSpec_To_v1alpha3_KopsControllerBootstrapSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_KopsControllerMetricsSpec_To_kops_KopsControllerMetricsSpec(in *KopsControllerMetricsSpec, out *kops.KopsControllerMetricsSpec, s conversion.Scope) error {
+	out.Expose = in.Expose
+	out.CertificateExpiryHorizon = in.CertificateExpiryHorizon
+	return nil
+}
+
+// Convert_v1alpha3_KopsControllerMetricsSpec_To_kops_KopsControllerMetricsSpec is an autogenerated conversion function.
+func Convert_v1alpha3_KopsControllerMetricsSpec_To_kops_KopsControllerMetricsSpec(in *KopsControllerMetricsSpec, out *kops.KopsControllerMetricsSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_KopsControllerMetricsSpec_To_kops_KopsControllerMetricsSpec(in, out, s)
+}
+
+func autoConvert_kops_KopsControllerMetricsSpec_To_v1alpha3_KopsControllerMetricsSpec(in *kops.KopsControllerMetricsSpec, out *KopsControllerMetricsSpec, s conversion.Scope) error {
+	out.Expose = in.Expose
+	out.CertificateExpiryHorizon = in.CertificateExpiryHorizon
+	return nil
+}
+
+// Convert_kops_KopsControllerMetricsSpec_To_v1alpha3_KopsControllerMetricsSpec is an autogenerated conversion function.
+func Convert_kops_KopsControllerMetricsSpec_To_v1alpha3_KopsControllerMetricsSpec(in *kops.KopsControllerMetricsSpec, out *KopsControllerMetricsSpec, s conversion.Scope) error {
+	return autoConvert_kops_KopsControllerMetricsSpec_To_v1alpha3_KopsControllerMetricsSpec(in, out, s)
+}
+
+func autoConvert_v1alpha3_KopsControllerSpec_To_kops_KopsControllerSpec(in *KopsControllerSpec, out *kops.KopsControllerSpec, s conversion.Scope) error {
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(kops.KopsControllerBootstrapSpec)
+		if err := Convert_v1alpha3_KopsControllerBootstrapSpec_To_kops_KopsControllerBootstrapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bootstrap = nil
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(kops.KopsControllerMetricsSpec)
+		if err := Convert_v1alpha3_KopsControllerMetricsSpec_To_kops_KopsControllerMetricsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metrics = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_KopsControllerSpec_To_kops_KopsControllerSpec is an autogenerated conversion function.
+func Convert_v1alpha3_KopsControllerSpec_To_kops_KopsControllerSpec(in *KopsControllerSpec, out *kops.KopsControllerSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_KopsControllerSpec_To_kops_KopsControllerSpec(in, out, s)
+}
+
+func autoConvert_kops_KopsControllerSpec_To_v1alpha3_KopsControllerSpec(in *kops.KopsControllerSpec, out *KopsControllerSpec, s conversion.Scope) error {
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(KopsControllerBootstrapSpec)
+		if err := Convert_kops_KopsControllerBootstrapSpec_To_v1alpha3_KopsControllerBootstrapSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Bootstrap = nil
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(KopsControllerMetricsSpec)
+		if err := Convert_kops_KopsControllerMetricsSpec_To_v1alpha3_KopsControllerMetricsSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Metrics = nil
+	}
+	return nil
+}
+
+// Convert_kops_KopsControllerSpec_To_v1alpha3_KopsControllerSpec is an autogenerated conversion function.
+func Convert_kops_KopsControllerSpec_To_v1alpha3_KopsControllerSpec(in *kops.KopsControllerSpec, out *KopsControllerSpec, s conversion.Scope) error {
+	return autoConvert_kops_KopsControllerSpec_To_v1alpha3_KopsControllerSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *KubeAPIServerConfig, out *kops.KubeAPIServerConfig, s conversion.Scope) error {
 	out.Image = in.Image
 	out.DisableBasicAuth = in.DisableBasicAuth
@@ -5765,6 +6197,7 @@ func autoConvert_v1alpha3_KubeAPIServerConfig_To_kops_KubeAPIServerConfig(in *Ku
 	out.WatchCacheSizes = in.WatchCacheSizes
 	out.ServiceAccountKeyFile = in.ServiceAccountKeyFile
 	out.ServiceAccountSigningKeyFile = in.ServiceAccountSigningKeyFile
+	out.ServiceAccountSigningEndpoint = in.ServiceAccountSigningEndpoint
 	out.ServiceAccountIssuer = in.ServiceAccountIssuer
 	out.AdditionalServiceAccountIssuers = in.AdditionalServiceAccountIssuers
 	out.ServiceAccountJWKSURI = in.ServiceAccountJWKSURI
@@ -5887,6 +6320,7 @@ func autoConvert_kops_KubeAPIServerConfig_To_v1alpha3_KubeAPIServerConfig(in *ko
 	out.WatchCacheSizes = in.WatchCacheSizes
 	out.ServiceAccountKeyFile = in.ServiceAccountKeyFile
 	out.ServiceAccountSigningKeyFile = in.ServiceAccountSigningKeyFile
+	out.ServiceAccountSigningEndpoint = in.ServiceAccountSigningEndpoint
 	out.ServiceAccountIssuer = in.ServiceAccountIssuer
 	out.AdditionalServiceAccountIssuers = in.AdditionalServiceAccountIssuers
 	out.ServiceAccountJWKSURI = in.ServiceAccountJWKSURI
@@ -6741,6 +7175,32 @@ func Convert_kops_LoadBalancerSubnetSpec_To_v1alpha3_LoadBalancerSubnetSpec(in *
 	return autoConvert_kops_LoadBalancerSubnetSpec_To_v1alpha3_LoadBalancerSubnetSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(in *MaintenanceWindowSpec, out *kops.MaintenanceWindowSpec, s conversion.Scope) error {
+	out.Days = in.Days
+	out.StartTime = in.StartTime
+	out.EndTime = in.EndTime
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_v1alpha3_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec is an autogenerated conversion function.
+func Convert_v1alpha3_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(in *MaintenanceWindowSpec, out *kops.MaintenanceWindowSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(in, out, s)
+}
+
+func autoConvert_kops_MaintenanceWindowSpec_To_v1alpha3_MaintenanceWindowSpec(in *kops.MaintenanceWindowSpec, out *MaintenanceWindowSpec, s conversion.Scope) error {
+	out.Days = in.Days
+	out.StartTime = in.StartTime
+	out.EndTime = in.EndTime
+	out.TimeZone = in.TimeZone
+	return nil
+}
+
+// Convert_kops_MaintenanceWindowSpec_To_v1alpha3_MaintenanceWindowSpec is an autogenerated conversion function.
+func Convert_kops_MaintenanceWindowSpec_To_v1alpha3_MaintenanceWindowSpec(in *kops.MaintenanceWindowSpec, out *MaintenanceWindowSpec, s conversion.Scope) error {
+	return autoConvert_kops_MaintenanceWindowSpec_To_v1alpha3_MaintenanceWindowSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_MetricsServerConfig_To_kops_MetricsServerConfig(in *MetricsServerConfig, out *kops.MetricsServerConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.Image = in.Image
@@ -7211,6 +7671,60 @@ func Convert_kops_NetworkingSpec_To_v1alpha3_NetworkingSpec(in *kops.NetworkingS
 	return autoConvert_kops_NetworkingSpec_To_v1alpha3_NetworkingSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_NodeAttestationSpec_To_kops_NodeAttestationSpec(in *NodeAttestationSpec, out *kops.NodeAttestationSpec, s conversion.Scope) error {
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(kops.TPMAttestationSpec)
+		if err := Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TPM = nil
+	}
+	if in.SPIFFE != nil {
+		in, out := &in.SPIFFE, &out.SPIFFE
+		*out = new(kops.SPIFFEAttestationSpec)
+		if err := Convert_v1alpha3_SPIFFEAttestationSpec_To_kops_SPIFFEAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SPIFFE = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_NodeAttestationSpec_To_kops_NodeAttestationSpec is an autogenerated conversion function.
+func Convert_v1alpha3_NodeAttestationSpec_To_kops_NodeAttestationSpec(in *NodeAttestationSpec, out *kops.NodeAttestationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_NodeAttestationSpec_To_kops_NodeAttestationSpec(in, out, s)
+}
+
+func autoConvert_kops_NodeAttestationSpec_To_v1alpha3_NodeAttestationSpec(in *kops.NodeAttestationSpec, out *NodeAttestationSpec, s conversion.Scope) error {
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(TPMAttestationSpec)
+		if err := Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.TPM = nil
+	}
+	if in.SPIFFE != nil {
+		in, out := &in.SPIFFE, &out.SPIFFE
+		*out = new(SPIFFEAttestationSpec)
+		if err := Convert_kops_SPIFFEAttestationSpec_To_v1alpha3_SPIFFEAttestationSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.SPIFFE = nil
+	}
+	return nil
+}
+
+// Convert_kops_NodeAttestationSpec_To_v1alpha3_NodeAttestationSpec is an autogenerated conversion function.
+func Convert_kops_NodeAttestationSpec_To_v1alpha3_NodeAttestationSpec(in *kops.NodeAttestationSpec, out *NodeAttestationSpec, s conversion.Scope) error {
+	return autoConvert_kops_NodeAttestationSpec_To_v1alpha3_NodeAttestationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_NodeLocalDNSConfig_To_kops_NodeLocalDNSConfig(in *NodeLocalDNSConfig, out *kops.NodeLocalDNSConfig, s conversion.Scope) error {
 	out.Enabled = in.Enabled
 	out.ExternalCoreFile = in.ExternalCoreFile
@@ -7781,6 +8295,28 @@ func Convert_kops_PodIdentityWebhookSpec_To_v1alpha3_PodIdentityWebhookSpec(in *
 	return autoConvert_kops_PodIdentityWebhookSpec_To_v1alpha3_PodIdentityWebhookSpec(in, out, s)
 }
 
+func autoConvert_v1alpha3_PrometheusValidationGateSpec_To_kops_PrometheusValidationGateSpec(in *PrometheusValidationGateSpec, out *kops.PrometheusValidationGateSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Query = in.Query
+	return nil
+}
+
+// Convert_v1alpha3_PrometheusValidationGateSpec_To_kops_PrometheusValidationGateSpec is an autogenerated conversion function.
+func Convert_v1alpha3_PrometheusValidationGateSpec_To_kops_PrometheusValidationGateSpec(in *PrometheusValidationGateSpec, out *kops.PrometheusValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_PrometheusValidationGateSpec_To_kops_PrometheusValidationGateSpec(in, out, s)
+}
+
+func autoConvert_kops_PrometheusValidationGateSpec_To_v1alpha3_PrometheusValidationGateSpec(in *kops.PrometheusValidationGateSpec, out *PrometheusValidationGateSpec, s conversion.Scope) error {
+	out.URL = in.URL
+	out.Query = in.Query
+	return nil
+}
+
+// Convert_kops_PrometheusValidationGateSpec_To_v1alpha3_PrometheusValidationGateSpec is an autogenerated conversion function.
+func Convert_kops_PrometheusValidationGateSpec_To_v1alpha3_PrometheusValidationGateSpec(in *kops.PrometheusValidationGateSpec, out *PrometheusValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_kops_PrometheusValidationGateSpec_To_v1alpha3_PrometheusValidationGateSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_RBACAuthorizationSpec_To_kops_RBACAuthorizationSpec(in *RBACAuthorizationSpec, out *kops.RBACAuthorizationSpec, s conversion.Scope) error {
 	return nil
 }
@@ -7803,6 +8339,26 @@ func autoConvert_v1alpha3_RollingUpdate_To_kops_RollingUpdate(in *RollingUpdate,
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(kops.MaintenanceWindowSpec)
+		if err := Convert_v1alpha3_MaintenanceWindowSpec_To_kops_MaintenanceWindowSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MaintenanceWindow = nil
+	}
+	if in.ValidationGates != nil {
+		in, out := &in.ValidationGates, &out.ValidationGates
+		*out = make([]kops.ValidationGateSpec, len(*in))
+		for i := range *in {
+			if err := Convert_v1alpha3_ValidationGateSpec_To_kops_ValidationGateSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ValidationGates = nil
+	}
 	return nil
 }
 
@@ -7815,6 +8371,26 @@ func autoConvert_kops_RollingUpdate_To_v1alpha3_RollingUpdate(in *kops.RollingUp
 	out.DrainAndTerminate = in.DrainAndTerminate
 	out.MaxUnavailable = in.MaxUnavailable
 	out.MaxSurge = in.MaxSurge
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowSpec)
+		if err := Convert_kops_MaintenanceWindowSpec_To_v1alpha3_MaintenanceWindowSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.MaintenanceWindow = nil
+	}
+	if in.ValidationGates != nil {
+		in, out := &in.ValidationGates, &out.ValidationGates
+		*out = make([]ValidationGateSpec, len(*in))
+		for i := range *in {
+			if err := Convert_kops_ValidationGateSpec_To_v1alpha3_ValidationGateSpec(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.ValidationGates = nil
+	}
 	return nil
 }
 
@@ -7883,6 +8459,30 @@ func Convert_kops_Runc_To_v1alpha3_Runc(in *kops.Runc, out *Runc, s conversion.S
 	return autoConvert_kops_Runc_To_v1alpha3_Runc(in, out, s)
 }
 
+func autoConvert_v1alpha3_SPIFFEAttestationSpec_To_kops_SPIFFEAttestationSpec(in *SPIFFEAttestationSpec, out *kops.SPIFFEAttestationSpec, s conversion.Scope) error {
+	out.TrustDomain = in.TrustDomain
+	out.TrustBundle = in.TrustBundle
+	out.PathPrefix = in.PathPrefix
+	return nil
+}
+
+// Convert_v1alpha3_SPIFFEAttestationSpec_To_kops_SPIFFEAttestationSpec is an autogenerated conversion function.
+func Convert_v1alpha3_SPIFFEAttestationSpec_To_kops_SPIFFEAttestationSpec(in *SPIFFEAttestationSpec, out *kops.SPIFFEAttestationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_SPIFFEAttestationSpec_To_kops_SPIFFEAttestationSpec(in, out, s)
+}
+
+func autoConvert_kops_SPIFFEAttestationSpec_To_v1alpha3_SPIFFEAttestationSpec(in *kops.SPIFFEAttestationSpec, out *SPIFFEAttestationSpec, s conversion.Scope) error {
+	out.TrustDomain = in.TrustDomain
+	out.TrustBundle = in.TrustBundle
+	out.PathPrefix = in.PathPrefix
+	return nil
+}
+
+// Convert_kops_SPIFFEAttestationSpec_To_v1alpha3_SPIFFEAttestationSpec is an autogenerated conversion function.
+func Convert_kops_SPIFFEAttestationSpec_To_v1alpha3_SPIFFEAttestationSpec(in *kops.SPIFFEAttestationSpec, out *SPIFFEAttestationSpec, s conversion.Scope) error {
+	return autoConvert_kops_SPIFFEAttestationSpec_To_v1alpha3_SPIFFEAttestationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_SSHCredential_To_kops_SSHCredential(in *SSHCredential, out *kops.SSHCredential, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1alpha3_SSHCredentialSpec_To_kops_SSHCredentialSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -8093,6 +8693,26 @@ func Convert_kops_SnapshotControllerConfig_To_v1alpha3_SnapshotControllerConfig(
 	return autoConvert_kops_SnapshotControllerConfig_To_v1alpha3_SnapshotControllerConfig(in, out, s)
 }
 
+func autoConvert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(in *TPMAttestationSpec, out *kops.TPMAttestationSpec, s conversion.Scope) error {
+	out.PCRs = in.PCRs
+	return nil
+}
+
+// Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec is an autogenerated conversion function.
+func Convert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(in *TPMAttestationSpec, out *kops.TPMAttestationSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_TPMAttestationSpec_To_kops_TPMAttestationSpec(in, out, s)
+}
+
+func autoConvert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(in *kops.TPMAttestationSpec, out *TPMAttestationSpec, s conversion.Scope) error {
+	out.PCRs = in.PCRs
+	return nil
+}
+
+// Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec is an autogenerated conversion function.
+func Convert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(in *kops.TPMAttestationSpec, out *TPMAttestationSpec, s conversion.Scope) error {
+	return autoConvert_kops_TPMAttestationSpec_To_v1alpha3_TPMAttestationSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_TargetSpec_To_kops_TargetSpec(in *TargetSpec, out *kops.TargetSpec, s conversion.Scope) error {
 	if in.Terraform != nil {
 		in, out := &in.Terraform, &out.Terraform
@@ -8213,6 +8833,80 @@ func Convert_kops_UserData_To_v1alpha3_UserData(in *kops.UserData, out *UserData
 	return autoConvert_kops_UserData_To_v1alpha3_UserData(in, out, s)
 }
 
+func autoConvert_v1alpha3_ValidationGateSpec_To_kops_ValidationGateSpec(in *ValidationGateSpec, out *kops.ValidationGateSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(kops.PrometheusValidationGateSpec)
+		if err := Convert_v1alpha3_PrometheusValidationGateSpec_To_kops_PrometheusValidationGateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Prometheus = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(kops.HTTPValidationGateSpec)
+		if err := Convert_v1alpha3_HTTPValidationGateSpec_To_kops_HTTPValidationGateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(kops.WorkloadValidationGateSpec)
+		if err := Convert_v1alpha3_WorkloadValidationGateSpec_To_kops_WorkloadValidationGateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Workload = nil
+	}
+	return nil
+}
+
+// Convert_v1alpha3_ValidationGateSpec_To_kops_ValidationGateSpec is an autogenerated conversion function.
+func Convert_v1alpha3_ValidationGateSpec_To_kops_ValidationGateSpec(in *ValidationGateSpec, out *kops.ValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_ValidationGateSpec_To_kops_ValidationGateSpec(in, out, s)
+}
+
+func autoConvert_kops_ValidationGateSpec_To_v1alpha3_ValidationGateSpec(in *kops.ValidationGateSpec, out *ValidationGateSpec, s conversion.Scope) error {
+	out.Name = in.Name
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusValidationGateSpec)
+		if err := Convert_kops_PrometheusValidationGateSpec_To_v1alpha3_PrometheusValidationGateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Prometheus = nil
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPValidationGateSpec)
+		if err := Convert_kops_HTTPValidationGateSpec_To_v1alpha3_HTTPValidationGateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.HTTP = nil
+	}
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(WorkloadValidationGateSpec)
+		if err := Convert_kops_WorkloadValidationGateSpec_To_v1alpha3_WorkloadValidationGateSpec(*in, *out, s); err != nil {
+			return err
+		}
+	} else {
+		out.Workload = nil
+	}
+	return nil
+}
+
+// Convert_kops_ValidationGateSpec_To_v1alpha3_ValidationGateSpec is an autogenerated conversion function.
+func Convert_kops_ValidationGateSpec_To_v1alpha3_ValidationGateSpec(in *kops.ValidationGateSpec, out *ValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_kops_ValidationGateSpec_To_v1alpha3_ValidationGateSpec(in, out, s)
+}
+
 func autoConvert_v1alpha3_VolumeMountSpec_To_kops_VolumeMountSpec(in *VolumeMountSpec, out *kops.VolumeMountSpec, s conversion.Scope) error {
 	out.Device = in.Device
 	out.Filesystem = in.Filesystem
@@ -8348,3 +9042,29 @@ func autoConvert_kops_WeaveNetworkingSpec_To_v1alpha3_WeaveNetworkingSpec(in *ko
 func Convert_kops_WeaveNetworkingSpec_To_v1alpha3_WeaveNetworkingSpec(in *kops.WeaveNetworkingSpec, out *WeaveNetworkingSpec, s conversion.Scope) error {
 	return autoConvert_kops_WeaveNetworkingSpec_To_v1alpha3_WeaveNetworkingSpec(in, out, s)
 }
+
+func autoConvert_v1alpha3_WorkloadValidationGateSpec_To_kops_WorkloadValidationGateSpec(in *WorkloadValidationGateSpec, out *kops.WorkloadValidationGateSpec, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.MinAvailable = in.MinAvailable
+	return nil
+}
+
+// Convert_v1alpha3_WorkloadValidationGateSpec_To_kops_WorkloadValidationGateSpec is an autogenerated conversion function.
+func Convert_v1alpha3_WorkloadValidationGateSpec_To_kops_WorkloadValidationGateSpec(in *WorkloadValidationGateSpec, out *kops.WorkloadValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_v1alpha3_WorkloadValidationGateSpec_To_kops_WorkloadValidationGateSpec(in, out, s)
+}
+
+func autoConvert_kops_WorkloadValidationGateSpec_To_v1alpha3_WorkloadValidationGateSpec(in *kops.WorkloadValidationGateSpec, out *WorkloadValidationGateSpec, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Namespace = in.Namespace
+	out.Name = in.Name
+	out.MinAvailable = in.MinAvailable
+	return nil
+}
+
+// Convert_kops_WorkloadValidationGateSpec_To_v1alpha3_WorkloadValidationGateSpec is an autogenerated conversion function.
+func Convert_kops_WorkloadValidationGateSpec_To_v1alpha3_WorkloadValidationGateSpec(in *kops.WorkloadValidationGateSpec, out *WorkloadValidationGateSpec, s conversion.Scope) error {
+	return autoConvert_kops_WorkloadValidationGateSpec_To_v1alpha3_WorkloadValidationGateSpec(in, out, s)
+}
@@ -273,9 +273,47 @@ func (in *AccessLogSpec) DeepCopy() *AccessLogSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonPatchSpec) DeepCopyInto(out *AddonPatchSpec) {
+	*out = *in
+	out.Target = in.Target
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonPatchSpec.
+func (in *AddonPatchSpec) DeepCopy() *AddonPatchSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonPatchSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AddonPatchTarget) DeepCopyInto(out *AddonPatchTarget) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AddonPatchTarget.
+func (in *AddonPatchTarget) DeepCopy() *AddonPatchTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(AddonPatchTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AddonSpec) DeepCopyInto(out *AddonSpec) {
 	*out = *in
+	if in.Chart != nil {
+		in, out := &in.Chart, &out.Chart
+		*out = new(HelmChartSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -1129,6 +1167,13 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	if in.Addons != nil {
 		in, out := &in.Addons, &out.Addons
 		*out = make([]AddonSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AddonPatches != nil {
+		in, out := &in.AddonPatches, &out.AddonPatches
+		*out = make([]AddonPatchSpec, len(*in))
 		copy(*out, *in)
 	}
 	out.ConfigStore = in.ConfigStore
@@ -1301,6 +1346,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(kops.NodeAuthorizationSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.NodeAttestation != nil {
+		in, out := &in.NodeAttestation, &out.NodeAttestation
+		*out = new(NodeAttestationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.KopsController != nil {
+		in, out := &in.KopsController, &out.KopsController
+		*out = new(KopsControllerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.CloudLabels != nil {
 		in, out := &in.CloudLabels, &out.CloudLabels
 		*out = make(map[string]string, len(*in))
@@ -2365,6 +2420,43 @@ func (in *HTTPProxy) DeepCopy() *HTTPProxy {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HTTPValidationGateSpec) DeepCopyInto(out *HTTPValidationGateSpec) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPValidationGateSpec.
+func (in *HTTPValidationGateSpec) DeepCopy() *HTTPValidationGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HTTPValidationGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HelmChartSpec) DeepCopyInto(out *HelmChartSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HelmChartSpec.
+func (in *HelmChartSpec) DeepCopy() *HelmChartSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HelmChartSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HetznerSpec) DeepCopyInto(out *HetznerSpec) {
 	*out = *in
@@ -3239,6 +3331,99 @@ func (in *KopeioNetworkingSpec) DeepCopy() *KopeioNetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KopsControllerBootstrapSpec) DeepCopyInto(out *KopsControllerBootstrapSpec) {
+	*out = *in
+	if in.RequestsPerMinute != nil {
+		in, out := &in.RequestsPerMinute, &out.RequestsPerMinute
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeRequestsPerMinute != nil {
+		in, out := &in.NodeRequestsPerMinute, &out.NodeRequestsPerMinute
+		*out = new(int32)
+		**out = **in
+	}
+	if in.NodeBurst != nil {
+		in, out := &in.NodeBurst, &out.NodeBurst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.MinReissueInterval != nil {
+		in, out := &in.MinReissueInterval, &out.MinReissueInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KopsControllerBootstrapSpec.
+func (in *KopsControllerBootstrapSpec) DeepCopy() *KopsControllerBootstrapSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KopsControllerBootstrapSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KopsControllerMetricsSpec) DeepCopyInto(out *KopsControllerMetricsSpec) {
+	*out = *in
+	if in.Expose != nil {
+		in, out := &in.Expose, &out.Expose
+		*out = new(bool)
+		**out = **in
+	}
+	if in.CertificateExpiryHorizon != nil {
+		in, out := &in.CertificateExpiryHorizon, &out.CertificateExpiryHorizon
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KopsControllerMetricsSpec.
+func (in *KopsControllerMetricsSpec) DeepCopy() *KopsControllerMetricsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KopsControllerMetricsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KopsControllerSpec) DeepCopyInto(out *KopsControllerSpec) {
+	*out = *in
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(KopsControllerBootstrapSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = new(KopsControllerMetricsSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KopsControllerSpec.
+func (in *KopsControllerSpec) DeepCopy() *KopsControllerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(KopsControllerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeAPIServerConfig) DeepCopyInto(out *KubeAPIServerConfig) {
 	*out = *in
@@ -3561,6 +3746,11 @@ func (in *KubeAPIServerConfig) DeepCopyInto(out *KubeAPIServerConfig) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.ServiceAccountSigningEndpoint != nil {
+		in, out := &in.ServiceAccountSigningEndpoint, &out.ServiceAccountSigningEndpoint
+		*out = new(string)
+		**out = **in
+	}
 	if in.ServiceAccountIssuer != nil {
 		in, out := &in.ServiceAccountIssuer, &out.ServiceAccountIssuer
 		*out = new(string)
@@ -4769,6 +4959,27 @@ func (in *LoadBalancerSubnetSpec) DeepCopy() *LoadBalancerSubnetSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindowSpec) DeepCopyInto(out *MaintenanceWindowSpec) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindowSpec.
+func (in *MaintenanceWindowSpec) DeepCopy() *MaintenanceWindowSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindowSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetricsServerConfig) DeepCopyInto(out *MetricsServerConfig) {
 	*out = *in
@@ -5057,6 +5268,32 @@ func (in *NetworkingSpec) DeepCopy() *NetworkingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NodeAttestationSpec) DeepCopyInto(out *NodeAttestationSpec) {
+	*out = *in
+	if in.TPM != nil {
+		in, out := &in.TPM, &out.TPM
+		*out = new(TPMAttestationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SPIFFE != nil {
+		in, out := &in.SPIFFE, &out.SPIFFE
+		*out = new(SPIFFEAttestationSpec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NodeAttestationSpec.
+func (in *NodeAttestationSpec) DeepCopy() *NodeAttestationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NodeAttestationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeLocalDNSConfig) DeepCopyInto(out *NodeLocalDNSConfig) {
 	*out = *in
@@ -5750,6 +5987,22 @@ func (in *PodIdentityWebhookSpec) DeepCopy() *PodIdentityWebhookSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusValidationGateSpec) DeepCopyInto(out *PrometheusValidationGateSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusValidationGateSpec.
+func (in *PrometheusValidationGateSpec) DeepCopy() *PrometheusValidationGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusValidationGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RBACAuthorizationSpec) DeepCopyInto(out *RBACAuthorizationSpec) {
 	*out = *in
@@ -5784,6 +6037,18 @@ func (in *RollingUpdate) DeepCopyInto(out *RollingUpdate) {
 		*out = new(intstr.IntOrString)
 		**out = **in
 	}
+	if in.MaintenanceWindow != nil {
+		in, out := &in.MaintenanceWindow, &out.MaintenanceWindow
+		*out = new(MaintenanceWindowSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ValidationGates != nil {
+		in, out := &in.ValidationGates, &out.ValidationGates
+		*out = make([]ValidationGateSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -5839,6 +6104,22 @@ func (in *Runc) DeepCopy() *Runc {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SPIFFEAttestationSpec) DeepCopyInto(out *SPIFFEAttestationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SPIFFEAttestationSpec.
+func (in *SPIFFEAttestationSpec) DeepCopy() *SPIFFEAttestationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(SPIFFEAttestationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SSHCredential) DeepCopyInto(out *SSHCredential) {
 	*out = *in
@@ -5999,6 +6280,29 @@ func (in *SnapshotControllerConfig) DeepCopy() *SnapshotControllerConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TPMAttestationSpec) DeepCopyInto(out *TPMAttestationSpec) {
+	*out = *in
+	if in.PCRs != nil {
+		in, out := &in.PCRs, &out.PCRs
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TPMAttestationSpec.
+func (in *TPMAttestationSpec) DeepCopy() *TPMAttestationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TPMAttestationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TargetSpec) DeepCopyInto(out *TargetSpec) {
 	*out = *in
@@ -6087,6 +6391,37 @@ func (in *UserData) DeepCopy() *UserData {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationGateSpec) DeepCopyInto(out *ValidationGateSpec) {
+	*out = *in
+	if in.Prometheus != nil {
+		in, out := &in.Prometheus, &out.Prometheus
+		*out = new(PrometheusValidationGateSpec)
+		**out = **in
+	}
+	if in.HTTP != nil {
+		in, out := &in.HTTP, &out.HTTP
+		*out = new(HTTPValidationGateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Workload != nil {
+		in, out := &in.Workload, &out.Workload
+		*out = new(WorkloadValidationGateSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationGateSpec.
+func (in *ValidationGateSpec) DeepCopy() *ValidationGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VolumeMountSpec) DeepCopyInto(out *VolumeMountSpec) {
 	*out = *in
@@ -6255,3 +6590,24 @@ func (in *WeaveNetworkingSpec) DeepCopy() *WeaveNetworkingSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkloadValidationGateSpec) DeepCopyInto(out *WorkloadValidationGateSpec) {
+	*out = *in
+	if in.MinAvailable != nil {
+		in, out := &in.MinAvailable, &out.MinAvailable
+		*out = new(intstr.IntOrString)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkloadValidationGateSpec.
+func (in *WorkloadValidationGateSpec) DeepCopy() *WorkloadValidationGateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkloadValidationGateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
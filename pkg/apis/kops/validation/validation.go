@@ -17,6 +17,8 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"net"
@@ -24,11 +26,15 @@ import (
 	"net/url"
 	"path/filepath"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/blang/semver/v4"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
 	"golang.org/x/net/ipv4"
 	"golang.org/x/net/ipv6"
 	"k8s.io/apimachinery/pkg/api/validation"
@@ -39,8 +45,10 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/util/subnet"
 	netutils "k8s.io/utils/net"
+	"sigs.k8s.io/yaml"
 
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/model/components"
 	"k8s.io/kops/pkg/model/iam"
 	"k8s.io/kops/upup/pkg/fi"
@@ -170,6 +178,18 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 		allErrs = append(allErrs, field.Forbidden(fieldPath.Child("nodeAuthorization"), "NodeAuthorization must be empty. The functionality has been reimplemented and is enabled on kubernetes >= 1.19.0."))
 	}
 
+	if spec.NodeAttestation != nil {
+		allErrs = append(allErrs, validateNodeAttestation(c, spec.NodeAttestation, fieldPath.Child("nodeAttestation"))...)
+	}
+
+	if spec.KopsController != nil && spec.KopsController.Bootstrap != nil {
+		allErrs = append(allErrs, validateKopsControllerBootstrap(spec.KopsController.Bootstrap, fieldPath.Child("kopsController", "bootstrap"))...)
+	}
+
+	if spec.KopsController != nil && spec.KopsController.Metrics != nil {
+		allErrs = append(allErrs, validateKopsControllerMetrics(spec.KopsController.Metrics, fieldPath.Child("kopsController", "metrics"))...)
+	}
+
 	if spec.ClusterAutoscaler != nil {
 		allErrs = append(allErrs, validateClusterAutoscaler(c, spec.ClusterAutoscaler, fieldPath.Child("clusterAutoscaler"))...)
 	}
@@ -189,12 +209,27 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 	// Custom addons: the kops-channels static pod fetches manifests via VFS at boot, so file:// schemes
 	// (which would resolve inside the container's mount namespace) aren't supported. Push the manifest
 	// to the state store or another VFS-supported backend.
+	// Charts are rendered by kops when the cluster is updated, so they may come from a local directory.
+	releaseNames := sets.New[string]()
 	for i, addon := range spec.Addons {
+		addonPath := fieldPath.Child("addons").Index(i)
 		if strings.HasPrefix(addon.Manifest, "file://") {
-			allErrs = append(allErrs, field.Invalid(fieldPath.Child("addons").Index(i).Child("manifest"), addon.Manifest, "file:// addon manifests are not supported"))
+			allErrs = append(allErrs, field.Invalid(addonPath.Child("manifest"), addon.Manifest, "file:// addon manifests are not supported"))
+		}
+		if addon.Chart != nil {
+			if addon.Manifest != "" {
+				allErrs = append(allErrs, field.Forbidden(addonPath.Child("chart"), "chart cannot be specified with manifest"))
+			}
+			allErrs = append(allErrs, validateAddonChart(addon.Chart, releaseNames, addonPath.Child("chart"))...)
+		} else if addon.Manifest == "" {
+			allErrs = append(allErrs, field.Required(addonPath.Child("manifest"), "either manifest or chart must be specified"))
 		}
 	}
 
+	for i, patch := range spec.AddonPatches {
+		allErrs = append(allErrs, validateAddonPatch(patch, fieldPath.Child("addonPatches").Index(i))...)
+	}
+
 	// IAM additional policies
 	for k, v := range spec.AdditionalPolicies {
 		allErrs = append(allErrs, validateAdditionalPolicy(k, v, fieldPath.Child("additionalPolicies"))...)
@@ -221,6 +256,31 @@ func validateClusterSpec(spec *kops.ClusterSpec, c *kops.Cluster, fieldPath *fie
 
 	allErrs = append(allErrs, validateAzureBlobAccountUniformity(spec, fieldPath)...)
 
+	if spec.ConfigStore.EncryptionKey != "" {
+		fieldEncryptionKey := fieldPath.Child("configStore", "encryptionKey")
+		if err := envelope.ValidateKeyURI(spec.ConfigStore.EncryptionKey); err != nil {
+			allErrs = append(allErrs, field.Invalid(fieldEncryptionKey, spec.ConfigStore.EncryptionKey, err.Error()))
+		} else {
+			// The control plane decrypts the state store with its instance credentials,
+			// so a cloud key must belong to the cluster's cloud provider.
+			scheme, _, _ := strings.Cut(spec.ConfigStore.EncryptionKey, "://")
+			switch scheme {
+			case "awskms":
+				if c.GetCloudProvider() != kops.CloudProviderAWS {
+					allErrs = append(allErrs, field.Forbidden(fieldEncryptionKey, "awskms keys are only supported on AWS clusters"))
+				}
+			case "gcpkms":
+				if c.GetCloudProvider() != kops.CloudProviderGCE {
+					allErrs = append(allErrs, field.Forbidden(fieldEncryptionKey, "gcpkms keys are only supported on GCE clusters"))
+				}
+			case "vaulttransit":
+				// The control plane is given AppRole credentials, on any cloud
+			default:
+				allErrs = append(allErrs, field.Forbidden(fieldEncryptionKey, fmt.Sprintf("%s keys cannot be used by the control plane; use awskms on AWS, gcpkms on GCE or vaulttransit", scheme)))
+			}
+		}
+	}
+
 	if spec.ContainerRuntime != "" {
 		allErrs = append(allErrs, validateContainerRuntime(c, spec.ContainerRuntime, fieldPath.Child("containerRuntime"))...)
 	}
@@ -2059,9 +2119,78 @@ func validateRollingUpdate(rollingUpdate *kops.RollingUpdate, fldpath *field.Pat
 			allErrs = append(allErrs, field.Forbidden(fldpath.Child("maxSurge"), "Cannot be zero if maxUnavailable is zero"))
 		}
 	}
+	if rollingUpdate.MaintenanceWindow != nil {
+		if _, err := kops.ParseMaintenanceWindow(rollingUpdate.MaintenanceWindow); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldpath.Child("maintenanceWindow"), rollingUpdate.MaintenanceWindow, err.Error()))
+		}
+	}
+	allErrs = append(allErrs, validateValidationGates(rollingUpdate.ValidationGates, fldpath.Child("validationGates"))...)
 	return allErrs
 }
 
+func validateValidationGates(gates []kops.ValidationGateSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	names := sets.New[string]()
+	for i, gate := range gates {
+		gatePath := fldPath.Index(i)
+		if gate.Name == "" {
+			allErrs = append(allErrs, field.Required(gatePath.Child("name"), ""))
+		} else if names.Has(gate.Name) {
+			allErrs = append(allErrs, field.Duplicate(gatePath.Child("name"), gate.Name))
+		}
+		names.Insert(gate.Name)
+
+		count := 0
+		if gate.Prometheus != nil {
+			count++
+			allErrs = append(allErrs, validateGateURL(gate.Prometheus.URL, gatePath.Child("prometheus", "url"))...)
+			if gate.Prometheus.Query == "" {
+				allErrs = append(allErrs, field.Required(gatePath.Child("prometheus", "query"), ""))
+			}
+		}
+		if gate.HTTP != nil {
+			count++
+			allErrs = append(allErrs, validateGateURL(gate.HTTP.URL, gatePath.Child("http", "url"))...)
+			if gate.HTTP.Timeout != nil && gate.HTTP.Timeout.Duration <= 0 {
+				allErrs = append(allErrs, field.Invalid(gatePath.Child("http", "timeout"), gate.HTTP.Timeout, "must be positive"))
+			}
+		}
+		if gate.Workload != nil {
+			count++
+			workloadPath := gatePath.Child("workload")
+			allErrs = append(allErrs, IsValidValue(workloadPath.Child("kind"), &gate.Workload.Kind, []string{"Deployment", "StatefulSet", "DaemonSet"})...)
+			if gate.Workload.Namespace == "" {
+				allErrs = append(allErrs, field.Required(workloadPath.Child("namespace"), ""))
+			}
+			if gate.Workload.Name == "" {
+				allErrs = append(allErrs, field.Required(workloadPath.Child("name"), ""))
+			}
+			if gate.Workload.MinAvailable == nil {
+				allErrs = append(allErrs, field.Required(workloadPath.Child("minAvailable"), ""))
+			} else if minAvailable, err := intstr.GetScaledValueFromIntOrPercent(gate.Workload.MinAvailable, 100, true); err != nil {
+				allErrs = append(allErrs, field.Invalid(workloadPath.Child("minAvailable"), gate.Workload.MinAvailable, fmt.Sprintf("Unable to parse: %v", err)))
+			} else if minAvailable < 0 {
+				allErrs = append(allErrs, field.Invalid(workloadPath.Child("minAvailable"), gate.Workload.MinAvailable, "Cannot be negative"))
+			}
+		}
+		if count != 1 {
+			allErrs = append(allErrs, field.Invalid(gatePath, gate.Name, "exactly one of prometheus, http or workload must be set"))
+		}
+	}
+	return allErrs
+}
+
+func validateGateURL(s string, fldPath *field.Path) field.ErrorList {
+	if s == "" {
+		return field.ErrorList{field.Required(fldPath, "")}
+	}
+	u, err := url.Parse(s)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return field.ErrorList{field.Invalid(fldPath, s, "must be an http or https URL")}
+	}
+	return nil
+}
+
 func validateNodeLocalDNS(spec *kops.ClusterSpec, fldpath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -2120,6 +2249,92 @@ func validateExternalDNS(cluster *kops.Cluster, spec *kops.ExternalDNSConfig, fl
 	return allErrs
 }
 
+// maxChartReleaseNameLength keeps the addon name, <releaseName>.helm.kops.k8s.io, within the length of a label value
+const maxChartReleaseNameLength = utilvalidation.LabelValueMaxLength - len(".helm.kops.k8s.io")
+
+func validateAddonChart(chart *kops.HelmChartSpec, releaseNames sets.Set[string], fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if chart.Repository == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("repository"), "chart repository must be specified"))
+	}
+	if chart.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("name"), "chart name must be specified"))
+	}
+	if chart.Version == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("version"), "chart version must be specified"))
+	}
+
+	releaseName := chart.ReleaseName
+	releaseNamePath := fldPath.Child("releaseName")
+	if releaseName == "" {
+		releaseName = chart.Name
+		releaseNamePath = fldPath.Child("name")
+	}
+	if releaseName != "" {
+		for _, msg := range utilvalidation.IsDNS1123Label(releaseName) {
+			allErrs = append(allErrs, field.Invalid(releaseNamePath, releaseName, msg))
+		}
+		if len(releaseName) > maxChartReleaseNameLength {
+			allErrs = append(allErrs, field.TooLong(releaseNamePath, releaseName, maxChartReleaseNameLength))
+		}
+		if releaseNames.Has(releaseName) {
+			allErrs = append(allErrs, field.Duplicate(releaseNamePath, releaseName))
+		}
+		releaseNames.Insert(releaseName)
+	}
+
+	if chart.Namespace != "" {
+		for _, msg := range utilvalidation.IsDNS1123Label(chart.Namespace) {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("namespace"), chart.Namespace, msg))
+		}
+	}
+
+	if chart.Values != "" {
+		values := make(map[string]interface{})
+		if err := utils.YamlUnmarshal([]byte(chart.Values), &values); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("values"), chart.Values, fmt.Sprintf("values must be a YAML map: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
+func validateAddonPatch(patch kops.AddonPatchSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if patch.Addon == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("addon"), "addon must be specified"))
+	}
+	if patch.Target.Kind == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("target", "kind"), "kind must be specified"))
+	}
+	if patch.Target.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("target", "name"), "name must be specified"))
+	}
+
+	if patch.StrategicMerge == "" && patch.JSON6902 == "" {
+		allErrs = append(allErrs, field.Required(fldPath, "either strategicMerge or json6902 must be specified"))
+	}
+	if patch.StrategicMerge != "" {
+		patchMap := make(map[string]interface{})
+		if err := utils.YamlUnmarshal([]byte(patch.StrategicMerge), &patchMap); err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("strategicMerge"), patch.StrategicMerge, fmt.Sprintf("patch must be a YAML map: %v", err)))
+		}
+	}
+	if patch.JSON6902 != "" {
+		operations, err := yaml.YAMLToJSON([]byte(patch.JSON6902))
+		if err == nil {
+			_, err = jsonpatch.DecodePatch(operations)
+		}
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("json6902"), patch.JSON6902, fmt.Sprintf("patch must be a list of JSON patch operations: %v", err)))
+		}
+	}
+
+	return allErrs
+}
+
 func validateMetricsServer(cluster *kops.Cluster, spec *kops.MetricsServerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if spec != nil && fi.ValueOf(spec.Enabled) {
 		if !fi.ValueOf(spec.Insecure) && !components.IsCertManagerEnabled(cluster) {
@@ -2181,6 +2396,76 @@ func validateWarmPool(warmPool *kops.WarmPoolSpec, fldPath *field.Path) (allErrs
 	return allErrs
 }
 
+func validateNodeAttestation(cluster *kops.Cluster, spec *kops.NodeAttestationSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	// Only enrolled machines choose their authenticator from the credentials present on the host.
+	if cluster.GetCloudProvider() != kops.CloudProviderMetal {
+		allErrs = append(allErrs, field.Forbidden(fldPath, "nodeAttestation is only supported for bare-metal clusters"))
+	}
+
+	if spec.TPM != nil {
+		for k, v := range spec.TPM.PCRs {
+			pcrPath := fldPath.Child("tpm", "pcrs").Key(k)
+			if index, err := strconv.Atoi(k); err != nil || index < 0 || index > 23 {
+				allErrs = append(allErrs, field.Invalid(pcrPath, k, "PCR index must be between 0 and 23"))
+			}
+			if b, err := hex.DecodeString(v); err != nil || len(b) != sha256.Size {
+				allErrs = append(allErrs, field.Invalid(pcrPath, v, "PCR value must be a hex-encoded SHA-256 digest"))
+			}
+		}
+	}
+
+	if spec.SPIFFE != nil {
+		spiffePath := fldPath.Child("spiffe")
+		td, err := spiffeid.TrustDomainFromString(spec.SPIFFE.TrustDomain)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(spiffePath.Child("trustDomain"), spec.SPIFFE.TrustDomain, err.Error()))
+		} else if spec.SPIFFE.TrustBundle == "" {
+			allErrs = append(allErrs, field.Required(spiffePath.Child("trustBundle"), "trust bundle must be specified"))
+		} else if bundle, err := x509bundle.Parse(td, []byte(spec.SPIFFE.TrustBundle)); err != nil {
+			allErrs = append(allErrs, field.Invalid(spiffePath.Child("trustBundle"), "", err.Error()))
+		} else if bundle.Empty() {
+			allErrs = append(allErrs, field.Invalid(spiffePath.Child("trustBundle"), "", "trust bundle must contain at least one certificate"))
+		}
+		if spec.SPIFFE.PathPrefix != "" {
+			if err := spiffeid.ValidatePath(spec.SPIFFE.PathPrefix); err != nil {
+				allErrs = append(allErrs, field.Invalid(spiffePath.Child("pathPrefix"), spec.SPIFFE.PathPrefix, err.Error()))
+			}
+		}
+	}
+
+	if spec.TPM == nil && spec.SPIFFE == nil {
+		allErrs = append(allErrs, field.Required(fldPath, "at least one of tpm or spiffe must be specified"))
+	}
+
+	return allErrs
+}
+
+func validateKopsControllerBootstrap(spec *kops.KopsControllerBootstrapSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if v := spec.RequestsPerMinute; v != nil && *v < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("requestsPerMinute"), *v, "must not be negative"))
+	}
+	if v := spec.Burst; v != nil && *v < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("burst"), *v, "must be at least 1"))
+	}
+	if v := spec.NodeRequestsPerMinute; v != nil && *v < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeRequestsPerMinute"), *v, "must not be negative"))
+	}
+	if v := spec.NodeBurst; v != nil && *v < 1 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("nodeBurst"), *v, "must be at least 1"))
+	}
+	if v := spec.MinReissueInterval; v != nil && v.Duration < 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("minReissueInterval"), v.Duration.String(), "must not be negative"))
+	}
+	return allErrs
+}
+
+func validateKopsControllerMetrics(spec *kops.KopsControllerMetricsSpec, fldPath *field.Path) (allErrs field.ErrorList) {
+	if v := spec.CertificateExpiryHorizon; v != nil && v.Duration <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("certificateExpiryHorizon"), v.Duration.String(), "must be positive"))
+	}
+	return allErrs
+}
+
 func validateSnapshotController(cluster *kops.Cluster, spec *kops.SnapshotControllerConfig, fldPath *field.Path) (allErrs field.ErrorList) {
 	if spec != nil && fi.ValueOf(spec.Enabled) {
 		if !components.IsCertManagerEnabled(cluster) {
@@ -17,16 +17,20 @@ limitations under the License.
 package validation
 
 import (
+	"crypto/x509/pkix"
 	"net"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
 	"k8s.io/utils/ptr"
 )
 
@@ -111,6 +115,70 @@ func Test_Validate_GossipConfig(t *testing.T) {
 	testErrors(t, "dnsControllerGossipConfig", errs, []string{"Forbidden::spec.dnsControllerGossipConfig"})
 }
 
+func Test_Validate_ConfigStoreEncryptionKey(t *testing.T) {
+	grid := []struct {
+		Description    string
+		CloudProvider  kops.CloudProviderSpec
+		EncryptionKey  string
+		ExpectedErrors []string
+	}{
+		{
+			Description:   "aws key on aws",
+			CloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			EncryptionKey: "awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+		},
+		{
+			Description:    "key alias",
+			CloudProvider:  kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			EncryptionKey:  "awskms://alias/kops",
+			ExpectedErrors: []string{"Invalid value::spec.configStore.encryptionKey"},
+		},
+		{
+			Description:   "gcp key on gce",
+			CloudProvider: kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			EncryptionKey: "gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k",
+		},
+		{
+			Description:    "gcp key on aws",
+			CloudProvider:  kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			EncryptionKey:  "gcpkms://projects/p/locations/global/keyRings/r/cryptoKeys/k",
+			ExpectedErrors: []string{"Forbidden::spec.configStore.encryptionKey"},
+		},
+		{
+			Description:   "vault key",
+			CloudProvider: kops.CloudProviderSpec{AWS: &kops.AWSSpec{}},
+			EncryptionKey: "vaulttransit://transit/kops",
+		},
+		{
+			Description:    "vault key without a mount",
+			CloudProvider:  kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			EncryptionKey:  "vaulttransit://kops",
+			ExpectedErrors: []string{"Invalid value::spec.configStore.encryptionKey"},
+		},
+		{
+			Description:    "local key",
+			CloudProvider:  kops.CloudProviderSpec{GCE: &kops.GCESpec{}},
+			EncryptionKey:  "local:///tmp/key",
+			ExpectedErrors: []string{"Forbidden::spec.configStore.encryptionKey"},
+		},
+	}
+	for _, g := range grid {
+		cluster := &kops.Cluster{
+			Spec: kops.ClusterSpec{
+				CloudProvider: g.CloudProvider,
+				ConfigStore:   kops.ConfigStoreSpec{EncryptionKey: g.EncryptionKey},
+			},
+		}
+		var errs field.ErrorList
+		for _, err := range validateClusterSpec(&cluster.Spec, cluster, field.NewPath("spec"), false) {
+			if err.Field == "spec.configStore.encryptionKey" {
+				errs = append(errs, err)
+			}
+		}
+		testErrors(t, g.Description, errs, g.ExpectedErrors)
+	}
+}
+
 func TestValidateCIDR(t *testing.T) {
 	grid := []struct {
 		Input          string
@@ -793,6 +861,65 @@ func Test_Validate_Addons(t *testing.T) {
 			Input:          []kops.AddonSpec{{Manifest: "file:///etc/kubernetes/kops/config/addons/extra.yaml"}},
 			ExpectedErrors: []string{"Invalid value::spec.addons[0].manifest"},
 		},
+		{
+			Input:          []kops.AddonSpec{{}},
+			ExpectedErrors: []string{"Required value::spec.addons[0].manifest"},
+		},
+		{
+			Input: []kops.AddonSpec{{Chart: &kops.HelmChartSpec{
+				Repository: "oci://registry.example.com/charts",
+				Name:       "example",
+				Version:    "1.2.3",
+				Values:     "replicas: 2\n",
+			}}},
+		},
+		{
+			Input: []kops.AddonSpec{{Chart: &kops.HelmChartSpec{
+				Repository: "/home/user/charts",
+				Name:       "example",
+				Version:    "1.2.3",
+			}}},
+		},
+		{
+			Input: []kops.AddonSpec{{
+				Manifest: "s3://somebucket/example.yaml",
+				Chart:    &kops.HelmChartSpec{Repository: "oci://registry.example.com/charts", Name: "example", Version: "1.2.3"},
+			}},
+			ExpectedErrors: []string{"Forbidden::spec.addons[0].chart"},
+		},
+		{
+			Input: []kops.AddonSpec{{Chart: &kops.HelmChartSpec{}}},
+			ExpectedErrors: []string{
+				"Required value::spec.addons[0].chart.repository",
+				"Required value::spec.addons[0].chart.name",
+				"Required value::spec.addons[0].chart.version",
+			},
+		},
+		{
+			Input: []kops.AddonSpec{
+				{Chart: &kops.HelmChartSpec{Repository: "oci://registry.example.com/charts", Name: "example", Version: "1.2.3"}},
+				{Chart: &kops.HelmChartSpec{Repository: "oci://registry.example.com/charts", Name: "other", Version: "1.2.3", ReleaseName: "example"}},
+			},
+			ExpectedErrors: []string{"Duplicate value::spec.addons[1].chart.releaseName"},
+		},
+		{
+			Input: []kops.AddonSpec{{Chart: &kops.HelmChartSpec{
+				Repository:  "oci://registry.example.com/charts",
+				Name:        "example",
+				Version:     "1.2.3",
+				ReleaseName: "a-release-name-that-is-much-too-long-for-an-addon",
+			}}},
+			ExpectedErrors: []string{"Too long::spec.addons[0].chart.releaseName"},
+		},
+		{
+			Input: []kops.AddonSpec{{Chart: &kops.HelmChartSpec{
+				Repository: "oci://registry.example.com/charts",
+				Name:       "example",
+				Version:    "1.2.3",
+				Values:     "- not\n- a map\n",
+			}}},
+			ExpectedErrors: []string{"Invalid value::spec.addons[0].chart.values"},
+		},
 	}
 	for _, g := range grid {
 		clusterSpec := &kops.ClusterSpec{
@@ -824,6 +951,202 @@ func Test_Validate_Addons(t *testing.T) {
 	}
 }
 
+func Test_Validate_AddonPatches(t *testing.T) {
+	target := kops.AddonPatchTarget{Group: "apps", Kind: "Deployment", Namespace: "kube-system", Name: "coredns"}
+	grid := []struct {
+		Input          kops.AddonPatchSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.AddonPatchSpec{
+				Addon:          "coredns.addons.k8s.io",
+				Target:         target,
+				StrategicMerge: "spec:\n  replicas: 3\n",
+			},
+		},
+		{
+			Input: kops.AddonPatchSpec{
+				Addon:    "coredns.addons.k8s.io",
+				Target:   target,
+				JSON6902: "- op: replace\n  path: /spec/replicas\n  value: 3\n",
+			},
+		},
+		{
+			Input: kops.AddonPatchSpec{},
+			ExpectedErrors: []string{
+				"Required value::spec.addonPatches[0].addon",
+				"Required value::spec.addonPatches[0].target.kind",
+				"Required value::spec.addonPatches[0].target.name",
+				"Required value::spec.addonPatches[0]",
+			},
+		},
+		{
+			Input: kops.AddonPatchSpec{
+				Addon:          "coredns.addons.k8s.io",
+				Target:         target,
+				StrategicMerge: "- not a map\n",
+			},
+			ExpectedErrors: []string{"Invalid value::spec.addonPatches[0].strategicMerge"},
+		},
+		{
+			Input: kops.AddonPatchSpec{
+				Addon:    "coredns.addons.k8s.io",
+				Target:   target,
+				JSON6902: "op: replace\n",
+			},
+			ExpectedErrors: []string{"Invalid value::spec.addonPatches[0].json6902"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateAddonPatch(g.Input, field.NewPath("spec", "addonPatches").Index(0))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_NodeAttestation(t *testing.T) {
+	caCertificate, _, _, err := pki.IssueCert(t.Context(), &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "example.org"},
+	}, nil)
+	require.NoError(t, err)
+	trustBundle, err := caCertificate.AsString()
+	require.NoError(t, err)
+
+	metal := &kops.Cluster{}
+	metal.Labels = map[string]string{kops.AlphaLabelCloudProvider: "metal"}
+	aws := &kops.Cluster{}
+	aws.Spec.CloudProvider.AWS = &kops.AWSSpec{}
+
+	grid := []struct {
+		Cluster        *kops.Cluster
+		Input          kops.NodeAttestationSpec
+		ExpectedErrors []string
+	}{
+		{
+			Cluster: metal,
+			Input: kops.NodeAttestationSpec{
+				TPM: &kops.TPMAttestationSpec{PCRs: map[string]string{"7": strings.Repeat("ab", 32)}},
+				SPIFFE: &kops.SPIFFEAttestationSpec{
+					TrustDomain: "example.org",
+					TrustBundle: trustBundle,
+					PathPrefix:  "/clusters/dev",
+				},
+			},
+		},
+		{
+			Cluster:        aws,
+			Input:          kops.NodeAttestationSpec{TPM: &kops.TPMAttestationSpec{}},
+			ExpectedErrors: []string{"Forbidden::spec.nodeAttestation"},
+		},
+		{
+			Cluster:        metal,
+			Input:          kops.NodeAttestationSpec{},
+			ExpectedErrors: []string{"Required value::spec.nodeAttestation"},
+		},
+		{
+			Cluster: metal,
+			Input: kops.NodeAttestationSpec{
+				TPM: &kops.TPMAttestationSpec{PCRs: map[string]string{"24": strings.Repeat("ab", 32), "7": "abcd"}},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.nodeAttestation.tpm.pcrs[24]",
+				"Invalid value::spec.nodeAttestation.tpm.pcrs[7]",
+			},
+		},
+		{
+			Cluster: metal,
+			Input: kops.NodeAttestationSpec{
+				SPIFFE: &kops.SPIFFEAttestationSpec{TrustDomain: "spiffe://Example.org", TrustBundle: trustBundle},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.nodeAttestation.spiffe.trustDomain"},
+		},
+		{
+			Cluster: metal,
+			Input: kops.NodeAttestationSpec{
+				SPIFFE: &kops.SPIFFEAttestationSpec{TrustDomain: "example.org", PathPrefix: "kops/"},
+			},
+			ExpectedErrors: []string{
+				"Required value::spec.nodeAttestation.spiffe.trustBundle",
+				"Invalid value::spec.nodeAttestation.spiffe.pathPrefix",
+			},
+		},
+		{
+			Cluster: metal,
+			Input: kops.NodeAttestationSpec{
+				SPIFFE: &kops.SPIFFEAttestationSpec{TrustDomain: "example.org", TrustBundle: "not a bundle"},
+			},
+			ExpectedErrors: []string{"Invalid value::spec.nodeAttestation.spiffe.trustBundle"},
+		},
+	}
+	for _, g := range grid {
+		errs := validateNodeAttestation(g.Cluster, &g.Input, field.NewPath("spec", "nodeAttestation"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_KopsControllerBootstrap(t *testing.T) {
+	grid := []struct {
+		Input          kops.KopsControllerBootstrapSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.KopsControllerBootstrapSpec{
+				RequestsPerMinute:     ptr.To(int32(0)),
+				Burst:                 ptr.To(int32(50)),
+				NodeRequestsPerMinute: ptr.To(int32(2)),
+				NodeBurst:             ptr.To(int32(1)),
+				MinReissueInterval:    &metav1.Duration{Duration: 10 * time.Minute},
+			},
+		},
+		{
+			Input: kops.KopsControllerBootstrapSpec{
+				RequestsPerMinute:     ptr.To(int32(-1)),
+				Burst:                 ptr.To(int32(0)),
+				NodeRequestsPerMinute: ptr.To(int32(-1)),
+				NodeBurst:             ptr.To(int32(0)),
+				MinReissueInterval:    &metav1.Duration{Duration: -time.Minute},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.kopsController.bootstrap.requestsPerMinute",
+				"Invalid value::spec.kopsController.bootstrap.burst",
+				"Invalid value::spec.kopsController.bootstrap.nodeRequestsPerMinute",
+				"Invalid value::spec.kopsController.bootstrap.nodeBurst",
+				"Invalid value::spec.kopsController.bootstrap.minReissueInterval",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateKopsControllerBootstrap(&g.Input, field.NewPath("spec", "kopsController", "bootstrap"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
+func Test_Validate_KopsControllerMetrics(t *testing.T) {
+	grid := []struct {
+		Input          kops.KopsControllerMetricsSpec
+		ExpectedErrors []string
+	}{
+		{
+			Input: kops.KopsControllerMetricsSpec{
+				Expose:                   ptr.To(true),
+				CertificateExpiryHorizon: &metav1.Duration{Duration: 14 * 24 * time.Hour},
+			},
+		},
+		{
+			Input: kops.KopsControllerMetricsSpec{
+				CertificateExpiryHorizon: &metav1.Duration{},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::spec.kopsController.metrics.certificateExpiryHorizon",
+			},
+		},
+	}
+	for _, g := range grid {
+		errs := validateKopsControllerMetrics(&g.Input, field.NewPath("spec", "kopsController", "metrics"))
+		testErrors(t, g.Input, errs, g.ExpectedErrors)
+	}
+}
+
 type caliInput struct {
 	Cluster *kops.ClusterSpec
 	Calico  *kops.CalicoNetworkingSpec
@@ -1450,6 +1773,76 @@ func Test_Validate_RollingUpdate(t *testing.T) {
 			},
 			ExpectedErrors: []string{"Invalid value::testField.maxUnavailable"},
 		},
+		{
+			Input: kops.RollingUpdate{
+				MaintenanceWindow: &kops.MaintenanceWindowSpec{
+					Days:      []string{"Mon-Fri"},
+					StartTime: "22:00",
+					EndTime:   "06:00",
+					TimeZone:  "Europe/Berlin",
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaintenanceWindow: &kops.MaintenanceWindowSpec{
+					Days:      []string{"Someday"},
+					StartTime: "22:00",
+					EndTime:   "06:00",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.maintenanceWindow"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				MaintenanceWindow: &kops.MaintenanceWindowSpec{
+					StartTime: "22:00",
+				},
+			},
+			ExpectedErrors: []string{"Invalid value::testField.maintenanceWindow"},
+		},
+		{
+			Input: kops.RollingUpdate{
+				ValidationGates: []kops.ValidationGateSpec{
+					{
+						Name:       "slo",
+						Prometheus: &kops.PrometheusValidationGateSpec{URL: "https://prometheus.example.com", Query: "vector(1)"},
+					},
+					{
+						Name: "probe",
+						HTTP: &kops.HTTPValidationGateSpec{URL: "http://app.example.com/healthz"},
+					},
+					{
+						Name:     "app",
+						Workload: &kops.WorkloadValidationGateSpec{Kind: "Deployment", Namespace: "default", Name: "app", MinAvailable: intStr(intstr.FromString("90%"))},
+					},
+				},
+			},
+		},
+		{
+			Input: kops.RollingUpdate{
+				ValidationGates: []kops.ValidationGateSpec{
+					{
+						Name: "probe",
+						HTTP: &kops.HTTPValidationGateSpec{URL: "app.example.com/healthz"},
+					},
+					{
+						Name: "probe",
+					},
+					{
+						Name:     "app",
+						Workload: &kops.WorkloadValidationGateSpec{Kind: "ReplicaSet", Namespace: "default", Name: "app"},
+					},
+				},
+			},
+			ExpectedErrors: []string{
+				"Invalid value::testField.validationGates[0].http.url",
+				"Duplicate value::testField.validationGates[1].name",
+				"Invalid value::testField.validationGates[1]",
+				"Unsupported value::testField.validationGates[2].workload.kind",
+				"Required value::testField.validationGates[2].workload.minAvailable",
+			},
+		},
 		{
 			Input: kops.RollingUpdate{
 				MaxSurge: intStr(intstr.FromInt(0)),
@@ -158,3 +158,44 @@ func (a *ApplySet) ApplyOnce(ctx context.Context) (*ApplyResults, error) {
 	}
 	return results, nil
 }
+
+// CheckHealth reads the current state of all objects and reports whether they are healthy.
+// Unlike the health reported by ApplyOnce, workloads must also have finished rolling out,
+// so this can be polled after applying to wait for the objects to converge.
+func (a *ApplySet) CheckHealth(ctx context.Context) (*HealthResults, error) {
+	// snapshot the state
+	a.mutex.Lock()
+	trackers := a.trackers
+	a.mutex.Unlock()
+
+	client := &UnstructuredClient{
+		client:     a.client,
+		restMapper: a.restMapper,
+	}
+
+	results := &HealthResults{}
+
+	for i := range trackers.items {
+		tracker := &trackers.items[i]
+		expectedObject := tracker.desired
+
+		gvk := expectedObject.GroupVersionKind()
+		nn := types.NamespacedName{Namespace: expectedObject.GetNamespace(), Name: expectedObject.GetName()}
+
+		currentObj, err := client.Get(ctx, gvk, nn)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				results.reportUnhealthy(gvk, nn, "not found")
+				continue
+			}
+			return nil, fmt.Errorf("error getting %v %v: %w", gvk, nn, err)
+		}
+
+		if !isHealthy(currentObj) {
+			results.reportUnhealthy(gvk, nn, "not ready")
+		} else if !isRolledOut(currentObj) {
+			results.reportUnhealthy(gvk, nn, "rolling out")
+		}
+	}
+	return results, nil
+}
@@ -164,6 +164,68 @@ func isHealthy(u *unstructured.Unstructured) bool {
 	return ready
 }
 
+// isRolledOut reports whether a workload has finished rolling out its current spec, following
+// the checks made by "kubectl rollout status". isHealthy only looks at conditions, which report
+// a Deployment as "Available" while its old replicas still serve, even if the new ones crash.
+// Objects of other kinds have no rollout, and are reported as rolled out.
+func isRolledOut(u *unstructured.Unstructured) bool {
+	gk := u.GroupVersionKind().GroupKind()
+	switch gk {
+	case schema.GroupKind{Group: "apps", Kind: "Deployment"},
+		schema.GroupKind{Group: "apps", Kind: "DaemonSet"},
+		schema.GroupKind{Group: "apps", Kind: "StatefulSet"}:
+	default:
+		return true
+	}
+
+	observedGeneration, found, _ := unstructured.NestedInt64(u.Object, "status", "observedGeneration")
+	if !found || observedGeneration < u.GetGeneration() {
+		klog.Infof("object %s has not been observed by its controller", humanName(u))
+		return false
+	}
+
+	status := func(field string) int64 {
+		v, _, _ := unstructured.NestedInt64(u.Object, "status", field)
+		return v
+	}
+	replicas, found, _ := unstructured.NestedInt64(u.Object, "spec", "replicas")
+	if !found {
+		replicas = 1
+	}
+
+	// Like "kubectl rollout status", we only follow rollouts that the controller performs.
+	// With the OnDelete strategy, pods are only replaced when something else deletes them,
+	// so the rollout is complete once the controller has observed the new spec.
+	if gk.Kind == "DaemonSet" || gk.Kind == "StatefulSet" {
+		strategy, _, _ := unstructured.NestedString(u.Object, "spec", "updateStrategy", "type")
+		if strategy == "OnDelete" {
+			return true
+		}
+	}
+
+	switch gk.Kind {
+	case "Deployment":
+		if status("updatedReplicas") < replicas || status("replicas") > status("updatedReplicas") || status("availableReplicas") < status("updatedReplicas") {
+			klog.Infof("deployment %s is rolling out", humanName(u))
+			return false
+		}
+	case "DaemonSet":
+		desired := status("desiredNumberScheduled")
+		if status("updatedNumberScheduled") < desired || status("numberAvailable") < desired {
+			klog.Infof("daemonset %s is rolling out", humanName(u))
+			return false
+		}
+	case "StatefulSet":
+		updateRevision, _, _ := unstructured.NestedString(u.Object, "status", "updateRevision")
+		currentRevision, _, _ := unstructured.NestedString(u.Object, "status", "currentRevision")
+		if status("readyReplicas") < replicas || updateRevision != currentRevision {
+			klog.Infof("statefulset %s is rolling out", humanName(u))
+			return false
+		}
+	}
+	return true
+}
+
 // humanName returns an identifier for the object suitable for printing in log messages
 func humanName(u *unstructured.Unstructured) string {
 	gvk := u.GroupVersionKind()
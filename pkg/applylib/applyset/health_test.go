@@ -257,6 +257,109 @@ func TestIsHealthy(t *testing.T) {
 	}
 }
 
+func TestIsRolledOut(t *testing.T) {
+	grid := []struct {
+		name   string
+		object map[string]interface{}
+		want   bool
+	}{
+		{
+			name: "kind without rollout",
+			object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]interface{}{"name": "svc"},
+			},
+			want: true,
+		},
+		{
+			name:   "deployment not yet observed",
+			object: workload("Deployment", 2, 1, map[string]interface{}{"replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(1)}),
+			want:   false,
+		},
+		{
+			name:   "deployment rolled out",
+			object: workload("Deployment", 2, 2, map[string]interface{}{"replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(1)}),
+			want:   true,
+		},
+		{
+			name:   "deployment with old replicas remaining",
+			object: workload("Deployment", 2, 2, map[string]interface{}{"replicas": int64(2), "updatedReplicas": int64(1), "availableReplicas": int64(2)}),
+			want:   false,
+		},
+		{
+			name:   "deployment with new replicas unavailable",
+			object: workload("Deployment", 2, 2, map[string]interface{}{"replicas": int64(1), "updatedReplicas": int64(1), "availableReplicas": int64(0)}),
+			want:   false,
+		},
+		{
+			name:   "daemonset rolled out",
+			object: workload("DaemonSet", 3, 3, map[string]interface{}{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberAvailable": int64(3)}),
+			want:   true,
+		},
+		{
+			name:   "daemonset with pods not updated",
+			object: workload("DaemonSet", 3, 3, map[string]interface{}{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(2), "numberAvailable": int64(3)}),
+			want:   false,
+		},
+		{
+			name:   "daemonset with OnDelete strategy and pods not updated",
+			object: withUpdateStrategy(workload("DaemonSet", 3, 3, map[string]interface{}{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(0), "numberAvailable": int64(3)}), "OnDelete"),
+			want:   true,
+		},
+		{
+			name:   "daemonset with OnDelete strategy not yet observed",
+			object: withUpdateStrategy(workload("DaemonSet", 3, 2, map[string]interface{}{"desiredNumberScheduled": int64(3), "updatedNumberScheduled": int64(3), "numberAvailable": int64(3)}), "OnDelete"),
+			want:   false,
+		},
+		{
+			name:   "daemonset with RollingUpdate strategy part way through a slow rollout",
+			object: withUpdateStrategy(workload("DaemonSet", 3, 3, map[string]interface{}{"desiredNumberScheduled": int64(500), "updatedNumberScheduled": int64(120), "numberAvailable": int64(499)}), "RollingUpdate"),
+			want:   false,
+		},
+		{
+			name:   "statefulset with OnDelete strategy and pods not updated",
+			object: withUpdateStrategy(workload("StatefulSet", 1, 1, map[string]interface{}{"readyReplicas": int64(1), "currentRevision": "a", "updateRevision": "b"}), "OnDelete"),
+			want:   true,
+		},
+		{
+			name:   "statefulset rolled out",
+			object: workload("StatefulSet", 1, 1, map[string]interface{}{"readyReplicas": int64(1), "currentRevision": "a", "updateRevision": "a"}),
+			want:   true,
+		},
+		{
+			name:   "statefulset updating",
+			object: workload("StatefulSet", 1, 1, map[string]interface{}{"readyReplicas": int64(1), "currentRevision": "a", "updateRevision": "b"}),
+			want:   false,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			u := &unstructured.Unstructured{Object: g.object}
+			if got := isRolledOut(u); got != g.want {
+				t.Errorf("isRolledOut() = %v, want %v", got, g.want)
+			}
+		})
+	}
+}
+
+func workload(kind string, generation, observedGeneration int64, status map[string]interface{}) map[string]interface{} {
+	status["observedGeneration"] = observedGeneration
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       kind,
+		"metadata":   map[string]interface{}{"name": "workload", "generation": generation},
+		"spec":       map[string]interface{}{},
+		"status":     status,
+	}
+}
+
+func withUpdateStrategy(object map[string]interface{}, strategy string) map[string]interface{} {
+	object["spec"].(map[string]interface{})["updateStrategy"] = map[string]interface{}{"type": strategy}
+	return object
+}
+
 func condition(conditionType, status string) map[string]interface{} {
 	return map[string]interface{}{
 		"type":   conditionType,
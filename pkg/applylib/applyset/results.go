@@ -76,3 +76,27 @@ func (r *ApplyResults) reportHealth(gvk schema.GroupVersionKind, nn types.Namesp
 		r.unhealthyCount++
 	}
 }
+
+// HealthResults contains the results of a CheckHealth operation.
+type HealthResults struct {
+	unhealthy []string
+}
+
+// AllHealthy is true if all the objects exist, are healthy and have finished rolling out.
+func (r *HealthResults) AllHealthy() bool {
+	return len(r.unhealthy) == 0
+}
+
+// Unhealthy returns a description of each object that is not healthy.
+func (r *HealthResults) Unhealthy() []string {
+	return r.unhealthy
+}
+
+// reportUnhealthy records that an object is not healthy.
+func (r *HealthResults) reportUnhealthy(gvk schema.GroupVersionKind, nn types.NamespacedName, reason string) {
+	s := gvk.Kind + ":" + nn.Name
+	if nn.Namespace != "" {
+		s = gvk.Kind + ":" + nn.Namespace + "/" + nn.Name
+	}
+	r.unhealthy = append(r.unhealthy, s+" ("+reason+")")
+}
@@ -0,0 +1,312 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package backup snapshots the state of a cluster, so that the control plane can be rebuilt from a known point.
+// A backup records three things that must be restored together: the files in the state store,
+// the etcd-manager backups of the etcd clusters, and the versions of the addons installed by channels.
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/client/simple/vfsclientset"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kops/util/pkg/vfs/acls"
+)
+
+// PathBackups is the path (relative to the cluster's config base) where backups are stored
+const PathBackups = "backups/kops"
+
+const (
+	// manifestFile is the name of the file describing a backup, in the backup's directory
+	manifestFile = "backup.json"
+	// stateDir is the directory of the backup holding the copy of the state store files
+	stateDir = "state"
+
+	// etcdBackupMetaFile is the file etcd-manager writes alongside each backup
+	etcdBackupMetaFile = "_etcd_backup.meta"
+	// etcdControlDir is the directory of the etcd-manager backup store holding commands
+	etcdControlDir = "control"
+	// etcdCommandFile is the file etcd-manager reads a command from
+	etcdCommandFile = "_command.json"
+)
+
+// Backup describes a snapshot of the state of a cluster
+type Backup struct {
+	// Name identifies the backup
+	Name string `json:"name"`
+	// Timestamp is when the backup was created
+	Timestamp time.Time `json:"timestamp"`
+	// KopsVersion is the version of kOps that created the backup
+	KopsVersion string `json:"kopsVersion,omitempty"`
+
+	// Files are the files copied from the state store
+	Files []File `json:"files"`
+	// Etcd references the latest etcd-manager backup of each etcd cluster
+	Etcd []EtcdBackup `json:"etcd,omitempty"`
+	// ChannelVersions are the installed addon versions, by namespace and addon name
+	ChannelVersions map[string]map[string]*channels.ChannelVersion `json:"channelVersions,omitempty"`
+}
+
+// File is a file copied from the state store
+type File struct {
+	// Path is relative to the cluster's config base
+	Path string `json:"path"`
+	// SHA256 is the hex-encoded hash of the contents
+	SHA256 string `json:"sha256"`
+}
+
+// EtcdBackup references a backup taken by etcd-manager
+type EtcdBackup struct {
+	// Cluster is the name of the etcd cluster, e.g. main or events
+	Cluster string `json:"cluster"`
+	// BackupStore is where etcd-manager stores backups for the etcd cluster
+	BackupStore string `json:"backupStore"`
+	// Backup is the name of the etcd-manager backup
+	Backup string `json:"backup"`
+}
+
+// NewName returns the default name for a backup created at the given time
+func NewName(now time.Time) string {
+	return now.UTC().Format("20060102T150405Z")
+}
+
+// Excluded returns true if the file (relative to the config base) is not part of the snapshot of the state store.
+// Backups themselves, the lock and the cluster history are excluded, and are never overwritten by a restore.
+func Excluded(relativePath string) bool {
+	if relativePath == "" || relativePath == statelock.PathLock {
+		return true
+	}
+	return strings.HasPrefix(relativePath, "backups/") || strings.HasPrefix(relativePath, vfsclientset.PathHistory+"/")
+}
+
+// Create snapshots the state of the cluster into a new backup.
+// The channel versions are read from the cluster by the caller, as Create only has access to the state store.
+func Create(ctx context.Context, vfsContext *vfs.VFSContext, configBase vfs.Path, cluster *kops.Cluster, name string, channelVersions map[string]map[string]*channels.ChannelVersion) (*Backup, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid backup name %q", name)
+	}
+	backupPath := configBase.Join(PathBackups, name)
+	if _, err := backupPath.Join(manifestFile).ReadFile(ctx); err == nil {
+		return nil, fmt.Errorf("backup %q already exists", name)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("checking for backup %q: %w", name, err)
+	}
+
+	b := &Backup{
+		Name:            name,
+		Timestamp:       time.Now().UTC(),
+		KopsVersion:     kopsbase.Version,
+		ChannelVersions: channelVersions,
+	}
+
+	// etcd-manager takes backups periodically, so we reference the latest one rather than taking our own
+	for _, etcdCluster := range cluster.Spec.EtcdClusters {
+		backupStore, err := etcdBackupStore(vfsContext, configBase, etcdCluster)
+		if err != nil {
+			return nil, err
+		}
+		latest, err := latestEtcdBackup(ctx, backupStore)
+		if err != nil {
+			return nil, err
+		}
+		if latest == "" {
+			return nil, fmt.Errorf("no etcd-manager backups found for etcd cluster %q in %s", etcdCluster.Name, backupStore)
+		}
+		b.Etcd = append(b.Etcd, EtcdBackup{
+			Cluster:     etcdCluster.Name,
+			BackupStore: backupStore.Path(),
+			Backup:      latest,
+		})
+	}
+
+	paths, err := configBase.ReadTree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing files in %s: %w", configBase, err)
+	}
+	for _, p := range paths {
+		relativePath, err := vfs.RelativePath(configBase, p)
+		if err != nil {
+			return nil, err
+		}
+		if Excluded(relativePath) {
+			continue
+		}
+
+		data, err := p.ReadFile(ctx)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// Deleted since we listed it
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", p, err)
+		}
+		hash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFile(ctx, backupPath.Join(stateDir, relativePath), data, cluster); err != nil {
+			return nil, err
+		}
+		b.Files = append(b.Files, File{Path: relativePath, SHA256: hash.Hex()})
+	}
+	if len(b.Files) == 0 {
+		return nil, fmt.Errorf("no files found in %s", configBase)
+	}
+	sort.Slice(b.Files, func(i, j int) bool { return b.Files[i].Path < b.Files[j].Path })
+
+	// The manifest is written last, so that an interrupted backup is not listed
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("serializing backup: %w", err)
+	}
+	if err := writeFile(ctx, backupPath.Join(manifestFile), data, cluster); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// List returns the backups of the cluster, oldest first
+func List(ctx context.Context, configBase vfs.Path) ([]*Backup, error) {
+	paths, err := configBase.Join(PathBackups).ReadTree(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("listing backups: %w", err)
+	}
+
+	var backups []*Backup
+	for _, p := range paths {
+		if p.Base() != manifestFile {
+			continue
+		}
+		relativePath, err := vfs.RelativePath(configBase.Join(PathBackups), p)
+		if err != nil {
+			return nil, err
+		}
+		if strings.Contains(path.Dir(relativePath), "/") {
+			// A file named like the manifest inside a backup's copy of the state store
+			continue
+		}
+		b, err := readManifest(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		backups = append(backups, b)
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Timestamp.Before(backups[j].Timestamp)
+	})
+	return backups, nil
+}
+
+// Get returns the backup with the given name
+func Get(ctx context.Context, configBase vfs.Path, name string) (*Backup, error) {
+	if name == "" || strings.Contains(name, "/") {
+		return nil, fmt.Errorf("invalid backup name %q", name)
+	}
+	b, err := readManifest(ctx, configBase.Join(PathBackups, name, manifestFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("backup %q not found", name)
+		}
+		return nil, err
+	}
+	return b, nil
+}
+
+func readManifest(ctx context.Context, p vfs.Path) (*Backup, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	b := &Backup{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", p, err)
+	}
+	return b, nil
+}
+
+// etcdBackupStore returns where etcd-manager stores backups for the etcd cluster,
+// matching the default applied when the cluster is built.
+func etcdBackupStore(vfsContext *vfs.VFSContext, configBase vfs.Path, etcdCluster kops.EtcdClusterSpec) (vfs.Path, error) {
+	if etcdCluster.Backups == nil || etcdCluster.Backups.BackupStore == "" {
+		return configBase.Join("backups", "etcd", etcdCluster.Name), nil
+	}
+	p, err := vfsContext.BuildVfsPath(etcdCluster.Backups.BackupStore)
+	if err != nil {
+		return nil, fmt.Errorf("parsing backupStore for etcd cluster %q: %w", etcdCluster.Name, err)
+	}
+	return p, nil
+}
+
+// latestEtcdBackup returns the name of the newest complete etcd-manager backup in the backup store,
+// or "" if there are none.
+// etcd-manager names backups by timestamp, and writes the meta file once the backup is complete.
+func latestEtcdBackup(ctx context.Context, backupStore vfs.Path) (string, error) {
+	paths, err := backupStore.ReadTree(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("listing etcd backups in %s: %w", backupStore, err)
+	}
+
+	latest := ""
+	for _, p := range paths {
+		if p.Base() != etcdBackupMetaFile {
+			continue
+		}
+		relativePath, err := vfs.RelativePath(backupStore, p)
+		if err != nil {
+			return "", err
+		}
+		name := path.Dir(relativePath)
+		if name == "." || strings.Contains(name, "/") || name == etcdControlDir {
+			continue
+		}
+		if name > latest {
+			latest = name
+		}
+	}
+	return latest, nil
+}
+
+func writeFile(ctx context.Context, p vfs.Path, data []byte, cluster *kops.Cluster) error {
+	acl, err := acls.GetACL(ctx, p, cluster)
+	if err != nil {
+		return err
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("writing %s: %w", p, err)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/channels/pkg/channels"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestBackupAndRestore(t *testing.T) {
+	ctx := context.Background()
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	write := func(p vfs.Path, s string) {
+		require.NoError(t, p.WriteFile(ctx, bytes.NewReader([]byte(s)), nil))
+	}
+	read := func(p vfs.Path) string {
+		b, err := p.ReadFile(ctx)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	configBase, err := vfsContext.BuildVfsPath("memfs://state/test.example.com")
+	require.NoError(t, err)
+	eventsStore, err := vfsContext.BuildVfsPath("memfs://etcd-backups/events")
+	require.NoError(t, err)
+
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "test.example.com"
+	cluster.Spec.EtcdClusters = []kops.EtcdClusterSpec{
+		{Name: "main"},
+		{Name: "events", Backups: &kops.EtcdBackupSpec{BackupStore: eventsStore.Path()}},
+	}
+
+	write(configBase.Join("config"), "v1")
+	write(configBase.Join("instancegroup/nodes"), "nodes-v1")
+	write(configBase.Join("pki/private/kubernetes-ca/keyset.yaml"), "ca")
+	write(configBase.Join(statelock.PathLock), "{}")
+	write(configBase.Join("history/00000001"), "{}")
+
+	mainStore := configBase.Join("backups/etcd/main")
+	write(mainStore.Join("2026-10-16T10:00:00Z-000001", etcdBackupMetaFile), `{"clusterSpec":{"memberCount":3,"etcdVersion":"3.5.21"}}`)
+	write(mainStore.Join("2026-10-17T10:00:00Z-000002", etcdBackupMetaFile), `{"clusterSpec":{"memberCount":3,"etcdVersion":"3.5.21"}}`)
+	write(mainStore.Join("2026-10-17T10:15:00Z-000003", "etcd.backup.gz"), "incomplete")
+	write(mainStore.Join("control/etcd-cluster-spec"), "{}")
+	write(eventsStore.Join("2026-10-17T10:05:00Z-000001", etcdBackupMetaFile), `{"clusterSpec":{"memberCount":1,"etcdVersion":"3.5.21"}}`)
+
+	channelVersions := map[string]map[string]*channels.ChannelVersion{
+		"kube-system": {
+			"coredns.addons.k8s.io": {Id: "k8s-1.12", ManifestHash: "abc", SystemGeneration: 1},
+		},
+	}
+
+	b, err := Create(ctx, vfsContext, configBase, cluster, "first", channelVersions)
+	require.NoError(t, err)
+
+	var files []string
+	for _, file := range b.Files {
+		files = append(files, file.Path)
+	}
+	assert.Equal(t, []string{"config", "instancegroup/nodes", "pki/private/kubernetes-ca/keyset.yaml"}, files)
+	assert.Equal(t, []EtcdBackup{
+		{Cluster: "main", BackupStore: mainStore.Path(), Backup: "2026-10-17T10:00:00Z-000002"},
+		{Cluster: "events", BackupStore: eventsStore.Path(), Backup: "2026-10-17T10:05:00Z-000001"},
+	}, b.Etcd)
+
+	_, err = Create(ctx, vfsContext, configBase, cluster, "first", channelVersions)
+	assert.ErrorContains(t, err, "already exists")
+
+	backups, err := List(ctx, configBase)
+	require.NoError(t, err)
+	require.Len(t, backups, 1)
+	assert.Equal(t, "first", backups[0].Name)
+	assert.Equal(t, channelVersions, backups[0].ChannelVersions)
+
+	// Change the state after the backup
+	write(configBase.Join("config"), "v2")
+	write(configBase.Join("instancegroup/extra"), "extra")
+
+	b, err = Get(ctx, configBase, "first")
+	require.NoError(t, err)
+	restore, err := PlanRestore(ctx, vfsContext, configBase, b)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"config"}, restore.Write)
+	assert.Equal(t, []string{"instancegroup/extra"}, restore.Delete)
+
+	require.NoError(t, restore.Apply(ctx, vfsContext, cluster))
+	assert.Equal(t, "v1", read(configBase.Join("config")))
+	_, err = configBase.Join("instancegroup/extra").ReadFile(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, "{}", read(configBase.Join(statelock.PathLock)))
+	assert.Equal(t, "{}", read(configBase.Join("history/00000001")))
+
+	for _, store := range []vfs.Path{mainStore, eventsStore} {
+		paths, err := store.Join(etcdControlDir).ReadTree(ctx)
+		require.NoError(t, err)
+		var commands []string
+		for _, p := range paths {
+			if p.Base() == etcdCommandFile {
+				commands = append(commands, read(p))
+			}
+		}
+		require.Len(t, commands, 1, "restore commands in %s", store)
+
+		command := &etcdCommand{}
+		require.NoError(t, json.Unmarshal([]byte(commands[0]), command))
+		require.NotNil(t, command.RestoreBackup)
+		assert.True(t, strings.HasPrefix(command.RestoreBackup.Backup, "2026-10-17T10:0"))
+		assert.Contains(t, string(command.RestoreBackup.ClusterSpec), `"memberCount"`)
+	}
+
+	restore, err = PlanRestore(ctx, vfsContext, configBase, b)
+	require.NoError(t, err)
+	assert.Empty(t, restore.Write)
+	assert.Empty(t, restore.Delete)
+}
+
+func TestRestoreDetectsCorruptBackup(t *testing.T) {
+	ctx := context.Background()
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+
+	configBase, err := vfsContext.BuildVfsPath("memfs://state/test.example.com")
+	require.NoError(t, err)
+	require.NoError(t, configBase.Join("config").WriteFile(ctx, bytes.NewReader([]byte("v1")), nil))
+
+	cluster := &kops.Cluster{}
+	cluster.ObjectMeta.Name = "test.example.com"
+
+	b, err := Create(ctx, vfsContext, configBase, cluster, "first", nil)
+	require.NoError(t, err)
+
+	require.NoError(t, configBase.Join(PathBackups, "first", stateDir, "config").WriteFile(ctx, bytes.NewReader([]byte("tampered")), nil))
+	_, err = PlanRestore(ctx, vfsContext, configBase, b)
+	assert.ErrorContains(t, err, "does not match the hash recorded")
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Restore is a plan to restore the state of a cluster from a backup
+type Restore struct {
+	// Backup is the backup being restored
+	Backup *Backup
+
+	// Write lists the files that will be written, because they are missing or differ from the backup
+	Write []string
+	// Delete lists the files that will be deleted, because they are not in the backup
+	Delete []string
+
+	configBase vfs.Path
+	// contents holds the verified contents of the files to write
+	contents map[string][]byte
+	// etcdClusterSpecs holds the etcd cluster spec recorded by etcd-manager for each etcd backup
+	etcdClusterSpecs map[string]json.RawMessage
+}
+
+// etcdBackupInfo is the part of the etcd-manager backup meta file that we need
+type etcdBackupInfo struct {
+	ClusterSpec json.RawMessage `json:"clusterSpec,omitempty"`
+}
+
+// etcdCommand is an etcd-manager command, in the JSON form etcd-manager reads
+type etcdCommand struct {
+	Timestamp     int64                     `json:"timestamp,string"`
+	RestoreBackup *etcdRestoreBackupCommand `json:"restoreBackup,omitempty"`
+}
+
+type etcdRestoreBackupCommand struct {
+	ClusterSpec json.RawMessage `json:"clusterSpec,omitempty"`
+	Backup      string          `json:"backup"`
+}
+
+// PlanRestore builds the plan to restore the state of a cluster from a backup.
+// The copy of each file in the backup is checked against the hash recorded when the backup was created,
+// and each referenced etcd-manager backup must still exist.
+func PlanRestore(ctx context.Context, vfsContext *vfs.VFSContext, configBase vfs.Path, b *Backup) (*Restore, error) {
+	r := &Restore{
+		Backup:           b,
+		configBase:       configBase,
+		contents:         make(map[string][]byte),
+		etcdClusterSpecs: make(map[string]json.RawMessage),
+	}
+
+	current := make(map[string]string)
+	paths, err := configBase.ReadTree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing files in %s: %w", configBase, err)
+	}
+	for _, p := range paths {
+		relativePath, err := vfs.RelativePath(configBase, p)
+		if err != nil {
+			return nil, err
+		}
+		if Excluded(relativePath) {
+			continue
+		}
+		data, err := p.ReadFile(ctx)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", p, err)
+		}
+		hash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		current[relativePath] = hash.Hex()
+	}
+
+	backupPath := configBase.Join(PathBackups, b.Name, stateDir)
+	inBackup := make(map[string]bool)
+	for _, file := range b.Files {
+		inBackup[file.Path] = true
+
+		p := backupPath.Join(file.Path)
+		data, err := p.ReadFile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", p, err)
+		}
+		hash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		if hash.Hex() != file.SHA256 {
+			return nil, fmt.Errorf("hash of %s (%s) does not match the hash recorded in backup %q (%s)", p, hash.Hex(), b.Name, file.SHA256)
+		}
+
+		if current[file.Path] != file.SHA256 {
+			r.Write = append(r.Write, file.Path)
+			r.contents[file.Path] = data
+		}
+	}
+	for relativePath := range current {
+		if !inBackup[relativePath] {
+			r.Delete = append(r.Delete, relativePath)
+		}
+	}
+	sort.Strings(r.Delete)
+
+	for _, etcd := range b.Etcd {
+		backupStore, err := vfsContext.BuildVfsPath(etcd.BackupStore)
+		if err != nil {
+			return nil, fmt.Errorf("parsing backup store for etcd cluster %q: %w", etcd.Cluster, err)
+		}
+		metaPath := backupStore.Join(etcd.Backup, etcdBackupMetaFile)
+		data, err := metaPath.ReadFile(ctx)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil, fmt.Errorf("etcd-manager backup %q of etcd cluster %q no longer exists in %s", etcd.Backup, etcd.Cluster, etcd.BackupStore)
+			}
+			return nil, fmt.Errorf("reading %s: %w", metaPath, err)
+		}
+		info := &etcdBackupInfo{}
+		if err := json.Unmarshal(data, info); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", metaPath, err)
+		}
+		r.etcdClusterSpecs[etcd.Cluster] = info.ClusterSpec
+	}
+
+	return r, nil
+}
+
+// Apply restores the files in the state store, then asks etcd-manager to restore each etcd cluster
+// from its backup.
+// etcd-manager only acts on the restore command once it is restarted.
+func (r *Restore) Apply(ctx context.Context, vfsContext *vfs.VFSContext, cluster *kops.Cluster) error {
+	for _, relativePath := range r.Write {
+		if err := writeFile(ctx, r.configBase.Join(relativePath), r.contents[relativePath], cluster); err != nil {
+			return err
+		}
+	}
+	for _, relativePath := range r.Delete {
+		p := r.configBase.Join(relativePath)
+		if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("deleting %s: %w", p, err)
+		}
+	}
+
+	for _, etcd := range r.Backup.Etcd {
+		backupStore, err := vfsContext.BuildVfsPath(etcd.BackupStore)
+		if err != nil {
+			return fmt.Errorf("parsing backup store for etcd cluster %q: %w", etcd.Cluster, err)
+		}
+		now := time.Now().UTC()
+		command := &etcdCommand{
+			Timestamp: now.UnixNano(),
+			RestoreBackup: &etcdRestoreBackupCommand{
+				ClusterSpec: r.etcdClusterSpecs[etcd.Cluster],
+				Backup:      etcd.Backup,
+			},
+		}
+		data, err := json.Marshal(command)
+		if err != nil {
+			return fmt.Errorf("serializing etcd-manager command: %w", err)
+		}
+		p := backupStore.Join(etcdControlDir, now.Format(time.RFC3339Nano), etcdCommandFile)
+		if err := writeFile(ctx, p, data, cluster); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attestsigner implements the nodeup side of attestation bootstrap;
+// it is separate from attestbootstrap so that the kops CLI (which only needs
+// the options) does not link the TPM client and its device flags.
+package attestsigner
+
+import (
+	"crypto"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/pki"
+)
+
+// NewMachineAuthenticator returns the authenticator for the strongest credential present in dir:
+// an X.509-SVID, then a TPM attestation key, then a software machine key.
+func NewMachineAuthenticator(dir string) (bootstrap.Authenticator, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return nil, fmt.Errorf("couldn't determine hostname: %w", err)
+	}
+
+	svidCertificatePath := filepath.Join(dir, attestbootstrap.SVIDCertificateFile)
+	if exists, err := fileExists(svidCertificatePath); err != nil {
+		return nil, err
+	} else if exists {
+		klog.Infof("authenticating with X.509-SVID from %q", svidCertificatePath)
+		return NewSPIFFEAuthenticator(svidCertificatePath, filepath.Join(dir, attestbootstrap.SVIDKeyFile)), nil
+	}
+
+	attestationKeyPath := filepath.Join(dir, attestbootstrap.AttestationKeyFile)
+	if exists, err := fileExists(attestationKeyPath); err != nil {
+		return nil, err
+	} else if exists {
+		klog.Infof("authenticating with TPM attestation key")
+		return NewTPMAuthenticator(hostname, &deviceTPM{}), nil
+	}
+
+	return pkibootstrap.NewAuthenticatorFromFile(filepath.Join(dir, attestbootstrap.PrivateKeyFile))
+}
+
+func fileExists(p string) (bool, error) {
+	if _, err := os.Stat(p); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error checking for %q: %w", p, err)
+	}
+	return true, nil
+}
+
+// TPM is the subset of TPM 2.0 operations we need to authenticate.
+type TPM interface {
+	// Quote quotes the SHA-256 PCR bank with the attestation key, including extraData as the qualifying data.
+	Quote(extraData []byte) (*attestbootstrap.TPMQuote, error)
+}
+
+type tpmAuthenticator struct {
+	hostname string
+	tpm      TPM
+}
+
+var _ bootstrap.Authenticator = (*tpmAuthenticator)(nil)
+
+// NewTPMAuthenticator builds an authenticator that proves possession of the attestation key in tpm.
+func NewTPMAuthenticator(hostname string, tpm TPM) bootstrap.Authenticator {
+	return &tpmAuthenticator{hostname: hostname, tpm: tpm}
+}
+
+func (a *tpmAuthenticator) CreateToken(body []byte) (string, error) {
+	payload, err := buildTokenData(a.hostname, body)
+	if err != nil {
+		return "", err
+	}
+
+	tpmStart := time.Now()
+	digest := sha256.Sum256(payload)
+	quote, err := a.tpm.Quote(digest[:])
+	if err != nil {
+		return "", fmt.Errorf("failed to quote token data: %w", err)
+	}
+	klog.Infof("TPM quote took %v", time.Since(tpmStart))
+
+	return encodeToken(&attestbootstrap.AuthToken{
+		Data:     payload,
+		TPMQuote: quote,
+	})
+}
+
+type spiffeAuthenticator struct {
+	certificatePath string
+	keyPath         string
+}
+
+var _ bootstrap.Authenticator = (*spiffeAuthenticator)(nil)
+
+// NewSPIFFEAuthenticator builds an authenticator that signs with the X.509-SVID in the given files.
+// The files are read for every token, because SVIDs are short-lived and rotated in place.
+func NewSPIFFEAuthenticator(certificatePath string, keyPath string) bootstrap.Authenticator {
+	return &spiffeAuthenticator{certificatePath: certificatePath, keyPath: keyPath}
+}
+
+func (a *spiffeAuthenticator) CreateToken(body []byte) (string, error) {
+	chain, signer, err := a.loadSVID()
+	if err != nil {
+		return "", err
+	}
+
+	// The node name is taken from the SPIFFE ID.
+	payload, err := buildTokenData("", body)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(payload)
+	signature, err := signer.Sign(cryptorand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token data: %w", err)
+	}
+
+	return encodeToken(&attestbootstrap.AuthToken{
+		Data:      payload,
+		SVID:      chain,
+		Signature: signature,
+	})
+}
+
+func (a *spiffeAuthenticator) loadSVID() ([][]byte, crypto.Signer, error) {
+	certificateBytes, err := os.ReadFile(a.certificatePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading %q: %w", a.certificatePath, err)
+	}
+	var chain [][]byte
+	for {
+		var block *pem.Block
+		block, certificateBytes = pem.Decode(certificateBytes)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			chain = append(chain, block.Bytes)
+		}
+	}
+	if len(chain) == 0 {
+		return nil, nil, fmt.Errorf("no certificates found in %q", a.certificatePath)
+	}
+
+	keyBytes, err := os.ReadFile(a.keyPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading %q: %w", a.keyPath, err)
+	}
+	key, err := pki.ParsePEMPrivateKey(keyBytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing key from %q: %w", a.keyPath, err)
+	}
+
+	// Catch a certificate and key that are out of step mid-rotation, rather than failing on the server.
+	leaf, err := x509.ParseCertificate(chain[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing certificate from %q: %w", a.certificatePath, err)
+	}
+	if !publicKeysEqual(leaf.PublicKey, key.Key.Public()) {
+		return nil, nil, fmt.Errorf("certificate in %q does not match key in %q", a.certificatePath, a.keyPath)
+	}
+
+	return chain, key.Key, nil
+}
+
+func publicKeysEqual(a, b crypto.PublicKey) bool {
+	k, ok := a.(interface{ Equal(crypto.PublicKey) bool })
+	return ok && k.Equal(b)
+}
+
+func buildTokenData(instance string, body []byte) ([]byte, error) {
+	requestHash := sha256.Sum256(body)
+
+	data := attestbootstrap.AuthTokenData{
+		Instance:    instance,
+		Timestamp:   time.Now().Unix(),
+		Audience:    attestbootstrap.AudienceNodeAuthentication,
+		RequestHash: requestHash[:],
+	}
+
+	payload, err := json.Marshal(&data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal token data: %w", err)
+	}
+	return payload, nil
+}
+
+func encodeToken(token *attestbootstrap.AuthToken) (string, error) {
+	b, err := json.Marshal(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal token: %w", err)
+	}
+	return attestbootstrap.AuthenticationTokenPrefix + base64.StdEncoding.EncodeToString(b), nil
+}
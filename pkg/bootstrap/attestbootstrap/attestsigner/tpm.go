@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestsigner
+
+import (
+	"fmt"
+
+	"github.com/google/go-tpm-tools/client"
+	"github.com/google/go-tpm/legacy/tpm2"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
+)
+
+// deviceTPM quotes with the attestation key persisted in the TPM of this machine.
+type deviceTPM struct{}
+
+var _ TPM = (*deviceTPM)(nil)
+
+func (t *deviceTPM) Quote(extraData []byte) (*attestbootstrap.TPMQuote, error) {
+	rw, err := openTPM()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open TPM: %w", err)
+	}
+	defer rw.Close()
+
+	key, err := client.LoadCachedKey(rw, attestbootstrap.AttestationKeyHandle, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load attestation key from handle 0x%x: %w", uint32(attestbootstrap.AttestationKeyHandle), err)
+	}
+	defer key.Close()
+
+	quote, err := key.Quote(client.FullPcrSel(tpm2.AlgSHA256), extraData)
+	if err != nil {
+		return nil, err
+	}
+
+	return &attestbootstrap.TPMQuote{
+		Quote:     quote.GetQuote(),
+		Signature: quote.GetRawSig(),
+		PCRs:      quote.GetPcrs().GetPcrs(),
+	}, nil
+}
@@ -0,0 +1,339 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attestverifier implements the kops-controller side of attestation
+// bootstrap; it is separate from attestbootstrap so that nodeup (which only
+// needs the authenticators) does not link the controller-runtime client.
+package attestverifier
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	pb "github.com/google/go-tpm-tools/proto/tpm"
+	"github.com/google/go-tpm-tools/quote"
+	"github.com/spiffe/go-spiffe/v2/bundle/x509bundle"
+	"github.com/spiffe/go-spiffe/v2/spiffeid"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/pki"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type verifier struct {
+	opt    attestbootstrap.Options
+	client client.Client
+
+	// pcrs is the parsed form of opt.TPM.PCRs.
+	pcrs map[uint32][]byte
+
+	// trustDomain, bundle and pathPrefix are the parsed form of opt.SPIFFE.
+	trustDomain spiffeid.TrustDomain
+	bundle      *x509bundle.Bundle
+	pathPrefix  string
+}
+
+// NewVerifier constructs a new verifier.
+func NewVerifier(options *attestbootstrap.Options, client client.Client) (bootstrap.Verifier, error) {
+	opt := *options
+	if opt.MaxTimeSkew == 0 {
+		opt.MaxTimeSkew = 300
+	}
+
+	v := &verifier{
+		opt:    opt,
+		client: client,
+	}
+
+	if opt.TPM != nil {
+		v.pcrs = make(map[uint32][]byte)
+		for index, value := range opt.TPM.PCRs {
+			b, err := hex.DecodeString(value)
+			if err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("PCR %d value %q is not a hex-encoded SHA-256 digest", index, value)
+			}
+			v.pcrs[index] = b
+		}
+	}
+
+	if opt.SPIFFE != nil {
+		td, err := spiffeid.TrustDomainFromString(opt.SPIFFE.TrustDomain)
+		if err != nil {
+			return nil, fmt.Errorf("parsing SPIFFE trust domain: %w", err)
+		}
+		bundle, err := x509bundle.Parse(td, []byte(opt.SPIFFE.TrustBundle))
+		if err != nil {
+			return nil, fmt.Errorf("parsing SPIFFE trust bundle: %w", err)
+		}
+		if bundle.Empty() {
+			return nil, fmt.Errorf("SPIFFE trust bundle for %q has no authorities", td)
+		}
+		pathPrefix := opt.SPIFFE.PathPrefix
+		if pathPrefix == "" {
+			pathPrefix = attestbootstrap.DefaultSPIFFEPathPrefix
+		}
+		if err := spiffeid.ValidatePath(pathPrefix); err != nil {
+			return nil, fmt.Errorf("invalid SPIFFE path prefix %q: %w", pathPrefix, err)
+		}
+		v.trustDomain = td
+		v.bundle = bundle
+		v.pathPrefix = pathPrefix
+	}
+
+	return v, nil
+}
+
+var _ bootstrap.Verifier = &verifier{}
+
+func (v *verifier) VerifyToken(ctx context.Context, rawRequest *http.Request, authToken string, body []byte) (*bootstrap.VerifyResult, error) {
+	// Reminder: we shouldn't trust any data we get from the client until we've checked the signature (and even then...)
+
+	token, tokenData, err := v.parseTokenData(authToken, body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case token.TPMQuote != nil:
+		if v.opt.TPM == nil {
+			return nil, fmt.Errorf("TPM attestation is not enabled")
+		}
+		return v.verifyTPMQuote(ctx, token, tokenData)
+
+	case len(token.SVID) != 0:
+		if v.opt.SPIFFE == nil {
+			return nil, fmt.Errorf("SPIFFE attestation is not enabled")
+		}
+		return v.verifySVID(token)
+
+	default:
+		return nil, fmt.Errorf("token has neither a TPM quote nor an SVID")
+	}
+}
+
+func (v *verifier) parseTokenData(authToken string, body []byte) (*attestbootstrap.AuthToken, *attestbootstrap.AuthTokenData, error) {
+	if !strings.HasPrefix(authToken, attestbootstrap.AuthenticationTokenPrefix) {
+		return nil, nil, bootstrap.ErrNotThisVerifier
+	}
+	authToken = strings.TrimPrefix(authToken, attestbootstrap.AuthenticationTokenPrefix)
+
+	tokenBytes, err := base64.StdEncoding.DecodeString(authToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decoding authorization token: %w", err)
+	}
+
+	token := &attestbootstrap.AuthToken{}
+	if err = json.Unmarshal(tokenBytes, token); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token: %w", err)
+	}
+
+	tokenData := &attestbootstrap.AuthTokenData{}
+	if err := json.Unmarshal(token.Data, tokenData); err != nil {
+		return nil, nil, fmt.Errorf("unmarshalling authorization token data: %w", err)
+	}
+
+	// Guard against replay attacks
+	if tokenData.Audience != attestbootstrap.AudienceNodeAuthentication {
+		return nil, nil, fmt.Errorf("incorrect Audience")
+	}
+	timeSkew := math.Abs(time.Since(time.Unix(tokenData.Timestamp, 0)).Seconds())
+	if timeSkew > float64(v.opt.MaxTimeSkew) {
+		return nil, nil, fmt.Errorf("incorrect Timestamp %v", tokenData.Timestamp)
+	}
+
+	// Verify the token has signed the body content.
+	requestHash := sha256.Sum256(body)
+	if !bytes.Equal(requestHash[:], tokenData.RequestHash) {
+		return nil, nil, fmt.Errorf("incorrect RequestHash")
+	}
+
+	return token, tokenData, nil
+}
+
+// verifyTPMQuote checks the quote was signed by the attestation key registered for the claimed host,
+// over the token data and PCR values that satisfy our policy.
+func (v *verifier) verifyTPMQuote(ctx context.Context, token *attestbootstrap.AuthToken, tokenData *attestbootstrap.AuthTokenData) (*bootstrap.VerifyResult, error) {
+	nodeName := tokenData.Instance
+	if nodeName == "" {
+		return nil, fmt.Errorf("instance is required")
+	}
+	id := types.NamespacedName{
+		Namespace: "kops-system",
+		Name:      nodeName,
+	}
+	var host kops.Host
+	if err := v.client.Get(ctx, id, &host); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("host not found for %v", id)
+		}
+		return nil, fmt.Errorf("error getting host %v: %w", id, err)
+	}
+
+	// A software key could sign something that looks like a quote, so the key must have been enrolled as a TPM key.
+	// This is trust-on-enroll: the attestation key is not checked against the TPM's endorsement key, so we rely on
+	// whoever created the Host (normally kops toolbox enroll, reading the key from the machine over SSH) for the
+	// key being held in a TPM.
+	if host.Annotations[pkibootstrap.HostAnnotationMachineKeyType] != pkibootstrap.MachineKeyTypeTPM {
+		return nil, fmt.Errorf("host %v was not enrolled with a TPM attestation key", id)
+	}
+	if host.Spec.PublicKey == "" {
+		return nil, fmt.Errorf("host %v did not have public-key", id)
+	}
+	instanceGroup := host.Spec.InstanceGroup
+	if instanceGroup == "" {
+		return nil, fmt.Errorf("host %v did not have spec.instanceGroup", id)
+	}
+	attestationKey, err := pki.ParsePEMPublicKey([]byte(host.Spec.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key: %w", err)
+	}
+
+	tpmQuote := &pb.Quote{
+		Quote:  token.TPMQuote.Quote,
+		RawSig: token.TPMQuote.Signature,
+		Pcrs: &pb.PCRs{
+			Hash: pb.HashAlgo_SHA256,
+			Pcrs: token.TPMQuote.PCRs,
+		},
+	}
+	digest := sha256.Sum256(token.Data)
+	if err := quote.Verify(tpmQuote, attestationKey.Key, digest[:]); err != nil {
+		return nil, fmt.Errorf("failed to verify TPM quote for host %v: %w", id, err)
+	}
+
+	// The PCR values are covered by the quote's digest, so now we can check them against our policy.
+	for index, want := range v.pcrs {
+		got, found := token.TPMQuote.PCRs[index]
+		if !found {
+			return nil, fmt.Errorf("TPM quote for host %v did not include PCR %d", id, index)
+		}
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			return nil, fmt.Errorf("TPM quote for host %v had unexpected value %x for PCR %d", id, got, index)
+		}
+	}
+
+	return &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: instanceGroup,
+	}, nil
+}
+
+// verifySVID checks the SVID chains to the trust bundle, that its SPIFFE ID names a node,
+// and that its key signed the token data.
+func (v *verifier) verifySVID(token *attestbootstrap.AuthToken) (*bootstrap.VerifyResult, error) {
+	var chain []*x509.Certificate
+	for _, der := range token.SVID {
+		cert, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, fmt.Errorf("parsing SVID certificate: %w", err)
+		}
+		chain = append(chain, cert)
+	}
+	leaf := chain[0]
+
+	// See https://github.com/spiffe/spiffe/blob/main/standards/X509-SVID.md#5-validation
+	if leaf.IsCA {
+		return nil, fmt.Errorf("SVID leaf certificate must not be a CA")
+	}
+	if leaf.KeyUsage&x509.KeyUsageDigitalSignature == 0 {
+		return nil, fmt.Errorf("SVID leaf certificate must have the digitalSignature key usage")
+	}
+	if len(leaf.URIs) != 1 {
+		return nil, fmt.Errorf("SVID leaf certificate must have exactly one URI SAN, found %d", len(leaf.URIs))
+	}
+	spiffeID, err := spiffeid.FromURI(leaf.URIs[0])
+	if err != nil {
+		return nil, fmt.Errorf("parsing SPIFFE ID: %w", err)
+	}
+	if !spiffeID.MemberOf(v.trustDomain) {
+		return nil, fmt.Errorf("SPIFFE ID %q is not a member of trust domain %q", spiffeID, v.trustDomain)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	roots := x509.NewCertPool()
+	for _, cert := range v.bundle.X509Authorities() {
+		roots.AddCert(cert)
+	}
+	if _, err := leaf.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		// SVIDs may be issued for any purpose.
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return nil, fmt.Errorf("verifying SVID for %q: %w", spiffeID, err)
+	}
+
+	instanceGroup, nodeName, err := v.parseNodeSPIFFEID(spiffeID)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(token.Data)
+	if !verifySignature(leaf.PublicKey, digest[:], token.Signature) {
+		return nil, fmt.Errorf("failed to verify claim signature for %q", spiffeID)
+	}
+
+	return &bootstrap.VerifyResult{
+		NodeName:          nodeName,
+		InstanceGroupName: instanceGroup,
+	}, nil
+}
+
+// parseNodeSPIFFEID extracts the instance group and node name from a SPIFFE ID of the form
+// spiffe://<trust-domain><path-prefix>/<instance-group>/<node-name>.
+func (v *verifier) parseNodeSPIFFEID(spiffeID spiffeid.ID) (string, string, error) {
+	rest, found := strings.CutPrefix(spiffeID.Path(), strings.TrimSuffix(v.pathPrefix, "/")+"/")
+	if !found {
+		return "", "", fmt.Errorf("SPIFFE ID %q is not under path %q", spiffeID, v.pathPrefix)
+	}
+	instanceGroup, nodeName, found := strings.Cut(rest, "/")
+	if !found || instanceGroup == "" || nodeName == "" || strings.Contains(nodeName, "/") {
+		return "", "", fmt.Errorf("SPIFFE ID %q does not have the form %s/<instance-group>/<node-name>", spiffeID, v.pathPrefix)
+	}
+	return instanceGroup, nodeName, nil
+}
+
+func verifySignature(signingKey crypto.PublicKey, digest []byte, signature []byte) bool {
+	switch signingKey := signingKey.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(signingKey, digest, signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(signingKey, crypto.SHA256, digest, signature) == nil
+	default:
+		return false
+	}
+}
@@ -0,0 +1,428 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestverifier
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-tpm/legacy/tpm2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kops "k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/bootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap/attestsigner"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
+	"k8s.io/kops/pkg/pki"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// softwareTPM simulates the TPM2_Quote command of a TPM holding an ECDSA attestation key,
+// producing the same TPMS_ATTEST and TPMT_SIGNATURE encodings as hardware.
+type softwareTPM struct {
+	key  *ecdsa.PrivateKey
+	pcrs map[uint32][]byte
+}
+
+var _ attestsigner.TPM = (*softwareTPM)(nil)
+
+func newSoftwareTPM(t *testing.T) *softwareTPM {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	pcrs := make(map[uint32][]byte)
+	for i := uint32(0); i < 24; i++ {
+		pcrs[i] = make([]byte, sha256.Size)
+	}
+	return &softwareTPM{key: key, pcrs: pcrs}
+}
+
+// extend performs TPM2_PCR_Extend on the SHA-256 bank.
+func (s *softwareTPM) extend(index uint32, measurement string) {
+	digest := sha256.Sum256([]byte(measurement))
+	extended := sha256.Sum256(append(slices.Clone(s.pcrs[index]), digest[:]...))
+	s.pcrs[index] = extended[:]
+}
+
+func (s *softwareTPM) Quote(extraData []byte) (*attestbootstrap.TPMQuote, error) {
+	var selection []int
+	pcrDigest := sha256.New()
+	for i := uint32(0); i < 24; i++ {
+		selection = append(selection, int(i))
+		pcrDigest.Write(s.pcrs[i])
+	}
+
+	attestationData := tpm2.AttestationData{
+		Magic:     0xff544347, // TPM_GENERATED_VALUE
+		Type:      tpm2.TagAttestQuote,
+		ExtraData: extraData,
+		AttestedQuoteInfo: &tpm2.QuoteInfo{
+			PCRSelection: tpm2.PCRSelection{Hash: tpm2.AlgSHA256, PCRs: selection},
+			PCRDigest:    pcrDigest.Sum(nil),
+		},
+	}
+	quoted, err := attestationData.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256(quoted)
+	r, sigS, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return nil, err
+	}
+	signature, err := tpm2.Signature{
+		Alg: tpm2.AlgECDSA,
+		ECC: &tpm2.SignatureECC{HashAlg: tpm2.AlgSHA256, R: r, S: sigS},
+	}.Encode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &attestbootstrap.TPMQuote{
+		Quote:     quoted,
+		Signature: signature,
+		PCRs:      s.pcrs,
+	}, nil
+}
+
+// fakeClient serves Host objects to the verifier.
+type fakeClient struct {
+	client.Client
+	hosts map[string]*kops.Host
+}
+
+func (c *fakeClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	host, found := c.hosts[key.Name]
+	if !found || key.Namespace != "kops-system" {
+		return apierrors.NewNotFound(schema.GroupResource{Group: "kops.k8s.io", Resource: "hosts"}, key.Name)
+	}
+	host.DeepCopyInto(obj.(*kops.Host))
+	return nil
+}
+
+func buildHost(t *testing.T, name string, publicKey crypto.PublicKey, keyType string) *kops.Host {
+	pkData, err := x509.MarshalPKIXPublicKey(publicKey)
+	require.NoError(t, err)
+
+	host := &kops.Host{}
+	host.Namespace = "kops-system"
+	host.Name = name
+	if keyType != "" {
+		host.Annotations = map[string]string{pkibootstrap.HostAnnotationMachineKeyType: keyType}
+	}
+	host.Spec.InstanceGroup = "nodes"
+	host.Spec.PublicKey = string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pkData}))
+	return host
+}
+
+func TestVerifyTPMQuote(t *testing.T) {
+	tpm := newSoftwareTPM(t)
+	tpm.extend(7, "secure-boot-policy")
+	otherTPM := newSoftwareTPM(t)
+
+	client := &fakeClient{hosts: map[string]*kops.Host{
+		"node-a":   buildHost(t, "node-a", tpm.key.Public(), pkibootstrap.MachineKeyTypeTPM),
+		"node-b":   buildHost(t, "node-b", otherTPM.key.Public(), pkibootstrap.MachineKeyTypeTPM),
+		"software": buildHost(t, "software", tpm.key.Public(), ""),
+	}}
+
+	body := []byte(`{"certs":{}}`)
+
+	grid := []struct {
+		name      string
+		hostname  string
+		pcrs      map[uint32]string
+		body      []byte
+		wantError string
+	}{
+		{
+			name:     "valid quote",
+			hostname: "node-a",
+		},
+		{
+			name:     "matching PCR policy",
+			hostname: "node-a",
+			pcrs:     map[uint32]string{7: hex.EncodeToString(tpm.pcrs[7]), 0: strings.Repeat("00", sha256.Size)},
+		},
+		{
+			name:      "mismatched PCR policy",
+			hostname:  "node-a",
+			pcrs:      map[uint32]string{7: strings.Repeat("00", sha256.Size)},
+			wantError: "unexpected value",
+		},
+		{
+			name:      "quote from a different TPM",
+			hostname:  "node-b",
+			wantError: "failed to verify TPM quote",
+		},
+		{
+			name:      "host enrolled with a software key",
+			hostname:  "software",
+			wantError: "not enrolled with a TPM attestation key",
+		},
+		{
+			name:      "unknown host",
+			hostname:  "node-c",
+			wantError: "host not found",
+		},
+		{
+			name:      "token for a different request",
+			hostname:  "node-a",
+			body:      []byte(`{"certs":{"other":""}}`),
+			wantError: "incorrect RequestHash",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			verifier, err := NewVerifier(&attestbootstrap.Options{TPM: &attestbootstrap.TPMOptions{PCRs: g.pcrs}}, client)
+			require.NoError(t, err)
+
+			token, err := attestsigner.NewTPMAuthenticator(g.hostname, tpm).CreateToken(body)
+			require.NoError(t, err)
+
+			requestBody := body
+			if g.body != nil {
+				requestBody = g.body
+			}
+			result, err := verifier.VerifyToken(t.Context(), nil, token, requestBody)
+			if g.wantError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), g.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &bootstrap.VerifyResult{NodeName: g.hostname, InstanceGroupName: "nodes"}, result)
+		})
+	}
+}
+
+type testCA struct {
+	key  *ecdsa.PrivateKey
+	cert *x509.Certificate
+}
+
+func newTestCA(t *testing.T, trustDomain string) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: trustDomain},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		URIs:                  []*url.URL{{Scheme: "spiffe", Host: trustDomain}},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCA{key: key, cert: cert}
+}
+
+func (ca *testCA) bundle() string {
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ca.cert.Raw}))
+}
+
+// writeSVID issues an X.509-SVID for spiffeID and writes it to dir in the layout of a SPIRE helper.
+func (ca *testCA) writeSVID(t *testing.T, dir string, spiffeID string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	id, err := url.Parse(spiffeID)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+		URIs:         []*url.URL{id},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, key.Public(), ca.key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, attestbootstrap.SVIDCertificateFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	keyData, err := (&pki.PrivateKey{Key: key}).AsBytes()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, attestbootstrap.SVIDKeyFile), keyData, 0o600))
+}
+
+func TestVerifySVID(t *testing.T) {
+	ca := newTestCA(t, "example.org")
+	otherCA := newTestCA(t, "example.org")
+
+	body := []byte(`{"certs":{}}`)
+
+	grid := []struct {
+		name      string
+		issuer    *testCA
+		spiffeID  string
+		options   attestbootstrap.SPIFFEOptions
+		wantNode  string
+		wantIG    string
+		wantError string
+	}{
+		{
+			name:     "default path prefix",
+			issuer:   ca,
+			spiffeID: "spiffe://example.org/kops/nodes/node-a",
+			wantNode: "node-a",
+			wantIG:   "nodes",
+		},
+		{
+			name:     "custom path prefix",
+			issuer:   ca,
+			spiffeID: "spiffe://example.org/clusters/dev/nodes-fast/node-b",
+			options:  attestbootstrap.SPIFFEOptions{PathPrefix: "/clusters/dev"},
+			wantNode: "node-b",
+			wantIG:   "nodes-fast",
+		},
+		{
+			name:      "untrusted issuer",
+			issuer:    otherCA,
+			spiffeID:  "spiffe://example.org/kops/nodes/node-a",
+			wantError: "verifying SVID",
+		},
+		{
+			name:      "different trust domain",
+			issuer:    ca,
+			spiffeID:  "spiffe://example.com/kops/nodes/node-a",
+			wantError: "not a member of trust domain",
+		},
+		{
+			name:      "outside path prefix",
+			issuer:    ca,
+			spiffeID:  "spiffe://example.org/workloads/nodes/node-a",
+			wantError: "is not under path",
+		},
+		{
+			name:      "missing node name",
+			issuer:    ca,
+			spiffeID:  "spiffe://example.org/kops/nodes",
+			wantError: "does not have the form",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			options := g.options
+			options.TrustDomain = "example.org"
+			options.TrustBundle = ca.bundle()
+			verifier, err := NewVerifier(&attestbootstrap.Options{SPIFFE: &options}, &fakeClient{})
+			require.NoError(t, err)
+
+			dir := t.TempDir()
+			g.issuer.writeSVID(t, dir, g.spiffeID)
+			authenticator, err := attestsigner.NewMachineAuthenticator(dir)
+			require.NoError(t, err)
+
+			token, err := authenticator.CreateToken(body)
+			require.NoError(t, err)
+
+			result, err := verifier.VerifyToken(t.Context(), nil, token, body)
+			if g.wantError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), g.wantError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, &bootstrap.VerifyResult{NodeName: g.wantNode, InstanceGroupName: g.wantIG}, result)
+		})
+	}
+}
+
+func TestVerifyTokenDisabled(t *testing.T) {
+	tpm := newSoftwareTPM(t)
+	ca := newTestCA(t, "example.org")
+	client := &fakeClient{hosts: map[string]*kops.Host{
+		"node-a": buildHost(t, "node-a", tpm.key.Public(), pkibootstrap.MachineKeyTypeTPM),
+	}}
+	body := []byte(`{}`)
+
+	tpmOnly, err := NewVerifier(&attestbootstrap.Options{TPM: &attestbootstrap.TPMOptions{}}, client)
+	require.NoError(t, err)
+	spiffeOnly, err := NewVerifier(&attestbootstrap.Options{SPIFFE: &attestbootstrap.SPIFFEOptions{TrustDomain: "example.org", TrustBundle: ca.bundle()}}, client)
+	require.NoError(t, err)
+
+	tpmToken, err := attestsigner.NewTPMAuthenticator("node-a", tpm).CreateToken(body)
+	require.NoError(t, err)
+	_, err = spiffeOnly.VerifyToken(t.Context(), nil, tpmToken, body)
+	assert.EqualError(t, err, "TPM attestation is not enabled")
+
+	dir := t.TempDir()
+	ca.writeSVID(t, dir, "spiffe://example.org/kops/nodes/node-a")
+	svidToken, err := attestsigner.NewSPIFFEAuthenticator(filepath.Join(dir, attestbootstrap.SVIDCertificateFile), filepath.Join(dir, attestbootstrap.SVIDKeyFile)).CreateToken(body)
+	require.NoError(t, err)
+	_, err = tpmOnly.VerifyToken(t.Context(), nil, svidToken, body)
+	assert.EqualError(t, err, "SPIFFE attestation is not enabled")
+
+	_, err = tpmOnly.VerifyToken(t.Context(), nil, pkibootstrap.AuthenticationTokenPrefix+"e30=", body)
+	assert.ErrorIs(t, err, bootstrap.ErrNotThisVerifier)
+}
+
+func TestNewVerifierRejectsInvalidOptions(t *testing.T) {
+	grid := []struct {
+		options   attestbootstrap.Options
+		wantError string
+	}{
+		{
+			options:   attestbootstrap.Options{TPM: &attestbootstrap.TPMOptions{PCRs: map[uint32]string{7: "abcd"}}},
+			wantError: "PCR 7 value",
+		},
+		{
+			options:   attestbootstrap.Options{SPIFFE: &attestbootstrap.SPIFFEOptions{TrustDomain: "example.org"}},
+			wantError: "has no authorities",
+		},
+		{
+			options:   attestbootstrap.Options{SPIFFE: &attestbootstrap.SPIFFEOptions{TrustDomain: "Not A Domain"}},
+			wantError: "parsing SPIFFE trust domain",
+		},
+	}
+	for i, g := range grid {
+		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
+			_, err := NewVerifier(&g.options, &fakeClient{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), g.wantError)
+		})
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package attestbootstrap implements node bootstrap authentication with
+// hardware-rooted or workload identities: TPM 2.0 quotes signed by an
+// attestation key registered at enrollment, and SPIFFE X.509-SVIDs.
+package attestbootstrap
+
+import "github.com/google/go-tpm/tpmutil"
+
+// Options describes how we authenticate instances with attestation.
+type Options struct {
+	// MaxTimeSkew is the maximum time skew to allow (in seconds)
+	MaxTimeSkew int64 `json:"MaxTimeSkew,omitempty"`
+
+	// TPM enables authentication with TPM 2.0 quotes.
+	TPM *TPMOptions `json:"tpm,omitempty"`
+
+	// SPIFFE enables authentication with SPIFFE X.509-SVIDs.
+	SPIFFE *SPIFFEOptions `json:"spiffe,omitempty"`
+}
+
+// TPMOptions describes how we verify TPM 2.0 quotes.
+// The attestation key is trusted because it was registered in the Host object when the machine was enrolled.
+type TPMOptions struct {
+	// PCRs maps the index of a SHA-256 PCR to its required hex-encoded value.
+	PCRs map[uint32]string `json:"pcrs,omitempty"`
+}
+
+// SPIFFEOptions describes how we verify SPIFFE X.509-SVIDs.
+type SPIFFEOptions struct {
+	// TrustDomain is the trust domain that node SPIFFE IDs must belong to.
+	TrustDomain string `json:"trustDomain,omitempty"`
+
+	// TrustBundle holds the PEM-encoded X.509 authorities of the trust domain.
+	TrustBundle string `json:"trustBundle,omitempty"`
+
+	// PathPrefix is the path under which node SPIFFE IDs are issued, defaulting to DefaultSPIFFEPathPrefix.
+	// Node SPIFFE IDs have the form spiffe://<trust-domain><path-prefix>/<instance-group>/<node-name>.
+	PathPrefix string `json:"pathPrefix,omitempty"`
+}
+
+// DefaultSPIFFEPathPrefix is the path prefix used for node SPIFFE IDs when none is configured.
+const DefaultSPIFFEPathPrefix = "/kops"
+
+// AuthenticationTokenPrefix is the prefix used for authentication using attestation
+const AuthenticationTokenPrefix = "x-kops-attest " //nolint:gosec // This is an authentication scheme prefix, not a credential.
+
+// MachineDir is the directory holding the credentials of an enrolled machine.
+const MachineDir = "/etc/kubernetes/kops/pki/machine"
+
+const (
+	// AttestationKeyFile is the name of the file in MachineDir holding the public TPM attestation key.
+	AttestationKeyFile = "attestation-key.pem"
+	// SVIDCertificateFile is the name of the file in MachineDir holding the X.509-SVID certificate chain.
+	SVIDCertificateFile = "svid.pem"
+	// SVIDKeyFile is the name of the file in MachineDir holding the X.509-SVID private key.
+	SVIDKeyFile = "svid.key"
+	// PrivateKeyFile is the name of the file in MachineDir holding a software machine key.
+	PrivateKeyFile = "private.pem"
+)
+
+// AttestationKeyHandle is the persistent handle at which `kops toolbox enroll --tpm` stores the attestation key.
+const AttestationKeyHandle = tpmutil.Handle(0x81010002)
@@ -0,0 +1,64 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package attestbootstrap
+
+// AuthToken describes the authentication header data when using attestation.
+// Exactly one of TPMQuote or SVID is set.
+type AuthToken struct {
+	// Data is the data we are attesting.
+	// It is a JSON encoded form of AuthTokenData.
+	Data []byte `json:"data,omitempty"`
+
+	// TPMQuote is a TPM quote whose qualifying data is the SHA-256 hash of Data.
+	TPMQuote *TPMQuote `json:"tpmQuote,omitempty"`
+
+	// SVID is the DER-encoded X.509-SVID certificate chain, leaf first.
+	SVID [][]byte `json:"svid,omitempty"`
+
+	// Signature is the signature of the SVID private key over the SHA-256 hash of Data.
+	Signature []byte `json:"signature,omitempty"`
+}
+
+// TPMQuote is the output of a TPM2_Quote over the SHA-256 PCR bank.
+type TPMQuote struct {
+	// Quote is the TPMS_ATTEST structure signed by the attestation key.
+	Quote []byte `json:"quote,omitempty"`
+
+	// Signature is the TPMT_SIGNATURE over Quote.
+	Signature []byte `json:"signature,omitempty"`
+
+	// PCRs holds the values of the quoted PCRs, by index.
+	PCRs map[uint32][]byte `json:"pcrs,omitempty"`
+}
+
+// AuthTokenData is the data that is attested as part of the header.
+type AuthTokenData struct {
+	// Instance is the name of the host we are claiming; it is not used with SVIDs, which carry the node name.
+	Instance string `json:"instance,omitempty"`
+
+	// RequestHash is the hash of the request
+	RequestHash []byte `json:"requestHash,omitempty"`
+
+	// Timestamp is the time of this request (to help prevent replay attacks)
+	Timestamp int64 `json:"timestamp,omitempty"`
+
+	// Audience is the audience for this request (to help prevent replay attacks)
+	Audience string `json:"audience,omitempty"`
+}
+
+// AudienceNodeAuthentication is used in case we have multiple audiences using attestation in future
+const AudienceNodeAuthentication = "kops.k8s.io/node-bootstrap"
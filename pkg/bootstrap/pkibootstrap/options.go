@@ -24,3 +24,11 @@ type Options struct {
 
 // AuthenticationTokenPrefix is the prefix used for authentication using PKI
 const AuthenticationTokenPrefix = "x-pki-tpm " //nolint:gosec // This is an authentication scheme prefix, not a credential.
+
+// HostAnnotationMachineKeyType is the Host annotation recording how the machine holds the private key for spec.publicKey.
+const HostAnnotationMachineKeyType = "kops.k8s.io/machine-key-type"
+
+// MachineKeyTypeTPM marks spec.publicKey as a restricted TPM attestation key.
+// Such keys only prove anything about the machine in a TPM quote, so they are not accepted for PKI signatures.
+// The annotation is trusted as set: the key is not verified against the TPM's endorsement key.
+const MachineKeyTypeTPM = "tpm"
@@ -143,6 +143,10 @@ func (v *verifier) getSigningKey(ctx context.Context, tokenData *pkibootstrap.Au
 		return nil, nil, fmt.Errorf("error getting host %v: %w", id, err)
 	}
 
+	if host.Annotations[pkibootstrap.HostAnnotationMachineKeyType] == pkibootstrap.MachineKeyTypeTPM {
+		return nil, nil, fmt.Errorf("host %v was enrolled with a TPM attestation key", id)
+	}
+
 	// TODO: Check instance-group matches request (does it matter?)
 
 	if host.Spec.PublicKey == "" {
@@ -0,0 +1,254 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinventory
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+const (
+	// NodeCertificateMinValidity is the shortest validity of the certificates issued to nodes,
+	// both by nodeup and by kops-controller when the node bootstraps.
+	NodeCertificateMinValidity = 455 * 24 * time.Hour
+
+	// NodeCertificateValiditySkewHours is the range, in hours, over which the validity of node certificates is skewed,
+	// so that the certificates of nodes created at the same time do not all expire at the same time.
+	NodeCertificateValiditySkewHours = 30 * 24
+)
+
+// BootstrapValidity returns the validity of the certificates that kops-controller issues to the named node.
+// It is salted on the node name so nodes sharing a NAT/load balancer still get independent skews.
+func BootstrapValidity(nodeName string) time.Duration {
+	hash := fnv.New32()
+	_, _ = hash.Write([]byte(nodeName))
+	return NodeCertificateMinValidity + time.Hour*time.Duration(hash.Sum32()%NodeCertificateValiditySkewHours)
+}
+
+// Source is where a certificate comes from.
+type Source string
+
+const (
+	// SourceKeystore is a keypair in the cluster's keystore, typically a CA.
+	SourceKeystore Source = "keystore"
+	// SourceBootstrap is a certificate issued by kops-controller to a node when it bootstraps.
+	SourceBootstrap Source = "bootstrap"
+	// SourceNodeup is a certificate that nodeup issues on the node itself.
+	SourceNodeup Source = "nodeup"
+)
+
+// NodeupCertificates is the Name of the entry for the certificates nodeup issues on a node.
+// nodeup issues all of them when the node boots, with the same validity.
+const NodeupCertificates = "*"
+
+// Certificate is an entry in the certificate inventory.
+type Certificate struct {
+	// Source is where the certificate comes from.
+	Source Source `json:"source"`
+	// Name is the name of the keyset, or of the certificate issued to the node.
+	Name string `json:"name"`
+	// ID is the keypair ID, for keystore certificates.
+	ID string `json:"id,omitempty"`
+	// Node is the node the certificate was issued to.
+	Node string `json:"node,omitempty"`
+	// Subject is the subject of the certificate, if known.
+	Subject string `json:"subject,omitempty"`
+	// Issuer is the name of the keyset that signed the certificate.
+	Issuer string `json:"issuer,omitempty"`
+	// IssuerID is the ID of the keypair that signed the certificate.
+	IssuerID string `json:"issuerID,omitempty"`
+	// NotAfter is when the certificate expires.
+	NotAfter time.Time `json:"notAfter"`
+	// Estimated is true if NotAfter is estimated from when the node registered, rather than read from the certificate.
+	Estimated bool `json:"estimated,omitempty"`
+}
+
+// Remaining returns the remaining validity of the certificate.
+func (c *Certificate) Remaining(now time.Time) time.Duration {
+	return c.NotAfter.Sub(now)
+}
+
+// Key identifies the certificate within the inventory.
+func (c *Certificate) Key() string {
+	return fmt.Sprintf("%s/%s/%s/%s", c.Source, c.Node, c.Name, c.ID)
+}
+
+// ForIssuedCertificate returns the entry for a certificate issued to a node.
+func ForIssuedCertificate(source Source, node string, name string, cert *pki.Certificate, issuer string, issuerID string) *Certificate {
+	return &Certificate{
+		Source:   source,
+		Name:     name,
+		Node:     node,
+		Subject:  cert.Subject.String(),
+		Issuer:   issuer,
+		IssuerID: issuerID,
+		NotAfter: cert.Certificate.NotAfter.UTC(),
+	}
+}
+
+// FromKeysets returns the entries for the trusted keypairs of the keysets.
+func FromKeysets(keysets map[string]*fi.Keyset) []*Certificate {
+	var certificates []*Certificate
+	for name, keyset := range keysets {
+		for _, item := range keyset.Items {
+			if item.Certificate == nil || item.DistrustTimestamp != nil {
+				continue
+			}
+			issuer, issuerID := findIssuer(item.Certificate, keysets)
+			certificates = append(certificates, &Certificate{
+				Source:   SourceKeystore,
+				Name:     name,
+				ID:       item.Id,
+				Subject:  item.Certificate.Subject.String(),
+				Issuer:   issuer,
+				IssuerID: issuerID,
+				NotAfter: item.Certificate.Certificate.NotAfter.UTC(),
+			})
+		}
+	}
+	Sort(certificates)
+	return certificates
+}
+
+// findIssuer returns the keyset and keypair ID whose certificate signed cert.
+// A CA is its own issuer.
+func findIssuer(cert *pki.Certificate, keysets map[string]*fi.Keyset) (string, string) {
+	names := make([]string, 0, len(keysets))
+	for name := range keysets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		for id, item := range keysets[name].Items {
+			if item.Certificate == nil {
+				continue
+			}
+			if cert.Certificate.CheckSignatureFrom(item.Certificate.Certificate) == nil {
+				return name, id
+			}
+		}
+	}
+	return "", ""
+}
+
+// NodeOptions describes the certificates issued to the nodes of a cluster.
+type NodeOptions struct {
+	// BootstrapCertNames are the certificates that nodes request from kops-controller.
+	BootstrapCertNames []string
+	// BootstrapCertSigner returns the keyset that signs the named bootstrap certificate.
+	BootstrapCertSigner func(name string) string
+	// Primaries maps keyset names to the ID of their primary keypair.
+	Primaries map[string]string
+}
+
+// FromNodes returns the entries for the certificates issued to the nodes when they booted.
+// The certificates never leave the nodes, so their expiry is estimated from when the node registered:
+// bootstrap certificates have a validity determined by the node name, and nodeup certificates
+// are reported with the shortest validity nodeup uses.
+// Control plane nodes run kops-controller rather than bootstrapping through it, so all their certificates come from nodeup.
+func FromNodes(nodes []v1.Node, options NodeOptions) []*Certificate {
+	var certificates []*Certificate
+	for i := range nodes {
+		node := &nodes[i]
+		registered := node.CreationTimestamp.Time.UTC()
+
+		if isControlPlane(node) || len(options.BootstrapCertNames) == 0 {
+			certificates = append(certificates, &Certificate{
+				Source:    SourceNodeup,
+				Name:      NodeupCertificates,
+				Node:      node.Name,
+				NotAfter:  registered.Add(NodeCertificateMinValidity),
+				Estimated: true,
+			})
+			continue
+		}
+
+		notAfter := registered.Add(BootstrapValidity(node.Name))
+		for _, name := range options.BootstrapCertNames {
+			issuer := fi.CertificateIDCA
+			if options.BootstrapCertSigner != nil {
+				issuer = options.BootstrapCertSigner(name)
+			}
+			certificates = append(certificates, &Certificate{
+				Source:    SourceBootstrap,
+				Name:      name,
+				Node:      node.Name,
+				Issuer:    issuer,
+				IssuerID:  options.Primaries[issuer],
+				NotAfter:  notAfter,
+				Estimated: true,
+			})
+		}
+	}
+	Sort(certificates)
+	return certificates
+}
+
+func isControlPlane(node *v1.Node) bool {
+	_, controlPlane := node.Labels["node-role.kubernetes.io/control-plane"]
+	_, apiServer := node.Labels["node-role.kubernetes.io/api-server"]
+	return controlPlane || apiServer
+}
+
+// Merge returns the certificates of base, with entries replaced by the entries of overrides that have the same key.
+// It is used to replace estimated entries with those read from the certificates.
+func Merge(base []*Certificate, overrides []*Certificate) []*Certificate {
+	byKey := make(map[string]*Certificate)
+	for _, c := range overrides {
+		byKey[c.Key()] = c
+	}
+	var merged []*Certificate
+	for _, c := range base {
+		if override := byKey[c.Key()]; override != nil {
+			merged = append(merged, override)
+		} else {
+			merged = append(merged, c)
+		}
+	}
+	Sort(merged)
+	return merged
+}
+
+// Expiring returns the certificates that expire within horizon of now, including those that have expired.
+func Expiring(certificates []*Certificate, now time.Time, horizon time.Duration) []*Certificate {
+	var expiring []*Certificate
+	for _, c := range certificates {
+		if c.Remaining(now) < horizon {
+			expiring = append(expiring, c)
+		}
+	}
+	return expiring
+}
+
+// Sort orders certificates by expiry, soonest first.
+func Sort(certificates []*Certificate) {
+	sort.SliceStable(certificates, func(i, j int) bool {
+		a, b := certificates[i], certificates[j]
+		if !a.NotAfter.Equal(b.NotAfter) {
+			return a.NotAfter.Before(b.NotAfter)
+		}
+		return a.Key() < b.Key()
+	})
+}
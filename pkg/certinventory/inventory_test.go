@@ -0,0 +1,167 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certinventory
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+type fakeKeystore map[string]*fi.Keyset
+
+func (k fakeKeystore) FindPrimaryKeypair(ctx context.Context, name string) (*pki.Certificate, *pki.PrivateKey, error) {
+	keyset := k[name]
+	return keyset.Primary.Certificate, keyset.Primary.PrivateKey, nil
+}
+
+func TestFromKeysets(t *testing.T) {
+	ctx := context.Background()
+	keysets := fakeKeystore{}
+
+	caCert, caKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:     "ca",
+		Subject:  pkix.Name{CommonName: "kubernetes-ca"},
+		Serial:   big.NewInt(1),
+		Validity: 24 * time.Hour,
+	}, nil)
+	require.NoError(t, err)
+	keysets["kubernetes-ca"], err = fi.NewKeyset(caCert, caKey)
+	require.NoError(t, err)
+
+	cert, key, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Signer:   "kubernetes-ca",
+		Type:     "client",
+		Subject:  pkix.Name{CommonName: "kubecfg"},
+		Serial:   big.NewInt(2),
+		Validity: time.Hour,
+	}, keysets)
+	require.NoError(t, err)
+	keysets["kubecfg"], err = fi.NewKeyset(cert, key)
+	require.NoError(t, err)
+
+	certificates := FromKeysets(keysets)
+	require.Len(t, certificates, 2)
+
+	assert.Equal(t, "kubecfg", certificates[0].Name)
+	assert.Equal(t, "2", certificates[0].ID)
+	assert.Equal(t, "CN=kubecfg", certificates[0].Subject)
+	assert.Equal(t, "kubernetes-ca", certificates[0].Issuer)
+	assert.Equal(t, "1", certificates[0].IssuerID)
+	assert.Equal(t, cert.Certificate.NotAfter.UTC(), certificates[0].NotAfter)
+	assert.False(t, certificates[0].Estimated)
+
+	assert.Equal(t, "kubernetes-ca", certificates[1].Name)
+	assert.Equal(t, "kubernetes-ca", certificates[1].Issuer, "CA is its own issuer")
+	assert.Equal(t, "1", certificates[1].IssuerID)
+}
+
+func TestFromNodes(t *testing.T) {
+	registered := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	nodes := []v1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "control-plane-1",
+				Labels:            map[string]string{"node-role.kubernetes.io/control-plane": ""},
+				CreationTimestamp: metav1.NewTime(registered),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "node-1",
+				CreationTimestamp: metav1.NewTime(registered),
+			},
+		},
+	}
+
+	certificates := FromNodes(nodes, NodeOptions{
+		BootstrapCertNames: []string{"kubelet", "cilium"},
+		BootstrapCertSigner: func(name string) string {
+			if name == "cilium" {
+				return "etcd-clients-ca-cilium"
+			}
+			return fi.CertificateIDCA
+		},
+		Primaries: map[string]string{fi.CertificateIDCA: "123"},
+	})
+	require.Len(t, certificates, 3)
+
+	byKey := make(map[string]*Certificate)
+	for _, c := range certificates {
+		byKey[c.Key()] = c
+		assert.True(t, c.Estimated)
+	}
+
+	nodeup := byKey["nodeup/control-plane-1/*/"]
+	require.NotNil(t, nodeup)
+	assert.Equal(t, registered.Add(NodeCertificateMinValidity), nodeup.NotAfter)
+
+	kubelet := byKey["bootstrap/node-1/kubelet/"]
+	require.NotNil(t, kubelet)
+	assert.Equal(t, registered.Add(BootstrapValidity("node-1")), kubelet.NotAfter)
+	assert.Equal(t, fi.CertificateIDCA, kubelet.Issuer)
+	assert.Equal(t, "123", kubelet.IssuerID)
+
+	cilium := byKey["bootstrap/node-1/cilium/"]
+	require.NotNil(t, cilium)
+	assert.Equal(t, "etcd-clients-ca-cilium", cilium.Issuer)
+	assert.Empty(t, cilium.IssuerID)
+}
+
+func TestBootstrapValidity(t *testing.T) {
+	for _, name := range []string{"node-1", "node-2", "ip-10-0-0-1.ec2.internal"} {
+		validity := BootstrapValidity(name)
+		assert.Equal(t, validity, BootstrapValidity(name), "validity is deterministic")
+		assert.GreaterOrEqual(t, validity, NodeCertificateMinValidity)
+		assert.Less(t, validity, NodeCertificateMinValidity+NodeCertificateValiditySkewHours*time.Hour)
+	}
+}
+
+func TestMergeAndExpiring(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	estimated := &Certificate{Source: SourceBootstrap, Name: "kubelet", Node: "node-1", NotAfter: now.Add(90 * 24 * time.Hour), Estimated: true}
+	other := &Certificate{Source: SourceBootstrap, Name: "kubelet", Node: "node-2", NotAfter: now.Add(60 * 24 * time.Hour), Estimated: true}
+	exact := &Certificate{Source: SourceBootstrap, Name: "kubelet", Node: "node-1", Subject: "CN=system:node:node-1", NotAfter: now.Add(10 * 24 * time.Hour)}
+	removed := &Certificate{Source: SourceBootstrap, Name: "kubelet", Node: "node-3", NotAfter: now.Add(-time.Hour)}
+
+	merged := Merge([]*Certificate{estimated, other}, []*Certificate{exact, removed})
+	assert.Equal(t, []*Certificate{exact, other}, merged)
+
+	grid := []struct {
+		horizon  time.Duration
+		expected []*Certificate
+	}{
+		{horizon: 0, expected: nil},
+		{horizon: 30 * 24 * time.Hour, expected: []*Certificate{exact}},
+		{horizon: 61 * 24 * time.Hour, expected: []*Certificate{exact, other}},
+	}
+	for _, g := range grid {
+		t.Run(g.horizon.String(), func(t *testing.T) {
+			assert.Equal(t, g.expected, Expiring(merged, now, g.horizon))
+		})
+	}
+	assert.Equal(t, []*Certificate{removed}, Expiring([]*Certificate{removed}, now, 0), "expired certificates are expiring")
+}
@@ -110,6 +110,11 @@ func (c *RESTClientset) InstanceGroupsFor(cluster *kops.Cluster) kopsinternalver
 	return c.KopsClient.InstanceGroups(namespace)
 }
 
+// ClusterHistoryFor implements the ClusterHistoryFor method of Clientset for a kubernetes-API state store
+func (c *RESTClientset) ClusterHistoryFor(cluster *kops.Cluster) (simple.ClusterHistoryClient, error) {
+	return nil, fmt.Errorf("cluster history is not supported for a kubernetes-API state store")
+}
+
 func (c *RESTClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	namespace := restNamespaceForClusterName(cluster.Name)
 	return secrets.NewClientsetSecretStore(cluster, c.KopsClient, namespace), nil
@@ -125,13 +130,13 @@ func (c *RESTClientset) SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredent
 	return fi.NewClientsetSSHCredentialStore(cluster, c.KopsClient, namespace), nil
 }
 
-func (c *RESTClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster) error {
+func (c *RESTClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster, options simple.DeleteClusterOptions) error {
 	configBase, err := registry.ConfigBase(c.VFSContext(), cluster)
 	if err != nil {
 		return err
 	}
 
-	err = vfsclientset.DeleteAllClusterState(ctx, configBase)
+	err = vfsclientset.DeleteAllClusterState(ctx, configBase, options.KeepBackups)
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,7 @@ package simple
 
 import (
 	"context"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/kops/pkg/apis/kops"
@@ -52,6 +53,9 @@ type Clientset interface {
 	// AddonsFor returns the client for addon objects for a particular Cluster
 	AddonsFor(cluster *kops.Cluster) AddonsClient
 
+	// ClusterHistoryFor returns the client for the revision history of a particular Cluster
+	ClusterHistoryFor(cluster *kops.Cluster) (ClusterHistoryClient, error)
+
 	// SecretStore builds the secret store for the specified cluster
 	SecretStore(cluster *kops.Cluster) (fi.SecretStore, error)
 
@@ -62,7 +66,13 @@ type Clientset interface {
 	SSHCredentialStore(cluster *kops.Cluster) (fi.SSHCredentialStore, error)
 
 	// DeleteCluster deletes all the state for the specified cluster
-	DeleteCluster(ctx context.Context, cluster *kops.Cluster) error
+	DeleteCluster(ctx context.Context, cluster *kops.Cluster, options DeleteClusterOptions) error
+}
+
+// DeleteClusterOptions are the options for Clientset.DeleteCluster
+type DeleteClusterOptions struct {
+	// KeepBackups keeps the backups in the state store, so that the cluster can be restored from them
+	KeepBackups bool
 }
 
 // AddonsClient is a client for manipulating cluster addons
@@ -74,3 +84,29 @@ type AddonsClient interface {
 	// List returns all the addon objects
 	List(ctx context.Context) (kubemanifest.ObjectList, error)
 }
+
+// ClusterHistoryClient is a client for the revision history of the cluster and instance group specs.
+// A revision is recorded each time the cluster or one of its instance groups is written.
+type ClusterHistoryClient interface {
+	// List returns the recorded revisions, oldest first
+	List(ctx context.Context) ([]*ClusterRevision, error)
+
+	// Get returns the cluster and instance groups as they were at the specified revision
+	Get(ctx context.Context, revision int) (*kops.Cluster, []*kops.InstanceGroup, error)
+}
+
+// ClusterRevision describes a recorded revision of the cluster and instance group specs
+type ClusterRevision struct {
+	// Revision is the number of the revision, starting at 1
+	Revision int `json:"revision"`
+	// Timestamp is when the revision was recorded
+	Timestamp time.Time `json:"timestamp"`
+	// Author is the user that made the change
+	Author string `json:"author,omitempty"`
+	// KopsVersion is the version of kOps that made the change
+	KopsVersion string `json:"kopsVersion,omitempty"`
+	// Change describes the write that created the revision, such as "update InstanceGroup nodes"
+	Change string `json:"change,omitempty"`
+	// SpecHash is a hash of the recorded specs, used to skip writes that did not change anything
+	SpecHash string `json:"specHash"`
+}
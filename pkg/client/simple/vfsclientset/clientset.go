@@ -19,6 +19,7 @@ package vfsclientset
 import (
 	"context"
 	"fmt"
+	"os"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -28,6 +29,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops/registry"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
 	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/statelock"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/upup/pkg/fi/secrets"
 	"k8s.io/kops/util/pkg/vfs"
@@ -91,6 +93,11 @@ func (c *VFSClientset) AddonsFor(cluster *kops.Cluster) simple.AddonsClient {
 	return newAddonsVFS(c, cluster)
 }
 
+// ClusterHistoryFor implements the ClusterHistoryFor method of simple.Clientset for a VFS-backed state store
+func (c *VFSClientset) ClusterHistoryFor(cluster *kops.Cluster) (simple.ClusterHistoryClient, error) {
+	return newClusterHistoryVFS(c.basePath, cluster), nil
+}
+
 func (c *VFSClientset) SecretStore(cluster *kops.Cluster) (fi.SecretStore, error) {
 	if cluster.Spec.ConfigStore.Secrets == "" {
 		configBase, err := registry.ConfigBase(c.VFSContext(), cluster)
@@ -137,18 +144,26 @@ func (c *VFSClientset) pkiPath(cluster *kops.Cluster) (vfs.Path, error) {
 	}
 }
 
-func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
+// DeleteAllClusterState deletes the state of the cluster under basePath.
+// If keepBackups is true, the backups are kept so that the cluster can be restored from them.
+func DeleteAllClusterState(ctx context.Context, basePath vfs.Path, keepBackups bool) error {
 	paths, err := basePath.ReadTree(ctx)
 	if err != nil {
 		return fmt.Errorf("error listing files in state store: %v", err)
 	}
 
+	var remove []vfs.Path
 	for _, path := range paths {
 		relativePath, err := vfs.RelativePath(basePath, path)
 		if err != nil {
 			return err
 		}
 
+		if keepBackups && strings.HasPrefix(relativePath, "backups/") {
+			continue
+		}
+		remove = append(remove, path)
+
 		if relativePath == "" {
 			continue
 		}
@@ -157,6 +172,10 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		if relativePath == "config" || relativePath == "cluster.spec" || relativePath == "cluster-completed.spec" || relativePath == registry.PathKopsVersionUpdated {
 			continue
 		}
+		// The lock is held by "kops delete cluster" while it deletes the cluster
+		if relativePath == statelock.PathLock {
+			continue
+		}
 		if strings.HasPrefix(relativePath, "addons/") {
 			continue
 		}
@@ -178,7 +197,15 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		if strings.HasPrefix(relativePath, "manifests/") {
 			continue
 		}
-		// TODO: offer an option _not_ to delete backups?
+		if strings.HasPrefix(relativePath, "rollingupdate/") {
+			continue
+		}
+		if strings.HasPrefix(relativePath, "rotate-ca/") {
+			continue
+		}
+		if strings.HasPrefix(relativePath, PathHistory+"/") {
+			continue
+		}
 		if strings.HasPrefix(relativePath, "backups/") {
 			continue
 		}
@@ -186,15 +213,24 @@ func DeleteAllClusterState(ctx context.Context, basePath vfs.Path) error {
 		return fmt.Errorf("refusing to delete: unknown file found: %s", path)
 	}
 
-	err = basePath.RemoveAll(ctx)
-	if err != nil {
-		return fmt.Errorf("error deleting cluster files in %s: %w", basePath, err)
+	if len(remove) == len(paths) {
+		err = basePath.RemoveAll(ctx)
+		if err != nil {
+			return fmt.Errorf("error deleting cluster files in %s: %w", basePath, err)
+		}
+		return nil
+	}
+
+	for _, path := range remove {
+		if err := path.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error deleting %s: %w", path, err)
+		}
 	}
 
 	return nil
 }
 
-func (c *VFSClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster) error {
+func (c *VFSClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster, options simple.DeleteClusterOptions) error {
 	if cluster.Spec.ServiceAccountIssuerDiscovery != nil {
 		discoveryStore := cluster.Spec.ServiceAccountIssuerDiscovery.DiscoveryStore
 		if discoveryStore != "" {
@@ -248,7 +284,7 @@ func (c *VFSClientset) DeleteCluster(ctx context.Context, cluster *kops.Cluster)
 		return err
 	}
 
-	return DeleteAllClusterState(ctx, configBase)
+	return DeleteAllClusterState(ctx, configBase, options.KeepBackups)
 }
 
 func NewVFSClientset(vfsContext *vfs.VFSContext, basePath vfs.Path) simple.Clientset {
@@ -17,8 +17,14 @@ limitations under the License.
 package vfsclientset
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -78,3 +84,50 @@ func TestSSHCredentialStoreOnOwnCFS(t *testing.T) {
 		t.Errorf("Expected %v, got %v", expected, actual)
 	}
 }
+
+func TestDeleteAllClusterState(t *testing.T) {
+	grid := []struct {
+		Path        string
+		KeepBackups bool
+		ExpectError bool
+		ExpectKept  bool
+	}{
+		{Path: "config"},
+		{Path: "pki/private/ca/keyset.yaml"},
+		{Path: "instancegroup/nodes"},
+		{Path: "rollingupdate/state"},
+		{Path: "rotate-ca/state"},
+		{Path: "backups/kops/before-upgrade/backup.yaml"},
+		{Path: "backups/etcd/main/2026-01-01T00:00:00Z-000001/etcd.backup.gz"},
+		{Path: "backups/kops/before-upgrade/backup.yaml", KeepBackups: true, ExpectKept: true},
+		{Path: "backups/etcd/main/2026-01-01T00:00:00Z-000001/etcd.backup.gz", KeepBackups: true, ExpectKept: true},
+		{Path: "unknown/file", ExpectError: true},
+	}
+	for _, g := range grid {
+		t.Run(fmt.Sprintf("%s/keepBackups=%v", g.Path, g.KeepBackups), func(t *testing.T) {
+			ctx := context.Background()
+			vfsContext := vfs.NewVFSContext()
+			vfsContext.ResetMemfsContext(true)
+			basePath, err := vfsContext.BuildVfsPath("memfs://state/test.example.com")
+			require.NoError(t, err)
+			require.NoError(t, basePath.Join("config").WriteFile(ctx, bytes.NewReader([]byte("config")), nil))
+			require.NoError(t, basePath.Join(g.Path).WriteFile(ctx, bytes.NewReader([]byte("data")), nil))
+
+			err = DeleteAllClusterState(ctx, basePath, g.KeepBackups)
+			if g.ExpectError {
+				assert.ErrorContains(t, err, "unknown file found")
+				return
+			}
+			require.NoError(t, err)
+
+			_, err = basePath.Join("config").ReadFile(ctx)
+			assert.ErrorIs(t, err, os.ErrNotExist, "config should have been deleted")
+			_, err = basePath.Join(g.Path).ReadFile(ctx)
+			if g.ExpectKept {
+				assert.NoError(t, err, "%s should have been kept", g.Path)
+			} else {
+				assert.ErrorIs(t, err, os.ErrNotExist, "%s should have been deleted", g.Path)
+			}
+		})
+	}
+}
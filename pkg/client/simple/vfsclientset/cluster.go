@@ -119,6 +119,8 @@ func (r *ClusterVFS) Create(c *api.Cluster) (*api.Cluster, error) {
 		return nil, fmt.Errorf("error writing Cluster %q: %v", c.ObjectMeta.Name, err)
 	}
 
+	recordRevision(ctx, r.basePath, c, "create Cluster")
+
 	return c, nil
 }
 
@@ -154,6 +156,8 @@ func (r *ClusterVFS) Update(c *api.Cluster, status *api.ClusterStatus) (*api.Clu
 		return nil, fmt.Errorf("error writing Cluster: %v", err)
 	}
 
+	recordRevision(ctx, r.basePath, c, "update Cluster")
+
 	return c, nil
 }
 
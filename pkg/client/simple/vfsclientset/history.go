@@ -0,0 +1,322 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"sort"
+	"strconv"
+	"time"
+
+	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/client/simple"
+	"k8s.io/kops/pkg/kopscodecs"
+	"k8s.io/kops/util/pkg/text"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kops/util/pkg/vfs/acls"
+)
+
+// PathHistory is the path (relative to the cluster's directory in the state store) where revisions of the
+// cluster and instance group specs are recorded.
+const PathHistory = "history"
+
+// maxRevisionAttempts bounds the retries when another writer records a revision with the same number
+const maxRevisionAttempts = 10
+
+// DefaultHistoryRetention is the number of revisions kept; older revisions are pruned when a revision is recorded
+const DefaultHistoryRetention = 100
+
+// clusterRevisionRecord is the file stored for each revision
+type clusterRevisionRecord struct {
+	simple.ClusterRevision
+
+	// Spec holds the cluster and instance group objects, as multiple YAML documents
+	Spec string `json:"spec"`
+}
+
+// ClusterHistoryVFS records and reads revisions of the cluster and instance group specs
+type ClusterHistoryVFS struct {
+	cluster *kops.Cluster
+	// clusterPath is the directory of the cluster in the state store
+	clusterPath vfs.Path
+	// retention is the number of revisions kept
+	retention int
+}
+
+var _ simple.ClusterHistoryClient = &ClusterHistoryVFS{}
+
+func newClusterHistoryVFS(basePath vfs.Path, cluster *kops.Cluster) *ClusterHistoryVFS {
+	return &ClusterHistoryVFS{
+		cluster:     cluster,
+		clusterPath: basePath.Join(cluster.Name),
+		retention:   DefaultHistoryRetention,
+	}
+}
+
+func (c *ClusterHistoryVFS) revisionPath(revision int) vfs.Path {
+	return c.clusterPath.Join(PathHistory, fmt.Sprintf("%08d", revision))
+}
+
+// List implements simple.ClusterHistoryClient
+func (c *ClusterHistoryVFS) List(ctx context.Context) ([]*simple.ClusterRevision, error) {
+	records, err := c.readAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var revisions []*simple.ClusterRevision
+	for _, record := range records {
+		revisions = append(revisions, &record.ClusterRevision)
+	}
+	return revisions, nil
+}
+
+// Get implements simple.ClusterHistoryClient
+func (c *ClusterHistoryVFS) Get(ctx context.Context, revision int) (*kops.Cluster, []*kops.InstanceGroup, error) {
+	record, err := c.read(ctx, c.revisionPath(revision))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, fmt.Errorf("revision %d of cluster %q not found", revision, c.cluster.Name)
+		}
+		return nil, nil, err
+	}
+
+	var cluster *kops.Cluster
+	var instanceGroups []*kops.InstanceGroup
+	for _, section := range text.SplitContentToSections([]byte(record.Spec)) {
+		if len(bytes.TrimSpace(section)) == 0 {
+			continue
+		}
+		o, _, err := kopscodecs.Decode(section, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing revision %d: %w", revision, err)
+		}
+		// We populate the same defaults as when reading the current objects, so that they can be compared
+		switch o := o.(type) {
+		case *kops.Cluster:
+			if o.Spec.ConfigStore.Base == "" {
+				o.Spec.ConfigStore.Base = c.clusterPath.Path()
+			}
+			cluster = o
+		case *kops.InstanceGroup:
+			if o.ObjectMeta.Labels == nil {
+				o.ObjectMeta.Labels = make(map[string]string)
+			}
+			o.ObjectMeta.Labels[kops.LabelClusterName] = c.cluster.Name
+			instanceGroups = append(instanceGroups, o)
+		default:
+			return nil, nil, fmt.Errorf("unexpected object of type %T in revision %d", o, revision)
+		}
+	}
+	if cluster == nil {
+		return nil, nil, fmt.Errorf("revision %d does not contain a cluster", revision)
+	}
+	return cluster, instanceGroups, nil
+}
+
+func (c *ClusterHistoryVFS) read(ctx context.Context, p vfs.Path) (*clusterRevisionRecord, error) {
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	record := &clusterRevisionRecord{}
+	if err := json.Unmarshal(b, record); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", p, err)
+	}
+	return record, nil
+}
+
+// revisions lists the recorded revision numbers, in increasing order, without reading the revisions
+func (c *ClusterHistoryVFS) revisions(ctx context.Context) ([]int, error) {
+	names, err := listChildNames(ctx, c.clusterPath.Join(PathHistory))
+	if err != nil {
+		return nil, err
+	}
+
+	var revisions []int
+	for _, name := range names {
+		revision, err := strconv.Atoi(name)
+		if err != nil {
+			klog.Warningf("ignoring unexpected file %q in cluster history", name)
+			continue
+		}
+		revisions = append(revisions, revision)
+	}
+	sort.Ints(revisions)
+	return revisions, nil
+}
+
+// readAll reads all the revisions, sorted by revision number
+func (c *ClusterHistoryVFS) readAll(ctx context.Context) ([]*clusterRevisionRecord, error) {
+	revisions, err := c.revisions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []*clusterRevisionRecord
+	for _, revision := range revisions {
+		record, err := c.read(ctx, c.revisionPath(revision))
+		if err != nil {
+			// The revision may have been pruned by another writer since we listed it
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// snapshot returns the current cluster and instance group objects, as stored
+func (c *ClusterHistoryVFS) snapshot(ctx context.Context) ([]byte, error) {
+	var sections [][]byte
+
+	configPath := c.clusterPath.Join(registry.PathCluster)
+	b, err := configPath.ReadFile(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %w", configPath, err)
+	}
+	sections = append(sections, b)
+
+	names, err := listChildNames(ctx, c.clusterPath.Join("instancegroup"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		p := c.clusterPath.Join("instancegroup", name)
+		b, err := p.ReadFile(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %w", p, err)
+		}
+		sections = append(sections, b)
+	}
+
+	for i, section := range sections {
+		if !bytes.HasSuffix(section, []byte("\n")) {
+			sections[i] = append(section, '\n')
+		}
+	}
+	return bytes.Join(sections, []byte("---\n")), nil
+}
+
+// record stores the current specs as a new revision, unless they are unchanged since the latest revision
+func (c *ClusterHistoryVFS) record(ctx context.Context, change string) error {
+	spec, err := c.snapshot(ctx)
+	if err != nil {
+		return err
+	}
+	hash := sha256.Sum256(spec)
+	specHash := hex.EncodeToString(hash[:])
+
+	// Only the latest revision is read, so that recording doesn't get slower as the history grows
+	revisions, err := c.revisions(ctx)
+	if err != nil {
+		return err
+	}
+	revision := 1
+	if len(revisions) != 0 {
+		latest, err := c.read(ctx, c.revisionPath(revisions[len(revisions)-1]))
+		if err != nil {
+			return err
+		}
+		if latest.SpecHash == specHash {
+			klog.V(4).Infof("cluster spec unchanged since revision %d", latest.Revision)
+			return nil
+		}
+		revision = revisions[len(revisions)-1] + 1
+	}
+
+	record := &clusterRevisionRecord{
+		ClusterRevision: simple.ClusterRevision{
+			Timestamp:   time.Now().UTC(),
+			Author:      currentAuthor(),
+			KopsVersion: kopsbase.Version,
+			Change:      change,
+			SpecHash:    specHash,
+		},
+		Spec: string(spec),
+	}
+
+	for attempt := 0; attempt < maxRevisionAttempts; attempt++ {
+		record.Revision = revision + attempt
+		b, err := json.Marshal(record)
+		if err != nil {
+			return fmt.Errorf("error encoding revision: %w", err)
+		}
+		p := c.revisionPath(record.Revision)
+		acl, err := acls.GetACL(ctx, p, c.cluster)
+		if err != nil {
+			return err
+		}
+		if err := p.CreateFile(ctx, bytes.NewReader(b), acl); err != nil {
+			if os.IsExist(err) {
+				continue
+			}
+			return fmt.Errorf("error writing %s: %w", p, err)
+		}
+		klog.V(2).Infof("recorded revision %d of cluster %q: %s", record.Revision, c.cluster.Name, change)
+		c.prune(ctx, append(revisions, record.Revision))
+		return nil
+	}
+	return fmt.Errorf("unable to record revision of cluster %q: too many concurrent writers", c.cluster.Name)
+}
+
+// prune deletes the oldest revisions beyond the retention limit.
+// Failures are only logged, as they leave extra revisions behind, which the next write will prune.
+func (c *ClusterHistoryVFS) prune(ctx context.Context, revisions []int) {
+	if c.retention <= 0 || len(revisions) <= c.retention {
+		return
+	}
+	for _, revision := range revisions[:len(revisions)-c.retention] {
+		p := c.revisionPath(revision)
+		if err := p.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			klog.Warningf("failed to prune revision %d of cluster %q: %v", revision, c.cluster.Name, err)
+			return
+		}
+		klog.V(4).Infof("pruned revision %d of cluster %q", revision, c.cluster.Name)
+	}
+}
+
+// recordRevision records a revision after a write, logging rather than failing as the write has already happened
+func recordRevision(ctx context.Context, basePath vfs.Path, cluster *kops.Cluster, change string) {
+	if err := newClusterHistoryVFS(basePath, cluster).record(ctx, change); err != nil {
+		klog.Warningf("failed to record cluster history for %q: %v", change, err)
+	}
+}
+
+// currentAuthor returns the user@host making changes
+func currentAuthor() string {
+	username := os.Getenv("USER")
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		username = u.Username
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return username + "@" + hostname
+	}
+	return username
+}
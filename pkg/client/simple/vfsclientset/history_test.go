@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestClusterHistory(t *testing.T) {
+	ctx := context.Background()
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	basePath, err := vfsContext.BuildVfsPath("memfs://state")
+	require.NoError(t, err)
+
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test.example.com"}}
+	clusterPath := basePath.Join(cluster.Name)
+	write := func(p vfs.Path, s string) {
+		require.NoError(t, p.WriteFile(ctx, bytes.NewReader([]byte(s)), nil))
+	}
+
+	history := newClusterHistoryVFS(basePath, cluster)
+
+	write(clusterPath.Join("config"), "apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: test.example.com\nspec:\n  kubernetesVersion: 1.30.0\n")
+	require.NoError(t, history.record(ctx, "create Cluster"))
+
+	write(clusterPath.Join("instancegroup", "nodes"), "apiVersion: kops.k8s.io/v1alpha2\nkind: InstanceGroup\nmetadata:\n  name: nodes\nspec:\n  role: Node\n  minSize: 2\n")
+	require.NoError(t, history.record(ctx, "create InstanceGroup nodes"))
+
+	// A write that doesn't change anything is not recorded
+	require.NoError(t, history.record(ctx, "update Cluster"))
+
+	write(clusterPath.Join("config"), "apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: test.example.com\nspec:\n  kubernetesVersion: 1.31.0\n")
+	require.NoError(t, history.record(ctx, "update Cluster"))
+
+	revisions, err := history.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	for i, change := range []string{"create Cluster", "create InstanceGroup nodes", "update Cluster"} {
+		assert.Equal(t, i+1, revisions[i].Revision)
+		assert.Equal(t, change, revisions[i].Change)
+		assert.NotEmpty(t, revisions[i].KopsVersion)
+		assert.False(t, revisions[i].Timestamp.IsZero())
+	}
+
+	c, igs, err := history.Get(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "1.30.0", c.Spec.KubernetesVersion)
+	assert.Equal(t, "memfs://state/test.example.com", c.Spec.ConfigStore.Base)
+	assert.Empty(t, igs)
+
+	c, igs, err = history.Get(ctx, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "1.30.0", c.Spec.KubernetesVersion)
+	require.Len(t, igs, 1)
+	assert.Equal(t, "nodes", igs[0].Name)
+	assert.Equal(t, int32(2), *igs[0].Spec.MinSize)
+	assert.Equal(t, cluster.Name, igs[0].Labels[kops.LabelClusterName])
+
+	c, _, err = history.Get(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, "1.31.0", c.Spec.KubernetesVersion)
+
+	_, _, err = history.Get(ctx, 4)
+	assert.Error(t, err)
+
+	// Deleting the cluster state also removes the history
+	require.NoError(t, DeleteAllClusterState(ctx, clusterPath, false))
+}
+
+func TestClusterHistoryRetention(t *testing.T) {
+	ctx := context.Background()
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	basePath, err := vfsContext.BuildVfsPath("memfs://state")
+	require.NoError(t, err)
+
+	cluster := &kops.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "test.example.com"}}
+	clusterPath := basePath.Join(cluster.Name)
+
+	history := newClusterHistoryVFS(basePath, cluster)
+	history.retention = 3
+
+	for i := 0; i < 5; i++ {
+		config := fmt.Sprintf("apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: test.example.com\nspec:\n  kubernetesVersion: 1.30.%d\n", i)
+		require.NoError(t, clusterPath.Join("config").WriteFile(ctx, bytes.NewReader([]byte(config)), nil))
+		require.NoError(t, history.record(ctx, "update Cluster"))
+	}
+
+	revisions, err := history.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	for i, revision := range revisions {
+		assert.Equal(t, i+3, revision.Revision)
+	}
+
+	_, _, err = history.Get(ctx, 2)
+	assert.ErrorContains(t, err, "not found")
+
+	c, _, err := history.Get(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "1.30.4", c.Spec.KubernetesVersion)
+
+	// Revision numbers keep increasing after pruning
+	require.NoError(t, clusterPath.Join("config").WriteFile(ctx, bytes.NewReader([]byte("apiVersion: kops.k8s.io/v1alpha2\nkind: Cluster\nmetadata:\n  name: test.example.com\nspec:\n  kubernetesVersion: 1.31.0\n")), nil))
+	require.NoError(t, history.record(ctx, "update Cluster"))
+	revisions, err = history.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, revisions, 3)
+	assert.Equal(t, 6, revisions[2].Revision)
+}
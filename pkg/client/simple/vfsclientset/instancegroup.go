@@ -31,6 +31,7 @@ import (
 	kopsapi "k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/validation"
 	kopsinternalversion "k8s.io/kops/pkg/client/clientset_generated/clientset/typed/kops/internalversion"
+	"k8s.io/kops/util/pkg/vfs"
 )
 
 type InstanceGroupVFS struct {
@@ -38,6 +39,8 @@ type InstanceGroupVFS struct {
 
 	clusterName string
 	cluster     *kopsapi.Cluster
+	// clustersPath is the base of the state store, under which the cluster history is recorded
+	clustersPath vfs.Path
 }
 
 func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGroupVFS {
@@ -49,8 +52,9 @@ func newInstanceGroupVFS(c *VFSClientset, cluster *kopsapi.Cluster) *InstanceGro
 	kind := "InstanceGroup"
 
 	r := &InstanceGroupVFS{
-		cluster:     cluster,
-		clusterName: clusterName,
+		cluster:      cluster,
+		clusterName:  clusterName,
+		clustersPath: c.basePath,
 	}
 	r.Init(kind, c.VFSContext(), c.basePath.Join(clusterName, "instancegroup"), StoreVersion)
 	r.validate = func(o runtime.Object) error {
@@ -105,6 +109,7 @@ func (c *InstanceGroupVFS) Create(ctx context.Context, g *kopsapi.InstanceGroup,
 	if err != nil {
 		return nil, err
 	}
+	recordRevision(ctx, c.clustersPath, c.cluster, "create InstanceGroup "+g.Name)
 	return g, nil
 }
 
@@ -123,11 +128,16 @@ func (c *InstanceGroupVFS) Update(ctx context.Context, g *kopsapi.InstanceGroup,
 	if err != nil {
 		return nil, err
 	}
+	recordRevision(ctx, c.clustersPath, c.cluster, "update InstanceGroup "+g.Name)
 	return g, nil
 }
 
 func (c *InstanceGroupVFS) Delete(ctx context.Context, name string, options metav1.DeleteOptions) error {
-	return c.delete(ctx, name, options)
+	if err := c.delete(ctx, name, options); err != nil {
+		return err
+	}
+	recordRevision(ctx, c.clustersPath, c.cluster, "delete InstanceGroup "+name)
+	return nil
 }
 
 func (r *InstanceGroupVFS) DeleteCollection(ctx context.Context, options metav1.DeleteOptions, listOptions metav1.ListOptions) error {
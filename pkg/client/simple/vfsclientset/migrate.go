@@ -0,0 +1,195 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/apis/kops/registry"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/util/pkg/hashing"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kops/util/pkg/vfs/acls"
+)
+
+// StateMigration is a plan to copy the state of a cluster from one state store to another.
+type StateMigration struct {
+	// From is the directory of the cluster in the source state store
+	From vfs.Path
+	// To is the directory of the cluster in the destination state store
+	To vfs.Path
+
+	// toStateStore is the destination state store
+	toStateStore vfs.Path
+
+	// Files are the files that will be copied, relative to From and To
+	Files []string
+
+	// Cluster is the cluster, with the paths into the source state store rewritten to the destination
+	Cluster *kops.Cluster
+	// Rewrites lists the fields of the cluster spec that are rewritten, as "field: old -> new"
+	Rewrites []string
+	// External lists the paths in the cluster spec that are outside the source directory, which are not copied
+	External []string
+}
+
+// PlanStateMigration builds the plan to copy the state of a cluster from its directory in one state store
+// (from) to another state store (toStateStore).
+// Everything in the directory is copied, except the state lock; the cluster spec is rewritten so that paths
+// into the source directory point to the same layout in the destination.
+func PlanStateMigration(ctx context.Context, cluster *kops.Cluster, from vfs.Path, toStateStore vfs.Path) (*StateMigration, error) {
+	to := toStateStore.Join(cluster.ObjectMeta.Name)
+	if _, err := to.Join(registry.PathCluster).ReadFile(ctx); err == nil {
+		return nil, fmt.Errorf("cluster %q already exists in %s", cluster.ObjectMeta.Name, to)
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("checking for cluster in %s: %w", to, err)
+	}
+
+	m := &StateMigration{
+		From:         from,
+		To:           to,
+		toStateStore: toStateStore,
+		Cluster:      cluster.DeepCopy(),
+	}
+
+	paths, err := from.ReadTree(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing files in %s: %w", from, err)
+	}
+	for _, p := range paths {
+		relativePath, err := vfs.RelativePath(from, p)
+		if err != nil {
+			return nil, err
+		}
+		if relativePath == "" || relativePath == statelock.PathLock {
+			continue
+		}
+		m.Files = append(m.Files, relativePath)
+	}
+	if len(m.Files) == 0 {
+		return nil, fmt.Errorf("no files found in %s", from)
+	}
+
+	spec := &m.Cluster.Spec
+	m.rewrite("spec.configStore.base", &spec.ConfigStore.Base)
+	m.rewrite("spec.configStore.keypairs", &spec.ConfigStore.Keypairs)
+	m.rewrite("spec.configStore.secrets", &spec.ConfigStore.Secrets)
+	for i := range spec.EtcdClusters {
+		if spec.EtcdClusters[i].Backups != nil {
+			m.rewrite(fmt.Sprintf("spec.etcdClusters[%s].backups.backupStore", spec.EtcdClusters[i].Name), &spec.EtcdClusters[i].Backups.BackupStore)
+		}
+	}
+	if spec.ConfigStore.Base == "" {
+		spec.ConfigStore.Base = to.Path()
+	}
+
+	return m, nil
+}
+
+// rewrite changes a path in the cluster spec from the source directory to the destination directory.
+func (m *StateMigration) rewrite(field string, location *string) {
+	if *location == "" {
+		return
+	}
+
+	from := strings.TrimSuffix(m.From.Path(), "/")
+	old := strings.TrimSuffix(*location, "/")
+	var rewritten string
+	if old == from {
+		rewritten = m.To.Path()
+	} else if strings.HasPrefix(old, from+"/") {
+		rewritten = m.To.Join(strings.TrimPrefix(old, from+"/")).Path()
+	} else {
+		m.External = append(m.External, fmt.Sprintf("%s: %s", field, *location))
+		return
+	}
+
+	m.Rewrites = append(m.Rewrites, fmt.Sprintf("%s: %s -> %s", field, *location, rewritten))
+	*location = rewritten
+}
+
+// Apply copies the files, verifying that the copy has the same SHA-256 hash as the source,
+// then writes the rewritten cluster spec.
+// The callback is called after each file is copied.
+func (m *StateMigration) Apply(ctx context.Context, vfsContext *vfs.VFSContext, copied func(relativePath string)) error {
+	for _, relativePath := range m.Files {
+		if relativePath == registry.PathCluster {
+			// We write the rewritten cluster spec last
+			continue
+		}
+		if err := m.copyFile(ctx, relativePath); err != nil {
+			return err
+		}
+		if copied != nil {
+			copied(relativePath)
+		}
+	}
+
+	clusters := newClusterVFS(vfsContext, m.toStateStore)
+	configPath := m.To.Join(registry.PathCluster)
+	if err := clusters.writeConfig(ctx, m.Cluster, configPath, m.Cluster, vfs.WriteOptionCreate); err != nil {
+		return fmt.Errorf("writing cluster spec to %s: %w", configPath, err)
+	}
+	if copied != nil {
+		copied(registry.PathCluster)
+	}
+
+	// The destination has no record of the history of the rewritten spec
+	recordRevision(ctx, m.toStateStore, m.Cluster, "migrate Cluster from "+m.From.Path())
+
+	return nil
+}
+
+func (m *StateMigration) copyFile(ctx context.Context, relativePath string) error {
+	src := m.From.Join(relativePath)
+	dest := m.To.Join(relativePath)
+
+	data, err := src.ReadFile(ctx)
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", src, err)
+	}
+	srcHash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	acl, err := acls.GetACL(ctx, dest, m.Cluster)
+	if err != nil {
+		return err
+	}
+	if err := dest.WriteFile(ctx, bytes.NewReader(data), acl); err != nil {
+		return fmt.Errorf("writing %s: %w", dest, err)
+	}
+
+	written, err := dest.ReadFile(ctx)
+	if err != nil {
+		return fmt.Errorf("reading back %s: %w", dest, err)
+	}
+	destHash, err := hashing.HashAlgorithmSHA256.Hash(bytes.NewReader(written))
+	if err != nil {
+		return err
+	}
+	if !srcHash.Equal(destHash) {
+		return fmt.Errorf("hash of %s (%s) does not match hash of %s (%s)", dest, destHash.Hex(), src, srcHash.Hex())
+	}
+	return nil
+}
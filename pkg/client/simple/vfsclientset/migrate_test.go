@@ -0,0 +1,132 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfsclientset
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/statelock"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestStateMigration(t *testing.T) {
+	ctx := context.Background()
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	write := func(p vfs.Path, s string) {
+		require.NoError(t, p.WriteFile(ctx, bytes.NewReader([]byte(s)), nil))
+	}
+
+	fromBase, err := vfsContext.BuildVfsPath("memfs://old-state")
+	require.NoError(t, err)
+	toBase, err := vfsContext.BuildVfsPath("memfs://new-state")
+	require.NoError(t, err)
+
+	from := fromBase.Join("test.example.com")
+	to := toBase.Join("test.example.com")
+
+	write(from.Join("config"), `apiVersion: kops.k8s.io/v1alpha2
+kind: Cluster
+metadata:
+  name: test.example.com
+spec:
+  configBase: memfs://old-state/test.example.com
+  keyStore: memfs://old-state/test.example.com/pki
+  secretStore: memfs://vault/test.example.com/secrets
+  etcdClusters:
+  - name: main
+    backups:
+      backupStore: memfs://old-state/test.example.com/backups/etcd/main
+  kubernetesVersion: 1.30.0
+`)
+	files := map[string]string{
+		"instancegroup/nodes":                    "apiVersion: kops.k8s.io/v1alpha2\nkind: InstanceGroup\nmetadata:\n  name: nodes\nspec:\n  role: Node\n",
+		"pki/private/kubernetes-ca/keyset.yaml":  "private",
+		"pki/ssh/public/admin/0123456789abcdef":  "ssh-rsa AAAA",
+		"addons/bootstrap-channel.yaml":          "kind: Addons",
+		"backups/etcd/main/control/etcd-cluster": "{}",
+	}
+	for relativePath, contents := range files {
+		write(from.Join(relativePath), contents)
+	}
+	write(from.Join(statelock.PathLock), "{}")
+
+	cluster, err := newClusterVFS(vfsContext, fromBase).find(ctx, "test.example.com")
+	require.NoError(t, err)
+
+	migration, err := PlanStateMigration(ctx, cluster, from, toBase)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{
+		"config",
+		"instancegroup/nodes",
+		"pki/private/kubernetes-ca/keyset.yaml",
+		"pki/ssh/public/admin/0123456789abcdef",
+		"addons/bootstrap-channel.yaml",
+		"backups/etcd/main/control/etcd-cluster",
+	}, migration.Files)
+	assert.Equal(t, []string{
+		"spec.configStore.base: memfs://old-state/test.example.com -> memfs://new-state/test.example.com",
+		"spec.configStore.keypairs: memfs://old-state/test.example.com/pki -> memfs://new-state/test.example.com/pki",
+		"spec.etcdClusters[main].backups.backupStore: memfs://old-state/test.example.com/backups/etcd/main -> memfs://new-state/test.example.com/backups/etcd/main",
+	}, migration.Rewrites)
+	assert.Equal(t, []string{"spec.configStore.secrets: memfs://vault/test.example.com/secrets"}, migration.External)
+
+	// Planning doesn't write anything
+	_, err = to.Join("config").ReadFile(ctx)
+	assert.Error(t, err)
+
+	var copied []string
+	require.NoError(t, migration.Apply(ctx, vfsContext, func(relativePath string) {
+		copied = append(copied, relativePath)
+	}))
+	assert.ElementsMatch(t, migration.Files, copied)
+
+	for relativePath, contents := range files {
+		data, err := to.Join(relativePath).ReadFile(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, contents, string(data), relativePath)
+	}
+	_, err = to.Join(statelock.PathLock).ReadFile(ctx)
+	assert.Error(t, err, "the lock should not be copied")
+
+	migrated, err := NewVFSClientset(vfsContext, toBase).GetCluster(ctx, "test.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "memfs://new-state/test.example.com", migrated.Spec.ConfigStore.Base)
+	assert.Equal(t, "memfs://new-state/test.example.com/pki", migrated.Spec.ConfigStore.Keypairs)
+	assert.Equal(t, "memfs://vault/test.example.com/secrets", migrated.Spec.ConfigStore.Secrets)
+	assert.Equal(t, "memfs://new-state/test.example.com/backups/etcd/main", migrated.Spec.EtcdClusters[0].Backups.BackupStore)
+	assert.Equal(t, "1.30.0", migrated.Spec.KubernetesVersion)
+
+	revisions, err := newClusterHistoryVFS(toBase, migrated).List(ctx)
+	require.NoError(t, err)
+	require.Len(t, revisions, 1)
+	assert.Equal(t, "migrate Cluster from memfs://old-state/test.example.com", revisions[0].Change)
+
+	// The source is unchanged
+	original, err := NewVFSClientset(vfsContext, fromBase).GetCluster(ctx, "test.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "memfs://old-state/test.example.com", original.Spec.ConfigStore.Base)
+
+	// We don't overwrite a cluster in the destination
+	_, err = PlanStateMigration(ctx, cluster, from, toBase)
+	assert.ErrorContains(t, err, "already exists")
+}
@@ -46,6 +46,8 @@ import (
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
 	"k8s.io/kops/pkg/apis/nodeup"
 	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
+	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
 	"k8s.io/kops/pkg/client/simple"
 	"k8s.io/kops/pkg/commands/commandutils"
 	"k8s.io/kops/pkg/featureflag"
@@ -74,6 +76,9 @@ type ToolboxEnrollOptions struct {
 	// BuildHost is a flag to only build the host resource, don't apply it or enroll the node
 	BuildHost bool
 
+	// TPM enrolls the machine with an attestation key held in its TPM, instead of a software machine key
+	TPM bool
+
 	// PodCIDRs is the list of IP Address ranges to use for pods that run on this node
 	PodCIDRs []string
 
@@ -122,6 +127,10 @@ func RunToolboxEnroll(ctx context.Context, f commandutils.Factory, out io.Writer
 		return err
 	}
 
+	if err := validateEnrollAttestation(fullCluster, options); err != nil {
+		return err
+	}
+
 	// Enroll the node over SSH.
 	restConfig, err := f.RESTConfig(ctx, fullCluster, options.CreateKubecfgOptions)
 	if err != nil {
@@ -167,9 +176,22 @@ func RunToolboxEnroll(ctx context.Context, f commandutils.Factory, out io.Writer
 	return nil
 }
 
+// validateEnrollAttestation checks that kops-controller will accept the credential the host is enrolled with.
+func validateEnrollAttestation(cluster *kops.Cluster, options *ToolboxEnrollOptions) error {
+	if options.TPM && (cluster.Spec.NodeAttestation == nil || cluster.Spec.NodeAttestation.TPM == nil) {
+		return fmt.Errorf("--tpm requires TPM attestation to be enabled in spec.nodeAttestation.tpm of cluster %q", cluster.Name)
+	}
+	return nil
+}
+
 // buildHostData builds an instance of the Host CRD, based on information in the options and by SSHing to the target host.
 func buildHostData(ctx context.Context, sshTarget *SSHHost, options *ToolboxEnrollOptions) (*v1alpha2.Host, error) {
-	publicKeyPath := "/etc/kubernetes/kops/pki/machine/public.pem"
+	publicKeyPath := path.Join(attestbootstrap.MachineDir, "public.pem")
+	createKeyScript := scriptCreateKey
+	if options.TPM {
+		publicKeyPath = path.Join(attestbootstrap.MachineDir, attestbootstrap.AttestationKeyFile)
+		createKeyScript = fmt.Sprintf(scriptCreateAttestationKey, uint32(attestbootstrap.AttestationKeyHandle))
+	}
 
 	publicKeyBytes, err := sshTarget.readFile(ctx, publicKeyPath)
 	if err != nil {
@@ -183,7 +205,7 @@ func buildHostData(ctx context.Context, sshTarget *SSHHost, options *ToolboxEnro
 	// Create the key if it doesn't exist
 	publicKeyBytes = bytes.TrimSpace(publicKeyBytes)
 	if len(publicKeyBytes) == 0 {
-		if _, err := sshTarget.runScript(ctx, scriptCreateKey, ExecOptions{Echo: true}); err != nil {
+		if _, err := sshTarget.runScript(ctx, createKeyScript, ExecOptions{Echo: true}); err != nil {
 			return nil, err
 		}
 
@@ -207,6 +229,11 @@ func buildHostData(ctx context.Context, sshTarget *SSHHost, options *ToolboxEnro
 	host.Spec.InstanceGroup = options.InstanceGroup
 	host.Spec.PublicKey = string(publicKeyBytes)
 	host.Spec.PodCIDRs = options.PodCIDRs
+	if options.TPM {
+		host.Annotations = map[string]string{
+			pkibootstrap.HostAnnotationMachineKeyType: pkibootstrap.MachineKeyTypeTPM,
+		}
+	}
 
 	return host, nil
 }
@@ -1030,3 +1057,29 @@ func rewriteChannelsManifestForEnroll(data []byte, bootstrapChannelURL string, l
 		kubemanifest.WithType(corev1.HostPathDirectory))
 	return k8scodecs.ToVersionedYaml(pod)
 }
+
+// scriptCreateAttestationKey creates a restricted signing key under the endorsement key with tpm2-tools,
+// persists it at the handle nodeup quotes with, and writes out its public key.
+const scriptCreateAttestationKey = `
+#!/bin/bash
+set -o errexit
+set -o nounset
+set -o pipefail
+
+set -x
+
+DIR=/etc/kubernetes/kops/pki/machine/
+mkdir -p ${DIR}
+
+if [[ ! -f "${DIR}/attestation-key.pem" ]]; then
+  WORKDIR=$(mktemp -d)
+  trap 'rm -rf "${WORKDIR}"' EXIT
+
+  if ! tpm2_readpublic -c 0x%[1]x >/dev/null 2>&1; then
+    tpm2_createek -c "${WORKDIR}/ek.ctx" -G ecc
+    tpm2_createak -C "${WORKDIR}/ek.ctx" -c "${WORKDIR}/ak.ctx" -G ecc -g sha256 -s ecdsa
+    tpm2_evictcontrol -C o -c "${WORKDIR}/ak.ctx" 0x%[1]x
+  fi
+  tpm2_readpublic -c 0x%[1]x -f pem -o "${DIR}/attestation-key.pem"
+fi
+`
@@ -21,6 +21,7 @@ import (
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/kops/pkg/apis/kops"
 	"sigs.k8s.io/yaml"
 )
 
@@ -97,3 +98,33 @@ spec:
 		t.Errorf("expected mountPath /etc/kubernetes/kops/config/addons, got %q", mount.MountPath)
 	}
 }
+
+func TestValidateEnrollAttestation(t *testing.T) {
+	grid := []struct {
+		name            string
+		tpm             bool
+		nodeAttestation *kops.NodeAttestationSpec
+		wantError       bool
+	}{
+		{name: "machine key without attestation"},
+		{name: "tpm without attestation", tpm: true, wantError: true},
+		{name: "tpm with only SPIFFE attestation", tpm: true, nodeAttestation: &kops.NodeAttestationSpec{SPIFFE: &kops.SPIFFEAttestationSpec{}}, wantError: true},
+		{name: "tpm with TPM attestation", tpm: true, nodeAttestation: &kops.NodeAttestationSpec{TPM: &kops.TPMAttestationSpec{}}},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			cluster := &kops.Cluster{}
+			cluster.Name = "example.k8s.local"
+			cluster.Spec.NodeAttestation = g.nodeAttestation
+
+			err := validateEnrollAttestation(cluster, &ToolboxEnrollOptions{TPM: g.tpm})
+			if g.wantError {
+				if err == nil || !strings.Contains(err.Error(), "spec.nodeAttestation.tpm") {
+					t.Errorf("expected spec.nodeAttestation.tpm error, got %v", err)
+				}
+			} else if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
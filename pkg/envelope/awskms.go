@@ -0,0 +1,69 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"k8s.io/kops/pkg/kmsclient"
+)
+
+// awsKMSKeyProvider wraps data keys with an AWS KMS key
+type awsKMSKeyProvider struct {
+	keyARN string
+	client *kms.Client
+}
+
+var _ KeyProvider = &awsKMSKeyProvider{}
+
+func newAWSKMSKeyProvider(ctx context.Context, key string) (*awsKMSKeyProvider, error) {
+	client, err := kmsclient.NewAWS(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	return &awsKMSKeyProvider{
+		keyARN: key,
+		client: client,
+	}, nil
+}
+
+// WrapKey implements KeyProvider
+func (p *awsKMSKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	response, err := p.client.Encrypt(ctx, &kms.EncryptInput{
+		KeyId:     aws.String(p.keyARN),
+		Plaintext: key,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling KMS Encrypt: %w", err)
+	}
+	return response.CiphertextBlob, nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *awsKMSKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	response, err := p.client.Decrypt(ctx, &kms.DecryptInput{
+		KeyId:          aws.String(p.keyARN),
+		CiphertextBlob: wrapped,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling KMS Decrypt: %w", err)
+	}
+	return response.Plaintext, nil
+}
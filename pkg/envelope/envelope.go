@@ -0,0 +1,181 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package envelope implements envelope encryption of secrets and private keys stored in the state store.
+//
+// Each object is encrypted with its own randomly generated data key (AES-256-GCM),
+// and the data key is wrapped by a key provider, such as a cloud KMS.
+// The encrypted object records the URI of the key that wrapped its data key,
+// so readers don't need to be configured to decrypt it.
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+)
+
+// header marks an encrypted object, distinguishing it from plaintext
+const header = "kops-envelope:v1\n"
+
+// dataKeySize is the size of the per-object AES-256 data key
+const dataKeySize = 32
+
+// sealed is the serialized form of an encrypted object, following the header
+type sealed struct {
+	// KeyURI identifies the key that wrapped the data key
+	KeyURI string `json:"keyURI"`
+	// WrappedKey is the data key, wrapped by the key provider
+	WrappedKey []byte `json:"wrappedKey"`
+	// Nonce is the AES-GCM nonce
+	Nonce []byte `json:"nonce"`
+	// Ciphertext is the object, encrypted with the data key
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// IsEncrypted returns true if data was produced by Encrypt
+func IsEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(header))
+}
+
+// Encrypt encrypts plaintext with a new data key, wrapped by the key identified by keyURI
+func Encrypt(ctx context.Context, keyURI string, plaintext []byte) ([]byte, error) {
+	provider, err := keyProviderFor(ctx, keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	dataKey := make([]byte, dataKeySize)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, fmt.Errorf("generating data key: %w", err)
+	}
+	wrappedKey, err := provider.WrapKey(ctx, dataKey)
+	if err != nil {
+		return nil, fmt.Errorf("wrapping data key with %q: %w", keyURI, err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+
+	s := &sealed{
+		KeyURI:     keyURI,
+		WrappedKey: wrappedKey,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, plaintext, []byte(keyURI)),
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(header), b...), nil
+}
+
+// Decrypt decrypts data produced by Encrypt. Data that is not encrypted is returned unchanged.
+func Decrypt(ctx context.Context, data []byte) ([]byte, error) {
+	if !IsEncrypted(data) {
+		return data, nil
+	}
+	s, err := parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	provider, err := keyProviderFor(ctx, s.KeyURI)
+	if err != nil {
+		return nil, err
+	}
+	dataKey, err := provider.UnwrapKey(ctx, s.WrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key with %q: %w", s.KeyURI, err)
+	}
+
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, fmt.Errorf("invalid nonce in encrypted object")
+	}
+	plaintext, err := aead.Open(nil, s.Nonce, s.Ciphertext, []byte(s.KeyURI))
+	if err != nil {
+		return nil, fmt.Errorf("decrypting object: %w", err)
+	}
+	return plaintext, nil
+}
+
+// KeyURIOf returns the URI of the key that encrypted data, or "" if data is not encrypted
+func KeyURIOf(data []byte) (string, error) {
+	if !IsEncrypted(data) {
+		return "", nil
+	}
+	s, err := parse(data)
+	if err != nil {
+		return "", err
+	}
+	return s.KeyURI, nil
+}
+
+// Reseal returns data encrypted with the key identified by keyURI, or in plaintext if keyURI is empty.
+// Data already encrypted with keyURI is returned unchanged.
+func Reseal(ctx context.Context, keyURI string, data []byte) ([]byte, error) {
+	current, err := KeyURIOf(data)
+	if err != nil {
+		return nil, err
+	}
+	if current == keyURI {
+		return data, nil
+	}
+	plaintext, err := Decrypt(ctx, data)
+	if err != nil {
+		return nil, err
+	}
+	if keyURI == "" {
+		return plaintext, nil
+	}
+	return Encrypt(ctx, keyURI, plaintext)
+}
+
+func parse(data []byte) (*sealed, error) {
+	s := &sealed{}
+	if err := json.Unmarshal(data[len(header):], s); err != nil {
+		return nil, fmt.Errorf("parsing encrypted object: %w", err)
+	}
+	if s.KeyURI == "" {
+		return nil, fmt.Errorf("encrypted object does not identify its key")
+	}
+	return s, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("data key has unexpected length %d", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"google.golang.org/api/cloudkms/v1"
+	"k8s.io/kops/pkg/kmsclient"
+)
+
+// gcpKMSKeyProvider wraps data keys with a Google Cloud KMS key
+type gcpKMSKeyProvider struct {
+	keyName string
+	service *cloudkms.Service
+}
+
+var _ KeyProvider = &gcpKMSKeyProvider{}
+
+func newGCPKMSKeyProvider(ctx context.Context, key string) (*gcpKMSKeyProvider, error) {
+	if err := kmsclient.ValidateGCPKeyName(key); err != nil {
+		return nil, err
+	}
+
+	service, err := kmsclient.NewGCP(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &gcpKMSKeyProvider{
+		keyName: key,
+		service: service,
+	}, nil
+}
+
+// WrapKey implements KeyProvider
+func (p *gcpKMSKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	request := &cloudkms.EncryptRequest{
+		Plaintext: base64.StdEncoding.EncodeToString(key),
+	}
+	response, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Encrypt(p.keyName, request).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("calling Cloud KMS encrypt: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(response.Ciphertext)
+}
+
+// UnwrapKey implements KeyProvider
+func (p *gcpKMSKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	request := &cloudkms.DecryptRequest{
+		Ciphertext: base64.StdEncoding.EncodeToString(wrapped),
+	}
+	response, err := p.service.Projects.Locations.KeyRings.CryptoKeys.Decrypt(p.keyName, request).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("calling Cloud KMS decrypt: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(response.Plaintext)
+}
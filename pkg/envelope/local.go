@@ -0,0 +1,85 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"os"
+)
+
+// localKeyProvider wraps data keys with an AES-256 key read from a local file.
+// It is intended for testing and for state stores that never leave the operator's machine;
+// every reader of the state store needs a copy of the key file.
+type localKeyProvider struct {
+	key []byte
+}
+
+var _ KeyProvider = &localKeyProvider{}
+
+func newLocalKeyProvider(path string) (*localKeyProvider, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading local key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(b)))
+	if err != nil {
+		return nil, fmt.Errorf("local key %q must contain a base64-encoded 32 byte key: %w", path, err)
+	}
+	if len(key) != dataKeySize {
+		return nil, fmt.Errorf("local key %q must contain a base64-encoded 32 byte key, found %d bytes", path, len(key))
+	}
+	return &localKeyProvider{key: key}, nil
+}
+
+// GenerateLocalKey returns a new key for the local:// key provider, base64-encoded as it is stored in the key file
+func GenerateLocalKey() ([]byte, error) {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return []byte(base64.StdEncoding.EncodeToString(key) + "\n"), nil
+}
+
+// WrapKey implements KeyProvider
+func (p *localKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	aead, err := newAEAD(p.key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, key, nil), nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *localKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	aead, err := newAEAD(p.key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, fmt.Errorf("wrapped key is too short")
+	}
+	nonce, ciphertext := wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, nil)
+}
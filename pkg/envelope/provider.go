@@ -0,0 +1,108 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"k8s.io/kops/pkg/kmsclient"
+)
+
+// KeyProvider wraps and unwraps the data keys of encrypted objects
+type KeyProvider interface {
+	// WrapKey encrypts a data key
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	// UnwrapKey decrypts a data key wrapped by WrapKey
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+}
+
+var (
+	providersMutex sync.Mutex
+	providers      = make(map[string]KeyProvider)
+)
+
+// keyProviderFor returns the KeyProvider for the key URI, reusing providers that have already been built
+func keyProviderFor(ctx context.Context, keyURI string) (KeyProvider, error) {
+	providersMutex.Lock()
+	defer providersMutex.Unlock()
+
+	if p := providers[keyURI]; p != nil {
+		return p, nil
+	}
+
+	scheme, key, err := splitKeyURI(keyURI)
+	if err != nil {
+		return nil, err
+	}
+
+	var p KeyProvider
+	switch scheme {
+	case "awskms":
+		p, err = newAWSKMSKeyProvider(ctx, key)
+	case "gcpkms":
+		p, err = newGCPKMSKeyProvider(ctx, key)
+	case "vaulttransit":
+		p, err = newVaultTransitKeyProvider(ctx, key)
+	case "local":
+		p, err = newLocalKeyProvider(key)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("building key provider for %q: %w", keyURI, err)
+	}
+
+	providers[keyURI] = p
+	return p, nil
+}
+
+// ValidateKeyURI checks that keyURI is well-formed, without contacting the key provider
+func ValidateKeyURI(keyURI string) error {
+	scheme, key, err := splitKeyURI(keyURI)
+	if err != nil {
+		return err
+	}
+	switch scheme {
+	case "awskms":
+		_, err = kmsclient.ParseAWSKeyARN(key)
+	case "gcpkms":
+		err = kmsclient.ValidateGCPKeyName(key)
+	case "vaulttransit":
+		_, _, err = parseVaultTransitKey(key)
+	case "local":
+		if !strings.HasPrefix(key, "/") {
+			err = fmt.Errorf("local key must be an absolute path, e.g. local:///path/to/key")
+		}
+	}
+	return err
+}
+
+// splitKeyURI splits a key URI into its scheme and the key within the provider.
+// The key can't be parsed as a URL: AWS key ARNs contain colons.
+func splitKeyURI(keyURI string) (string, string, error) {
+	scheme, key, found := strings.Cut(keyURI, "://")
+	if !found || key == "" {
+		return "", "", fmt.Errorf("invalid key URI %q", keyURI)
+	}
+	switch scheme {
+	case "awskms", "gcpkms", "vaulttransit", "local":
+		return scheme, key, nil
+	default:
+		return "", "", fmt.Errorf("unsupported key URI %q; expected awskms://, gcpkms://, vaulttransit:// or local://", keyURI)
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package envelope
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// vaultTransitKeyProvider wraps data keys with a key in a Vault transit secrets engine.
+// The Vault server and credentials are taken from the standard Vault environment variables, as for vault:// paths.
+type vaultTransitKeyProvider struct {
+	client  *vfs.VaultClient
+	mount   string
+	keyName string
+}
+
+var _ KeyProvider = &vaultTransitKeyProvider{}
+
+// parseVaultTransitKey parses a key of the form <mount>/<name>, e.g. transit/kops-state.
+// The mount may itself contain slashes.
+func parseVaultTransitKey(key string) (string, string, error) {
+	i := strings.LastIndex(key, "/")
+	if i <= 0 || i == len(key)-1 {
+		return "", "", fmt.Errorf("vault transit key must be <mount>/<key>, e.g. vaulttransit://transit/kops-state")
+	}
+	return key[:i], key[i+1:], nil
+}
+
+func newVaultTransitKeyProvider(ctx context.Context, key string) (*vaultTransitKeyProvider, error) {
+	mount, keyName, err := parseVaultTransitKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := vfs.NewVaultClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &vaultTransitKeyProvider{
+		client:  client,
+		mount:   mount,
+		keyName: keyName,
+	}, nil
+}
+
+// WrapKey implements KeyProvider
+func (p *vaultTransitKeyProvider) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	request := map[string]any{
+		"plaintext": key,
+	}
+	var response struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := p.call(ctx, "encrypt", request, &response); err != nil {
+		return nil, err
+	}
+	if response.Data.Ciphertext == "" {
+		return nil, fmt.Errorf("vault transit encrypt returned no ciphertext")
+	}
+	return []byte(response.Data.Ciphertext), nil
+}
+
+// UnwrapKey implements KeyProvider
+func (p *vaultTransitKeyProvider) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	request := map[string]any{
+		"ciphertext": string(wrapped),
+	}
+	var response struct {
+		Data struct {
+			Plaintext []byte `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := p.call(ctx, "decrypt", request, &response); err != nil {
+		return nil, err
+	}
+	return response.Data.Plaintext, nil
+}
+
+func (p *vaultTransitKeyProvider) call(ctx context.Context, operation string, request any, response any) error {
+	apiPath := fmt.Sprintf("%s/%s/%s", p.mount, operation, p.keyName)
+	if err := p.client.Do(ctx, http.MethodPost, apiPath, request, response); err != nil {
+		return fmt.Errorf("calling Vault transit %s: %w", operation, err)
+	}
+	return nil
+}
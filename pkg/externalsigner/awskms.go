@@ -0,0 +1,110 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	kmstypes "github.com/aws/aws-sdk-go-v2/service/kms/types"
+	"k8s.io/kops/pkg/kmsclient"
+)
+
+// awsKMSSigner signs with an asymmetric AWS KMS key
+type awsKMSSigner struct {
+	client *kms.Client
+	keyARN string
+	public crypto.PublicKey
+}
+
+var _ crypto.Signer = &awsKMSSigner{}
+
+func newAWSKMSSigner(ctx context.Context, keyURI string) (crypto.Signer, error) {
+	keyARN := strings.TrimPrefix(keyURI, "awskms://")
+
+	client, err := kmsclient.NewAWS(ctx, keyARN)
+	if err != nil {
+		return nil, err
+	}
+	return newAWSKMSSignerWithClient(ctx, client, keyARN)
+}
+
+func newAWSKMSSignerWithClient(ctx context.Context, client *kms.Client, keyARN string) (*awsKMSSigner, error) {
+	response, err := client.GetPublicKey(ctx, &kms.GetPublicKeyInput{
+		KeyId: aws.String(keyARN),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting public key of AWS KMS key %q: %w", keyARN, err)
+	}
+	if response.KeyUsage != kmstypes.KeyUsageTypeSignVerify {
+		return nil, fmt.Errorf("AWS KMS key %q has usage %q, expected SIGN_VERIFY", keyARN, response.KeyUsage)
+	}
+	public, err := x509.ParsePKIXPublicKey(response.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key of AWS KMS key %q: %w", keyARN, err)
+	}
+
+	return &awsKMSSigner{
+		client: client,
+		keyARN: keyARN,
+		public: public,
+	}, nil
+}
+
+// Public implements crypto.Signer
+func (s *awsKMSSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign implements crypto.Signer
+func (s *awsKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	algorithm, err := signatureAlgorithmFor(s.public, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var signingAlgorithm string
+	hash := strings.Replace(algorithm.hash.String(), "-", "_", 1)
+	switch {
+	case algorithm.ecdsa:
+		signingAlgorithm = "ECDSA_" + hash
+	case algorithm.pss:
+		if algorithm.saltLength != algorithm.hash.Size() {
+			return nil, fmt.Errorf("AWS KMS only supports RSA-PSS salts of the hash length")
+		}
+		signingAlgorithm = "RSASSA_PSS_" + hash
+	default:
+		signingAlgorithm = "RSASSA_PKCS1_V1_5_" + hash
+	}
+
+	response, err := s.client.Sign(context.TODO(), &kms.SignInput{
+		KeyId:            aws.String(s.keyARN),
+		Message:          digest,
+		MessageType:      kmstypes.MessageTypeDigest,
+		SigningAlgorithm: kmstypes.SigningAlgorithmSpec(signingAlgorithm),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("calling KMS Sign: %w", err)
+	}
+	return response.Signature, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package externalsigner implements signers for private keys held outside of kOps, in an HSM or a cloud KMS.
+//
+// Register adds the signers to the pki package, for key URIs such as:
+//
+//	awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+//	gcpkms://projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key/cryptoKeyVersions/1
+//	pkcs11:token=kops;object=service-account?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=file:/etc/kops/pin
+//
+// PKCS#11 requires building with cgo and the pkcs11 build tag.
+package externalsigner
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+
+	"k8s.io/kops/pkg/pki"
+)
+
+// Register registers the external signers with the pki package.
+// Binaries that sign with keysets call it at startup.
+func Register() {
+	pki.RegisterExternalSigner("awskms", newAWSKMSSigner)
+	pki.RegisterExternalSigner("gcpkms", newGCPKMSSigner)
+	pki.RegisterExternalSigner("pkcs11", newPKCS11Signer)
+}
+
+// signatureAlgorithm describes the algorithm the caller of crypto.Signer expects.
+type signatureAlgorithm struct {
+	// hash is the hash function that produced the digest
+	hash crypto.Hash
+	// pss is true for RSA-PSS signatures, false for RSA PKCS #1 v1.5 signatures
+	pss bool
+	// saltLength is the RSA-PSS salt length
+	saltLength int
+	// ecdsa is true for ECDSA signatures
+	ecdsa bool
+}
+
+func signatureAlgorithmFor(public crypto.PublicKey, digest []byte, opts crypto.SignerOpts) (*signatureAlgorithm, error) {
+	hash := opts.HashFunc()
+	switch hash {
+	case crypto.SHA256, crypto.SHA384, crypto.SHA512:
+	default:
+		return nil, fmt.Errorf("unsupported hash function %v", hash)
+	}
+	if len(digest) != hash.Size() {
+		return nil, fmt.Errorf("digest length %d does not match hash function %v", len(digest), hash)
+	}
+
+	algorithm := &signatureAlgorithm{hash: hash}
+	switch public.(type) {
+	case *rsa.PublicKey:
+		if pssOptions, ok := opts.(*rsa.PSSOptions); ok {
+			algorithm.pss = true
+			algorithm.saltLength = pssOptions.SaltLength
+			if algorithm.saltLength == rsa.PSSSaltLengthAuto || algorithm.saltLength == rsa.PSSSaltLengthEqualsHash {
+				algorithm.saltLength = hash.Size()
+			}
+		}
+	case *ecdsa.PublicKey:
+		algorithm.ecdsa = true
+	default:
+		return nil, fmt.Errorf("unsupported public key type %T", public)
+	}
+	return algorithm, nil
+}
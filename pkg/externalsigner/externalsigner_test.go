@@ -0,0 +1,284 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/option"
+	"k8s.io/kops/pkg/kmsclient"
+	"k8s.io/kops/pkg/pki"
+)
+
+// fakeAWSKMS serves the GetPublicKey and Sign operations of the AWS KMS JSON API for a single key
+func fakeAWSKMS(t *testing.T, keyARN string, key crypto.Signer) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Contains(t, r.Header.Get("Authorization"), "AWS4-HMAC-SHA256")
+
+		var request struct {
+			KeyId            string
+			Message          []byte
+			MessageType      string
+			SigningAlgorithm string
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		if request.KeyId != keyARN {
+			http.Error(w, "key not found", http.StatusBadRequest)
+			return
+		}
+
+		var response any
+		switch r.Header.Get("X-Amz-Target") {
+		case "TrentService.GetPublicKey":
+			public, err := x509.MarshalPKIXPublicKey(key.Public())
+			require.NoError(t, err)
+			response = map[string]any{"PublicKey": public, "KeyUsage": "SIGN_VERIFY"}
+		case "TrentService.Sign":
+			assert.Equal(t, "DIGEST", request.MessageType)
+			var opts crypto.SignerOpts
+			switch request.SigningAlgorithm {
+			case "ECDSA_SHA_256", "RSASSA_PKCS1_V1_5_SHA_256":
+				opts = crypto.SHA256
+			case "RSASSA_PSS_SHA_256":
+				opts = &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthEqualsHash}
+			default:
+				http.Error(w, "unexpected algorithm "+request.SigningAlgorithm, http.StatusBadRequest)
+				return
+			}
+			signature, err := key.Sign(rand.Reader, request.Message, opts)
+			require.NoError(t, err)
+			response = map[string]any{"Signature": signature}
+		default:
+			http.Error(w, "unexpected operation", http.StatusBadRequest)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+// fakeGCPKMS serves the publicKey and asymmetricSign methods of the Cloud KMS REST API for a single key version
+func fakeGCPKMS(t *testing.T, keyName string, algorithm string, key crypto.Signer) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var response any
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/"+keyName+"/publicKey":
+			public, err := x509.MarshalPKIXPublicKey(key.Public())
+			require.NoError(t, err)
+			response = map[string]any{
+				"pem":       string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: public})),
+				"algorithm": algorithm,
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/"+keyName+":asymmetricSign":
+			var request struct {
+				Digest struct {
+					SHA256 []byte `json:"sha256"`
+				} `json:"digest"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			signature, err := key.Sign(rand.Reader, request.Digest.SHA256, crypto.SHA256)
+			require.NoError(t, err)
+			response = map[string]any{"signature": signature}
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+}
+
+// issueSelfSigned issues a self-signed CA certificate with signer, and checks its signature
+func issueSelfSigned(t *testing.T, signer crypto.Signer) {
+	cert, _, _, err := pki.IssueCert(context.Background(), &pki.IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: "kubernetes-ca"},
+		Serial:     big.NewInt(1),
+		PrivateKey: &pki.PrivateKey{Key: signer},
+	}, nil)
+	require.NoError(t, err)
+	require.NoError(t, cert.Certificate.CheckSignatureFrom(cert.Certificate))
+}
+
+func TestAWSKMSSigner(t *testing.T) {
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	grid := []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{name: "ecdsa", key: ecdsaKey},
+		{name: "rsa", key: rsaKey},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			keyARN := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+			server := fakeAWSKMS(t, keyARN, g.key)
+			defer server.Close()
+
+			client := kms.New(kms.Options{
+				Region:       "us-east-1",
+				BaseEndpoint: aws.String(server.URL),
+				Credentials: aws.CredentialsProviderFunc(func(ctx context.Context) (aws.Credentials, error) {
+					return aws.Credentials{AccessKeyID: "AKID", SecretAccessKey: "SECRET"}, nil
+				}),
+				HTTPClient: server.Client(),
+			})
+			signer, err := newAWSKMSSignerWithClient(context.Background(), client, keyARN)
+			require.NoError(t, err)
+			assert.True(t, g.key.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(signer.Public()))
+
+			issueSelfSigned(t, signer)
+		})
+	}
+}
+
+func TestAWSKMSSignerInvalidURI(t *testing.T) {
+	_, err := newAWSKMSSigner(context.Background(), "awskms://alias/kops")
+	assert.ErrorContains(t, err, "AWS KMS key must be a key ARN")
+}
+
+func TestGCPKMSSigner(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyName := "projects/p/locations/global/keyRings/kops/cryptoKeys/kubernetes-ca/cryptoKeyVersions/1"
+
+	server := fakeGCPKMS(t, keyName, "EC_SIGN_P256_SHA256", key)
+	defer server.Close()
+
+	service, err := kmsclient.NewGCP(context.Background(), option.WithEndpoint(server.URL), option.WithoutAuthentication())
+	require.NoError(t, err)
+	signer, err := newGCPKMSSignerWithService(context.Background(), service, keyName)
+	require.NoError(t, err)
+	assert.True(t, key.PublicKey.Equal(signer.Public()))
+
+	issueSelfSigned(t, signer)
+
+	digest := make([]byte, crypto.SHA384.Size())
+	_, err = signer.Sign(rand.Reader, digest, crypto.SHA384)
+	assert.ErrorContains(t, err, "cannot sign a SHA-384 digest")
+}
+
+func TestGCPKMSSignerInvalidURI(t *testing.T) {
+	_, err := newGCPKMSSigner(context.Background(), "gcpkms://projects/p/locations/global/keyRings/kops/cryptoKeys/kubernetes-ca")
+	assert.ErrorContains(t, err, "GCP KMS key must be a key version name")
+}
+
+func TestParsePKCS11URI(t *testing.T) {
+	pinFile := filepath.Join(t.TempDir(), "pin")
+	require.NoError(t, os.WriteFile(pinFile, []byte("1234\n"), 0o600))
+
+	grid := []struct {
+		keyURI   string
+		expected *pkcs11URI
+		err      string
+	}{
+		{
+			keyURI: "pkcs11:token=kops;object=kubernetes-ca?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-value=1234",
+			expected: &pkcs11URI{
+				modulePath: "/usr/lib/softhsm/libsofthsm2.so",
+				token:      "kops",
+				object:     "kubernetes-ca",
+				pin:        "1234",
+			},
+		},
+		{
+			keyURI: "pkcs11:slot-id=2;id=%01%02;type=private?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=file:" + pinFile,
+			expected: &pkcs11URI{
+				modulePath: "/usr/lib/softhsm/libsofthsm2.so",
+				slotID:     new(uint(2)),
+				id:         []byte{1, 2},
+				pin:        "1234",
+			},
+		},
+		{
+			keyURI: "pkcs11:token=My%20Token;object=service-account?module-path=/opt/hsm/lib.so",
+			expected: &pkcs11URI{
+				modulePath: "/opt/hsm/lib.so",
+				token:      "My Token",
+				object:     "service-account",
+			},
+		},
+		{
+			keyURI: "pkcs11:token=kops;object=kubernetes-ca",
+			err:    "must specify module-path",
+		},
+		{
+			keyURI: "pkcs11:object=kubernetes-ca?module-path=/opt/hsm/lib.so",
+			err:    "must specify token or slot-id",
+		},
+		{
+			keyURI: "pkcs11:token=kops?module-path=/opt/hsm/lib.so",
+			err:    "must specify object or id",
+		},
+		{
+			keyURI: "pkcs11:token=kops;object=kubernetes-ca;type=cert?module-path=/opt/hsm/lib.so",
+			err:    "must identify a private key",
+		},
+		{
+			keyURI: "pkcs11:token=kops;object=kubernetes-ca?module-path=/opt/hsm/lib.so&pin-source=https://example.com/pin",
+			err:    "unsupported pin-source",
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.keyURI, func(t *testing.T) {
+			u, err := parsePKCS11URI(g.keyURI)
+			if g.err != "" {
+				assert.ErrorContains(t, err, g.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, g.expected, u)
+		})
+	}
+}
+
+func TestRegister(t *testing.T) {
+	Register()
+
+	for _, keyURI := range []string{
+		"awskms://alias/kops",
+		"gcpkms://projects/p",
+		"pkcs11:token=kops",
+	} {
+		_, err := pki.NewExternalPrivateKey(context.Background(), keyURI)
+		require.Error(t, err)
+		assert.False(t, strings.Contains(err.Error(), "no external signer"), fmt.Sprintf("%s: %v", keyURI, err))
+	}
+}
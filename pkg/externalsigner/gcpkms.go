@@ -0,0 +1,119 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/api/cloudkms/v1"
+	"k8s.io/kops/pkg/kmsclient"
+)
+
+// gcpKMSSigner signs with an asymmetric Google Cloud KMS key version
+type gcpKMSSigner struct {
+	keyName string
+	service *cloudkms.Service
+	public  crypto.PublicKey
+	// algorithm is the Cloud KMS algorithm of the key version, e.g. RSA_SIGN_PKCS1_2048_SHA256
+	algorithm string
+}
+
+var _ crypto.Signer = &gcpKMSSigner{}
+
+func newGCPKMSSigner(ctx context.Context, keyURI string) (crypto.Signer, error) {
+	keyName := strings.TrimPrefix(keyURI, "gcpkms://")
+	if err := kmsclient.ValidateGCPKeyVersionName(keyName); err != nil {
+		return nil, err
+	}
+
+	service, err := kmsclient.NewGCP(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return newGCPKMSSignerWithService(ctx, service, keyName)
+}
+
+func newGCPKMSSignerWithService(ctx context.Context, service *cloudkms.Service, keyName string) (*gcpKMSSigner, error) {
+	response, err := service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions.GetPublicKey(keyName).Context(ctx).Do()
+	if err != nil {
+		return nil, fmt.Errorf("getting public key of Cloud KMS key %q: %w", keyName, err)
+	}
+	block, _ := pem.Decode([]byte(response.Pem))
+	if block == nil {
+		return nil, fmt.Errorf("parsing public key of Cloud KMS key %q: no PEM data", keyName)
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key of Cloud KMS key %q: %w", keyName, err)
+	}
+
+	return &gcpKMSSigner{
+		keyName:   keyName,
+		service:   service,
+		public:    public,
+		algorithm: response.Algorithm,
+	}, nil
+}
+
+// Public implements crypto.Signer
+func (s *gcpKMSSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign implements crypto.Signer
+func (s *gcpKMSSigner) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	algorithm, err := signatureAlgorithmFor(s.public, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// The algorithm of a Cloud KMS key version is fixed when it is created
+	digestField := strings.Replace(algorithm.hash.String(), "-", "", 1)
+	if !strings.HasSuffix(s.algorithm, "_"+digestField) {
+		return nil, fmt.Errorf("cloud KMS key %q with algorithm %s cannot sign a %v digest", s.keyName, s.algorithm, algorithm.hash)
+	}
+	if !algorithm.ecdsa && algorithm.pss != strings.HasPrefix(s.algorithm, "RSA_SIGN_PSS_") {
+		return nil, fmt.Errorf("cloud KMS key %q with algorithm %s cannot make the requested RSA signature", s.keyName, s.algorithm)
+	}
+	if algorithm.pss && algorithm.saltLength != algorithm.hash.Size() {
+		return nil, fmt.Errorf("cloud KMS only supports RSA-PSS salts of the hash length")
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(digest)
+	request := &cloudkms.AsymmetricSignRequest{Digest: &cloudkms.Digest{}}
+	switch algorithm.hash {
+	case crypto.SHA256:
+		request.Digest.Sha256 = encoded
+	case crypto.SHA384:
+		request.Digest.Sha384 = encoded
+	case crypto.SHA512:
+		request.Digest.Sha512 = encoded
+	}
+
+	response, err := s.service.Projects.Locations.KeyRings.CryptoKeys.CryptoKeyVersions.AsymmetricSign(s.keyName, request).Context(context.TODO()).Do()
+	if err != nil {
+		return nil, fmt.Errorf("calling Cloud KMS asymmetricSign: %w", err)
+	}
+	return base64.StdEncoding.DecodeString(response.Signature)
+}
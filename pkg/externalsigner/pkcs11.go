@@ -0,0 +1,562 @@
+//go:build pkcs11 && cgo
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+/*
+#cgo LDFLAGS: -ldl
+#include <dlfcn.h>
+#include <stdlib.h>
+
+typedef unsigned long CK_ULONG;
+typedef CK_ULONG CK_RV;
+
+typedef struct CK_VERSION {
+	unsigned char major;
+	unsigned char minor;
+} CK_VERSION;
+
+typedef struct CK_ATTRIBUTE {
+	CK_ULONG type;
+	void *pValue;
+	CK_ULONG ulValueLen;
+} CK_ATTRIBUTE;
+
+typedef struct CK_MECHANISM {
+	CK_ULONG mechanism;
+	void *pParameter;
+	CK_ULONG ulParameterLen;
+} CK_MECHANISM;
+
+typedef struct CK_RSA_PKCS_PSS_PARAMS {
+	CK_ULONG hashAlg;
+	CK_ULONG mgf;
+	CK_ULONG sLen;
+} CK_RSA_PKCS_PSS_PARAMS;
+
+typedef struct CK_TOKEN_INFO {
+	unsigned char label[32];
+	unsigned char manufacturerID[32];
+	unsigned char model[16];
+	unsigned char serialNumber[16];
+	CK_ULONG flags;
+	CK_ULONG ulMaxSessionCount;
+	CK_ULONG ulSessionCount;
+	CK_ULONG ulMaxRwSessionCount;
+	CK_ULONG ulRwSessionCount;
+	CK_ULONG ulMaxPinLen;
+	CK_ULONG ulMinPinLen;
+	CK_ULONG ulTotalPublicMemory;
+	CK_ULONG ulFreePublicMemory;
+	CK_ULONG ulTotalPrivateMemory;
+	CK_ULONG ulFreePrivateMemory;
+	CK_VERSION hardwareVersion;
+	CK_VERSION firmwareVersion;
+	unsigned char utcTime[16];
+} CK_TOKEN_INFO;
+
+// CK_FUNCTION_LIST declares the PKCS#11 function list up to C_Sign; the functions after it are not used.
+typedef struct CK_FUNCTION_LIST {
+	CK_VERSION version;
+	CK_RV (*C_Initialize)(void *);
+	void *C_Finalize;
+	void *C_GetInfo;
+	void *C_GetFunctionList;
+	CK_RV (*C_GetSlotList)(unsigned char, CK_ULONG *, CK_ULONG *);
+	void *C_GetSlotInfo;
+	CK_RV (*C_GetTokenInfo)(CK_ULONG, CK_TOKEN_INFO *);
+	void *C_GetMechanismList;
+	void *C_GetMechanismInfo;
+	void *C_InitToken;
+	void *C_InitPIN;
+	void *C_SetPIN;
+	CK_RV (*C_OpenSession)(CK_ULONG, CK_ULONG, void *, void *, CK_ULONG *);
+	void *C_CloseSession;
+	void *C_CloseAllSessions;
+	void *C_GetSessionInfo;
+	void *C_GetOperationState;
+	void *C_SetOperationState;
+	CK_RV (*C_Login)(CK_ULONG, CK_ULONG, unsigned char *, CK_ULONG);
+	void *C_Logout;
+	void *C_CreateObject;
+	void *C_CopyObject;
+	void *C_DestroyObject;
+	void *C_GetObjectSize;
+	CK_RV (*C_GetAttributeValue)(CK_ULONG, CK_ULONG, CK_ATTRIBUTE *, CK_ULONG);
+	void *C_SetAttributeValue;
+	CK_RV (*C_FindObjectsInit)(CK_ULONG, CK_ATTRIBUTE *, CK_ULONG);
+	CK_RV (*C_FindObjects)(CK_ULONG, CK_ULONG *, CK_ULONG, CK_ULONG *);
+	CK_RV (*C_FindObjectsFinal)(CK_ULONG);
+	void *C_EncryptInit;
+	void *C_Encrypt;
+	void *C_EncryptUpdate;
+	void *C_EncryptFinal;
+	void *C_DecryptInit;
+	void *C_Decrypt;
+	void *C_DecryptUpdate;
+	void *C_DecryptFinal;
+	void *C_DigestInit;
+	void *C_Digest;
+	void *C_DigestUpdate;
+	void *C_DigestKey;
+	void *C_DigestFinal;
+	CK_RV (*C_SignInit)(CK_ULONG, CK_MECHANISM *, CK_ULONG);
+	CK_RV (*C_Sign)(CK_ULONG, unsigned char *, CK_ULONG, unsigned char *, CK_ULONG *);
+} CK_FUNCTION_LIST;
+
+static CK_RV kops_pkcs11_get_function_list(void *getFunctionList, CK_FUNCTION_LIST **functions) {
+	return ((CK_RV (*)(CK_FUNCTION_LIST **))getFunctionList)(functions);
+}
+
+static CK_RV kops_pkcs11_initialize(CK_FUNCTION_LIST *f) {
+	return f->C_Initialize(NULL);
+}
+
+static CK_RV kops_pkcs11_get_slot_list(CK_FUNCTION_LIST *f, CK_ULONG *slots, CK_ULONG *count) {
+	return f->C_GetSlotList(1, slots, count);
+}
+
+static CK_RV kops_pkcs11_get_token_info(CK_FUNCTION_LIST *f, CK_ULONG slot, CK_TOKEN_INFO *info) {
+	return f->C_GetTokenInfo(slot, info);
+}
+
+static CK_RV kops_pkcs11_open_session(CK_FUNCTION_LIST *f, CK_ULONG slot, CK_ULONG *session) {
+	// CKF_SERIAL_SESSION
+	return f->C_OpenSession(slot, 4, NULL, NULL, session);
+}
+
+static CK_RV kops_pkcs11_login(CK_FUNCTION_LIST *f, CK_ULONG session, unsigned char *pin, CK_ULONG pinLen) {
+	// CKU_USER
+	return f->C_Login(session, 1, pin, pinLen);
+}
+
+static CK_RV kops_pkcs11_find_object(CK_FUNCTION_LIST *f, CK_ULONG session, CK_ATTRIBUTE *template, CK_ULONG count, CK_ULONG *object, CK_ULONG *found) {
+	CK_RV rv = f->C_FindObjectsInit(session, template, count);
+	if (rv != 0) {
+		return rv;
+	}
+	rv = f->C_FindObjects(session, object, 1, found);
+	CK_RV finalRV = f->C_FindObjectsFinal(session);
+	return rv != 0 ? rv : finalRV;
+}
+
+static CK_RV kops_pkcs11_get_attribute_value(CK_FUNCTION_LIST *f, CK_ULONG session, CK_ULONG object, CK_ATTRIBUTE *template, CK_ULONG count) {
+	return f->C_GetAttributeValue(session, object, template, count);
+}
+
+static CK_RV kops_pkcs11_sign(CK_FUNCTION_LIST *f, CK_ULONG session, CK_MECHANISM *mechanism, CK_ULONG key, unsigned char *data, CK_ULONG dataLen, unsigned char *signature, CK_ULONG *signatureLen) {
+	CK_RV rv = f->C_SignInit(session, mechanism, key);
+	if (rv != 0) {
+		return rv;
+	}
+	return f->C_Sign(session, data, dataLen, signature, signatureLen);
+}
+*/
+import "C"
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/asn1"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+const (
+	ckrOK                         = 0x0
+	ckrUserAlreadyLoggedIn        = 0x100
+	ckrCryptokiAlreadyInitialized = 0x191
+	ckaClass                      = 0x0
+	ckaLabel                      = 0x3
+	ckaKeyType                    = 0x100
+	ckaID                         = 0x102
+	ckaModulus                    = 0x120
+	ckaPublicExponent             = 0x122
+	ckaECParams                   = 0x180
+	ckaECPoint                    = 0x181
+	ckoPublicKey                  = 0x2
+	ckoPrivateKey                 = 0x3
+	ckkRSA                        = 0x0
+	ckkEC                         = 0x3
+	ckmRSAPKCS                    = 0x1
+	ckmRSAPKCSPSS                 = 0xd
+	ckmECDSA                      = 0x1041
+	ckmSHA256                     = 0x250
+	ckmSHA384                     = 0x260
+	ckmSHA512                     = 0x270
+	ckgMGF1SHA256                 = 0x2
+	ckgMGF1SHA384                 = 0x3
+	ckgMGF1SHA512                 = 0x4
+)
+
+// pkcs11DigestInfoPrefixes are the DER prefixes of the DigestInfo that CKM_RSA_PKCS signs, by hash function
+var pkcs11DigestInfoPrefixes = map[crypto.Hash][]byte{
+	crypto.SHA256: {0x30, 0x31, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x01, 0x05, 0x00, 0x04, 0x20},
+	crypto.SHA384: {0x30, 0x41, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x02, 0x05, 0x00, 0x04, 0x30},
+	crypto.SHA512: {0x30, 0x51, 0x30, 0x0d, 0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, 0x03, 0x05, 0x00, 0x04, 0x40},
+}
+
+var pkcs11PSSParameters = map[crypto.Hash][2]C.CK_ULONG{
+	crypto.SHA256: {ckmSHA256, ckgMGF1SHA256},
+	crypto.SHA384: {ckmSHA384, ckgMGF1SHA384},
+	crypto.SHA512: {ckmSHA512, ckgMGF1SHA512},
+}
+
+// pkcs11Module is a loaded PKCS#11 module.
+// Calls are serialized, as the module is initialized without locking callbacks and sessions are not thread-safe.
+type pkcs11Module struct {
+	mutex     sync.Mutex
+	functions *C.CK_FUNCTION_LIST
+}
+
+var (
+	pkcs11ModulesMutex sync.Mutex
+	pkcs11Modules      = make(map[string]*pkcs11Module)
+)
+
+// pkcs11Signer signs with a private key in a PKCS#11 token
+type pkcs11Signer struct {
+	module  *pkcs11Module
+	session C.CK_ULONG
+	key     C.CK_ULONG
+	public  crypto.PublicKey
+}
+
+var _ crypto.Signer = &pkcs11Signer{}
+
+func pkcs11Error(function string, rv C.CK_RV) error {
+	return fmt.Errorf("PKCS#11 %s failed with CKR 0x%x", function, uint64(rv))
+}
+
+func loadPKCS11Module(path string) (*pkcs11Module, error) {
+	pkcs11ModulesMutex.Lock()
+	defer pkcs11ModulesMutex.Unlock()
+
+	if m := pkcs11Modules[path]; m != nil {
+		return m, nil
+	}
+
+	cPath := C.CString(path)
+	defer C.free(unsafe.Pointer(cPath))
+	handle := C.dlopen(cPath, C.RTLD_NOW|C.RTLD_LOCAL)
+	if handle == nil {
+		return nil, fmt.Errorf("loading PKCS#11 module %q: %s", path, C.GoString(C.dlerror()))
+	}
+	cSymbol := C.CString("C_GetFunctionList")
+	defer C.free(unsafe.Pointer(cSymbol))
+	getFunctionList := C.dlsym(handle, cSymbol)
+	if getFunctionList == nil {
+		return nil, fmt.Errorf("loading PKCS#11 module %q: %s", path, C.GoString(C.dlerror()))
+	}
+
+	var functions *C.CK_FUNCTION_LIST
+	if rv := C.kops_pkcs11_get_function_list(getFunctionList, &functions); rv != ckrOK {
+		return nil, pkcs11Error("C_GetFunctionList", rv)
+	}
+	if rv := C.kops_pkcs11_initialize(functions); rv != ckrOK && rv != ckrCryptokiAlreadyInitialized {
+		return nil, pkcs11Error("C_Initialize", rv)
+	}
+
+	m := &pkcs11Module{functions: functions}
+	pkcs11Modules[path] = m
+	return m, nil
+}
+
+func newPKCS11Signer(ctx context.Context, keyURI string) (crypto.Signer, error) {
+	u, err := parsePKCS11URI(keyURI)
+	if err != nil {
+		return nil, err
+	}
+	m, err := loadPKCS11Module(u.modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	slot, err := m.findSlot(u)
+	if err != nil {
+		return nil, err
+	}
+	var session C.CK_ULONG
+	if rv := C.kops_pkcs11_open_session(m.functions, slot, &session); rv != ckrOK {
+		return nil, pkcs11Error("C_OpenSession", rv)
+	}
+	if u.pin != "" {
+		pin := []byte(u.pin)
+		if rv := C.kops_pkcs11_login(m.functions, session, (*C.uchar)(unsafe.Pointer(&pin[0])), C.CK_ULONG(len(pin))); rv != ckrOK && rv != ckrUserAlreadyLoggedIn {
+			return nil, pkcs11Error("C_Login", rv)
+		}
+	}
+
+	key, err := m.findObject(session, ckoPrivateKey, u)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := m.findObject(session, ckoPublicKey, u)
+	if err != nil {
+		return nil, err
+	}
+	public, err := m.readPublicKey(session, publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pkcs11Signer{
+		module:  m,
+		session: session,
+		key:     key,
+		public:  public,
+	}, nil
+}
+
+func (m *pkcs11Module) findSlot(u *pkcs11URI) (C.CK_ULONG, error) {
+	var count C.CK_ULONG
+	if rv := C.kops_pkcs11_get_slot_list(m.functions, nil, &count); rv != ckrOK {
+		return 0, pkcs11Error("C_GetSlotList", rv)
+	}
+	if count == 0 {
+		return 0, fmt.Errorf("no PKCS#11 tokens found")
+	}
+	slots := make([]C.CK_ULONG, count)
+	if rv := C.kops_pkcs11_get_slot_list(m.functions, &slots[0], &count); rv != ckrOK {
+		return 0, pkcs11Error("C_GetSlotList", rv)
+	}
+
+	for _, slot := range slots[:count] {
+		if u.slotID != nil && uint(slot) != *u.slotID {
+			continue
+		}
+		if u.token != "" {
+			var info C.CK_TOKEN_INFO
+			if rv := C.kops_pkcs11_get_token_info(m.functions, slot, &info); rv != ckrOK {
+				return 0, pkcs11Error("C_GetTokenInfo", rv)
+			}
+			label := C.GoBytes(unsafe.Pointer(&info.label[0]), C.int(len(info.label)))
+			if strings.TrimRight(string(label), " ") != u.token {
+				continue
+			}
+		}
+		return slot, nil
+	}
+	return 0, fmt.Errorf("PKCS#11 token %q not found", u.token)
+}
+
+func (m *pkcs11Module) findObject(session C.CK_ULONG, class C.CK_ULONG, u *pkcs11URI) (C.CK_ULONG, error) {
+	attributes := map[C.CK_ULONG][]byte{
+		ckaClass: ckULongBytes(class),
+	}
+	if u.object != "" {
+		attributes[ckaLabel] = []byte(u.object)
+	}
+	if u.id != nil {
+		attributes[ckaID] = u.id
+	}
+	template, count, free := newPKCS11Template(attributes)
+	defer free()
+
+	var object, found C.CK_ULONG
+	if rv := C.kops_pkcs11_find_object(m.functions, session, template, count, &object, &found); rv != ckrOK {
+		return 0, pkcs11Error("C_FindObjects", rv)
+	}
+	if found == 0 {
+		return 0, fmt.Errorf("PKCS#11 key (class %d) with label %q not found", uint64(class), u.object)
+	}
+	return object, nil
+}
+
+// getAttributes reads attributes of an object, first querying their lengths
+func (m *pkcs11Module) getAttributes(session C.CK_ULONG, object C.CK_ULONG, types ...C.CK_ULONG) ([][]byte, error) {
+	count := C.CK_ULONG(len(types))
+	template := unsafe.Slice((*C.CK_ATTRIBUTE)(C.calloc(C.size_t(len(types)), C.sizeof_CK_ATTRIBUTE)), len(types))
+	defer func() {
+		for i := range template {
+			C.free(template[i].pValue)
+		}
+		C.free(unsafe.Pointer(&template[0]))
+	}()
+
+	for i, t := range types {
+		template[i]._type = t
+	}
+	if rv := C.kops_pkcs11_get_attribute_value(m.functions, session, object, &template[0], count); rv != ckrOK {
+		return nil, pkcs11Error("C_GetAttributeValue", rv)
+	}
+	for i := range template {
+		template[i].pValue = C.malloc(C.size_t(template[i].ulValueLen))
+	}
+	if rv := C.kops_pkcs11_get_attribute_value(m.functions, session, object, &template[0], count); rv != ckrOK {
+		return nil, pkcs11Error("C_GetAttributeValue", rv)
+	}
+
+	values := make([][]byte, len(types))
+	for i := range template {
+		values[i] = C.GoBytes(template[i].pValue, C.int(template[i].ulValueLen))
+	}
+	return values, nil
+}
+
+func (m *pkcs11Module) readPublicKey(session C.CK_ULONG, object C.CK_ULONG) (crypto.PublicKey, error) {
+	values, err := m.getAttributes(session, object, ckaKeyType)
+	if err != nil {
+		return nil, err
+	}
+	var keyType C.CK_ULONG
+	if len(values[0]) != int(unsafe.Sizeof(keyType)) {
+		return nil, fmt.Errorf("invalid PKCS#11 key type")
+	}
+	keyType = *(*C.CK_ULONG)(unsafe.Pointer(&values[0][0]))
+
+	switch keyType {
+	case ckkRSA:
+		values, err := m.getAttributes(session, object, ckaModulus, ckaPublicExponent)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(values[1])
+		if !exponent.IsInt64() {
+			return nil, fmt.Errorf("invalid RSA public exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(values[0]), E: int(exponent.Int64())}, nil
+
+	case ckkEC:
+		values, err := m.getAttributes(session, object, ckaECParams, ckaECPoint)
+		if err != nil {
+			return nil, err
+		}
+		var oid asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(values[0], &oid); err != nil {
+			return nil, fmt.Errorf("parsing EC parameters: %w", err)
+		}
+		var curve elliptic.Curve
+		switch oid.String() {
+		case "1.2.840.10045.3.1.7":
+			curve = elliptic.P256()
+		case "1.3.132.0.34":
+			curve = elliptic.P384()
+		case "1.3.132.0.35":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported EC curve %s", oid)
+		}
+		// CKA_EC_POINT is a DER-encoded OCTET STRING, though some modules return the raw point
+		point := values[1]
+		var raw []byte
+		if rest, err := asn1.Unmarshal(point, &raw); err == nil && len(rest) == 0 {
+			point = raw
+		}
+		return ecdsa.ParseUncompressedPublicKey(curve, point)
+
+	default:
+		return nil, fmt.Errorf("unsupported PKCS#11 key type %d", uint64(keyType))
+	}
+}
+
+// Public implements crypto.Signer
+func (s *pkcs11Signer) Public() crypto.PublicKey {
+	return s.public
+}
+
+// Sign implements crypto.Signer
+func (s *pkcs11Signer) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	algorithm, err := signatureAlgorithmFor(s.public, digest, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	mechanism := (*C.CK_MECHANISM)(C.calloc(1, C.sizeof_CK_MECHANISM))
+	defer C.free(unsafe.Pointer(mechanism))
+
+	data := digest
+	var signatureLength int
+	switch public := s.public.(type) {
+	case *ecdsa.PublicKey:
+		mechanism.mechanism = ckmECDSA
+		signatureLength = 2 * ((public.Curve.Params().BitSize + 7) / 8)
+	case *rsa.PublicKey:
+		signatureLength = public.Size()
+		if algorithm.pss {
+			params := (*C.CK_RSA_PKCS_PSS_PARAMS)(C.calloc(1, C.sizeof_CK_RSA_PKCS_PSS_PARAMS))
+			defer C.free(unsafe.Pointer(params))
+			params.hashAlg = pkcs11PSSParameters[algorithm.hash][0]
+			params.mgf = pkcs11PSSParameters[algorithm.hash][1]
+			params.sLen = C.CK_ULONG(algorithm.saltLength)
+			mechanism.mechanism = ckmRSAPKCSPSS
+			mechanism.pParameter = unsafe.Pointer(params)
+			mechanism.ulParameterLen = C.sizeof_CK_RSA_PKCS_PSS_PARAMS
+		} else {
+			mechanism.mechanism = ckmRSAPKCS
+			data = append(append([]byte{}, pkcs11DigestInfoPrefixes[algorithm.hash]...), digest...)
+		}
+	}
+
+	signature := make([]byte, signatureLength)
+	length := C.CK_ULONG(len(signature))
+
+	s.module.mutex.Lock()
+	rv := C.kops_pkcs11_sign(s.module.functions, s.session, mechanism, s.key,
+		(*C.uchar)(unsafe.Pointer(&data[0])), C.CK_ULONG(len(data)),
+		(*C.uchar)(unsafe.Pointer(&signature[0])), &length)
+	s.module.mutex.Unlock()
+	if rv != ckrOK {
+		return nil, pkcs11Error("C_Sign", rv)
+	}
+	signature = signature[:length]
+
+	if algorithm.ecdsa {
+		// PKCS#11 returns r || s, crypto.Signer returns the ASN.1 encoding
+		half := len(signature) / 2
+		return asn1.Marshal(struct {
+			R, S *big.Int
+		}{
+			R: new(big.Int).SetBytes(signature[:half]),
+			S: new(big.Int).SetBytes(signature[half:]),
+		})
+	}
+	return signature, nil
+}
+
+// newPKCS11Template returns a template of attributes in C memory, and the function to free it
+func newPKCS11Template(attributes map[C.CK_ULONG][]byte) (*C.CK_ATTRIBUTE, C.CK_ULONG, func()) {
+	template := unsafe.Slice((*C.CK_ATTRIBUTE)(C.calloc(C.size_t(len(attributes)), C.sizeof_CK_ATTRIBUTE)), len(attributes))
+	i := 0
+	for t, value := range attributes {
+		template[i]._type = t
+		template[i].pValue = C.CBytes(value)
+		template[i].ulValueLen = C.CK_ULONG(len(value))
+		i++
+	}
+	return &template[0], C.CK_ULONG(len(template)), func() {
+		for i := range template {
+			C.free(template[i].pValue)
+		}
+		C.free(unsafe.Pointer(&template[0]))
+	}
+}
+
+func ckULongBytes(v C.CK_ULONG) []byte {
+	return C.GoBytes(unsafe.Pointer(&v), C.int(unsafe.Sizeof(v)))
+}
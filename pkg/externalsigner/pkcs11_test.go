@@ -0,0 +1,143 @@
+//go:build pkcs11 && cgo
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/pki"
+)
+
+// softHSMModule returns the path to the SoftHSM PKCS#11 module, skipping the test if SoftHSM is not installed.
+func softHSMModule(t *testing.T) string {
+	if _, err := exec.LookPath("softhsm2-util"); err != nil {
+		t.Skip("softhsm2-util not found; install SoftHSM to run the PKCS#11 tests")
+	}
+	if modulePath := os.Getenv("SOFTHSM2_MODULE"); modulePath != "" {
+		return modulePath
+	}
+	for _, modulePath := range []string{
+		"/usr/lib/softhsm/libsofthsm2.so",
+		"/usr/lib/x86_64-linux-gnu/softhsm/libsofthsm2.so",
+		"/usr/lib/aarch64-linux-gnu/softhsm/libsofthsm2.so",
+		"/usr/local/lib/softhsm/libsofthsm2.so",
+		"/opt/homebrew/lib/softhsm/libsofthsm2.so",
+	} {
+		if _, err := os.Stat(modulePath); err == nil {
+			return modulePath
+		}
+	}
+	t.Skip("SoftHSM module not found; set SOFTHSM2_MODULE to the path of libsofthsm2.so")
+	return ""
+}
+
+func runSoftHSMUtil(t *testing.T, args ...string) {
+	output, err := exec.Command("softhsm2-util", args...).CombinedOutput()
+	require.NoError(t, err, "softhsm2-util %v: %s", args, output)
+}
+
+// TestPKCS11SignerSoftHSM imports keys into a SoftHSM token and signs a certificate with each of them.
+func TestPKCS11SignerSoftHSM(t *testing.T) {
+	modulePath := softHSMModule(t)
+
+	dir := t.TempDir()
+	tokenDir := filepath.Join(dir, "tokens")
+	require.NoError(t, os.Mkdir(tokenDir, 0o700))
+	conf := filepath.Join(dir, "softhsm2.conf")
+	require.NoError(t, os.WriteFile(conf, []byte("directories.tokendir = "+tokenDir+"\nobjectstore.backend = file\n"), 0o600))
+	t.Setenv("SOFTHSM2_CONF", conf)
+
+	pinFile := filepath.Join(dir, "pin")
+	require.NoError(t, os.WriteFile(pinFile, []byte("1234\n"), 0o600))
+
+	runSoftHSMUtil(t, "--init-token", "--free", "--label", "kops", "--pin", "1234", "--so-pin", "5678")
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	grid := []struct {
+		name string
+		key  crypto.Signer
+	}{
+		{name: "kubernetes-ca", key: rsaKey},
+		{name: "service-account", key: ecdsaKey},
+	}
+	for i, g := range grid {
+		der, err := x509.MarshalPKCS8PrivateKey(g.key)
+		require.NoError(t, err)
+		keyFile := filepath.Join(dir, g.name+".pem")
+		require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600))
+		runSoftHSMUtil(t, "--import", keyFile, "--token", "kops", "--label", g.name, "--id", fmt.Sprintf("%02x", i+1), "--pin", "1234")
+	}
+
+	Register()
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			keyURI := fmt.Sprintf("pkcs11:token=kops;object=%s?module-path=%s&pin-source=file:%s", g.name, modulePath, pinFile)
+
+			privateKey, err := pki.NewExternalPrivateKey(context.Background(), keyURI)
+			require.NoError(t, err)
+			assert.Equal(t, keyURI, privateKey.ExternalKeyURI())
+			assert.True(t, g.key.Public().(interface{ Equal(crypto.PublicKey) bool }).Equal(privateKey.Key.Public()))
+
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{CommonName: g.name},
+				NotBefore:             time.Now(),
+				NotAfter:              time.Now().Add(time.Hour),
+				IsCA:                  true,
+				BasicConstraintsValid: true,
+				KeyUsage:              x509.KeyUsageCertSign,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, privateKey.Key.Public(), privateKey.Key)
+			require.NoError(t, err)
+			cert, err := x509.ParseCertificate(der)
+			require.NoError(t, err)
+			require.NoError(t, cert.CheckSignatureFrom(cert))
+
+			if _, ok := g.key.(*rsa.PrivateKey); ok {
+				digest := crypto.SHA256.New()
+				digest.Write([]byte("kops"))
+				opts := &rsa.PSSOptions{Hash: crypto.SHA256, SaltLength: rsa.PSSSaltLengthEqualsHash}
+				signature, err := privateKey.Key.Sign(rand.Reader, digest.Sum(nil), opts)
+				require.NoError(t, err)
+				require.NoError(t, rsa.VerifyPSS(&rsaKey.PublicKey, crypto.SHA256, digest.Sum(nil), signature, opts))
+			}
+		})
+	}
+}
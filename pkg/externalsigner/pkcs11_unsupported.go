@@ -0,0 +1,32 @@
+//go:build !pkcs11 || !cgo
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+import (
+	"context"
+	"crypto"
+	"fmt"
+)
+
+func newPKCS11Signer(ctx context.Context, keyURI string) (crypto.Signer, error) {
+	if _, err := parsePKCS11URI(keyURI); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("PKCS#11 keys are not supported by this build; build with cgo and the pkcs11 build tag")
+}
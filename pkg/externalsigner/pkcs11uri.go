@@ -0,0 +1,133 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package externalsigner
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// pkcs11URI identifies a private key in a PKCS#11 token, as described in RFC 7512
+type pkcs11URI struct {
+	// modulePath is the path to the PKCS#11 module (shared library) of the token
+	modulePath string
+	// token is the label of the token
+	token string
+	// slotID is the ID of the slot of the token
+	slotID *uint
+	// object is the label of the key
+	object string
+	// id is the CKA_ID of the key
+	id []byte
+	// pin is the user PIN of the token
+	pin string
+}
+
+// parsePKCS11URI parses a URI such as pkcs11:token=kops;object=kubernetes-ca?module-path=/usr/lib/softhsm/libsofthsm2.so&pin-source=file:/etc/kops/pin
+func parsePKCS11URI(keyURI string) (*pkcs11URI, error) {
+	opaque, found := strings.CutPrefix(keyURI, "pkcs11:")
+	if !found {
+		return nil, fmt.Errorf("PKCS#11 key URI must start with pkcs11:")
+	}
+	path, query, _ := strings.Cut(opaque, "?")
+
+	u := &pkcs11URI{}
+	for _, attribute := range strings.Split(path, ";") {
+		if attribute == "" {
+			continue
+		}
+		name, value, err := parsePKCS11Attribute(attribute)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "token":
+			u.token = value
+		case "object":
+			u.object = value
+		case "id":
+			u.id = []byte(value)
+		case "slot-id":
+			slotID, err := strconv.ParseUint(value, 10, 0)
+			if err != nil {
+				return nil, fmt.Errorf("invalid slot-id %q in PKCS#11 URI", value)
+			}
+			u.slotID = new(uint(slotID))
+		case "type":
+			if value != "private" {
+				return nil, fmt.Errorf("PKCS#11 URI must identify a private key, found type=%s", value)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported attribute %q in PKCS#11 URI", name)
+		}
+	}
+
+	for _, attribute := range strings.Split(query, "&") {
+		if attribute == "" {
+			continue
+		}
+		name, value, err := parsePKCS11Attribute(attribute)
+		if err != nil {
+			return nil, err
+		}
+		switch name {
+		case "module-path":
+			u.modulePath = value
+		case "pin-value":
+			u.pin = value
+		case "pin-source":
+			file, found := strings.CutPrefix(value, "file:")
+			if !found {
+				return nil, fmt.Errorf("unsupported pin-source %q in PKCS#11 URI; expected file:", value)
+			}
+			b, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("reading PKCS#11 PIN: %w", err)
+			}
+			u.pin = string(bytes.TrimSpace(b))
+		default:
+			return nil, fmt.Errorf("unsupported query attribute %q in PKCS#11 URI", name)
+		}
+	}
+
+	if u.modulePath == "" {
+		return nil, fmt.Errorf("PKCS#11 URI must specify module-path")
+	}
+	if u.token == "" && u.slotID == nil {
+		return nil, fmt.Errorf("PKCS#11 URI must specify token or slot-id")
+	}
+	if u.object == "" && u.id == nil {
+		return nil, fmt.Errorf("PKCS#11 URI must specify object or id")
+	}
+	return u, nil
+}
+
+func parsePKCS11Attribute(attribute string) (string, string, error) {
+	name, value, found := strings.Cut(attribute, "=")
+	if !found {
+		return "", "", fmt.Errorf("invalid attribute %q in PKCS#11 URI", attribute)
+	}
+	unescaped, err := url.PathUnescape(value)
+	if err != nil {
+		return "", "", fmt.Errorf("invalid attribute %q in PKCS#11 URI: %w", attribute, err)
+	}
+	return name, unescaped, nil
+}
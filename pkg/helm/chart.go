@@ -0,0 +1,227 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package helm renders Helm charts into manifests in-process, so that charts can be installed as addons
+// without the helm binary or a release history in the cluster.
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Chart is a Helm chart loaded into memory
+type Chart struct {
+	// Metadata is the contents of Chart.yaml
+	Metadata *Metadata
+	// Values are the default values of the chart, from values.yaml
+	Values map[string]interface{}
+	// Templates are the files in the templates directory
+	Templates []*File
+	// CRDs are the files in the crds directory; they are not rendered, and are installed before the templates
+	CRDs []*File
+	// Files are the other files in the chart, which templates can read with .Files
+	Files []*File
+	// Dependencies are the subcharts in the charts directory
+	Dependencies []*Chart
+}
+
+// File is a file in a chart
+type File struct {
+	// Name is the path of the file, relative to the chart directory
+	Name string
+	Data []byte
+}
+
+// Metadata is the contents of Chart.yaml.
+// Templates see it as .Chart, so field names match Helm.
+type Metadata struct {
+	APIVersion   string        `json:"apiVersion,omitempty"`
+	Name         string        `json:"name,omitempty"`
+	Version      string        `json:"version,omitempty"`
+	AppVersion   string        `json:"appVersion,omitempty"`
+	Description  string        `json:"description,omitempty"`
+	Type         string        `json:"type,omitempty"`
+	KubeVersion  string        `json:"kubeVersion,omitempty"`
+	Dependencies []*Dependency `json:"dependencies,omitempty"`
+}
+
+// Dependency is a subchart declared in Chart.yaml
+type Dependency struct {
+	Name       string `json:"name,omitempty"`
+	Version    string `json:"version,omitempty"`
+	Repository string `json:"repository,omitempty"`
+	// Condition is a comma-separated list of value paths; the first that is set enables or disables the subchart
+	Condition string `json:"condition,omitempty"`
+	// Alias is the name used for the values of the subchart, instead of its name
+	Alias string `json:"alias,omitempty"`
+}
+
+// LoadDir loads a chart from a directory on the local filesystem.
+func LoadDir(dir string) (*Chart, error) {
+	files := make(map[string][]byte)
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if d.IsDir() {
+			if rel != "." && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		files[rel] = data
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading chart directory %q: %w", dir, err)
+	}
+	return loadFiles(files)
+}
+
+// LoadArchive loads a chart from a gzipped tar archive, as created by `helm package`.
+func LoadArchive(data []byte) (*Chart, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading chart archive: %w", err)
+	}
+	defer gz.Close()
+
+	files := make(map[string][]byte)
+	r := tar.NewReader(gz)
+	for {
+		header, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading chart archive: %w", err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// Files in the archive are in a directory named after the chart
+		name := path.Clean(header.Name)
+		_, rel, found := strings.Cut(name, "/")
+		if !found || strings.HasPrefix(rel, "../") {
+			return nil, fmt.Errorf("unexpected file %q in chart archive", header.Name)
+		}
+
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading %q from chart archive: %w", header.Name, err)
+		}
+		files[rel] = b
+	}
+	return loadFiles(files)
+}
+
+// loadFiles builds a chart from its files, keyed by their path relative to the chart directory.
+func loadFiles(files map[string][]byte) (*Chart, error) {
+	chartYAML, found := files["Chart.yaml"]
+	if !found {
+		return nil, fmt.Errorf("chart has no Chart.yaml")
+	}
+	chart := &Chart{Metadata: &Metadata{}}
+	if err := yaml.Unmarshal(chartYAML, chart.Metadata); err != nil {
+		return nil, fmt.Errorf("parsing Chart.yaml: %w", err)
+	}
+	if chart.Metadata.Name == "" {
+		return nil, fmt.Errorf("chart has no name in Chart.yaml")
+	}
+
+	chart.Values = make(map[string]interface{})
+	if valuesYAML, found := files["values.yaml"]; found {
+		if err := yaml.Unmarshal(valuesYAML, &chart.Values); err != nil {
+			return nil, fmt.Errorf("parsing values.yaml of chart %q: %w", chart.Metadata.Name, err)
+		}
+		if chart.Values == nil {
+			chart.Values = make(map[string]interface{})
+		}
+	}
+
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	subcharts := make(map[string]map[string][]byte)
+	var subchartNames []string
+	for _, name := range names {
+		data := files[name]
+		switch {
+		case name == "Chart.yaml" || name == "values.yaml" || name == "Chart.lock" || name == "values.schema.json":
+		case strings.HasPrefix(name, "templates/"):
+			chart.Templates = append(chart.Templates, &File{Name: name, Data: data})
+		case strings.HasPrefix(name, "crds/"):
+			chart.CRDs = append(chart.CRDs, &File{Name: name, Data: data})
+		case strings.HasPrefix(name, "charts/"):
+			rel := strings.TrimPrefix(name, "charts/")
+			if !strings.Contains(rel, "/") {
+				if !strings.HasSuffix(rel, ".tgz") {
+					continue
+				}
+				subchart, err := LoadArchive(data)
+				if err != nil {
+					return nil, fmt.Errorf("loading subchart %q of chart %q: %w", rel, chart.Metadata.Name, err)
+				}
+				chart.Dependencies = append(chart.Dependencies, subchart)
+				continue
+			}
+			dir, subpath, _ := strings.Cut(rel, "/")
+			if subcharts[dir] == nil {
+				subcharts[dir] = make(map[string][]byte)
+				subchartNames = append(subchartNames, dir)
+			}
+			subcharts[dir][subpath] = data
+		default:
+			chart.Files = append(chart.Files, &File{Name: name, Data: data})
+		}
+	}
+
+	for _, dir := range subchartNames {
+		subchart, err := loadFiles(subcharts[dir])
+		if err != nil {
+			return nil, fmt.Errorf("loading subchart %q of chart %q: %w", dir, chart.Metadata.Name, err)
+		}
+		chart.Dependencies = append(chart.Dependencies, subchart)
+	}
+
+	return chart, nil
+}
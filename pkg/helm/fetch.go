@@ -0,0 +1,199 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"k8s.io/kops/util/pkg/vfs"
+	"sigs.k8s.io/yaml"
+)
+
+// chartLayerMediaType is the media type of the layer holding the chart archive in an OCI artifact
+const chartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+
+// ChartSource identifies a version of a chart in a repository
+type ChartSource struct {
+	// Repository is the location of the chart repository. It can be:
+	//   an OCI registry, such as oci://registry.example.com/charts
+	//   an HTTP(S) chart repository serving index.yaml
+	//   a VFS path, such as s3://bucket/charts, holding <name>-<version>.tgz archives
+	//   a local directory holding the chart in <name>/
+	Repository string
+	// Name is the name of the chart
+	Name string
+	// Version is the exact version of the chart
+	Version string
+}
+
+func (s ChartSource) String() string {
+	return fmt.Sprintf("%s/%s:%s", strings.TrimSuffix(s.Repository, "/"), s.Name, s.Version)
+}
+
+// ArchiveName is the file name of the chart archive, as Helm names it
+func (s ChartSource) ArchiveName() string {
+	return s.Name + "-" + s.Version + ".tgz"
+}
+
+// LocalDir returns the directory of the chart if the repository is a local directory holding it
+func (s ChartSource) LocalDir() (string, bool) {
+	dir := s.Repository
+	if strings.HasPrefix(dir, "file://") {
+		dir = strings.TrimPrefix(dir, "file://")
+	} else if strings.Contains(dir, "://") {
+		return "", false
+	}
+	dir = filepath.Join(dir, s.Name)
+	if _, err := os.Stat(filepath.Join(dir, "Chart.yaml")); err != nil {
+		return "", false
+	}
+	return dir, true
+}
+
+// Load loads the chart from a local directory, or fetches its archive from the repository.
+func Load(ctx context.Context, vfsContext *vfs.VFSContext, source ChartSource) (*Chart, error) {
+	if dir, ok := source.LocalDir(); ok {
+		return LoadDir(dir)
+	}
+	data, err := FetchArchive(ctx, vfsContext, source)
+	if err != nil {
+		return nil, err
+	}
+	chart, err := LoadArchive(data)
+	if err != nil {
+		return nil, fmt.Errorf("loading chart %s: %w", source, err)
+	}
+	return chart, nil
+}
+
+// FetchArchive fetches the archive of the chart from the repository.
+func FetchArchive(ctx context.Context, vfsContext *vfs.VFSContext, source ChartSource) ([]byte, error) {
+	if source.Repository == "" || source.Name == "" || source.Version == "" {
+		return nil, fmt.Errorf("chart repository, name and version must be specified")
+	}
+
+	repository := strings.TrimSuffix(source.Repository, "/")
+	switch {
+	case strings.HasPrefix(repository, "oci://"):
+		return fetchOCIArchive(ctx, source)
+	case strings.HasPrefix(repository, "http://"), strings.HasPrefix(repository, "https://"):
+		return fetchIndexedArchive(vfsContext, source)
+	default:
+		location := repository + "/" + source.ArchiveName()
+		data, err := vfsContext.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("reading chart %s from %q: %w", source, location, err)
+		}
+		return data, nil
+	}
+}
+
+// repositoryIndex is the subset of a chart repository index.yaml that we use
+type repositoryIndex struct {
+	Entries map[string][]struct {
+		Version string   `json:"version"`
+		URLs    []string `json:"urls"`
+	} `json:"entries"`
+}
+
+func fetchIndexedArchive(vfsContext *vfs.VFSContext, source ChartSource) ([]byte, error) {
+	repository := strings.TrimSuffix(source.Repository, "/")
+	indexLocation := repository + "/index.yaml"
+	b, err := vfsContext.ReadFile(indexLocation)
+	if err != nil {
+		return nil, fmt.Errorf("reading chart repository index %q: %w", indexLocation, err)
+	}
+	index := &repositoryIndex{}
+	if err := yaml.Unmarshal(b, index); err != nil {
+		return nil, fmt.Errorf("parsing chart repository index %q: %w", indexLocation, err)
+	}
+
+	var chartURL string
+	for _, entry := range index.Entries[source.Name] {
+		if entry.Version == source.Version && len(entry.URLs) != 0 {
+			chartURL = entry.URLs[0]
+			break
+		}
+	}
+	if chartURL == "" {
+		return nil, fmt.Errorf("chart %s not found in repository index %q", source, indexLocation)
+	}
+
+	base, err := url.Parse(repository + "/")
+	if err != nil {
+		return nil, fmt.Errorf("parsing repository URL %q: %w", repository, err)
+	}
+	ref, err := url.Parse(chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing chart URL %q: %w", chartURL, err)
+	}
+	location := base.ResolveReference(ref).String()
+
+	data, err := vfsContext.ReadFile(location)
+	if err != nil {
+		return nil, fmt.Errorf("reading chart %s from %q: %w", source, location, err)
+	}
+	return data, nil
+}
+
+func fetchOCIArchive(ctx context.Context, source ChartSource) ([]byte, error) {
+	repository := strings.TrimSuffix(strings.TrimPrefix(source.Repository, "oci://"), "/")
+	// OCI tags cannot contain "+", so Helm replaces it with "_"
+	tag := strings.ReplaceAll(source.Version, "+", "_")
+	ref, err := name.ParseReference(repository + "/" + source.Name + ":" + tag)
+	if err != nil {
+		return nil, fmt.Errorf("parsing chart reference for %s: %w", source, err)
+	}
+
+	image, err := remote.Image(ref, remote.WithContext(ctx), remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, fmt.Errorf("fetching chart %s: %w", source, err)
+	}
+	layers, err := image.Layers()
+	if err != nil {
+		return nil, fmt.Errorf("reading layers of chart %s: %w", source, err)
+	}
+	for _, layer := range layers {
+		mediaType, err := layer.MediaType()
+		if err != nil {
+			return nil, fmt.Errorf("reading layers of chart %s: %w", source, err)
+		}
+		if string(mediaType) != chartLayerMediaType {
+			continue
+		}
+		r, err := layer.Compressed()
+		if err != nil {
+			return nil, fmt.Errorf("reading chart %s: %w", source, err)
+		}
+		defer r.Close()
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return nil, fmt.Errorf("reading chart %s: %w", source, err)
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%s does not contain a chart layer of type %q", ref, chartLayerMediaType)
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// buildArchive packages a chart directory as `helm package` would.
+func buildArchive(t *testing.T, dir string, chartName string) []byte {
+	var b bytes.Buffer
+	gz := gzip.NewWriter(&b)
+	w := tar.NewWriter(gz)
+	err := filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		header := &tar.Header{
+			Name:     chartName + "/" + filepath.ToSlash(rel),
+			Mode:     0644,
+			Size:     int64(len(data)),
+			Typeflag: tar.TypeReg,
+		}
+		if err := w.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	})
+	if err != nil {
+		t.Fatalf("building chart archive: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("building chart archive: %v", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("building chart archive: %v", err)
+	}
+	return b.Bytes()
+}
+
+func renderForTest(t *testing.T, chart *Chart) string {
+	b, err := Render(chart, RenderOptions{ReleaseName: "test"})
+	if err != nil {
+		t.Fatalf("rendering chart: %v", err)
+	}
+	return string(b)
+}
+
+func TestLoadArchive(t *testing.T) {
+	dirChart, err := LoadDir(filepath.Join("testdata", "example"))
+	if err != nil {
+		t.Fatalf("loading chart: %v", err)
+	}
+	archiveChart, err := LoadArchive(buildArchive(t, filepath.Join("testdata", "example"), "example"))
+	if err != nil {
+		t.Fatalf("loading chart archive: %v", err)
+	}
+
+	if renderForTest(t, dirChart) != renderForTest(t, archiveChart) {
+		t.Errorf("chart loaded from archive rendered differently from chart loaded from directory")
+	}
+}
+
+func TestLoadLocalDir(t *testing.T) {
+	source := ChartSource{Repository: "testdata", Name: "example", Version: "1.2.3"}
+	if _, ok := source.LocalDir(); !ok {
+		t.Fatalf("expected %v to be a local chart directory", source)
+	}
+	chart, err := Load(context.Background(), vfs.NewVFSContext(), source)
+	if err != nil {
+		t.Fatalf("loading chart: %v", err)
+	}
+	if chart.Metadata.Version != "1.2.3" {
+		t.Errorf("unexpected chart version %q", chart.Metadata.Version)
+	}
+}
+
+func TestFetchArchiveFromVFS(t *testing.T) {
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+
+	archive := buildArchive(t, filepath.Join("testdata", "example"), "example")
+	p, err := vfsContext.BuildVfsPath("memfs://charts/example-1.2.3.tgz")
+	if err != nil {
+		t.Fatalf("building path: %v", err)
+	}
+	if err := p.WriteFile(context.Background(), bytes.NewReader(archive), nil); err != nil {
+		t.Fatalf("writing archive: %v", err)
+	}
+
+	source := ChartSource{Repository: "memfs://charts", Name: "example", Version: "1.2.3"}
+	if _, ok := source.LocalDir(); ok {
+		t.Errorf("expected %v not to be a local chart directory", source)
+	}
+	chart, err := Load(context.Background(), vfsContext, source)
+	if err != nil {
+		t.Fatalf("loading chart: %v", err)
+	}
+	if chart.Metadata.Name != "example" {
+		t.Errorf("unexpected chart name %q", chart.Metadata.Name)
+	}
+
+	source.Version = "9.9.9"
+	if _, err := FetchArchive(context.Background(), vfsContext, source); err == nil {
+		t.Errorf("expected error fetching missing chart version")
+	}
+}
+
+func TestFetchArchiveFromRepository(t *testing.T) {
+	archive := buildArchive(t, filepath.Join("testdata", "example"), "example")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/charts/index.yaml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`apiVersion: v1
+entries:
+  example:
+  - name: example
+    version: 1.2.4
+    urls:
+    - https://invalid.example.com/example-1.2.4.tgz
+  - name: example
+    version: 1.2.3
+    urls:
+    - packages/example-1.2.3.tgz
+`))
+	})
+	mux.HandleFunc("/charts/packages/example-1.2.3.tgz", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(archive)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	vfsContext := vfs.NewVFSContext()
+	data, err := FetchArchive(context.Background(), vfsContext, ChartSource{Repository: server.URL + "/charts/", Name: "example", Version: "1.2.3"})
+	if err != nil {
+		t.Fatalf("fetching chart: %v", err)
+	}
+	if !bytes.Equal(data, archive) {
+		t.Errorf("fetched archive did not match")
+	}
+
+	if _, err := FetchArchive(context.Background(), vfsContext, ChartSource{Repository: server.URL + "/charts", Name: "missing", Version: "1.2.3"}); err == nil {
+		t.Errorf("expected error fetching chart missing from index")
+	}
+}
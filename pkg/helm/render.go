@@ -0,0 +1,572 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/blang/semver/v4"
+	"k8s.io/kops/pkg/kubemanifest"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultKubeVersion is the Kubernetes version reported to templates if none is specified
+const DefaultKubeVersion = "v1.34.0"
+
+// RenderOptions configures how a chart is rendered
+type RenderOptions struct {
+	// ReleaseName is the name of the release, seen by templates as .Release.Name
+	ReleaseName string
+	// Namespace is the namespace of the release; namespaced objects without a namespace are placed in it
+	Namespace string
+	// Values override the default values of the chart
+	Values map[string]interface{}
+	// KubeVersion is the Kubernetes version seen by templates as .Capabilities.KubeVersion
+	KubeVersion string
+	// APIVersions are API versions seen by templates in .Capabilities.APIVersions, in addition to the built-in ones
+	APIVersions []string
+	// CreateNamespace adds the namespace of the release to the manifest, if the chart does not create it
+	CreateNamespace bool
+}
+
+// builtinAPIVersions are the API versions served by all supported versions of Kubernetes
+var builtinAPIVersions = []string{
+	"v1",
+	"admissionregistration.k8s.io/v1",
+	"apiextensions.k8s.io/v1",
+	"apiregistration.k8s.io/v1",
+	"apps/v1",
+	"authentication.k8s.io/v1",
+	"authorization.k8s.io/v1",
+	"autoscaling/v1",
+	"autoscaling/v2",
+	"batch/v1",
+	"certificates.k8s.io/v1",
+	"coordination.k8s.io/v1",
+	"discovery.k8s.io/v1",
+	"events.k8s.io/v1",
+	"flowcontrol.apiserver.k8s.io/v1",
+	"networking.k8s.io/v1",
+	"node.k8s.io/v1",
+	"policy/v1",
+	"rbac.authorization.k8s.io/v1",
+	"scheduling.k8s.io/v1",
+	"storage.k8s.io/v1",
+}
+
+// installOrder is the order in which Helm installs kinds; other kinds are installed last
+var installOrder = []string{
+	"PriorityClass",
+	"Namespace",
+	"NetworkPolicy",
+	"ResourceQuota",
+	"LimitRange",
+	"PodDisruptionBudget",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"StorageClass",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"CustomResourceDefinition",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicationController",
+	"ReplicaSet",
+	"Deployment",
+	"HorizontalPodAutoscaler",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"IngressClass",
+	"Ingress",
+	"APIService",
+	"MutatingWebhookConfiguration",
+	"ValidatingWebhookConfiguration",
+}
+
+// clusterScopedKinds are the built-in kinds that are not namespaced
+var clusterScopedKinds = map[string]bool{
+	"APIService":                       true,
+	"CSIDriver":                        true,
+	"CSINode":                          true,
+	"ClusterRole":                      true,
+	"ClusterRoleBinding":               true,
+	"CustomResourceDefinition":         true,
+	"FlowSchema":                       true,
+	"IngressClass":                     true,
+	"MutatingWebhookConfiguration":     true,
+	"Namespace":                        true,
+	"Node":                             true,
+	"PersistentVolume":                 true,
+	"PriorityClass":                    true,
+	"PriorityLevelConfiguration":       true,
+	"RuntimeClass":                     true,
+	"StorageClass":                     true,
+	"ValidatingAdmissionPolicy":        true,
+	"ValidatingAdmissionPolicyBinding": true,
+	"ValidatingWebhookConfiguration":   true,
+	"VolumeAttachment":                 true,
+}
+
+// maxIncludeDepth limits recursion of include, as Helm does
+const maxIncludeDepth = 1000
+
+// chartTemplate is a template of a chart or one of its subcharts, with the values it is rendered with
+type chartTemplate struct {
+	// name is the path of the template, prefixed with the path of the chart, such as mychart/charts/sub/templates/service.yaml
+	name   string
+	data   []byte
+	chart  *Chart
+	values map[string]interface{}
+	// basePath is the templates directory of the chart, such as mychart/charts/sub/templates
+	basePath string
+}
+
+// Render renders the chart to a manifest, similar to `helm template`.
+// Objects are ordered as Helm would install them, and test hooks are omitted.
+func Render(chart *Chart, options RenderOptions) ([]byte, error) {
+	if options.ReleaseName == "" {
+		options.ReleaseName = chart.Metadata.Name
+	}
+	if options.Namespace == "" {
+		options.Namespace = "default"
+	}
+	if options.KubeVersion == "" {
+		options.KubeVersion = DefaultKubeVersion
+	}
+	kubeVersion, err := semver.ParseTolerant(options.KubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Kubernetes version %q: %w", options.KubeVersion, err)
+	}
+
+	values := coalesceValues(chart.Values, options.Values)
+	var templates []*chartTemplate
+	var crds []*File
+	collectTemplates(chart, chart.Metadata.Name, values, &templates, &crds)
+
+	capabilities := &capabilities{
+		KubeVersion: kubeVersionInfo{
+			Version:    "v" + kubeVersion.String(),
+			GitVersion: "v" + kubeVersion.String(),
+			Major:      fmt.Sprint(kubeVersion.Major),
+			Minor:      fmt.Sprint(kubeVersion.Minor),
+		},
+		APIVersions: append(append(versionSet{}, builtinAPIVersions...), options.APIVersions...),
+	}
+	release := map[string]interface{}{
+		"Name":      options.ReleaseName,
+		"Namespace": options.Namespace,
+		"Service":   "Helm",
+		"IsInstall": true,
+		"IsUpgrade": false,
+		"Revision":  1,
+	}
+
+	t := template.New("chart").Option("missingkey=zero")
+	includeDepth := 0
+	t.Funcs(funcMap(t, &includeDepth))
+	for _, tmpl := range templates {
+		if _, err := t.New(tmpl.name).Parse(string(tmpl.data)); err != nil {
+			return nil, fmt.Errorf("parsing template %q: %w", tmpl.name, err)
+		}
+	}
+
+	var objects kubemanifest.ObjectList
+	for _, crd := range crds {
+		crdObjects, err := kubemanifest.LoadObjectsFrom(crd.Data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %q: %w", crd.Name, err)
+		}
+		objects = append(objects, crdObjects...)
+	}
+
+	for _, tmpl := range templates {
+		if isPartial(tmpl.name) || tmpl.chart.Metadata.Type == "library" {
+			continue
+		}
+		data := map[string]interface{}{
+			"Values":       tmpl.values,
+			"Release":      release,
+			"Chart":        tmpl.chart.Metadata,
+			"Capabilities": capabilities,
+			"Files":        newFiles(tmpl.chart.Files),
+			"Template": map[string]interface{}{
+				"Name":     tmpl.name,
+				"BasePath": tmpl.basePath,
+			},
+		}
+
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, tmpl.name, data); err != nil {
+			return nil, fmt.Errorf("rendering template %q: %w", tmpl.name, err)
+		}
+		rendered := strings.ReplaceAll(b.String(), "<no value>", "")
+
+		templateObjects, err := kubemanifest.LoadObjectsFrom([]byte(rendered))
+		if err != nil {
+			return nil, fmt.Errorf("parsing output of template %q: %w", tmpl.name, err)
+		}
+		for _, object := range templateObjects {
+			if isTestHook(object) {
+				continue
+			}
+			objects = append(objects, object)
+		}
+	}
+
+	if err := setNamespaces(objects, options.Namespace); err != nil {
+		return nil, err
+	}
+
+	if options.CreateNamespace && !hasNamespace(objects, options.Namespace) {
+		namespace := kubemanifest.NewObject(map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"metadata": map[string]interface{}{
+				"name": options.Namespace,
+			},
+		})
+		objects = append(kubemanifest.ObjectList{namespace}, objects...)
+	}
+
+	sortByInstallOrder(objects)
+
+	return objects.ToYAML()
+}
+
+// collectTemplates gathers the templates and CRDs of the chart and its enabled subcharts.
+func collectTemplates(chart *Chart, chartPath string, values map[string]interface{}, templates *[]*chartTemplate, crds *[]*File) {
+	for _, f := range chart.Templates {
+		*templates = append(*templates, &chartTemplate{
+			name:     chartPath + "/" + f.Name,
+			data:     f.Data,
+			chart:    chart,
+			values:   values,
+			basePath: chartPath + "/templates",
+		})
+	}
+	*crds = append(*crds, chart.CRDs...)
+
+	for _, subchart := range chart.Dependencies {
+		key := subchart.Metadata.Name
+		var dependency *Dependency
+		for _, d := range chart.Metadata.Dependencies {
+			if d.Name == subchart.Metadata.Name {
+				dependency = d
+				break
+			}
+		}
+		if dependency != nil && dependency.Alias != "" {
+			key = dependency.Alias
+		}
+		if !dependencyEnabled(values, dependency) {
+			continue
+		}
+
+		subValues := subchartValues(values, key, subchart)
+		// The parent chart sees the values of the subchart, including its defaults
+		values[key] = subValues
+		collectTemplates(subchart, chartPath+"/charts/"+key, subValues, templates, crds)
+	}
+}
+
+func isPartial(name string) bool {
+	base := path.Base(name)
+	return strings.HasPrefix(base, "_") || base == "NOTES.txt"
+}
+
+// isTestHook returns true for objects that are only created by `helm test`
+func isTestHook(object *kubemanifest.Object) bool {
+	hooks := object.ToUnstructured().GetAnnotations()["helm.sh/hook"]
+	for _, hook := range strings.Split(hooks, ",") {
+		hook = strings.TrimSpace(hook)
+		if hook == "test" || hook == "test-success" {
+			return true
+		}
+	}
+	return false
+}
+
+// setNamespaces places namespaced objects without a namespace in the namespace of the release.
+// Kinds defined by CustomResourceDefinitions in the chart are namespaced according to the definition;
+// other kinds that are not built-in are assumed to be namespaced.
+func setNamespaces(objects kubemanifest.ObjectList, namespace string) error {
+	clusterScoped := make(map[string]bool)
+	for kind := range clusterScopedKinds {
+		clusterScoped[kind] = true
+	}
+	for _, object := range objects {
+		if object.Kind() != "CustomResourceDefinition" {
+			continue
+		}
+		u := object.ToUnstructured()
+		scope, _, _ := unstructuredString(u.Object, "spec", "scope")
+		kind, _, _ := unstructuredString(u.Object, "spec", "names", "kind")
+		if scope == "Cluster" && kind != "" {
+			clusterScoped[kind] = true
+		}
+	}
+
+	for _, object := range objects {
+		if object.GetNamespace() != "" || clusterScoped[object.Kind()] {
+			continue
+		}
+		if err := object.Set(namespace, "metadata", "namespace"); err != nil {
+			return fmt.Errorf("setting namespace of %s %s: %w", object.Kind(), object.GetName(), err)
+		}
+	}
+	return nil
+}
+
+func hasNamespace(objects kubemanifest.ObjectList, namespace string) bool {
+	for _, object := range objects {
+		if object.Kind() == "Namespace" && object.GetName() == namespace {
+			return true
+		}
+	}
+	return false
+}
+
+func sortByInstallOrder(objects kubemanifest.ObjectList) {
+	rank := make(map[string]int)
+	for i, kind := range installOrder {
+		rank[kind] = i + 1
+	}
+	order := func(kind string) int {
+		if r, found := rank[kind]; found {
+			return r
+		}
+		return len(installOrder) + 1
+	}
+	sort.SliceStable(objects, func(i, j int) bool {
+		return order(objects[i].Kind()) < order(objects[j].Kind())
+	})
+}
+
+func unstructuredString(obj map[string]interface{}, fields ...string) (string, bool, error) {
+	v, found := lookupPath(obj, strings.Join(fields, "."))
+	if !found {
+		return "", false, nil
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", true, fmt.Errorf("%s is %T, not a string", strings.Join(fields, "."), v)
+	}
+	return s, true, nil
+}
+
+// funcMap returns the functions available to templates: those of sprig, and those Helm adds.
+func funcMap(t *template.Template, includeDepth *int) template.FuncMap {
+	f := sprig.TxtFuncMap()
+	// Templates must not read the environment of the process rendering them
+	delete(f, "env")
+	delete(f, "expandenv")
+
+	f["toYaml"] = func(v interface{}) string {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return strings.TrimSuffix(string(b), "\n")
+	}
+	f["fromYaml"] = func(s string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	f["fromYamlArray"] = func(s string) []interface{} {
+		var a []interface{}
+		if err := yaml.Unmarshal([]byte(s), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+		return a
+	}
+	f["toJson"] = func(v interface{}) string {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	f["fromJson"] = func(s string) map[string]interface{} {
+		m := make(map[string]interface{})
+		if err := json.Unmarshal([]byte(s), &m); err != nil {
+			m["Error"] = err.Error()
+		}
+		return m
+	}
+	f["fromJsonArray"] = func(s string) []interface{} {
+		var a []interface{}
+		if err := json.Unmarshal([]byte(s), &a); err != nil {
+			a = []interface{}{err.Error()}
+		}
+		return a
+	}
+	f["required"] = func(message string, v interface{}) (interface{}, error) {
+		if v == nil {
+			return nil, fmt.Errorf("%s", message)
+		}
+		if s, ok := v.(string); ok && s == "" {
+			return nil, fmt.Errorf("%s", message)
+		}
+		return v, nil
+	}
+	f["include"] = func(name string, data interface{}) (string, error) {
+		if *includeDepth >= maxIncludeDepth {
+			return "", fmt.Errorf("rendering template %q has a nested reference to itself", name)
+		}
+		*includeDepth++
+		defer func() { *includeDepth-- }()
+
+		var b bytes.Buffer
+		if err := t.ExecuteTemplate(&b, name, data); err != nil {
+			return "", err
+		}
+		return b.String(), nil
+	}
+	f["tpl"] = func(text string, data interface{}) (string, error) {
+		c, err := t.Clone()
+		if err != nil {
+			return "", err
+		}
+		tt, err := c.New("tpl").Parse(text)
+		if err != nil {
+			return "", fmt.Errorf("parsing tpl: %w", err)
+		}
+		var b bytes.Buffer
+		if err := tt.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("rendering tpl: %w", err)
+		}
+		return strings.ReplaceAll(b.String(), "<no value>", ""), nil
+	}
+	// There is no cluster to look up objects in, so lookup finds nothing, as with `helm template`
+	f["lookup"] = func(apiVersion, kind, namespace, name string) (map[string]interface{}, error) {
+		return map[string]interface{}{}, nil
+	}
+	return f
+}
+
+// capabilities is .Capabilities in templates
+type capabilities struct {
+	KubeVersion kubeVersionInfo
+	APIVersions versionSet
+}
+
+type kubeVersionInfo struct {
+	Version    string
+	GitVersion string
+	Major      string
+	Minor      string
+}
+
+func (v kubeVersionInfo) String() string {
+	return v.Version
+}
+
+type versionSet []string
+
+// Has returns true if the API version is available
+func (s versionSet) Has(apiVersion string) bool {
+	for _, v := range s {
+		if v == apiVersion {
+			return true
+		}
+	}
+	return false
+}
+
+// files is .Files in templates
+type files map[string][]byte
+
+func newFiles(chartFiles []*File) files {
+	f := make(files)
+	for _, file := range chartFiles {
+		f[file.Name] = file.Data
+	}
+	return f
+}
+
+// Get returns the contents of a file
+func (f files) Get(name string) string {
+	return string(f[name])
+}
+
+// GetBytes returns the contents of a file as bytes
+func (f files) GetBytes(name string) []byte {
+	return f[name]
+}
+
+// Lines returns the lines of a file
+func (f files) Lines(name string) []string {
+	if len(f[name]) == 0 {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(string(f[name]), "\n"), "\n")
+}
+
+// Glob returns the files matching a pattern
+func (f files) Glob(pattern string) files {
+	matches := make(files)
+	for name, data := range f {
+		if ok, _ := path.Match(pattern, name); ok {
+			matches[name] = data
+		}
+	}
+	return matches
+}
+
+// AsConfig returns the files as the data of a ConfigMap
+func (f files) AsConfig() string {
+	m := make(map[string]string)
+	for name, data := range f {
+		m[path.Base(name)] = string(data)
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
+
+// AsSecrets returns the files as the data of a Secret
+func (f files) AsSecrets() string {
+	m := make(map[string]string)
+	for name, data := range f {
+		m[path.Base(name)] = base64.StdEncoding.EncodeToString(data)
+	}
+	b, err := yaml.Marshal(m)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSuffix(string(b), "\n")
+}
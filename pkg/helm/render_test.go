@@ -0,0 +1,175 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/testutils/golden"
+)
+
+func TestRender(t *testing.T) {
+	grid := []struct {
+		name    string
+		options RenderOptions
+	}{
+		{
+			name: "defaults",
+			options: RenderOptions{
+				ReleaseName: "test",
+			},
+		},
+		{
+			name: "overrides",
+			options: RenderOptions{
+				ReleaseName: "test",
+				Namespace:   "example",
+				Values: map[string]interface{}{
+					"replicas": 3,
+					"image": map[string]interface{}{
+						"tag": "v1.0.0",
+					},
+					"backend": map[string]interface{}{
+						"enabled": false,
+					},
+				},
+				KubeVersion:     "1.33.2",
+				APIVersions:     []string{"example.com/v1"},
+				CreateNamespace: true,
+			},
+		},
+	}
+
+	chart, err := LoadDir(filepath.Join("testdata", "example"))
+	if err != nil {
+		t.Fatalf("loading chart: %v", err)
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			actual, err := Render(chart, g.options)
+			if err != nil {
+				t.Fatalf("rendering chart: %v", err)
+			}
+			golden.AssertMatchesFile(t, string(actual), filepath.Join("testdata", "example-"+g.name+".yaml"))
+		})
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	grid := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "required",
+			template: `value: {{ required "name is required" .Values.name }}`,
+			expected: "name is required",
+		},
+		{
+			name:     "recursive include",
+			template: `{{ define "loop" }}{{ include "loop" . }}{{ end }}{{ include "loop" . }}`,
+			expected: "nested reference",
+		},
+		{
+			name:     "env is not available",
+			template: `value: {{ env "HOME" }}`,
+			expected: `function "env" not defined`,
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			chart, err := loadFiles(map[string][]byte{
+				"Chart.yaml":              []byte("name: errors\nversion: 0.0.1\n"),
+				"templates/template.yaml": []byte(g.template),
+			})
+			if err != nil {
+				t.Fatalf("loading chart: %v", err)
+			}
+			_, err = Render(chart, RenderOptions{})
+			if err == nil {
+				t.Fatalf("expected error containing %q", g.expected)
+			}
+			if !strings.Contains(err.Error(), g.expected) {
+				t.Errorf("expected error containing %q, got %v", g.expected, err)
+			}
+		})
+	}
+}
+
+func TestDependencyEnabled(t *testing.T) {
+	grid := []struct {
+		condition string
+		values    string
+		expected  bool
+	}{
+		{condition: "", expected: true},
+		{condition: "sub.enabled", expected: true},
+		{condition: "sub.enabled", values: "sub: {enabled: false}", expected: false},
+		{condition: "sub.enabled,global.sub.enabled", values: "global: {sub: {enabled: false}}", expected: false},
+		{condition: "sub.enabled,global.sub.enabled", values: "sub: {enabled: true}\nglobal: {sub: {enabled: false}}", expected: true},
+		{condition: "sub.enabled", values: "sub: {enabled: yes-please}", expected: true},
+	}
+
+	for _, g := range grid {
+		chart, err := loadFiles(map[string][]byte{
+			"Chart.yaml":  []byte("name: parent\n"),
+			"values.yaml": []byte(g.values),
+		})
+		if err != nil {
+			t.Fatalf("loading chart: %v", err)
+		}
+		actual := dependencyEnabled(chart.Values, &Dependency{Name: "sub", Condition: g.condition})
+		if actual != g.expected {
+			t.Errorf("condition %q with values %q: expected %v, got %v", g.condition, g.values, g.expected, actual)
+		}
+	}
+}
+
+func TestCoalesceValues(t *testing.T) {
+	defaults := map[string]interface{}{
+		"a": map[string]interface{}{"b": 1, "c": 2},
+		"d": "keep",
+		"e": "remove",
+	}
+	overrides := map[string]interface{}{
+		"a": map[string]interface{}{"c": 3},
+		"e": nil,
+	}
+
+	values := coalesceValues(defaults, overrides)
+
+	if v, _ := lookupPath(values, "a.b"); v != 1 {
+		t.Errorf("expected a.b to be 1, got %v", v)
+	}
+	if v, _ := lookupPath(values, "a.c"); v != 3 {
+		t.Errorf("expected a.c to be 3, got %v", v)
+	}
+	if v, _ := lookupPath(values, "d"); v != "keep" {
+		t.Errorf("expected d to be keep, got %v", v)
+	}
+	if _, found := values["e"]; found {
+		t.Errorf("expected e to be removed")
+	}
+	if v, _ := lookupPath(defaults, "a.c"); v != 2 {
+		t.Errorf("expected defaults to be unchanged, got a.c=%v", v)
+	}
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"strings"
+)
+
+// coalesceValues returns defaults overridden by overrides, like Helm does for values.yaml and user-supplied values.
+// Maps are merged recursively, and a null override removes the default.
+func coalesceValues(defaults, overrides map[string]interface{}) map[string]interface{} {
+	values := deepCopyMap(defaults)
+	for k, v := range overrides {
+		if v == nil {
+			delete(values, k)
+			continue
+		}
+		if override, ok := v.(map[string]interface{}); ok {
+			if existing, ok := values[k].(map[string]interface{}); ok {
+				values[k] = coalesceValues(existing, override)
+				continue
+			}
+		}
+		values[k] = deepCopyValue(v)
+	}
+	return values
+}
+
+// subchartValues returns the values for a subchart, whose values are under key in the values of its parent.
+// The global values of the parent are passed down to the subchart.
+func subchartValues(parent map[string]interface{}, key string, subchart *Chart) map[string]interface{} {
+	overrides, _ := parent[key].(map[string]interface{})
+	values := coalesceValues(subchart.Values, overrides)
+	if globals, ok := parent["global"].(map[string]interface{}); ok {
+		subchartGlobals, _ := values["global"].(map[string]interface{})
+		values["global"] = coalesceValues(subchartGlobals, globals)
+	}
+	return values
+}
+
+// dependencyEnabled evaluates the condition of a subchart; the first condition path that is a boolean decides.
+func dependencyEnabled(values map[string]interface{}, dependency *Dependency) bool {
+	if dependency == nil || dependency.Condition == "" {
+		return true
+	}
+	for _, condition := range strings.Split(dependency.Condition, ",") {
+		v, found := lookupPath(values, strings.TrimSpace(condition))
+		if !found {
+			continue
+		}
+		if enabled, ok := v.(bool); ok {
+			return enabled
+		}
+	}
+	return true
+}
+
+// lookupPath returns the value at a dotted path, such as subchart.enabled
+func lookupPath(values map[string]interface{}, p string) (interface{}, bool) {
+	var current interface{} = values
+	for _, key := range strings.Split(p, ".") {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+func deepCopyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = deepCopyValue(v)
+	}
+	return c
+}
+
+func deepCopyValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return deepCopyMap(v)
+	case []interface{}:
+		c := make([]interface{}, len(v))
+		for i := range v {
+			c[i] = deepCopyValue(v[i])
+		}
+		return c
+	default:
+		return v
+	}
+}
@@ -134,13 +134,58 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 		update = append(update, group.Ready...)
 	}
 
+	name := group.InstanceGroup.Name
+	recorded := c.state.group(name)
+	if recorded != nil {
+		if recorded.Phase == InstanceGroupPhaseCompleted {
+			klog.Infof("Rolling update of InstanceGroup %q already completed, skipping", name)
+			return nil
+		}
+
+		var remaining []*cloudinstances.CloudInstance
+		for _, u := range update {
+			if recorded.IsTerminated(u.ID) {
+				klog.Infof("Skipping instance %q, which was already terminated", u.ID)
+				continue
+			}
+			remaining = append(remaining, u)
+		}
+		update = remaining
+	}
+
 	if len(update) == 0 {
 		return nil
 	}
 
+	if err := c.state.update(ctx, name, func(g *InstanceGroupState) {
+		g.Phase = InstanceGroupPhaseUpdating
+		g.Error = ""
+	}); err != nil {
+		return err
+	}
+	defer func() {
+		updateErr := c.state.update(ctx, name, func(g *InstanceGroupState) {
+			if err != nil {
+				g.Phase = InstanceGroupPhaseFailed
+				g.Error = err.Error()
+			} else {
+				g.Phase = InstanceGroupPhaseCompleted
+			}
+		})
+		if updateErr != nil {
+			if err == nil {
+				err = updateErr
+			} else {
+				klog.Warningf("unable to record the failure of the rolling update of InstanceGroup %q: %v", name, updateErr)
+			}
+		}
+	}()
+
 	if isBastion {
 		klog.V(3).Info("Not validating the cluster as instance is a bastion.")
-	} else if err = c.maybeValidate("", 1, group); err != nil {
+	} else if recorded != nil && recorded.lastValidationSucceeded() {
+		klog.Infof("Not validating the cluster before resuming InstanceGroup %q, as it last validated successfully.", name)
+	} else if err = c.maybeValidate(ctx, "", 1, group); err != nil {
 		return err
 	}
 
@@ -168,6 +213,18 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 
 	settings := resolveSettings(c.Cluster, group.InstanceGroup, numInstances)
 
+	var window *api.MaintenanceWindow
+	if settings.MaintenanceWindow != nil {
+		window, err = api.ParseMaintenanceWindow(settings.MaintenanceWindow)
+		if err != nil {
+			return fmt.Errorf("invalid maintenance window for InstanceGroup %q: %w", name, err)
+		}
+	}
+
+	if err = c.waitForSafePoint(ctx, group, window); err != nil {
+		return err
+	}
+
 	runningDrains := 0
 	maxSurge := settings.MaxSurge.IntValue()
 
@@ -225,7 +282,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 					klog.Infof("waiting for %v after detaching instance", sleepAfterTerminate)
 					time.Sleep(sleepAfterTerminate)
 
-					if err := c.maybeValidate(" after detaching instance", c.ValidateCount, group); err != nil {
+					if err := c.maybeValidate(ctx, " after detaching instance", c.ValidateCount, group); err != nil {
 						return err
 					}
 					noneReady = false
@@ -242,6 +299,31 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 	terminateChan := make(chan error, maxConcurrency)
 
 	for uIdx, u := range update {
+		if err = c.checkPaused(ctx); err != nil {
+			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+		}
+
+		if window != nil && !window.IsOpen(time.Now()) {
+			// Let the instances we have already started on finish before waiting for the window to open again
+			if runningDrains > 0 {
+				for runningDrains > 0 {
+					err = <-terminateChan
+					runningDrains--
+					if err != nil {
+						return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
+					}
+				}
+
+				if err = c.maybeValidate(ctx, " after terminating instance", c.ValidateCount, group); err != nil {
+					return err
+				}
+			}
+
+			if err = c.waitForSafePoint(ctx, group, window); err != nil {
+				return err
+			}
+		}
+
 		go func(m *cloudinstances.CloudInstance) {
 			terminateChan <- c.drainTerminateAndWait(ctx, m, sleepAfterTerminate)
 		}(u)
@@ -259,7 +341,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
 
-		err = c.maybeValidate(" after terminating instance", c.ValidateCount, group)
+		err = c.maybeValidate(ctx, " after terminating instance", c.ValidateCount, group)
 		if err != nil {
 			return waitForPendingBeforeReturningError(runningDrains, terminateChan, err)
 		}
@@ -305,7 +387,7 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 			}
 		}
 
-		err = c.maybeValidate(" after terminating instance", c.ValidateCount, group)
+		err = c.maybeValidate(ctx, " after terminating instance", c.ValidateCount, group)
 		if err != nil {
 			return err
 		}
@@ -314,6 +396,60 @@ func (c *RollingUpdateCluster) rollingUpdateInstanceGroup(ctx context.Context, g
 	return nil
 }
 
+// maintenanceWindowPollInterval is how often we check for a pause request while waiting for a maintenance window to open
+const maintenanceWindowPollInterval = time.Minute
+
+// waitForSafePoint is called before we start disrupting instances.
+// It returns ErrRollingUpdatePaused if a pause was requested. If the maintenance window (if any) is closed,
+// it returns ErrMaintenanceWindowClosed, or waits until the window opens if WaitForWindow is set.
+func (c *RollingUpdateCluster) waitForSafePoint(ctx context.Context, group *cloudinstances.CloudInstanceGroup, window *api.MaintenanceWindow) error {
+	if err := c.checkPaused(ctx); err != nil {
+		return err
+	}
+
+	if window == nil || window.IsOpen(time.Now()) {
+		return nil
+	}
+
+	nextOpen := window.NextOpen(time.Now()).Format(time.RFC3339)
+	if !c.WaitForWindow {
+		// Waiting would hold the state store lock, blocking other changes to the cluster, until the window opens
+		return fmt.Errorf("%w for InstanceGroup %q until %s; use --resume to continue the rolling update once it opens, or --wait-for-window to wait for it", ErrMaintenanceWindowClosed, group.InstanceGroup.Name, nextOpen)
+	}
+
+	klog.Infof("Maintenance window for InstanceGroup %q is closed; waiting until %s", group.InstanceGroup.Name, nextOpen)
+	for {
+		wait := min(time.Until(window.NextOpen(time.Now())), maintenanceWindowPollInterval)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		if err := c.checkPaused(ctx); err != nil {
+			return err
+		}
+		if window.IsOpen(time.Now()) {
+			klog.Infof("Maintenance window for InstanceGroup %q is open; continuing rolling update", group.InstanceGroup.Name)
+			return nil
+		}
+	}
+}
+
+// checkPaused returns ErrRollingUpdatePaused if a pause of the rolling update was requested
+func (c *RollingUpdateCluster) checkPaused(ctx context.Context) error {
+	paused, err := c.state.pauseRequested(ctx)
+	if err != nil {
+		klog.Warningf("unable to check whether rolling update was paused: %v", err)
+		return nil
+	}
+	if paused {
+		klog.Info("Rolling update pause was requested, stopping")
+		return ErrRollingUpdatePaused
+	}
+	return nil
+}
+
 func prioritizeUpdate(update []*cloudinstances.CloudInstance) []*cloudinstances.CloudInstance {
 	// The priorities are, in order:
 	//   attached before detached
@@ -457,6 +593,12 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(ctx context.Context, u *clo
 					return fmt.Errorf("failed to drain node %q: %w", nodeName, err)
 				}
 				klog.Infof("Ignoring error draining node %q: %v", nodeName, err)
+			} else {
+				if err := c.state.update(ctx, u.CloudInstanceGroup.InstanceGroup.Name, func(g *InstanceGroupState) {
+					g.Drained = appendUnique(g.Drained, instanceID)
+				}); err != nil {
+					return err
+				}
 			}
 		} else {
 			klog.Warningf("Skipping drain of instance %q, because it is not registered in kubernetes", instanceID)
@@ -482,6 +624,12 @@ func (c *RollingUpdateCluster) drainTerminateAndWait(ctx context.Context, u *clo
 		return err
 	}
 
+	if err := c.state.update(ctx, u.CloudInstanceGroup.InstanceGroup.Name, func(g *InstanceGroupState) {
+		g.Terminated = appendUnique(g.Terminated, instanceID)
+	}); err != nil {
+		return err
+	}
+
 	if err := c.reconcileInstanceGroup(ctx); err != nil {
 		klog.Errorf("error reconciling instance group %q: %v", u.CloudInstanceGroup.HumanName, err)
 		return err
@@ -523,13 +671,17 @@ func (c *RollingUpdateCluster) reconcileInstanceGroup(ctx context.Context) error
 	return err
 }
 
-func (c *RollingUpdateCluster) maybeValidate(operation string, validateCount int, group *cloudinstances.CloudInstanceGroup) error {
+func (c *RollingUpdateCluster) maybeValidate(ctx context.Context, operation string, validateCount int, group *cloudinstances.CloudInstanceGroup) error {
 	if c.CloudOnly {
 		klog.Warningf("Not validating cluster as cloudonly flag is set.")
 	} else {
 		klog.Info("Validating the cluster.")
 
-		if err := c.validateClusterWithTimeout(validateCount, group); err != nil {
+		err := c.validateClusterWithTimeout(validateCount, group)
+		if recordErr := c.recordValidation(ctx, group, operation, err); recordErr != nil {
+			return recordErr
+		}
+		if err != nil {
 
 			if c.FailOnValidate {
 				klog.Errorf("Cluster did not validate within %s", c.ValidationTimeout)
@@ -545,6 +697,24 @@ func (c *RollingUpdateCluster) maybeValidate(operation string, validateCount int
 	return nil
 }
 
+// recordValidation records the result of validating the cluster in the rolling update state
+func (c *RollingUpdateCluster) recordValidation(ctx context.Context, group *cloudinstances.CloudInstanceGroup, operation string, err error) error {
+	record := ValidationRecord{
+		Time:      time.Now().UTC(),
+		Operation: strings.TrimSpace(operation),
+		Succeeded: err == nil,
+	}
+	if err != nil {
+		record.Message = err.Error()
+	}
+	return c.state.update(ctx, group.InstanceGroup.Name, func(g *InstanceGroupState) {
+		g.Validations = append(g.Validations, record)
+		if len(g.Validations) > maxValidationRecords {
+			g.Validations = g.Validations[len(g.Validations)-maxValidationRecords:]
+		}
+	})
+}
+
 // validateClusterWithTimeout runs validation.ValidateCluster until either we get positive result or the timeout expires
 func (c *RollingUpdateCluster) validateClusterWithTimeout(validateCount int, group *cloudinstances.CloudInstanceGroup) error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.ValidationTimeout)
@@ -771,7 +941,7 @@ func (c *RollingUpdateCluster) UpdateSingleInstance(ctx context.Context, cloudMe
 			if err != nil {
 				return fmt.Errorf("failed to detach instance: %v", err)
 			}
-			if err := c.maybeValidate(" after detaching instance", c.ValidateCount, cloudMember.CloudInstanceGroup); err != nil {
+			if err := c.maybeValidate(ctx, " after detaching instance", c.ValidateCount, cloudMember.CloudInstanceGroup); err != nil {
 				return err
 			}
 		}
@@ -86,6 +86,18 @@ type RollingUpdateCluster struct {
 
 	// Options holds user-specified options
 	Options RollingUpdateOptions
+
+	// Resume continues a previously interrupted rolling update from the progress recorded in the state store
+	Resume bool
+
+	// DiscardState discards the progress recorded for a previously interrupted rolling update, and starts a new one
+	DiscardState bool
+
+	// WaitForWindow waits for closed maintenance windows to open, instead of stopping the rolling update of the instance group
+	WaitForWindow bool
+
+	// state records the progress of the rolling update in the state store; nil if progress is not recorded
+	state *rollingUpdateStateStore
 }
 
 type RollingUpdateOptions struct {
@@ -135,6 +147,18 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 		}
 	}
 
+	if c.Clientset != nil {
+		configBase, err := c.Clientset.ConfigBaseFor(c.Cluster)
+		if err != nil {
+			return fmt.Errorf("error getting config base for cluster %q: %w", c.Cluster.Name, err)
+		}
+		state, err := openRollingUpdateState(ctx, configBase, c.Resume, c.DiscardState)
+		if err != nil {
+			return err
+		}
+		c.state = state
+	}
+
 	// Upgrade bastions first; if these go down we can't see anything
 	{
 		var wg sync.WaitGroup
@@ -161,6 +185,9 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 
 	// Do not continue update if bastion(s) failed
 	for _, err := range results {
+		if stderrors.Is(err, ErrRollingUpdatePaused) || stderrors.Is(err, ErrMaintenanceWindowClosed) {
+			return err
+		}
 		if err != nil {
 			return fmt.Errorf("bastion not healthy after update, stopping rolling-update: %q", err)
 		}
@@ -174,6 +201,9 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 
 		for _, k := range sortGroups(masterGroups) {
 			err := c.rollingUpdateInstanceGroup(ctx, masterGroups[k], c.MasterInterval)
+			if stderrors.Is(err, ErrRollingUpdatePaused) || stderrors.Is(err, ErrMaintenanceWindowClosed) {
+				return err
+			}
 			// Do not continue update if control-plane node(s) failed; cluster is potentially in an unhealthy state.
 			if err != nil {
 				return fmt.Errorf("control-plane node not healthy after update, stopping rolling-update: %q", err)
@@ -232,6 +262,12 @@ func (c *RollingUpdateCluster) RollingUpdate(ctx context.Context, groups map[str
 		}
 	}
 
+	if len(errs) == 0 {
+		if err := c.state.complete(ctx); err != nil {
+			return err
+		}
+	}
+
 	igNames := slices.Sorted(maps.Keys(groups))
 	klog.Infof("Completed rolling update for cluster %q instance groups %v", c.ClusterName, igNames)
 	return errors.NewAggregate(errs)
@@ -254,7 +290,7 @@ func sortGroups(groupMap map[string]*cloudinstances.CloudInstanceGroup) []string
 // warning to the user is more appropriate. Likewise, if we cannot deregister an
 // instance from cloud load balancers, continuing would leave traffic routed to
 // nodes that are being terminated, so we bail out instead of plowing through
-// the remaining instance groups.
+// the remaining instance groups. A requested pause also stops the rolling update.
 func isExitableError(err error) bool {
-	return stderrors.Is(err, &ValidationTimeoutError{}) || stderrors.Is(err, &DeregisterError{})
+	return stderrors.Is(err, &ValidationTimeoutError{}) || stderrors.Is(err, &DeregisterError{}) || stderrors.Is(err, ErrRollingUpdatePaused)
 }
@@ -39,6 +39,9 @@ func resolveSettings(cluster *kops.Cluster, group *kops.InstanceGroup, numInstan
 		if rollingUpdate.MaxSurge == nil {
 			rollingUpdate.MaxSurge = def.MaxSurge
 		}
+		if rollingUpdate.MaintenanceWindow == nil {
+			rollingUpdate.MaintenanceWindow = def.MaintenanceWindow
+		}
 	}
 
 	if rollingUpdate.DrainAndTerminate == nil {
@@ -117,6 +117,40 @@ func assertResolvesValue(t *testing.T, name string, expected interface{}, rollin
 		assert.Equal(t, expected, value.Elem().Interface(), msg)
 }
 
+func TestMaintenanceWindowSettings(t *testing.T) {
+	clusterWindow := &kops.MaintenanceWindowSpec{StartTime: "22:00", EndTime: "06:00"}
+	groupWindow := &kops.MaintenanceWindowSpec{Days: []string{"Sat-Sun"}, StartTime: "00:00", EndTime: "00:00"}
+
+	for _, tc := range []struct {
+		name     string
+		cluster  *kops.RollingUpdate
+		group    *kops.RollingUpdate
+		expected *kops.MaintenanceWindowSpec
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name:     "cluster",
+			cluster:  &kops.RollingUpdate{MaintenanceWindow: clusterWindow},
+			expected: clusterWindow,
+		},
+		{
+			name:     "group overrides cluster",
+			cluster:  &kops.RollingUpdate{MaintenanceWindow: clusterWindow},
+			group:    &kops.RollingUpdate{MaintenanceWindow: groupWindow},
+			expected: groupWindow,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			cluster := &kops.Cluster{Spec: kops.ClusterSpec{RollingUpdate: tc.cluster}}
+			group := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{RollingUpdate: tc.group}}
+			resolved := resolveSettings(cluster, group, 1)
+			assert.Equal(t, tc.expected, resolved.MaintenanceWindow)
+		})
+	}
+}
+
 func TestMaxUnavailable(t *testing.T) {
 	for _, tc := range []struct {
 		numInstances int
@@ -0,0 +1,323 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// PathRollingUpdateState is the path (relative to the cluster's config base) where the progress of
+// an in-progress rolling update is recorded.
+const PathRollingUpdateState = "rollingupdate/state"
+
+// PathRollingUpdatePause is the path (relative to the cluster's config base) of the marker that
+// asks an in-progress rolling update to stop at the next safe point.
+const PathRollingUpdatePause = "rollingupdate/pause"
+
+// ErrRollingUpdateInProgress is returned when a rolling update is started while another one is recorded as in progress.
+var ErrRollingUpdateInProgress = errors.New("a rolling update is already in progress")
+
+// ErrRollingUpdatePaused is returned when a rolling update stops because a pause was requested.
+var ErrRollingUpdatePaused = errors.New("rolling update paused; use --resume to continue it")
+
+// ErrMaintenanceWindowClosed is returned when a rolling update would disrupt an instance outside of its maintenance window.
+var ErrMaintenanceWindowClosed = errors.New("maintenance window is closed")
+
+// InstanceGroupPhase is the phase of the rolling update of a single instance group.
+type InstanceGroupPhase string
+
+const (
+	// InstanceGroupPhasePending means we have not yet started updating the instance group.
+	InstanceGroupPhasePending InstanceGroupPhase = "Pending"
+	// InstanceGroupPhaseUpdating means we are draining and terminating instances in the instance group.
+	InstanceGroupPhaseUpdating InstanceGroupPhase = "Updating"
+	// InstanceGroupPhaseCompleted means all instances in the instance group have been updated.
+	InstanceGroupPhaseCompleted InstanceGroupPhase = "Completed"
+	// InstanceGroupPhaseFailed means the rolling update of the instance group stopped with an error.
+	InstanceGroupPhaseFailed InstanceGroupPhase = "Failed"
+)
+
+// RollingUpdateState is the persisted progress of a rolling update.
+type RollingUpdateState struct {
+	// StartedAt is when the rolling update was first started.
+	StartedAt time.Time `json:"startedAt"`
+	// UpdatedAt is when the state was last written.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// InstanceGroups holds the progress of each instance group, keyed by instance group name.
+	InstanceGroups map[string]*InstanceGroupState `json:"instanceGroups,omitempty"`
+}
+
+// InstanceGroupState is the persisted progress of the rolling update of a single instance group.
+type InstanceGroupState struct {
+	// Phase is the current phase of the instance group.
+	Phase InstanceGroupPhase `json:"phase"`
+	// Drained is the list of instance IDs whose nodes have been drained.
+	Drained []string `json:"drained,omitempty"`
+	// Terminated is the list of instance IDs that have been terminated.
+	Terminated []string `json:"terminated,omitempty"`
+	// Validations records the results of cluster validation for the instance group.
+	Validations []ValidationRecord `json:"validations,omitempty"`
+	// Error is the error that stopped the rolling update of the instance group, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// ValidationRecord is the result of validating the cluster during a rolling update.
+type ValidationRecord struct {
+	// Time is when the validation finished.
+	Time time.Time `json:"time"`
+	// Operation describes the step after which the cluster was validated.
+	Operation string `json:"operation,omitempty"`
+	// Succeeded is true if the cluster validated.
+	Succeeded bool `json:"succeeded"`
+	// Message holds the reason validation failed.
+	Message string `json:"message,omitempty"`
+}
+
+// maxValidationRecords limits the number of validation results we keep per instance group.
+const maxValidationRecords = 10
+
+// IsTerminated returns true if the instance was recorded as terminated.
+func (s *InstanceGroupState) IsTerminated(id string) bool {
+	return slices.Contains(s.Terminated, id)
+}
+
+// lastValidationSucceeded returns true if the most recent validation of the instance group succeeded.
+func (s *InstanceGroupState) lastValidationSucceeded() bool {
+	if len(s.Validations) == 0 {
+		return false
+	}
+	return s.Validations[len(s.Validations)-1].Succeeded
+}
+
+// rollingUpdateStateStore persists a RollingUpdateState to a vfs.Path.
+// It is safe for concurrent use, as instances are drained and terminated concurrently.
+type rollingUpdateStateStore struct {
+	mutex     sync.Mutex
+	path      vfs.Path
+	pausePath vfs.Path
+	state     RollingUpdateState
+}
+
+// ReadRollingUpdateState reads the state of an in-progress rolling update.
+// If no rolling update is in progress, it returns (nil, nil).
+func ReadRollingUpdateState(ctx context.Context, configBase vfs.Path) (*RollingUpdateState, error) {
+	p := configBase.Join(PathRollingUpdateState)
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading rolling update state %q: %w", p, err)
+	}
+	state := &RollingUpdateState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error parsing rolling update state %q: %w", p, err)
+	}
+	return state, nil
+}
+
+// openRollingUpdateState starts recording progress under configBase.
+// If resume is false, it refuses to start if another rolling update is recorded as in progress,
+// unless discard is true, in which case the recorded progress is removed.
+// If resume is true, it continues from the recorded progress, if any.
+func openRollingUpdateState(ctx context.Context, configBase vfs.Path, resume bool, discard bool) (*rollingUpdateStateStore, error) {
+	s := &rollingUpdateStateStore{
+		path:      configBase.Join(PathRollingUpdateState),
+		pausePath: configBase.Join(PathRollingUpdatePause),
+	}
+
+	existing, err := ReadRollingUpdateState(ctx, configBase)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing != nil && discard {
+		klog.Infof("Discarding the progress of the rolling update started at %s", existing.StartedAt.Format(time.RFC3339))
+		if err := s.path.Remove(ctx); err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("error removing rolling update state %q: %w", s.path, err)
+		}
+		existing = nil
+	}
+
+	if existing != nil {
+		if !resume {
+			return nil, fmt.Errorf("%w (started at %s); use --resume to continue it, or --discard-state to discard its progress and start again", ErrRollingUpdateInProgress, existing.StartedAt.Format(time.RFC3339))
+		}
+		klog.Infof("Resuming rolling update started at %s", existing.StartedAt.Format(time.RFC3339))
+		if err := s.clearPause(ctx); err != nil {
+			return nil, err
+		}
+		s.state = *existing
+		if s.state.InstanceGroups == nil {
+			s.state.InstanceGroups = make(map[string]*InstanceGroupState)
+		}
+		return s, nil
+	}
+
+	if resume {
+		klog.Infof("No rolling update in progress to resume; starting a new rolling update")
+	}
+
+	// Any pause marker was left behind by a rolling update that has since finished
+	if err := s.clearPause(ctx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	s.state = RollingUpdateState{
+		StartedAt:      now,
+		UpdatedAt:      now,
+		InstanceGroups: make(map[string]*InstanceGroupState),
+	}
+	data, err := s.marshal()
+	if err != nil {
+		return nil, err
+	}
+	// CreateFile guards against two rolling updates starting at the same time
+	if err := s.path.CreateFile(ctx, bytes.NewReader(data), nil); err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("%w; use --resume to continue it", ErrRollingUpdateInProgress)
+		}
+		return nil, fmt.Errorf("error writing rolling update state %q: %w", s.path, err)
+	}
+	return s, nil
+}
+
+// group returns a copy of the recorded state of the named instance group, or nil if there is none.
+func (s *rollingUpdateStateStore) group(name string) *InstanceGroupState {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	g := s.state.InstanceGroups[name]
+	if g == nil {
+		return nil
+	}
+	copied := *g
+	copied.Drained = slices.Clone(g.Drained)
+	copied.Terminated = slices.Clone(g.Terminated)
+	copied.Validations = slices.Clone(g.Validations)
+	return &copied
+}
+
+// update applies fn to the state of the named instance group and writes the result.
+// We stop the rolling update if the state can't be written, as a resumed rolling update
+// would otherwise repeat or skip work.
+func (s *rollingUpdateStateStore) update(ctx context.Context, name string, fn func(g *InstanceGroupState)) error {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	g := s.state.InstanceGroups[name]
+	if g == nil {
+		g = &InstanceGroupState{Phase: InstanceGroupPhasePending}
+		s.state.InstanceGroups[name] = g
+	}
+	fn(g)
+	s.state.UpdatedAt = time.Now().UTC()
+
+	data, err := s.marshal()
+	if err != nil {
+		return err
+	}
+	if err := s.path.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error recording rolling update progress in %q: %w", s.path, err)
+	}
+	return nil
+}
+
+// complete removes the recorded state, as the rolling update has finished.
+func (s *rollingUpdateStateStore) complete(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.path.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing rolling update state %q: %w", s.path, err)
+	}
+	return s.clearPause(ctx)
+}
+
+// PauseRollingUpdate asks the rolling update in progress to stop at the next safe point.
+// It returns false if no rolling update is in progress.
+func PauseRollingUpdate(ctx context.Context, configBase vfs.Path) (bool, error) {
+	state, err := ReadRollingUpdateState(ctx, configBase)
+	if err != nil || state == nil {
+		return false, err
+	}
+
+	p := configBase.Join(PathRollingUpdatePause)
+	requestedAt := time.Now().UTC().Format(time.RFC3339)
+	if err := p.WriteFile(ctx, bytes.NewReader([]byte(requestedAt)), nil); err != nil {
+		return false, fmt.Errorf("error writing rolling update pause marker %q: %w", p, err)
+	}
+	return true, nil
+}
+
+// pauseRequested returns true if the pause marker is present.
+func (s *rollingUpdateStateStore) pauseRequested(ctx context.Context) (bool, error) {
+	if s == nil {
+		return false, nil
+	}
+	if _, err := s.pausePath.ReadFile(ctx); err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("error reading rolling update pause marker %q: %w", s.pausePath, err)
+	}
+	return true, nil
+}
+
+func (s *rollingUpdateStateStore) clearPause(ctx context.Context) error {
+	if err := s.pausePath.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing rolling update pause marker %q: %w", s.pausePath, err)
+	}
+	return nil
+}
+
+func (s *rollingUpdateStateStore) marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(&s.state, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing rolling update state: %w", err)
+	}
+	return data, nil
+}
+
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package instancegroups
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func newTestConfigBase(t *testing.T) vfs.Path {
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	configBase, err := vfsContext.BuildVfsPath("memfs://tests/test.k8s.local")
+	require.NoError(t, err)
+	return configBase
+}
+
+func TestRollingUpdateStateRefusesConcurrentUpdate(t *testing.T) {
+	ctx := context.TODO()
+	configBase := newTestConfigBase(t)
+
+	_, err := openRollingUpdateState(ctx, configBase, false, false)
+	require.NoError(t, err)
+
+	_, err = openRollingUpdateState(ctx, configBase, false, false)
+	assert.ErrorIs(t, err, ErrRollingUpdateInProgress)
+
+	_, err = openRollingUpdateState(ctx, configBase, true, false)
+	assert.NoError(t, err, "resume")
+}
+
+func TestRollingUpdateStateDiscard(t *testing.T) {
+	ctx := context.TODO()
+	configBase := newTestConfigBase(t)
+
+	s, err := openRollingUpdateState(ctx, configBase, false, false)
+	require.NoError(t, err)
+	require.NoError(t, s.update(ctx, "nodes", func(g *InstanceGroupState) {
+		g.Terminated = appendUnique(g.Terminated, "i-1")
+	}))
+
+	_, err = openRollingUpdateState(ctx, configBase, false, false)
+	require.ErrorIs(t, err, ErrRollingUpdateInProgress)
+	assert.Contains(t, err.Error(), "--discard-state")
+
+	_, err = openRollingUpdateState(ctx, configBase, false, true)
+	require.NoError(t, err)
+
+	state, err := ReadRollingUpdateState(ctx, configBase)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Empty(t, state.InstanceGroups)
+}
+
+// vfsPath lets us embed a vfs.Path, which has a Path method
+type vfsPath = vfs.Path
+
+// failingWritePath is a vfs.Path that can't be written
+type failingWritePath struct {
+	vfsPath
+}
+
+func (p *failingWritePath) WriteFile(ctx context.Context, data io.ReadSeeker, acl vfs.ACL) error {
+	return fmt.Errorf("state store unavailable")
+}
+
+func TestRollingUpdateStateWriteFailure(t *testing.T) {
+	ctx := context.TODO()
+	configBase := newTestConfigBase(t)
+
+	s, err := openRollingUpdateState(ctx, configBase, false, false)
+	require.NoError(t, err)
+	s.path = &failingWritePath{vfsPath: s.path}
+
+	err = s.update(ctx, "nodes", func(g *InstanceGroupState) {
+		g.Terminated = appendUnique(g.Terminated, "i-1")
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "state store unavailable")
+}
+
+func TestRollingUpdateStateRoundTrip(t *testing.T) {
+	ctx := context.TODO()
+	configBase := newTestConfigBase(t)
+
+	s, err := openRollingUpdateState(ctx, configBase, false, false)
+	require.NoError(t, err)
+
+	require.NoError(t, s.update(ctx, "nodes", func(g *InstanceGroupState) {
+		g.Phase = InstanceGroupPhaseUpdating
+		g.Drained = appendUnique(g.Drained, "i-1")
+		g.Drained = appendUnique(g.Drained, "i-1")
+		g.Terminated = appendUnique(g.Terminated, "i-1")
+		g.Validations = append(g.Validations, ValidationRecord{Succeeded: true})
+	}))
+
+	state, err := ReadRollingUpdateState(ctx, configBase)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	g := state.InstanceGroups["nodes"]
+	require.NotNil(t, g)
+	assert.Equal(t, InstanceGroupPhaseUpdating, g.Phase)
+	assert.Equal(t, []string{"i-1"}, g.Drained)
+	assert.True(t, g.IsTerminated("i-1"))
+	assert.True(t, g.lastValidationSucceeded())
+
+	require.NoError(t, s.complete(ctx))
+	state, err = ReadRollingUpdateState(ctx, configBase)
+	require.NoError(t, err)
+	assert.Nil(t, state)
+}
+
+func TestRollingUpdateResumeSkipsTerminatedInstances(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud := getTestSetup()
+	configBase := newTestConfigBase(t)
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+	group := groups["node-1"]
+
+	// Record the first instance as already replaced by an interrupted rolling update
+	s, err := openRollingUpdateState(ctx, configBase, false, false)
+	require.NoError(t, err)
+	terminated := group.NeedUpdate[0].ID
+	require.NoError(t, s.update(ctx, "node-1", func(g *InstanceGroupState) {
+		g.Phase = InstanceGroupPhaseFailed
+		g.Terminated = append(g.Terminated, terminated)
+		g.Error = "interrupted"
+	}))
+
+	c.state, err = openRollingUpdateState(ctx, configBase, true, false)
+	require.NoError(t, err)
+
+	err = c.rollingUpdateInstanceGroup(ctx, group, 0)
+	require.NoError(t, err)
+
+	// The previously terminated instance is still registered in the mock cloud, as we skipped it
+	assertGroupInstanceCount(t, cloud, "node-1", 1)
+	asgGroups, _ := cloud.Autoscaling().DescribeAutoScalingGroups(ctx, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: []string{"node-1"},
+	})
+	for _, asg := range asgGroups.AutoScalingGroups {
+		assert.Len(t, asg.Instances, 1)
+		assert.Equal(t, terminated, *asg.Instances[0].InstanceId)
+	}
+
+	state, err := ReadRollingUpdateState(ctx, configBase)
+	require.NoError(t, err)
+	g := state.InstanceGroups["node-1"]
+	assert.Equal(t, InstanceGroupPhaseCompleted, g.Phase)
+	assert.Len(t, g.Terminated, 3)
+	assert.Empty(t, g.Error)
+
+	// A completed group is not rolled again on resume
+	c.ClusterValidator = &assertNotCalledClusterValidator{T: t}
+	err = c.rollingUpdateInstanceGroup(ctx, group, 0)
+	assert.NoError(t, err)
+}
+
+func TestRollingUpdatePause(t *testing.T) {
+	ctx := context.TODO()
+	c, cloud := getTestSetup()
+	configBase := newTestConfigBase(t)
+
+	paused, err := PauseRollingUpdate(ctx, configBase)
+	require.NoError(t, err)
+	assert.False(t, paused, "no rolling update in progress")
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+
+	c.state, err = openRollingUpdateState(ctx, configBase, false, false)
+	require.NoError(t, err)
+
+	paused, err = PauseRollingUpdate(ctx, configBase)
+	require.NoError(t, err)
+	assert.True(t, paused)
+
+	err = c.rollingUpdateInstanceGroup(ctx, groups["node-1"], 0)
+	assert.ErrorIs(t, err, ErrRollingUpdatePaused)
+	assert.True(t, isExitableError(err))
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+
+	// Resuming clears the pause
+	c.state, err = openRollingUpdateState(ctx, configBase, true, false)
+	require.NoError(t, err)
+	err = c.rollingUpdateInstanceGroup(ctx, groups["node-1"], 0)
+	assert.NoError(t, err)
+	assertGroupInstanceCount(t, cloud, "node-1", 0)
+}
+
+func TestRollingUpdateStopsOutsideMaintenanceWindow(t *testing.T) {
+	c, cloud := getTestSetup()
+
+	// A window that opened an hour ago and closed a minute ago, so it is closed for the rest of the day
+	now := time.Now().UTC()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaintenanceWindow: &kopsapi.MaintenanceWindowSpec{
+			StartTime: now.Add(-time.Hour).Format("15:04"),
+			EndTime:   now.Add(-time.Minute).Format("15:04"),
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+
+	err := c.rollingUpdateInstanceGroup(context.TODO(), groups["node-1"], 0)
+	assert.ErrorIs(t, err, ErrMaintenanceWindowClosed)
+	assert.Contains(t, err.Error(), "--wait-for-window")
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
+
+func TestRollingUpdateWaitsForMaintenanceWindow(t *testing.T) {
+	c, cloud := getTestSetup()
+	c.WaitForWindow = true
+
+	// A window that opened an hour ago and closed a minute ago, so it is closed for the rest of the day
+	now := time.Now().UTC()
+	c.Cluster.Spec.RollingUpdate = &kopsapi.RollingUpdate{
+		MaintenanceWindow: &kopsapi.MaintenanceWindowSpec{
+			StartTime: now.Add(-time.Hour).Format("15:04"),
+			EndTime:   now.Add(-time.Minute).Format("15:04"),
+		},
+	}
+
+	groups := make(map[string]*cloudinstances.CloudInstanceGroup)
+	makeGroup(groups, c.K8sClient, cloud, "node-1", kopsapi.InstanceGroupRoleNode, 3, 3)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+	defer cancel()
+	err := c.rollingUpdateInstanceGroup(ctx, groups["node-1"], 0)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assertGroupInstanceCount(t, cloud, "node-1", 3)
+}
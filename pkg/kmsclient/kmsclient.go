@@ -0,0 +1,92 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package kmsclient builds the clients for the cloud KMS keys that kOps uses,
+// such as the keys that encrypt the state store.
+package kmsclient
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/kms"
+	"google.golang.org/api/cloudkms/v1"
+	"google.golang.org/api/option"
+)
+
+// AWSKeyARN is a parsed AWS KMS key ARN
+type AWSKeyARN struct {
+	Partition string
+	Region    string
+}
+
+// ParseAWSKeyARN parses a key ARN such as arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab
+func ParseAWSKeyARN(keyARN string) (*AWSKeyARN, error) {
+	tokens := strings.SplitN(keyARN, ":", 6)
+	if len(tokens) != 6 || tokens[0] != "arn" || tokens[2] != "kms" || tokens[3] == "" || !strings.HasPrefix(tokens[5], "key/") {
+		return nil, fmt.Errorf("AWS KMS key must be a key ARN, e.g. awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab")
+	}
+	return &AWSKeyARN{Partition: tokens[1], Region: tokens[3]}, nil
+}
+
+// NewAWS builds an AWS KMS client for the region of the key, using the default credential chain:
+// the environment, the shared configuration files, or the instance role.
+func NewAWS(ctx context.Context, keyARN string) (*kms.Client, error) {
+	arn, err := ParseAWSKeyARN(keyARN)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(arn.Region))
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+	return kms.NewFromConfig(config), nil
+}
+
+var gcpKeyNameRegex = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+$`)
+
+// ValidateGCPKeyName checks that name is a Cloud KMS key name, such as projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key
+func ValidateGCPKeyName(name string) error {
+	if !gcpKeyNameRegex.MatchString(name) {
+		return fmt.Errorf("GCP KMS key must be a key name, e.g. gcpkms://projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key")
+	}
+	return nil
+}
+
+var gcpKeyVersionNameRegex = regexp.MustCompile(`^projects/[^/]+/locations/[^/]+/keyRings/[^/]+/cryptoKeys/[^/]+/cryptoKeyVersions/[^/]+$`)
+
+// ValidateGCPKeyVersionName checks that name is a Cloud KMS key version name, such as projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key/cryptoKeyVersions/1
+func ValidateGCPKeyVersionName(name string) error {
+	if !gcpKeyVersionNameRegex.MatchString(name) {
+		return fmt.Errorf("GCP KMS key must be a key version name, e.g. gcpkms://projects/my-project/locations/global/keyRings/my-ring/cryptoKeys/my-key/cryptoKeyVersions/1")
+	}
+	return nil
+}
+
+// NewGCP builds a Cloud KMS client, using the application default credentials:
+// the environment, the gcloud configuration, or the instance service account.
+func NewGCP(ctx context.Context, opts ...option.ClientOption) (*cloudkms.Service, error) {
+	opts = append([]option.ClientOption{option.WithScopes(cloudkms.CloudkmsScope)}, opts...)
+	service, err := cloudkms.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("building Cloud KMS client: %w", err)
+	}
+	return service, nil
+}
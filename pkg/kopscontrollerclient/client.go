@@ -167,6 +167,11 @@ func (b *Client) Query(ctx context.Context, req any, resp any) error {
 		defer response.Body.Close()
 	}
 
+	// kops-controller rate limits bootstrap requests; try again later
+	if response.StatusCode == http.StatusTooManyRequests {
+		return fi.NewTryAgainLaterError(fmt.Sprintf("kops-controller rate limited the request (retry after %q seconds)", response.Header.Get("Retry-After")))
+	}
+
 	if response.StatusCode != http.StatusOK {
 		detail := ""
 		if response.Body != nil {
@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonmanifests
+
+import (
+	"encoding/json"
+	"fmt"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/yaml"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/kubemanifest"
+)
+
+// PatchAddonManifest applies the patches that target the addon to the objects in its manifest.
+// Every patch must match an object, so that a patch does not silently stop applying when the addon changes.
+func PatchAddonManifest(addonName string, patches []kops.AddonPatchSpec, manifest []byte) ([]byte, error) {
+	var addonPatches []kops.AddonPatchSpec
+	for _, patch := range patches {
+		if patch.Addon == addonName {
+			addonPatches = append(addonPatches, patch)
+		}
+	}
+	if len(addonPatches) == 0 {
+		return manifest, nil
+	}
+
+	objects, err := kubemanifest.LoadObjectsFrom(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, patch := range addonPatches {
+		matched := false
+		for i, object := range objects {
+			if !patchTargetMatches(patch.Target, object) {
+				continue
+			}
+			matched = true
+
+			patched, err := patchObject(object, patch)
+			if err != nil {
+				return nil, fmt.Errorf("error patching %s %s/%s in addon %q: %w", object.Kind(), object.GetNamespace(), object.GetName(), addonName, err)
+			}
+			objects[i] = patched
+		}
+		if !matched {
+			return nil, fmt.Errorf("addon %q has no %s", addonName, describePatchTarget(patch.Target))
+		}
+	}
+
+	return objects.ToYAML()
+}
+
+func patchTargetMatches(target kops.AddonPatchTarget, object *kubemanifest.Object) bool {
+	gvk := object.GroupVersionKind()
+	if gvk.Group != target.Group || gvk.Kind != target.Kind {
+		return false
+	}
+	if target.Version != "" && gvk.Version != target.Version {
+		return false
+	}
+	if target.Namespace != "" && object.GetNamespace() != target.Namespace {
+		return false
+	}
+	return object.GetName() == target.Name
+}
+
+func describePatchTarget(target kops.AddonPatchTarget) string {
+	s := target.Kind
+	if target.Group != "" {
+		s += "." + target.Group
+	}
+	if target.Namespace != "" {
+		return s + " " + target.Namespace + "/" + target.Name
+	}
+	return s + " " + target.Name
+}
+
+func patchObject(object *kubemanifest.Object, patch kops.AddonPatchSpec) (*kubemanifest.Object, error) {
+	data := object.ToUnstructured().Object
+
+	if patch.StrategicMerge != "" {
+		patchMap := make(map[string]interface{})
+		if err := yaml.Unmarshal([]byte(patch.StrategicMerge), &patchMap); err != nil {
+			return nil, fmt.Errorf("parsing strategic-merge patch: %w", err)
+		}
+
+		// Strategic merge needs the schema of the kind; other kinds are patched as a JSON merge patch, as kubectl does
+		typed, err := scheme.Scheme.New(object.GroupVersionKind())
+		if err == nil {
+			data, err = strategicpatch.StrategicMergeMapPatch(data, patchMap, typed)
+			if err != nil {
+				return nil, fmt.Errorf("applying strategic-merge patch: %w", err)
+			}
+		} else if runtime.IsNotRegisteredError(err) {
+			data, err = mergePatch(data, patchMap)
+			if err != nil {
+				return nil, fmt.Errorf("applying merge patch: %w", err)
+			}
+		} else {
+			return nil, err
+		}
+	}
+
+	if patch.JSON6902 != "" {
+		operations, err := yaml.YAMLToJSON([]byte(patch.JSON6902))
+		if err != nil {
+			return nil, fmt.Errorf("parsing JSON patch: %w", err)
+		}
+		jsonPatch, err := jsonpatch.DecodePatch(operations)
+		if err != nil {
+			return nil, fmt.Errorf("parsing JSON patch: %w", err)
+		}
+		doc, err := json.Marshal(data)
+		if err != nil {
+			return nil, err
+		}
+		doc, err = jsonPatch.Apply(doc)
+		if err != nil {
+			return nil, fmt.Errorf("applying JSON patch: %w", err)
+		}
+		data = make(map[string]interface{})
+		if err := json.Unmarshal(doc, &data); err != nil {
+			return nil, err
+		}
+	}
+
+	return kubemanifest.NewObject(data), nil
+}
+
+func mergePatch(data, patch map[string]interface{}) (map[string]interface{}, error) {
+	doc, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	patchJSON, err := json.Marshal(patch)
+	if err != nil {
+		return nil, err
+	}
+	doc, err = jsonpatch.MergePatch(doc, patchJSON)
+	if err != nil {
+		return nil, err
+	}
+	merged := make(map[string]interface{})
+	if err := json.Unmarshal(doc, &merged); err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package addonmanifests
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+const patchTestManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: coredns
+  namespace: kube-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: coredns
+        image: registry.k8s.io/coredns/coredns:v1.11.1
+        args:
+        - -conf
+        - /etc/coredns/Corefile
+      tolerations:
+      - key: CriticalAddonsOnly
+        operator: Exists
+---
+apiVersion: monitoring.coreos.com/v1
+kind: PodMonitor
+metadata:
+  name: coredns
+  namespace: kube-system
+spec:
+  podMetricsEndpoints:
+  - port: metrics
+    interval: 30s
+`
+
+func TestPatchAddonManifest(t *testing.T) {
+	grid := []struct {
+		name     string
+		patches  []kops.AddonPatchSpec
+		expected []string
+		error    string
+	}{
+		{
+			name: "strategic merge merges containers by name",
+			patches: []kops.AddonPatchSpec{{
+				Addon:  "coredns.addons.k8s.io",
+				Target: kops.AddonPatchTarget{Group: "apps", Kind: "Deployment", Namespace: "kube-system", Name: "coredns"},
+				StrategicMerge: `
+spec:
+  template:
+    spec:
+      containers:
+      - name: coredns
+        resources:
+          limits:
+            memory: 256Mi
+      tolerations:
+      - key: dedicated
+        operator: Equal
+        value: dns
+`,
+			}},
+			expected: []string{
+				"key: dedicated",
+				"memory: 256Mi",
+				"image: registry.k8s.io/coredns/coredns:v1.11.1",
+			},
+		},
+		{
+			name: "json6902",
+			patches: []kops.AddonPatchSpec{{
+				Addon:  "coredns.addons.k8s.io",
+				Target: kops.AddonPatchTarget{Group: "apps", Version: "v1", Kind: "Deployment", Name: "coredns"},
+				JSON6902: `
+- op: add
+  path: /spec/template/spec/containers/0/args/-
+  value: -dns.port=1053
+`,
+			}},
+			expected: []string{"- -dns.port=1053"},
+		},
+		{
+			name: "kinds without a schema use a merge patch",
+			patches: []kops.AddonPatchSpec{{
+				Addon:          "coredns.addons.k8s.io",
+				Target:         kops.AddonPatchTarget{Group: "monitoring.coreos.com", Kind: "PodMonitor", Name: "coredns"},
+				StrategicMerge: "spec:\n  jobLabel: k8s-app\n",
+			}},
+			expected: []string{"jobLabel: k8s-app", "interval: 30s"},
+		},
+		{
+			name: "patches for other addons are ignored",
+			patches: []kops.AddonPatchSpec{{
+				Addon:          "other.addons.k8s.io",
+				Target:         kops.AddonPatchTarget{Kind: "ConfigMap", Name: "missing"},
+				StrategicMerge: "data:\n  key: value\n",
+			}},
+			expected: []string{"name: coredns"},
+		},
+		{
+			name: "target must exist",
+			patches: []kops.AddonPatchSpec{{
+				Addon:          "coredns.addons.k8s.io",
+				Target:         kops.AddonPatchTarget{Group: "apps", Kind: "Deployment", Namespace: "default", Name: "coredns"},
+				StrategicMerge: "spec:\n  replicas: 3\n",
+			}},
+			error: `addon "coredns.addons.k8s.io" has no Deployment.apps default/coredns`,
+		},
+		{
+			name: "invalid json6902",
+			patches: []kops.AddonPatchSpec{{
+				Addon:    "coredns.addons.k8s.io",
+				Target:   kops.AddonPatchTarget{Group: "apps", Kind: "Deployment", Name: "coredns"},
+				JSON6902: `[{"op": "remove", "path": "/spec/missing"}]`,
+			}},
+			error: "applying JSON patch",
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			actual, err := PatchAddonManifest("coredns.addons.k8s.io", g.patches, []byte(patchTestManifest))
+			if g.error != "" {
+				if err == nil || !strings.Contains(err.Error(), g.error) {
+					t.Fatalf("expected error containing %q, got %v", g.error, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, expected := range g.expected {
+				if !strings.Contains(string(actual), expected) {
+					t.Errorf("expected patched manifest to contain %q, got:\n%s", expected, actual)
+				}
+			}
+		})
+	}
+}
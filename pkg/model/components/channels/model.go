@@ -83,8 +83,9 @@ func (b *ChannelsBuilder) Build(c *fi.CloudupModelBuilderContext) error {
 	return nil
 }
 
-// channelList returns the bootstrap channel plus cluster.Spec.Addons. The bootstrap URL is
-// built via vfs path joining so toolbox_enroll's identity comparison stays byte-identical.
+// channelList returns the bootstrap channel plus the manifests of cluster.Spec.Addons; chart addons
+// are rendered into the bootstrap channel. The bootstrap URL is built via vfs path joining so
+// toolbox_enroll's identity comparison stays byte-identical.
 func (b *ChannelsBuilder) channelList() ([]string, error) {
 	configBase, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.ConfigStore.Base)
 	if err != nil {
@@ -94,6 +95,9 @@ func (b *ChannelsBuilder) channelList() ([]string, error) {
 		configBase.Join("addons", "bootstrap-channel.yaml").Path(),
 	}
 	for i := range b.Cluster.Spec.Addons {
+		if b.Cluster.Spec.Addons[i].Manifest == "" {
+			continue
+		}
 		channels = append(channels, b.Cluster.Spec.Addons[i].Manifest)
 	}
 	return channels, nil
@@ -214,10 +214,16 @@ func (b *AutoscalingGroupModelBuilder) buildInstanceTemplate(c *fi.CloudupModelB
 			t.Scopes = append(t.Scopes, "https://www.googleapis.com/auth/ndev.clouddns.readwrite")
 			t.Tags = append(t.Tags, b.GCETagForRole(kops.InstanceGroupRoleControlPlane))
 			t.Tags = append(t.Tags, b.GCETagForRole("master"))
+			if strings.HasPrefix(b.Cluster.Spec.ConfigStore.EncryptionKey, "gcpkms://") {
+				t.Scopes = append(t.Scopes, "https://www.googleapis.com/auth/cloudkms")
+			}
 
 		case kops.InstanceGroupRoleAPIServer:
 			t.Scopes = append(t.Scopes, "https://www.googleapis.com/auth/ndev.clouddns.readwrite")
 			t.Tags = append(t.Tags, b.GCETagForRole(kops.InstanceGroupRoleAPIServer))
+			if strings.HasPrefix(b.Cluster.Spec.ConfigStore.EncryptionKey, "gcpkms://") {
+				t.Scopes = append(t.Scopes, "https://www.googleapis.com/auth/cloudkms")
+			}
 
 		case kops.InstanceGroupRoleNode:
 			t.Tags = append(t.Tags, b.GCETagForRole(kops.InstanceGroupRoleNode))
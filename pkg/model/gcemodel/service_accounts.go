@@ -17,6 +17,8 @@ limitations under the License.
 package gcemodel
 
 import (
+	"strings"
+
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/upup/pkg/fi"
@@ -108,6 +110,18 @@ func (b *ServiceAccountsBuilder) addInstanceGroupServiceAccountPermissions(c *fi
 			Role:                 s("roles/container.serviceAgent"),
 		})
 
+		// The control plane decrypts secrets and keypairs read from the state store
+		if key, ok := strings.CutPrefix(b.Cluster.Spec.ConfigStore.EncryptionKey, "gcpkms://"); ok {
+			c.AddTask(&gcetasks.CryptoKeyIAMBinding{
+				Name:      s("serviceaccount-control-plane-state-decrypter"),
+				Lifecycle: b.Lifecycle,
+
+				CryptoKey:            s(key),
+				MemberServiceAccount: serviceAccount,
+				Role:                 s("roles/cloudkms.cryptoKeyDecrypter"),
+			})
+		}
+
 	case kops.InstanceGroupRoleNode:
 		// Worker nodes need no project-level role.
 	}
@@ -393,6 +393,8 @@ func (r *NodeRoleAPIServer) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
 	}
 
+	b.addStateEncryptionKeyPermissions(p)
+
 	// The API server role may host a kms-plugin sidecar wired to the instance role
 	// when EncryptionConfig is enabled; bypass kms:ViaService so that direct KMS
 	// calls from kube-apiserver are not denied.
@@ -443,6 +445,8 @@ func (r *NodeRoleMaster) BuildAWSPolicy(b *PolicyBuilder) (*Policy, error) {
 		return nil, fmt.Errorf("failed to generate AWS IAM S3 access statements: %v", err)
 	}
 
+	b.addStateEncryptionKeyPermissions(p)
+
 	// The combined master role hosts kube-apiserver, so a user-supplied kms-plugin
 	// for EncryptionConfig will call KMS directly from this role. Bypass ViaService
 	// in that case.
@@ -620,6 +624,8 @@ func (b *PolicyBuilder) AddS3Permissions(p *Policy) error {
 			iamS3path := "placeholder-read-bucket/" + strings.TrimPrefix(path.Path(), "file://")
 			b.buildS3GetStatements(p, iamS3path)
 			s3Buckets.Insert("placeholder-read-bucket")
+		case *vfs.VaultPath:
+			// Access to vault is granted by vault policies, not IAM
 		default:
 			// We could implement this approach, but it seems better to
 			// get all clouds using cluster-readable storage
@@ -1469,6 +1475,20 @@ func AddKubeRouterPermissions(b *PolicyBuilder, p *Policy) {
 	)
 }
 
+// addStateEncryptionKeyPermissions allows the control plane to decrypt the secrets and private keys
+// that were encrypted in the state store with an AWS KMS key.
+func (b *PolicyBuilder) addStateEncryptionKeyPermissions(p *Policy) {
+	keyARN, found := strings.CutPrefix(b.Cluster.Spec.ConfigStore.EncryptionKey, "awskms://")
+	if !found {
+		return
+	}
+	p.Statement = append(p.Statement, &Statement{
+		Effect:   StatementEffectAllow,
+		Action:   stringorset.String("kms:Decrypt"),
+		Resource: stringorset.String(keyARN),
+	})
+}
+
 // addKMSIAMPolicies grants the KMS permissions for customer managed keys used for EBS encryption
 // (etcd, EBS CSI, and Karpenter root volumes) and state store SSE-KMS. AWS services make the KMS
 // calls on the role's behalf, so kms:CreateGrant requires kms:GrantIsForAWSResource and all other
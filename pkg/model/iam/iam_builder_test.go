@@ -428,6 +428,89 @@ func TestAddKMSIAMPolicies(t *testing.T) {
 	}
 }
 
+func TestAddStateEncryptionKeyPermissions(t *testing.T) {
+	cluster := testutils.BuildMinimalClusterAWS("c.example.com")
+	b := &PolicyBuilder{Cluster: cluster}
+
+	p := NewPolicy("c.example.com", "aws", "us-east-1")
+	b.addStateEncryptionKeyPermissions(p)
+	if len(p.Statement) != 0 {
+		t.Errorf("expected no statements without an encryption key, got %d", len(p.Statement))
+	}
+
+	cluster.Spec.ConfigStore.EncryptionKey = "awskms://arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+	b.addStateEncryptionKeyPermissions(p)
+	got, err := p.AsJSON()
+	if err != nil {
+		t.Fatalf("failed to render policy: %v", err)
+	}
+	want := `{
+  "Statement": [
+    {
+      "Action": "kms:Decrypt",
+      "Effect": "Allow",
+      "Resource": "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+    }
+  ],
+  "Version": "2012-10-17"
+}`
+	if got != want {
+		t.Errorf("addStateEncryptionKeyPermissions rendered:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+// TestStateEncryptionKeyRoles checks that every role reading the state store can decrypt it.
+func TestStateEncryptionKeyRoles(t *testing.T) {
+	keyARN := "arn:aws:kms:us-east-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab"
+
+	grid := []struct {
+		Role          Subject
+		ExpectDecrypt bool
+	}{
+		{Role: &NodeRoleMaster{}, ExpectDecrypt: true},
+		{Role: &NodeRoleAPIServer{}, ExpectDecrypt: true},
+		{Role: &NodeRoleNode{}, ExpectDecrypt: false},
+		{Role: &NodeRoleBastion{}, ExpectDecrypt: false},
+	}
+	for _, g := range grid {
+		cluster := testutils.BuildMinimalClusterAWS("c.example.com")
+		cluster.Spec.ConfigStore.Base = "s3://kops-tests/c.example.com"
+		cluster.Spec.ConfigStore.EncryptionKey = "awskms://" + keyARN
+
+		b := &PolicyBuilder{
+			Cluster:   cluster,
+			Role:      g.Role,
+			Region:    "us-east-1",
+			Partition: "aws",
+		}
+		p, err := b.BuildAWSPolicy()
+		if err != nil {
+			t.Fatalf("%T: failed to build policy: %v", g.Role, err)
+		}
+
+		statePaths, err := ReadableStatePaths(cluster, g.Role)
+		if err != nil {
+			t.Fatalf("%T: failed to get readable state paths: %v", g.Role, err)
+		}
+		readsState := len(statePaths) != 0
+		if readsState != g.ExpectDecrypt {
+			t.Errorf("%T: reads state store = %v, but expected decrypt = %v", g.Role, readsState, g.ExpectDecrypt)
+		}
+
+		canDecrypt := false
+		if p != nil {
+			for _, statement := range p.Statement {
+				if statement.Effect == StatementEffectAllow && slices.Contains(statement.Action.Value(), "kms:Decrypt") && slices.Contains(statement.Resource.Value(), keyARN) {
+					canDecrypt = true
+				}
+			}
+		}
+		if canDecrypt != g.ExpectDecrypt {
+			t.Errorf("%T: kms:Decrypt on the state encryption key = %v, expected %v", g.Role, canDecrypt, g.ExpectDecrypt)
+		}
+	}
+}
+
 func TestKmsViaServices(t *testing.T) {
 	// Per AWS KMS docs, kms:ViaService uses the .amazonaws.com suffix in all
 	// partitions, so the result depends only on the region.
@@ -551,6 +551,18 @@ func buildEnvironmentVariables(cluster *kops.Cluster, ig *kops.InstanceGroup) (m
 		}
 	}
 
+	// Pass in the Vault settings when the keypairs or secrets are stored in Vault; the AppRole secret ID is never
+	// written to the user-data, it is read from a file provisioned on the host
+	if cluster.UsesVault() && ig.IsControlPlane() {
+		vaultEnv, err := vfs.VaultControlPlaneEnv()
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range vaultEnv {
+			env[k] = fmt.Sprintf("'%s'", v)
+		}
+	}
+
 	if cluster.GetCloudProvider() == kops.CloudProviderOpenstack {
 
 		osEnvs := []string{
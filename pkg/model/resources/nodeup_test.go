@@ -23,6 +23,7 @@ import (
 	"strings"
 	"testing"
 
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/architectures"
@@ -363,6 +364,43 @@ func Test_S3DownloadRequiresRegion(t *testing.T) {
 	}
 }
 
+func Test_VaultEnvironmentVariables(t *testing.T) {
+	t.Setenv("S3_ENDPOINT", "")
+	t.Setenv("VAULT_ADDR", "https://vault.example.com:8200")
+	t.Setenv("VAULT_ROLE_ID", "kops-role")
+	t.Setenv("VAULT_SECRET_ID", "kops-secret")
+	t.Setenv("VAULT_CACERT", "")
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.ConfigStore.Keypairs = "vault://secret/kops/pki"
+	controlPlane := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleControlPlane}}
+	node := &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{Role: kops.InstanceGroupRoleNode}}
+
+	env, err := buildEnvironmentVariables(cluster, controlPlane)
+	if err != nil {
+		t.Fatalf("building environment variables: %v", err)
+	}
+	if env["VAULT_ADDR"] != "'https://vault.example.com:8200'" || env["VAULT_ROLE_ID"] != "'kops-role'" || env["VAULT_SECRET_ID_FILE"] != "'/etc/kubernetes/vault/secret-id'" {
+		t.Errorf("control plane did not get the vault settings: %v", env)
+	}
+	if _, found := env["VAULT_SECRET_ID"]; found {
+		t.Errorf("the vault secret ID should not be in the user-data: %v", env)
+	}
+
+	env, err = buildEnvironmentVariables(cluster, node)
+	if err != nil {
+		t.Fatalf("building environment variables: %v", err)
+	}
+	if _, found := env["VAULT_ROLE_ID"]; found {
+		t.Errorf("nodes should not get the vault credentials: %v", env)
+	}
+
+	t.Setenv("VAULT_ROLE_ID", "")
+	if _, err := buildEnvironmentVariables(cluster, controlPlane); err == nil {
+		t.Errorf("expected an error building the control plane environment without AppRole credentials")
+	}
+}
+
 func verifyShellSyntax(t *testing.T, script string) {
 	t.Helper()
 
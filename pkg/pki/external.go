@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pki
+
+import (
+	"context"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// externalPrivateKeyPEMType is the PEM block type of a reference to a private key held by an external signer.
+// The block holds the public key, with the URI of the private key in a header.
+const externalPrivateKeyPEMType = "KOPS EXTERNAL PRIVATE KEY"
+
+// ExternalSignerProvider returns the signer for a private key held outside of kOps, such as in an HSM or a cloud KMS.
+type ExternalSignerProvider func(ctx context.Context, keyURI string) (crypto.Signer, error)
+
+var (
+	externalSignersMutex sync.Mutex
+	externalSigners      = make(map[string]ExternalSignerProvider)
+)
+
+// RegisterExternalSigner registers the provider of signers for key URIs with the given scheme.
+func RegisterExternalSigner(scheme string, provider ExternalSignerProvider) {
+	externalSignersMutex.Lock()
+	defer externalSignersMutex.Unlock()
+
+	externalSigners[scheme] = provider
+}
+
+// ExternalKeyScheme returns the scheme of a key URI, e.g. "awskms" for awskms://arn:aws:kms:... or "pkcs11" for pkcs11:token=...
+func ExternalKeyScheme(keyURI string) string {
+	scheme, _, found := strings.Cut(keyURI, ":")
+	if !found {
+		return ""
+	}
+	return scheme
+}
+
+func newExternalSigner(ctx context.Context, keyURI string) (crypto.Signer, error) {
+	scheme := ExternalKeyScheme(keyURI)
+
+	externalSignersMutex.Lock()
+	provider := externalSigners[scheme]
+	externalSignersMutex.Unlock()
+
+	if provider == nil {
+		return nil, fmt.Errorf("unsupported key URI %q: no external signer for scheme %q", keyURI, scheme)
+	}
+	signer, err := provider(ctx, keyURI)
+	if err != nil {
+		return nil, fmt.Errorf("building signer for %q: %w", keyURI, err)
+	}
+	return signer, nil
+}
+
+// NewExternalPrivateKey returns a private key held by the external signer identified by keyURI.
+// Only a reference to the key is serialized; signing calls the external signer.
+func NewExternalPrivateKey(ctx context.Context, keyURI string) (*PrivateKey, error) {
+	signer, err := newExternalSigner(ctx, keyURI)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{Key: &externalKey{
+		keyURI: keyURI,
+		public: signer.Public(),
+		signer: signer,
+	}}, nil
+}
+
+// ExternalKeyURI returns the URI of the key if it is held by an external signer, or "" otherwise.
+func (k *PrivateKey) ExternalKeyURI() string {
+	if k == nil {
+		return ""
+	}
+	if key, ok := k.Key.(*externalKey); ok {
+		return key.keyURI
+	}
+	return ""
+}
+
+// externalKey is a crypto.Signer that signs through an external signer.
+// The signer is built the first time it is needed, so that parsing a keyset doesn't contact the external signer.
+type externalKey struct {
+	keyURI string
+	public crypto.PublicKey
+
+	mutex  sync.Mutex
+	signer crypto.Signer
+}
+
+var _ crypto.Signer = &externalKey{}
+
+// Public implements crypto.Signer
+func (k *externalKey) Public() crypto.PublicKey {
+	return k.public
+}
+
+// Sign implements crypto.Signer
+func (k *externalKey) Sign(rand io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	signer, err := k.getSigner()
+	if err != nil {
+		return nil, err
+	}
+	return signer.Sign(rand, digest, opts)
+}
+
+func (k *externalKey) getSigner() (crypto.Signer, error) {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+
+	if k.signer != nil {
+		return k.signer, nil
+	}
+
+	signer, err := newExternalSigner(context.TODO(), k.keyURI)
+	if err != nil {
+		return nil, err
+	}
+	if public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool }); !ok || !public.Equal(k.public) {
+		return nil, fmt.Errorf("public key of %q does not match the keypair", k.keyURI)
+	}
+	k.signer = signer
+	return signer, nil
+}
+
+func (k *externalKey) writeTo(w io.Writer) error {
+	b, err := x509.MarshalPKIXPublicKey(k.public)
+	if err != nil {
+		return fmt.Errorf("encoding public key of %q: %w", k.keyURI, err)
+	}
+	return pem.Encode(w, &pem.Block{
+		Type:    externalPrivateKeyPEMType,
+		Headers: map[string]string{"URI": k.keyURI},
+		Bytes:   b,
+	})
+}
+
+func parseExternalKey(block *pem.Block) (*externalKey, error) {
+	keyURI := block.Headers["URI"]
+	if keyURI == "" {
+		return nil, fmt.Errorf("external private key has no URI")
+	}
+	public, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing public key of %q: %w", keyURI, err)
+	}
+	return &externalKey{keyURI: keyURI, public: public}, nil
+}
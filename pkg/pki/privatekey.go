@@ -164,6 +164,10 @@ func (k *PrivateKey) WriteTo(w io.Writer) (int64, error) {
 		if err := pem.Encode(&data, &pem.Block{Type: "EC PRIVATE KEY", Bytes: b}); err != nil {
 			return 0, fmt.Errorf("error encoding ECDSA private key: %w", err)
 		}
+	case *externalKey:
+		if err := pk.writeTo(&data); err != nil {
+			return 0, err
+		}
 	default:
 		return 0, fmt.Errorf("unknown private key type: %T", k.Key)
 	}
@@ -204,6 +208,9 @@ func parsePEMPrivateKey(pemData []byte) (crypto.Signer, error) {
 				return nil, err
 			}
 			return k.(crypto.Signer), nil
+		case externalPrivateKeyPEMType:
+			klog.V(10).Infof("Parsing pem block: %q", block.Type)
+			return parseExternalKey(block)
 		default:
 			klog.Infof("Ignoring unexpected PEM block: %q", block.Type)
 		}
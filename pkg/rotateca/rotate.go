@@ -0,0 +1,329 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotateca
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// Cluster performs the operations on the cluster between the keyset changes of a CA rotation.
+type Cluster interface {
+	// Reconcile applies the current keysets to the cloud resources and replaces the instances
+	// that are not running the current configuration, control plane first.
+	Reconcile(ctx context.Context) error
+	// Verify returns an error unless every node is running the current configuration
+	// and trusts exactly the trusted keypairs of the given keysets.
+	Verify(ctx context.Context, keysets map[string]*fi.Keyset) error
+}
+
+// Rotator rotates the keypairs of one or more keysets: it stages a new keypair in each keyset,
+// promotes it to primary and then distrusts the previous keypairs, rolling the cluster after each
+// phase. Progress is recorded under ConfigBase, so that an interrupted rotation can be resumed.
+type Rotator struct {
+	// ConfigBase is the cluster's config base in the state store.
+	ConfigBase vfs.Path
+	// KeyStore holds the keysets being rotated.
+	KeyStore fi.CAStore
+	// Cluster updates, rolls and verifies the cluster.
+	Cluster Cluster
+	// Lock, if set, is held while the keysets are changed.
+	// The keysets are changed with the returned context, which is cancelled if the lock is lost.
+	Lock func(ctx context.Context) (context.Context, func(), error)
+	// Out receives progress messages.
+	Out io.Writer
+
+	// StopAfter, if set, stops the rotation once the given phase has been verified.
+	StopAfter Phase
+	// VerifyTimeout is how long to keep retrying verification of the cluster after it has been rolled.
+	VerifyTimeout time.Duration
+	// VerifyInterval is the time between verification attempts.
+	VerifyInterval time.Duration
+}
+
+// Start starts a rotation of the named keysets.
+func (r *Rotator) Start(ctx context.Context, keysets []string) error {
+	if len(keysets) == 0 {
+		return fmt.Errorf("no keysets to rotate")
+	}
+	existing, err := ReadRotationState(ctx, r.ConfigBase)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("%w (keysets %s, started at %s); use --resume to continue it", ErrRotationInProgress, strings.Join(existing.KeysetNames(), ","), existing.StartedAt.Format(time.RFC3339))
+	}
+
+	s, err := createRotationState(ctx, r.ConfigBase, keysets)
+	if err != nil {
+		return err
+	}
+	return r.run(ctx, s)
+}
+
+// Resume continues the rotation in progress.
+// If keysets is not empty, it must match the keysets of the rotation in progress.
+func (r *Rotator) Resume(ctx context.Context, keysets []string) error {
+	existing, err := ReadRotationState(ctx, r.ConfigBase)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		if len(keysets) == 0 {
+			return fmt.Errorf("no CA rotation in progress to resume")
+		}
+		klog.Infof("No CA rotation in progress to resume; starting a new rotation")
+		return r.Start(ctx, keysets)
+	}
+	if len(keysets) != 0 && !slices.Equal(sortedCopy(keysets), sortedCopy(existing.KeysetNames())) {
+		return fmt.Errorf("the CA rotation in progress is of keysets %s, not %s", strings.Join(existing.KeysetNames(), ","), strings.Join(keysets, ","))
+	}
+
+	klog.Infof("Resuming CA rotation started at %s", existing.StartedAt.Format(time.RFC3339))
+	s := &rotationStateStore{
+		path:  r.ConfigBase.Join(PathRotationState),
+		state: *existing,
+	}
+	return r.run(ctx, s)
+}
+
+func (r *Rotator) run(ctx context.Context, s *rotationStateStore) error {
+	for {
+		phase := s.state.Phase
+		if err := r.runPhase(ctx, s); err != nil {
+			s.state.Error = err.Error()
+			if saveErr := s.save(ctx); saveErr != nil {
+				klog.Warningf("%v", saveErr)
+			}
+			return fmt.Errorf("CA rotation phase %q: %w", phase, err)
+		}
+
+		next := phase.next()
+		if next == "" {
+			fmt.Fprintf(r.Out, "CA rotation of %s complete\n", strings.Join(s.state.KeysetNames(), ","))
+			return s.complete(ctx)
+		}
+
+		s.state.Phase = next
+		s.state.Applied = false
+		s.state.Error = ""
+		if err := s.save(ctx); err != nil {
+			return err
+		}
+
+		if phase == r.StopAfter {
+			fmt.Fprintf(r.Out, "Stopping after the %s phase; use --resume to continue with the %s phase\n", phase, next)
+			return nil
+		}
+	}
+}
+
+// runPhase changes the keysets for the current phase, if that has not already been done,
+// then rolls and verifies the cluster.
+func (r *Rotator) runPhase(ctx context.Context, s *rotationStateStore) error {
+	phase := s.state.Phase
+
+	if !s.state.Applied {
+		if err := r.apply(ctx, s); err != nil {
+			return err
+		}
+		s.state.Applied = true
+		if err := s.save(ctx); err != nil {
+			return err
+		}
+	}
+
+	fmt.Fprintf(r.Out, "Updating and rolling the cluster after the %s phase\n", phase)
+	if err := r.Cluster.Reconcile(ctx); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(r.Out, "Verifying that every node has picked up the %s phase\n", phase)
+	return r.verify(ctx, s)
+}
+
+// apply makes the keyset changes of the current phase.
+func (r *Rotator) apply(ctx context.Context, s *rotationStateStore) error {
+	if r.Lock != nil {
+		lockedCtx, unlock, err := r.Lock(ctx)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+		ctx = lockedCtx
+	}
+
+	for _, k := range s.state.Keysets {
+		keyset, err := r.KeyStore.FindKeyset(ctx, k.Name)
+		if err != nil {
+			return fmt.Errorf("reading keyset %q: %w", k.Name, err)
+		}
+		if keyset == nil || keyset.Primary == nil {
+			return fmt.Errorf("keyset %q not found", k.Name)
+		}
+
+		switch s.state.Phase {
+		case PhaseStage:
+			if k.Staged != "" {
+				continue
+			}
+			k.PreviousPrimary = keyset.Primary.Id
+			item, err := stageKeypair(ctx, k.Name, keyset)
+			if err != nil {
+				return fmt.Errorf("staging keypair for %q: %w", k.Name, err)
+			}
+			if err := r.KeyStore.StoreKeyset(ctx, k.Name, keyset); err != nil {
+				return fmt.Errorf("writing keyset %q: %w", k.Name, err)
+			}
+			k.Staged = item.Id
+			// Record each staged keypair as we go, so a resumed rotation does not stage another one
+			if err := s.save(ctx); err != nil {
+				return err
+			}
+			fmt.Fprintf(r.Out, "Staged %s %s\n", k.Name, item.Id)
+
+		case PhasePromote:
+			item := keyset.Items[k.Staged]
+			if item == nil {
+				return fmt.Errorf("staged keypair %s not found in keyset %q", k.Staged, k.Name)
+			}
+			if item.DistrustTimestamp != nil {
+				return fmt.Errorf("staged keypair %s in keyset %q is distrusted", k.Staged, k.Name)
+			}
+			if keyset.Primary.Id == item.Id {
+				continue
+			}
+			keyset.Primary = item
+			if err := r.KeyStore.StoreKeyset(ctx, k.Name, keyset); err != nil {
+				return fmt.Errorf("writing keyset %q: %w", k.Name, err)
+			}
+			fmt.Fprintf(r.Out, "Promoted %s %s\n", k.Name, item.Id)
+
+		case PhaseDistrust:
+			if keyset.Primary.Id != k.Staged {
+				return fmt.Errorf("primary keypair of keyset %q is %s, not the staged keypair %s", k.Name, keyset.Primary.Id, k.Staged)
+			}
+			ids := distrustOlderKeypairs(keyset, time.Now().UTC().Round(0))
+			if len(ids) == 0 {
+				continue
+			}
+			if err := r.KeyStore.StoreKeyset(ctx, k.Name, keyset); err != nil {
+				return fmt.Errorf("writing keyset %q: %w", k.Name, err)
+			}
+			fmt.Fprintf(r.Out, "Distrusted %s %s\n", k.Name, strings.Join(ids, ","))
+
+		default:
+			return fmt.Errorf("unknown phase %q", s.state.Phase)
+		}
+	}
+	return nil
+}
+
+// verify retries verification of the cluster until it succeeds or VerifyTimeout passes.
+func (r *Rotator) verify(ctx context.Context, s *rotationStateStore) error {
+	deadline := time.Now().Add(r.VerifyTimeout)
+	for {
+		keysets := make(map[string]*fi.Keyset)
+		for _, k := range s.state.Keysets {
+			keyset, err := r.KeyStore.FindKeyset(ctx, k.Name)
+			if err != nil {
+				return fmt.Errorf("reading keyset %q: %w", k.Name, err)
+			}
+			if keyset == nil {
+				return fmt.Errorf("keyset %q not found", k.Name)
+			}
+			keysets[k.Name] = keyset
+		}
+
+		err := r.Cluster.Verify(ctx, keysets)
+		if err == nil {
+			return nil
+		}
+		if !time.Now().Before(deadline) {
+			return fmt.Errorf("cluster did not verify: %w", err)
+		}
+		klog.Infof("Cluster did not yet verify: %v", err)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(r.VerifyInterval):
+		}
+	}
+}
+
+// stageKeypair adds a new, non-primary keypair to the keyset.
+func stageKeypair(ctx context.Context, name string, keyset *fi.Keyset) (*fi.KeysetItem, error) {
+	privateKey, err := pki.GeneratePrivateKey()
+	if err != nil {
+		return nil, fmt.Errorf("error generating private key: %w", err)
+	}
+
+	serial := pki.BuildPKISerial(time.Now().UnixNano())
+	req := pki.IssueCertRequest{
+		Type:       "ca",
+		Subject:    pkix.Name{CommonName: name, SerialNumber: serial.String()},
+		Serial:     serial,
+		PrivateKey: privateKey,
+	}
+	cert, _, _, err := pki.IssueCert(ctx, &req, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing certificate: %w", err)
+	}
+
+	return keyset.AddItem(cert, privateKey, false)
+}
+
+// distrustOlderKeypairs distrusts the trusted keypairs that are older than the primary,
+// returning their IDs.
+func distrustOlderKeypairs(keyset *fi.Keyset, now time.Time) []string {
+	primarySerial := keyset.Primary.Certificate.Certificate.SerialNumber
+	var ids []string
+	for id, item := range keyset.Items {
+		if item.DistrustTimestamp == nil && item.Certificate.Certificate.SerialNumber.Cmp(primarySerial) < 0 {
+			item.DistrustTimestamp = &now
+			ids = append(ids, id)
+		}
+	}
+	slices.SortFunc(ids, func(a, b string) int {
+		if fi.KeysetItemIdOlder(a, b) {
+			return -1
+		}
+		if fi.KeysetItemIdOlder(b, a) {
+			return 1
+		}
+		return 0
+	})
+	return ids
+}
+
+func sortedCopy(s []string) []string {
+	s = slices.Clone(s)
+	slices.Sort(s)
+	return s
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotateca
+
+import (
+	"context"
+	"crypto/x509/pkix"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/pki"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// fakeCluster simulates nodes that pick up the trusted keypairs of each keyset when the cluster is rolled.
+type fakeCluster struct {
+	keyStore fi.CAStore
+	// nodeBundles holds the trust bundle of each keyset, as seen by the nodes
+	nodeBundles map[string][]byte
+	reconciles  int
+	// failReconcile, if set, is returned by the next Reconcile
+	failReconcile error
+}
+
+func (c *fakeCluster) Reconcile(ctx context.Context) error {
+	if err := c.failReconcile; err != nil {
+		c.failReconcile = nil
+		return err
+	}
+	c.reconciles++
+	keysets, err := c.keyStore.ListKeysets()
+	if err != nil {
+		return err
+	}
+	c.nodeBundles = make(map[string][]byte)
+	for name, keyset := range keysets {
+		bundle, err := keyset.ToCertificateBytes()
+		if err != nil {
+			return err
+		}
+		c.nodeBundles[name] = bundle
+	}
+	return nil
+}
+
+func (c *fakeCluster) Verify(ctx context.Context, keysets map[string]*fi.Keyset) error {
+	var errs []error
+	for name, keyset := range keysets {
+		errs = append(errs, VerifyTrustBundle(name, c.nodeBundles[name], keyset))
+	}
+	return errors.Join(errs...)
+}
+
+func newTestRotator(t *testing.T, keysets ...string) (*Rotator, *fakeCluster, map[string]string) {
+	ctx := context.TODO()
+
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	configBase, err := vfsContext.BuildVfsPath("memfs://tests/test.k8s.local")
+	require.NoError(t, err)
+
+	keyStore := fi.NewVFSCAStore(&kops.Cluster{}, configBase.Join("pki"))
+	originals := make(map[string]string)
+	for i, name := range keysets {
+		cert, privateKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+			Type:    "ca",
+			Subject: pkix.Name{CommonName: name},
+			Serial:  big.NewInt(int64(i + 1)),
+		}, nil)
+		require.NoError(t, err)
+		keyset, err := fi.NewKeyset(cert, privateKey)
+		require.NoError(t, err)
+		require.NoError(t, keyStore.StoreKeyset(ctx, name, keyset))
+		originals[name] = keyset.Primary.Id
+	}
+
+	cluster := &fakeCluster{keyStore: keyStore}
+	require.NoError(t, cluster.Reconcile(ctx))
+	cluster.reconciles = 0
+
+	r := &Rotator{
+		ConfigBase: configBase,
+		KeyStore:   keyStore,
+		Cluster:    cluster,
+		Out:        io.Discard,
+	}
+	return r, cluster, originals
+}
+
+func findKeyset(t *testing.T, r *Rotator, name string) *fi.Keyset {
+	keyset, err := r.KeyStore.FindKeyset(context.TODO(), name)
+	require.NoError(t, err)
+	require.NotNil(t, keyset)
+	return keyset
+}
+
+func TestRotateKeysets(t *testing.T) {
+	ctx := context.TODO()
+	r, cluster, originals := newTestRotator(t, "kubernetes-ca", "service-account")
+
+	require.NoError(t, r.Start(ctx, []string{"kubernetes-ca", "service-account"}))
+
+	assert.Equal(t, 3, cluster.reconciles, "the cluster is rolled once per phase")
+	for name, original := range originals {
+		keyset := findKeyset(t, r, name)
+		assert.NotEqual(t, original, keyset.Primary.Id, "%s primary", name)
+		assert.NotNil(t, keyset.Items[original].DistrustTimestamp, "%s original keypair is distrusted", name)
+		assert.Nil(t, keyset.Primary.DistrustTimestamp, "%s primary is trusted", name)
+	}
+
+	state, err := ReadRotationState(ctx, r.ConfigBase)
+	require.NoError(t, err)
+	assert.Nil(t, state, "state is removed once the rotation completes")
+}
+
+func TestRotateStopAfterAndResume(t *testing.T) {
+	ctx := context.TODO()
+	r, cluster, originals := newTestRotator(t, "kubernetes-ca")
+	r.StopAfter = PhaseStage
+
+	require.NoError(t, r.Start(ctx, []string{"kubernetes-ca"}))
+
+	keyset := findKeyset(t, r, "kubernetes-ca")
+	assert.Equal(t, originals["kubernetes-ca"], keyset.Primary.Id, "primary is unchanged after staging")
+	assert.Len(t, keyset.Items, 2)
+	assert.Equal(t, 1, cluster.reconciles)
+
+	state, err := ReadRotationState(ctx, r.ConfigBase)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, PhasePromote, state.Phase)
+	assert.False(t, state.Applied)
+	staged := state.Keysets[0].Staged
+	assert.NotEmpty(t, staged)
+
+	err = r.Start(ctx, []string{"kubernetes-ca"})
+	assert.ErrorIs(t, err, ErrRotationInProgress)
+
+	err = r.Resume(ctx, []string{"service-account"})
+	assert.ErrorContains(t, err, "the CA rotation in progress is of keysets kubernetes-ca")
+
+	r.StopAfter = ""
+	require.NoError(t, r.Resume(ctx, nil))
+
+	keyset = findKeyset(t, r, "kubernetes-ca")
+	assert.Equal(t, staged, keyset.Primary.Id)
+	assert.Len(t, keyset.Items, 2, "resuming does not stage another keypair")
+	assert.Equal(t, 3, cluster.reconciles)
+}
+
+func TestRotateResumeAfterFailedRoll(t *testing.T) {
+	ctx := context.TODO()
+	r, cluster, originals := newTestRotator(t, "kubernetes-ca")
+
+	cluster.failReconcile = errors.New("rolling update failed")
+	err := r.Start(ctx, []string{"kubernetes-ca"})
+	assert.ErrorContains(t, err, "rolling update failed")
+
+	state, err := ReadRotationState(ctx, r.ConfigBase)
+	require.NoError(t, err)
+	require.NotNil(t, state)
+	assert.Equal(t, PhaseStage, state.Phase)
+	assert.True(t, state.Applied)
+	assert.Equal(t, "rolling update failed", state.Error)
+
+	// The staged keypair is not trusted by the nodes until the cluster has been rolled
+	keyset := findKeyset(t, r, "kubernetes-ca")
+	assert.Error(t, cluster.Verify(ctx, map[string]*fi.Keyset{"kubernetes-ca": keyset}))
+
+	require.NoError(t, r.Resume(ctx, nil))
+
+	keyset = findKeyset(t, r, "kubernetes-ca")
+	assert.Len(t, keyset.Items, 2, "resuming does not stage another keypair")
+	assert.NotEqual(t, originals["kubernetes-ca"], keyset.Primary.Id)
+}
+
+func TestRotateFailsVerification(t *testing.T) {
+	ctx := context.TODO()
+	r, _, _ := newTestRotator(t, "kubernetes-ca")
+	// Nodes that never pick up the staged keypair
+	r.Cluster = &staleCluster{r.Cluster.(*fakeCluster)}
+
+	err := r.Start(ctx, []string{"kubernetes-ca"})
+	assert.ErrorContains(t, err, "is not yet trusted")
+
+	keyset := findKeyset(t, r, "kubernetes-ca")
+	assert.Len(t, keyset.Items, 2)
+	for _, item := range keyset.Items {
+		assert.Nil(t, item.DistrustTimestamp, "nothing is promoted or distrusted after failed verification")
+	}
+}
+
+// staleCluster is a fakeCluster whose rolling updates have no effect.
+type staleCluster struct {
+	*fakeCluster
+}
+
+func (c *staleCluster) Reconcile(ctx context.Context) error {
+	return nil
+}
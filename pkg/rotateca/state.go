@@ -0,0 +1,194 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotateca
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// PathRotationState is the path (relative to the cluster's config base) where the progress of
+// an in-progress CA rotation is recorded.
+const PathRotationState = "rotate-ca/state"
+
+// ErrRotationInProgress is returned when a rotation is started while another one is recorded as in progress.
+var ErrRotationInProgress = errors.New("a CA rotation is already in progress")
+
+// Phase is a phase of a CA rotation.
+// Each phase changes the keysets, then updates and rolls the cluster and verifies that every node has picked up the change.
+type Phase string
+
+const (
+	// PhaseStage adds a new, not yet primary, keypair to each keyset so that it becomes trusted.
+	PhaseStage Phase = "stage"
+	// PhasePromote makes the staged keypair of each keyset the primary, so that it is used for signing.
+	PhasePromote Phase = "promote"
+	// PhaseDistrust distrusts the keypairs of each keyset that are older than the primary.
+	PhaseDistrust Phase = "distrust"
+)
+
+// Phases lists the phases of a CA rotation in the order they are performed.
+var Phases = []Phase{PhaseStage, PhasePromote, PhaseDistrust}
+
+// ParsePhase parses the name of a phase.
+func ParsePhase(s string) (Phase, error) {
+	for _, phase := range Phases {
+		if string(phase) == s {
+			return phase, nil
+		}
+	}
+	return "", fmt.Errorf("unknown phase %q", s)
+}
+
+// next returns the phase after p, or "" if p is the last phase.
+func (p Phase) next() Phase {
+	for i, phase := range Phases {
+		if phase == p && i+1 < len(Phases) {
+			return Phases[i+1]
+		}
+	}
+	return ""
+}
+
+// RotationState is the persisted progress of a CA rotation.
+type RotationState struct {
+	// StartedAt is when the rotation was first started.
+	StartedAt time.Time `json:"startedAt"`
+	// UpdatedAt is when the state was last written.
+	UpdatedAt time.Time `json:"updatedAt"`
+	// Phase is the phase in progress.
+	Phase Phase `json:"phase"`
+	// Applied is true once the keyset changes of Phase have been written to the keystore;
+	// what remains of the phase is to update and roll the cluster and verify the nodes.
+	Applied bool `json:"applied,omitempty"`
+	// Keysets holds the progress of each keyset being rotated.
+	Keysets []*KeysetState `json:"keysets"`
+	// Error is the error that stopped the rotation, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// KeysetState is the persisted progress of the rotation of a single keyset.
+type KeysetState struct {
+	// Name is the name of the keyset.
+	Name string `json:"name"`
+	// PreviousPrimary is the ID of the keypair that was primary when the rotation started.
+	PreviousPrimary string `json:"previousPrimary,omitempty"`
+	// Staged is the ID of the keypair added by the rotation.
+	Staged string `json:"staged,omitempty"`
+}
+
+// KeysetNames returns the names of the keysets being rotated.
+func (s *RotationState) KeysetNames() []string {
+	var names []string
+	for _, k := range s.Keysets {
+		names = append(names, k.Name)
+	}
+	return names
+}
+
+// ReadRotationState reads the state of an in-progress CA rotation.
+// If no rotation is in progress, it returns (nil, nil).
+func ReadRotationState(ctx context.Context, configBase vfs.Path) (*RotationState, error) {
+	p := configBase.Join(PathRotationState)
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading CA rotation state %q: %w", p, err)
+	}
+	state := &RotationState{}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("error parsing CA rotation state %q: %w", p, err)
+	}
+	return state, nil
+}
+
+// rotationStateStore persists a RotationState to a vfs.Path.
+type rotationStateStore struct {
+	path  vfs.Path
+	state RotationState
+}
+
+// createRotationState records the start of a new rotation of the named keysets.
+// It refuses to start if another rotation is recorded as in progress.
+func createRotationState(ctx context.Context, configBase vfs.Path, keysets []string) (*rotationStateStore, error) {
+	s := &rotationStateStore{
+		path: configBase.Join(PathRotationState),
+	}
+
+	now := time.Now().UTC()
+	s.state = RotationState{
+		StartedAt: now,
+		UpdatedAt: now,
+		Phase:     Phases[0],
+	}
+	for _, name := range keysets {
+		s.state.Keysets = append(s.state.Keysets, &KeysetState{Name: name})
+	}
+
+	data, err := s.marshal()
+	if err != nil {
+		return nil, err
+	}
+	// CreateFile guards against two rotations starting at the same time
+	if err := s.path.CreateFile(ctx, bytes.NewReader(data), nil); err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("%w; use --resume to continue it", ErrRotationInProgress)
+		}
+		return nil, fmt.Errorf("error writing CA rotation state %q: %w", s.path, err)
+	}
+	return s, nil
+}
+
+// save writes the state.
+// Unlike a rolling update, a rotation cannot safely repeat work it has lost track of,
+// so errors writing the state are returned.
+func (s *rotationStateStore) save(ctx context.Context) error {
+	s.state.UpdatedAt = time.Now().UTC()
+	data, err := s.marshal()
+	if err != nil {
+		return err
+	}
+	if err := s.path.WriteFile(ctx, bytes.NewReader(data), nil); err != nil {
+		return fmt.Errorf("error writing CA rotation state %q: %w", s.path, err)
+	}
+	return nil
+}
+
+// complete removes the recorded state, as the rotation has finished.
+func (s *rotationStateStore) complete(ctx context.Context) error {
+	if err := s.path.Remove(ctx); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("error removing CA rotation state %q: %w", s.path, err)
+	}
+	return nil
+}
+
+func (s *rotationStateStore) marshal() ([]byte, error) {
+	data, err := json.MarshalIndent(&s.state, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing CA rotation state: %w", err)
+	}
+	return data, nil
+}
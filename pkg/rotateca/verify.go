@@ -0,0 +1,123 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotateca
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"sort"
+
+	v1 "k8s.io/api/core/v1"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// VerifyCloudGroups returns an error if any instance is not running the current configuration,
+// or if any instance that should have joined the cluster does not have a Ready node.
+// Instances pick up keyset changes from their configuration, so once every instance is
+// up to date and Ready, every node trusts the current keysets.
+func VerifyCloudGroups(groups map[string]*cloudinstances.CloudInstanceGroup) error {
+	var names []string
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var errs []error
+	for _, name := range names {
+		group := groups[name]
+		for _, instance := range group.NeedUpdate {
+			errs = append(errs, fmt.Errorf("instance %s in group %q is not running the current configuration", instance.ID, name))
+		}
+		if group.InstanceGroup != nil && group.InstanceGroup.Spec.Role == kops.InstanceGroupRoleBastion {
+			continue
+		}
+		for _, instance := range group.Ready {
+			if instance.State == cloudinstances.WarmPool {
+				continue
+			}
+			if instance.Node == nil {
+				errs = append(errs, fmt.Errorf("instance %s in group %q has not joined the cluster", instance.ID, name))
+			} else if !isNodeReady(instance.Node) {
+				errs = append(errs, fmt.Errorf("node %q in group %q is not ready", instance.Node.Name, name))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func isNodeReady(node *v1.Node) bool {
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == v1.NodeReady {
+			return condition.Status == v1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// VerifyTrustBundle returns an error unless the PEM bundle holds the certificates of exactly
+// the trusted keypairs of the keyset.
+func VerifyTrustBundle(name string, bundle []byte, keyset *fi.Keyset) error {
+	var found [][]byte
+	for rest := bundle; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type == "CERTIFICATE" {
+			found = append(found, block.Bytes)
+		}
+	}
+
+	contains := func(raw []byte) bool {
+		for _, b := range found {
+			if bytes.Equal(b, raw) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var ids []string
+	for id := range keyset.Items {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return fi.KeysetItemIdOlder(ids[i], ids[j])
+	})
+
+	var errs []error
+	for _, id := range ids {
+		item := keyset.Items[id]
+		if item.Certificate == nil {
+			continue
+		}
+		trusted := contains(item.Certificate.Certificate.Raw)
+		if item.DistrustTimestamp == nil && !trusted {
+			errs = append(errs, fmt.Errorf("%s keypair %s is not yet trusted", name, id))
+		}
+		if item.DistrustTimestamp != nil && trusted {
+			errs = append(errs, fmt.Errorf("%s keypair %s is still trusted", name, id))
+		}
+	}
+	return errors.Join(errs...)
+}
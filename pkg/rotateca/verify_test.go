@@ -0,0 +1,118 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rotateca
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/cloudinstances"
+)
+
+func TestVerifyCloudGroups(t *testing.T) {
+	readyNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready"},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionTrue}},
+		},
+	}
+	notReadyNode := &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "notready"},
+		Status: v1.NodeStatus{
+			Conditions: []v1.NodeCondition{{Type: v1.NodeReady, Status: v1.ConditionFalse}},
+		},
+	}
+
+	group := func(role kops.InstanceGroupRole, ready []*cloudinstances.CloudInstance, needUpdate []*cloudinstances.CloudInstance) map[string]*cloudinstances.CloudInstanceGroup {
+		return map[string]*cloudinstances.CloudInstanceGroup{
+			"nodes": {
+				InstanceGroup: &kops.InstanceGroup{Spec: kops.InstanceGroupSpec{Role: role}},
+				Ready:         ready,
+				NeedUpdate:    needUpdate,
+			},
+		}
+	}
+
+	grid := []struct {
+		name     string
+		groups   map[string]*cloudinstances.CloudInstanceGroup
+		expected string
+	}{
+		{
+			name:   "up to date",
+			groups: group(kops.InstanceGroupRoleNode, []*cloudinstances.CloudInstance{{ID: "i-1", Node: readyNode}}, nil),
+		},
+		{
+			name:     "needs update",
+			groups:   group(kops.InstanceGroupRoleNode, nil, []*cloudinstances.CloudInstance{{ID: "i-1", Node: readyNode}}),
+			expected: `instance i-1 in group "nodes" is not running the current configuration`,
+		},
+		{
+			name:     "not joined",
+			groups:   group(kops.InstanceGroupRoleNode, []*cloudinstances.CloudInstance{{ID: "i-1"}}, nil),
+			expected: `instance i-1 in group "nodes" has not joined the cluster`,
+		},
+		{
+			name:     "not ready",
+			groups:   group(kops.InstanceGroupRoleNode, []*cloudinstances.CloudInstance{{ID: "i-1", Node: notReadyNode}}, nil),
+			expected: `node "notready" in group "nodes" is not ready`,
+		},
+		{
+			name:   "warm pool",
+			groups: group(kops.InstanceGroupRoleNode, []*cloudinstances.CloudInstance{{ID: "i-1", State: cloudinstances.WarmPool}}, nil),
+		},
+		{
+			name:   "bastion",
+			groups: group(kops.InstanceGroupRoleBastion, []*cloudinstances.CloudInstance{{ID: "i-1"}}, nil),
+		},
+	}
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			err := VerifyCloudGroups(g.groups)
+			if g.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, g.expected)
+			}
+		})
+	}
+}
+
+func TestVerifyTrustBundle(t *testing.T) {
+	r, _, originals := newTestRotator(t, "kubernetes-ca")
+	keyset := findKeyset(t, r, "kubernetes-ca")
+
+	original, err := keyset.ToCertificateBytes()
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTrustBundle("kubernetes-ca", original, keyset))
+
+	item, err := stageKeypair(t.Context(), "kubernetes-ca", keyset)
+	assert.NoError(t, err)
+	assert.EqualError(t, VerifyTrustBundle("kubernetes-ca", original, keyset), "kubernetes-ca keypair "+item.Id+" is not yet trusted")
+
+	staged, err := keyset.ToCertificateBytes()
+	assert.NoError(t, err)
+	assert.NoError(t, VerifyTrustBundle("kubernetes-ca", staged, keyset))
+
+	keyset.Primary = item
+	assert.Equal(t, []string{originals["kubernetes-ca"]}, distrustOlderKeypairs(keyset, keyset.Primary.Certificate.Certificate.NotBefore))
+	assert.EqualError(t, VerifyTrustBundle("kubernetes-ca", staged, keyset), "kubernetes-ca keypair "+originals["kubernetes-ca"]+" is still trusted")
+}
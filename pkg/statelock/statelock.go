@@ -0,0 +1,442 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package statelock implements an advisory lease lock in the state store,
+// so that concurrent kOps commands do not make conflicting changes to the same cluster.
+package statelock
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+	kopsbase "k8s.io/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// PathLock is the path (relative to the cluster's config base) of the lock
+const PathLock = "lock"
+
+// DefaultLeaseDuration is how long a lock is held without being renewed.
+// A lock held by a process that has died can be taken over once the lease has expired.
+const DefaultLeaseDuration = 2 * time.Minute
+
+// maxAcquireAttempts bounds the retries when another process changes the lock while we are acquiring it
+const maxAcquireAttempts = 5
+
+// LockRecord is the content of the lock file
+type LockRecord struct {
+	// ID identifies the lease, so that a holder can recognize its own lock
+	ID string `json:"id"`
+	// Holder is the user@host holding the lock
+	Holder string `json:"holder"`
+	// PID is the process ID of the holder
+	PID int `json:"pid"`
+	// Command is the command that is holding the lock
+	Command string `json:"command,omitempty"`
+	// KopsVersion is the version of kOps holding the lock
+	KopsVersion string `json:"kopsVersion,omitempty"`
+	// AcquiredAt is when the lock was acquired
+	AcquiredAt time.Time `json:"acquiredAt"`
+	// RenewedAt is when the lease was last renewed
+	RenewedAt time.Time `json:"renewedAt"`
+	// ExpiresAt is when the lease expires, unless it is renewed
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Expired returns true if the lease has expired
+func (r *LockRecord) Expired(now time.Time) bool {
+	return now.After(r.ExpiresAt)
+}
+
+func (r *LockRecord) String() string {
+	return fmt.Sprintf("%s (pid %d, %q) since %s", r.Holder, r.PID, r.Command, r.AcquiredAt.Format(time.RFC3339))
+}
+
+// LockedError is returned when the lock is held by someone else
+type LockedError struct {
+	Record *LockRecord
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("state store is locked by %s; the lease expires at %s unless renewed. If the holder is no longer running, use \"kops lock break\" to release it",
+		e.Record, e.Record.ExpiresAt.Format(time.RFC3339))
+}
+
+// LostError is returned when another process has taken the lock from us
+type LostError struct {
+	Location string
+}
+
+func (e *LostError) Error() string {
+	return fmt.Sprintf("state store lock %s was taken by another process", e.Location)
+}
+
+// Lease is a held lock, renewed in the background until it is released
+type Lease struct {
+	path     vfs.HasConditionalWrite
+	location string
+	duration time.Duration
+
+	mutex   sync.Mutex
+	record  LockRecord
+	version string
+	refs    int
+	lost    bool
+	// lostCh is closed when the lease is lost
+	lostCh  chan struct{}
+	stop    chan struct{}
+	stopped chan struct{}
+}
+
+var (
+	// heldLeases tracks the leases held by this process, so that nested commands share the lock
+	heldLeases      = make(map[string]*Lease)
+	heldLeasesMutex sync.Mutex
+)
+
+// Acquire takes the lock for the cluster whose config base is configBase.
+// If this process already holds the lock, the existing lease is shared.
+func Acquire(ctx context.Context, configBase vfs.Path, command string, duration time.Duration) (*Lease, error) {
+	p := configBase.Join(PathLock)
+
+	heldLeasesMutex.Lock()
+	defer heldLeasesMutex.Unlock()
+
+	if l := heldLeases[p.Path()]; l != nil {
+		l.mutex.Lock()
+		l.refs++
+		l.mutex.Unlock()
+		return l, nil
+	}
+
+	l := &Lease{
+		path:     conditionalPath(p),
+		location: p.Path(),
+		duration: duration,
+		refs:     1,
+		lostCh:   make(chan struct{}),
+		stop:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	if err := l.acquire(ctx, command); err != nil {
+		return nil, err
+	}
+	heldLeases[l.location] = l
+
+	go l.renewLoop()
+
+	return l, nil
+}
+
+func (l *Lease) acquire(ctx context.Context, command string) error {
+	for attempt := 0; attempt < maxAcquireAttempts; attempt++ {
+		now := time.Now().UTC()
+
+		version := ""
+		existing, v, err := readRecord(ctx, l.path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("error reading lock %s: %w", l.location, err)
+		}
+		if existing != nil {
+			if !existing.Expired(now) {
+				return &LockedError{Record: existing}
+			}
+			klog.Warningf("taking over expired state store lock held by %s", existing)
+			version = v
+		}
+
+		l.record = LockRecord{
+			ID:          newLeaseID(),
+			Holder:      currentHolder(),
+			PID:         os.Getpid(),
+			Command:     command,
+			KopsVersion: kopsbase.Version,
+			AcquiredAt:  now,
+			RenewedAt:   now,
+			ExpiresAt:   now.Add(l.duration),
+		}
+		newVersion, err := writeRecord(ctx, l.path, &l.record, version)
+		if err != nil {
+			if errors.Is(err, vfs.ErrPreconditionFailed) {
+				klog.V(2).Infof("lock %s changed while acquiring it; retrying", l.location)
+				continue
+			}
+			return fmt.Errorf("error writing lock %s: %w", l.location, err)
+		}
+		l.version = newVersion
+		klog.V(2).Infof("acquired state store lock %s", l.location)
+		return nil
+	}
+	return fmt.Errorf("unable to acquire lock %s: it is being changed concurrently", l.location)
+}
+
+// renewLoop renews the lease until it is released
+func (l *Lease) renewLoop() {
+	defer close(l.stopped)
+
+	ticker := time.NewTicker(l.duration / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			if err := l.renew(context.Background()); err != nil {
+				klog.Errorf("failed to renew state store lock %s: %v", l.location, err)
+				if errors.Is(err, vfs.ErrPreconditionFailed) {
+					l.mutex.Lock()
+					l.lost = true
+					l.mutex.Unlock()
+					close(l.lostCh)
+					klog.Errorf("state store lock %s was taken by another process; stopping", l.location)
+					return
+				}
+			}
+		}
+	}
+}
+
+func (l *Lease) renew(ctx context.Context) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now().UTC()
+	record := l.record
+	record.RenewedAt = now
+	record.ExpiresAt = now.Add(l.duration)
+	version, err := writeRecord(ctx, l.path, &record, l.version)
+	if err != nil {
+		return err
+	}
+	l.record = record
+	l.version = version
+	return nil
+}
+
+// Lost returns true if another process has taken the lock from us
+func (l *Lease) Lost() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.lost
+}
+
+// Context returns a context that is cancelled if the lease is lost, so that the command holding the lock stops
+// before making changes that may conflict with the new holder.
+// The cause of the cancellation is a *LostError.
+func (l *Lease) Context(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	go func() {
+		select {
+		case <-l.lostCh:
+			cancel(&LostError{Location: l.location})
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() { cancel(context.Canceled) }
+}
+
+// Release releases the lease; the lock is removed when the last user in this process releases it
+func (l *Lease) Release(ctx context.Context) error {
+	heldLeasesMutex.Lock()
+	defer heldLeasesMutex.Unlock()
+
+	l.mutex.Lock()
+	l.refs--
+	refs := l.refs
+	l.mutex.Unlock()
+	if refs > 0 {
+		return nil
+	}
+
+	delete(heldLeases, l.location)
+	close(l.stop)
+	<-l.stopped
+
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.lost {
+		return &LostError{Location: l.location}
+	}
+	if err := l.path.RemoveIfVersion(ctx, l.version); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		if errors.Is(err, vfs.ErrPreconditionFailed) {
+			return fmt.Errorf("state store lock %s was taken by another process", l.location)
+		}
+		return fmt.Errorf("error releasing lock %s: %w", l.location, err)
+	}
+	klog.V(2).Infof("released state store lock %s", l.location)
+	return nil
+}
+
+// Status returns the current holder of the lock, or nil if it is not held
+func Status(ctx context.Context, configBase vfs.Path) (*LockRecord, error) {
+	p := configBase.Join(PathLock)
+	record, _, err := readRecord(ctx, conditionalPath(p))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading lock %s: %w", p, err)
+	}
+	return record, nil
+}
+
+// Break forcibly releases the lock, returning the record of the holder, or nil if it was not held
+func Break(ctx context.Context, configBase vfs.Path) (*LockRecord, error) {
+	p := configBase.Join(PathLock)
+	cp := conditionalPath(p)
+	record, version, err := readRecord(ctx, cp)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error reading lock %s: %w", p, err)
+	}
+	if err := cp.RemoveIfVersion(ctx, version); err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if errors.Is(err, vfs.ErrPreconditionFailed) {
+			return nil, fmt.Errorf("lock %s changed while breaking it; please retry", p)
+		}
+		return nil, fmt.Errorf("error removing lock %s: %w", p, err)
+	}
+	return record, nil
+}
+
+func readRecord(ctx context.Context, p vfs.HasConditionalWrite) (*LockRecord, string, error) {
+	b, version, err := p.ReadFileWithVersion(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	record := &LockRecord{}
+	if err := json.Unmarshal(b, record); err != nil {
+		return nil, "", fmt.Errorf("error parsing lock: %w", err)
+	}
+	return record, version, nil
+}
+
+func writeRecord(ctx context.Context, p vfs.HasConditionalWrite, record *LockRecord, version string) (string, error) {
+	b, err := json.Marshal(record)
+	if err != nil {
+		return "", fmt.Errorf("error encoding lock: %w", err)
+	}
+	return p.WriteFileIfVersion(ctx, bytes.NewReader(b), nil, version)
+}
+
+// conditionalPath returns the path as a HasConditionalWrite, falling back to a best-effort
+// implementation for backends that do not support conditional writes.
+func conditionalPath(p vfs.Path) vfs.HasConditionalWrite {
+	if cp, ok := p.(vfs.HasConditionalWrite); ok {
+		return cp
+	}
+	klog.Warningf("state store %s does not support conditional writes; locking is best-effort", p)
+	return &bestEffortPath{Path: p}
+}
+
+// bestEffortPath emulates conditional writes by comparing the contents before writing.
+// This is not atomic, but still protects against most concurrent changes.
+type bestEffortPath struct {
+	vfs.Path
+}
+
+var _ vfs.HasConditionalWrite = &bestEffortPath{}
+
+func (p *bestEffortPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, contentVersion(b), nil
+}
+
+func (p *bestEffortPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl vfs.ACL, version string) (string, error) {
+	if err := p.checkVersion(ctx, version); err != nil {
+		return "", err
+	}
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return "", err
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(b), acl); err != nil {
+		return "", err
+	}
+	return contentVersion(b), nil
+}
+
+func (p *bestEffortPath) RemoveIfVersion(ctx context.Context, version string) error {
+	if err := p.checkVersion(ctx, version); err != nil {
+		return err
+	}
+	return p.Remove(ctx)
+}
+
+func (p *bestEffortPath) checkVersion(ctx context.Context, version string) error {
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if version == "" {
+				return nil
+			}
+			return os.ErrNotExist
+		}
+		return err
+	}
+	if version == "" || contentVersion(b) != version {
+		return vfs.ErrPreconditionFailed
+	}
+	return nil
+}
+
+func contentVersion(b []byte) string {
+	hash := sha256.Sum256(b)
+	return hex.EncodeToString(hash[:])
+}
+
+func newLeaseID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		klog.Fatalf("error generating lease ID: %v", err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// currentHolder returns the user@host taking the lock
+func currentHolder() string {
+	username := os.Getenv("USER")
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		username = u.Username
+	}
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return username + "@" + hostname
+	}
+	return username
+}
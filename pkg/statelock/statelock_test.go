@@ -0,0 +1,166 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statelock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func newConfigBase(t *testing.T) vfs.Path {
+	vfsContext := vfs.NewVFSContext()
+	vfsContext.ResetMemfsContext(true)
+	configBase, err := vfsContext.BuildVfsPath("memfs://state/test.example.com")
+	require.NoError(t, err)
+	return configBase
+}
+
+// writeOtherHolder writes a lock record as if held by another process
+func writeOtherHolder(t *testing.T, configBase vfs.Path, expiresAt time.Time) {
+	record := &LockRecord{ID: "other", Holder: "someone@elsewhere", PID: 1, Command: "kops update cluster", ExpiresAt: expiresAt}
+	_, err := writeRecord(context.Background(), conditionalPath(configBase.Join(PathLock)), record, "")
+	require.NoError(t, err)
+}
+
+func TestAcquireRelease(t *testing.T) {
+	ctx := context.Background()
+	configBase := newConfigBase(t)
+
+	lease, err := Acquire(ctx, configBase, "kops update cluster", DefaultLeaseDuration)
+	require.NoError(t, err)
+
+	record, err := Status(ctx, configBase)
+	require.NoError(t, err)
+	require.NotNil(t, record)
+	assert.Equal(t, "kops update cluster", record.Command)
+	assert.NotEmpty(t, record.Holder)
+
+	// Nested commands in the same process share the lease
+	nested, err := Acquire(ctx, configBase, "kops rolling-update cluster", DefaultLeaseDuration)
+	require.NoError(t, err)
+	assert.Same(t, lease, nested)
+	require.NoError(t, nested.Release(ctx))
+
+	record, err = Status(ctx, configBase)
+	require.NoError(t, err)
+	assert.NotNil(t, record, "lock should be held until the last release")
+
+	require.NoError(t, lease.Release(ctx))
+	record, err = Status(ctx, configBase)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestAcquireHeldByOther(t *testing.T) {
+	ctx := context.Background()
+	configBase := newConfigBase(t)
+
+	writeOtherHolder(t, configBase, time.Now().Add(time.Hour))
+
+	_, err := Acquire(ctx, configBase, "kops edit cluster", DefaultLeaseDuration)
+	var lockedError *LockedError
+	require.True(t, errors.As(err, &lockedError), "expected LockedError, got %v", err)
+	assert.Equal(t, "someone@elsewhere", lockedError.Record.Holder)
+
+	record, err := Break(ctx, configBase)
+	require.NoError(t, err)
+	assert.Equal(t, "other", record.ID)
+
+	lease, err := Acquire(ctx, configBase, "kops edit cluster", DefaultLeaseDuration)
+	require.NoError(t, err)
+	require.NoError(t, lease.Release(ctx))
+
+	record, err = Break(ctx, configBase)
+	require.NoError(t, err)
+	assert.Nil(t, record)
+}
+
+func TestAcquireExpired(t *testing.T) {
+	ctx := context.Background()
+	configBase := newConfigBase(t)
+
+	writeOtherHolder(t, configBase, time.Now().Add(-time.Minute))
+
+	lease, err := Acquire(ctx, configBase, "kops edit cluster", DefaultLeaseDuration)
+	require.NoError(t, err)
+
+	record, err := Status(ctx, configBase)
+	require.NoError(t, err)
+	assert.NotEqual(t, "other", record.ID)
+
+	require.NoError(t, lease.Release(ctx))
+}
+
+func TestLeaseRenewal(t *testing.T) {
+	ctx := context.Background()
+	configBase := newConfigBase(t)
+
+	lease, err := Acquire(ctx, configBase, "kops rolling-update cluster", 30*time.Millisecond)
+	require.NoError(t, err)
+	leaseCtx, cancel := lease.Context(ctx)
+	defer cancel()
+
+	first, err := Status(ctx, configBase)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		record, err := Status(ctx, configBase)
+		return err == nil && record.ExpiresAt.After(first.ExpiresAt)
+	}, time.Second, 5*time.Millisecond, "lease should be renewed")
+
+	// Another process breaks the lock and takes it
+	_, err = Break(ctx, configBase)
+	require.NoError(t, err)
+	writeOtherHolder(t, configBase, time.Now().Add(time.Hour))
+
+	assert.Eventually(t, lease.Lost, time.Second, 5*time.Millisecond, "lease should be lost")
+	select {
+	case <-leaseCtx.Done():
+	case <-time.After(time.Second):
+		t.Fatal("context should be cancelled when the lease is lost")
+	}
+	var lostError *LostError
+	assert.True(t, errors.As(context.Cause(leaseCtx), &lostError), "expected LostError, got %v", context.Cause(leaseCtx))
+	assert.Error(t, lease.Release(ctx))
+
+	record, err := Status(ctx, configBase)
+	require.NoError(t, err)
+	assert.Equal(t, "other", record.ID, "releasing a lost lease should not remove the new holder's lock")
+}
+
+func TestBestEffortPath(t *testing.T) {
+	ctx := context.Background()
+	configBase := newConfigBase(t)
+
+	p := &bestEffortPath{Path: configBase.Join(PathLock)}
+	record := &LockRecord{ID: "a"}
+	v1, err := writeRecord(ctx, p, record, "")
+	require.NoError(t, err)
+
+	_, err = writeRecord(ctx, p, record, "")
+	assert.ErrorIs(t, err, vfs.ErrPreconditionFailed)
+
+	record.ID = "b"
+	_, err = writeRecord(ctx, p, record, v1)
+	require.NoError(t, err)
+	assert.ErrorIs(t, p.RemoveIfVersion(ctx, v1), vfs.ErrPreconditionFailed)
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	"k8s.io/kops/pkg/apis/kops"
+)
+
+// defaultGateTimeout is the timeout for HTTP and Prometheus gates, if not otherwise specified
+const defaultGateTimeout = 10 * time.Second
+
+// collectGateFailures evaluates validation gates, recording a failure for each gate that does not pass.
+// Failures are attributed to the InstanceGroup ig, which is nil for cluster-wide gates.
+func (v *ValidationCluster) collectGateFailures(ctx context.Context, httpClient *http.Client, k8sClient kubernetes.Interface, gates []kops.ValidationGateSpec, ig *kops.InstanceGroup) {
+	for i := range gates {
+		gate := &gates[i]
+
+		var err error
+		switch {
+		case gate.Prometheus != nil:
+			err = checkPrometheusGate(ctx, httpClient, gate.Prometheus)
+		case gate.HTTP != nil:
+			err = checkHTTPGate(ctx, httpClient, gate.HTTP)
+		case gate.Workload != nil:
+			err = checkWorkloadGate(ctx, k8sClient, gate.Workload)
+		default:
+			err = fmt.Errorf("no check configured")
+		}
+		if err == nil {
+			klog.V(2).Infof("validation gate %q passed", gate.Name)
+			continue
+		}
+
+		message := fmt.Sprintf("validation gate %q did not pass: %v", gate.Name, err)
+		if ig != nil {
+			message = fmt.Sprintf("InstanceGroup %q %s", ig.Name, message)
+		}
+		v.addError(&ValidationError{
+			Kind:          "ValidationGate",
+			Name:          gate.Name,
+			Message:       message,
+			InstanceGroup: ig,
+		})
+	}
+}
+
+// prometheusQueryResponse is the subset of the Prometheus query API response that we need
+type prometheusQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// checkPrometheusGate evaluates a PromQL query; it passes if the query returns at least one sample and every sample is non-zero
+func checkPrometheusGate(ctx context.Context, httpClient *http.Client, gate *kops.PrometheusValidationGateSpec) error {
+	ctx, cancel := context.WithTimeout(ctx, defaultGateTimeout)
+	defer cancel()
+
+	u := strings.TrimSuffix(gate.URL, "/") + "/api/v1/query?" + url.Values{"query": []string{gate.Query}}.Encode()
+	body, err := httpGet(ctx, httpClient, u)
+	if err != nil {
+		return err
+	}
+
+	response := &prometheusQueryResponse{}
+	if err := json.Unmarshal(body, response); err != nil {
+		return fmt.Errorf("error parsing prometheus response: %w", err)
+	}
+	if response.Status != "success" {
+		return fmt.Errorf("prometheus query failed: %s", response.Error)
+	}
+
+	var values []string
+	switch response.Data.ResultType {
+	case "scalar":
+		var sample []any
+		if err := json.Unmarshal(response.Data.Result, &sample); err != nil {
+			return fmt.Errorf("error parsing prometheus scalar result: %w", err)
+		}
+		if len(sample) == 2 {
+			values = append(values, fmt.Sprint(sample[1]))
+		}
+	case "vector":
+		var samples []struct {
+			Value []any `json:"value"`
+		}
+		if err := json.Unmarshal(response.Data.Result, &samples); err != nil {
+			return fmt.Errorf("error parsing prometheus vector result: %w", err)
+		}
+		for _, sample := range samples {
+			if len(sample.Value) == 2 {
+				values = append(values, fmt.Sprint(sample.Value[1]))
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported prometheus result type %q", response.Data.ResultType)
+	}
+
+	if len(values) == 0 {
+		return fmt.Errorf("query %q returned no samples", gate.Query)
+	}
+	for _, value := range values {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("error parsing prometheus sample value %q: %w", value, err)
+		}
+		if f == 0 {
+			return fmt.Errorf("query %q returned false", gate.Query)
+		}
+	}
+	return nil
+}
+
+// checkHTTPGate passes if a GET request to the endpoint returns a 2xx status code
+func checkHTTPGate(ctx context.Context, httpClient *http.Client, gate *kops.HTTPValidationGateSpec) error {
+	timeout := defaultGateTimeout
+	if gate.Timeout != nil {
+		timeout = gate.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	_, err := httpGet(ctx, httpClient, gate.URL)
+	return err
+}
+
+// httpGet performs a GET request, returning an error if the response status is not 2xx
+func httpGet(ctx context.Context, httpClient *http.Client, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error building request for %q: %w", u, err)
+	}
+	response, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error querying %q: %w", u, err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response from %q: %w", u, err)
+	}
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return nil, fmt.Errorf("%q returned status %q", u, response.Status)
+	}
+	return body, nil
+}
+
+// checkWorkloadGate passes if the workload has at least the minimum number of available replicas
+func checkWorkloadGate(ctx context.Context, k8sClient kubernetes.Interface, gate *kops.WorkloadValidationGateSpec) error {
+	var desired, available int
+	switch gate.Kind {
+	case "Deployment":
+		obj, err := k8sClient.AppsV1().Deployments(gate.Namespace).Get(ctx, gate.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting deployment %s/%s: %w", gate.Namespace, gate.Name, err)
+		}
+		desired = 1
+		if obj.Spec.Replicas != nil {
+			desired = int(*obj.Spec.Replicas)
+		}
+		available = int(obj.Status.AvailableReplicas)
+	case "StatefulSet":
+		obj, err := k8sClient.AppsV1().StatefulSets(gate.Namespace).Get(ctx, gate.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting statefulset %s/%s: %w", gate.Namespace, gate.Name, err)
+		}
+		desired = 1
+		if obj.Spec.Replicas != nil {
+			desired = int(*obj.Spec.Replicas)
+		}
+		available = int(obj.Status.AvailableReplicas)
+	case "DaemonSet":
+		obj, err := k8sClient.AppsV1().DaemonSets(gate.Namespace).Get(ctx, gate.Name, metav1.GetOptions{})
+		if err != nil {
+			return fmt.Errorf("error getting daemonset %s/%s: %w", gate.Namespace, gate.Name, err)
+		}
+		desired = int(obj.Status.DesiredNumberScheduled)
+		available = int(obj.Status.NumberAvailable)
+	default:
+		return fmt.Errorf("unsupported workload kind %q", gate.Kind)
+	}
+
+	minAvailable := intstr.FromInt(desired)
+	if gate.MinAvailable != nil {
+		minAvailable = *gate.MinAvailable
+	}
+	required, err := intstr.GetScaledValueFromIntOrPercent(&minAvailable, desired, true)
+	if err != nil {
+		return fmt.Errorf("invalid minAvailable %q: %w", minAvailable.String(), err)
+	}
+	if available < required {
+		return fmt.Errorf("%s %s/%s has %d available replicas, fewer than the required %d", strings.ToLower(gate.Kind), gate.Namespace, gate.Name, available, required)
+	}
+	return nil
+}
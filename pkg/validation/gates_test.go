@@ -0,0 +1,159 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package validation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/kubernetes/fake"
+	kopsapi "k8s.io/kops/pkg/apis/kops"
+)
+
+// newPrometheusServer returns a stand-in Prometheus server that answers every query with the given result
+func newPrometheusServer(t *testing.T, resultType string, result string) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/query" || r.URL.Query().Get("query") == "" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":%q,"result":%s}}`, resultType, result)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newHTTPServer(t *testing.T, statusCode int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(statusCode)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func Test_ValidationGates(t *testing.T) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "app"},
+		Spec:       appsv1.DeploymentSpec{Replicas: new(int32(10))},
+		Status:     appsv1.DeploymentStatus{AvailableReplicas: 9},
+	}
+	daemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "agent"},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 4, NumberAvailable: 4},
+	}
+	k8sClient := fake.NewClientset(deployment, daemonSet)
+
+	grid := []struct {
+		name string
+		gate kopsapi.ValidationGateSpec
+		pass bool
+	}{
+		{
+			name: "prometheus vector true",
+			gate: kopsapi.ValidationGateSpec{Prometheus: &kopsapi.PrometheusValidationGateSpec{
+				URL:   newPrometheusServer(t, "vector", `[{"metric":{},"value":[1700000000,"1"]},{"metric":{},"value":[1700000000,"0.5"]}]`).URL,
+				Query: "slo_ok",
+			}},
+			pass: true,
+		},
+		{
+			name: "prometheus vector false",
+			gate: kopsapi.ValidationGateSpec{Prometheus: &kopsapi.PrometheusValidationGateSpec{
+				URL:   newPrometheusServer(t, "vector", `[{"metric":{},"value":[1700000000,"1"]},{"metric":{},"value":[1700000000,"0"]}]`).URL,
+				Query: "slo_ok",
+			}},
+		},
+		{
+			name: "prometheus vector empty",
+			gate: kopsapi.ValidationGateSpec{Prometheus: &kopsapi.PrometheusValidationGateSpec{
+				URL:   newPrometheusServer(t, "vector", `[]`).URL,
+				Query: "slo_ok",
+			}},
+		},
+		{
+			name: "prometheus scalar",
+			gate: kopsapi.ValidationGateSpec{Prometheus: &kopsapi.PrometheusValidationGateSpec{
+				URL:   newPrometheusServer(t, "scalar", `[1700000000,"1"]`).URL,
+				Query: "scalar(slo_ok)",
+			}},
+			pass: true,
+		},
+		{
+			name: "http ok",
+			gate: kopsapi.ValidationGateSpec{HTTP: &kopsapi.HTTPValidationGateSpec{URL: newHTTPServer(t, http.StatusNoContent).URL}},
+			pass: true,
+		},
+		{
+			name: "http unavailable",
+			gate: kopsapi.ValidationGateSpec{HTTP: &kopsapi.HTTPValidationGateSpec{URL: newHTTPServer(t, http.StatusServiceUnavailable).URL}},
+		},
+		{
+			name: "deployment enough replicas",
+			gate: kopsapi.ValidationGateSpec{Workload: &kopsapi.WorkloadValidationGateSpec{
+				Kind: "Deployment", Namespace: "default", Name: "app", MinAvailable: new(intstr.FromString("90%")),
+			}},
+			pass: true,
+		},
+		{
+			name: "deployment too few replicas",
+			gate: kopsapi.ValidationGateSpec{Workload: &kopsapi.WorkloadValidationGateSpec{
+				Kind: "Deployment", Namespace: "default", Name: "app", MinAvailable: new(intstr.FromInt(10)),
+			}},
+		},
+		{
+			name: "daemonset",
+			gate: kopsapi.ValidationGateSpec{Workload: &kopsapi.WorkloadValidationGateSpec{
+				Kind: "DaemonSet", Namespace: "kube-system", Name: "agent", MinAvailable: new(intstr.FromString("100%")),
+			}},
+			pass: true,
+		},
+		{
+			name: "missing workload",
+			gate: kopsapi.ValidationGateSpec{Workload: &kopsapi.WorkloadValidationGateSpec{
+				Kind: "StatefulSet", Namespace: "default", Name: "db", MinAvailable: new(intstr.FromInt(1)),
+			}},
+		},
+	}
+
+	for _, g := range grid {
+		t.Run(g.name, func(t *testing.T) {
+			ig := &kopsapi.InstanceGroup{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}}
+			g.gate.Name = "gate"
+
+			v := &ValidationCluster{}
+			v.collectGateFailures(context.TODO(), http.DefaultClient, k8sClient, []kopsapi.ValidationGateSpec{g.gate}, ig)
+			if g.pass {
+				if !assert.Empty(t, v.Failures) {
+					printDebug(t, v)
+				}
+				return
+			}
+			if assert.Len(t, v.Failures, 1) {
+				assert.Equal(t, "ValidationGate", v.Failures[0].Kind)
+				assert.Equal(t, "gate", v.Failures[0].Name)
+				assert.Same(t, ig, v.Failures[0].InstanceGroup)
+			}
+		})
+	}
+}
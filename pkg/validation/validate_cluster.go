@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/http"
 	"net/url"
 	"sort"
 	"strings"
@@ -65,6 +66,9 @@ type clusterValidatorImpl struct {
 	restConfig *rest.Config
 	k8sClient  kubernetes.Interface
 
+	// httpClient is used to evaluate HTTP and Prometheus validation gates
+	httpClient *http.Client
+
 	// allInstanceGroups is the list of all instance groups in the cluster
 	allInstanceGroups []*kops.InstanceGroup
 
@@ -143,6 +147,7 @@ func NewClusterValidator(cluster *kops.Cluster, cloud fi.Cloud, instanceGroupLis
 		allInstanceGroups:       allInstanceGroups,
 		restConfig:              restConfig,
 		k8sClient:               k8sClient,
+		httpClient:              http.DefaultClient,
 		filterInstanceGroups:    filterInstanceGroups,
 		filterPodsForValidation: filterPodsForValidation,
 		maxUnreadyNodes:         maxUnreadyNodes,
@@ -234,6 +239,15 @@ func (v *clusterValidatorImpl) Validate(ctx context.Context) (*ValidationCluster
 		return nil, fmt.Errorf("cannot get pod health for %q: %v", v.cluster.Name, err)
 	}
 
+	if v.cluster.Spec.RollingUpdate != nil {
+		validation.collectGateFailures(ctx, v.httpClient, v.k8sClient, v.cluster.Spec.RollingUpdate.ValidationGates, nil)
+	}
+	for _, ig := range v.allInstanceGroups {
+		if ig.Spec.RollingUpdate != nil && v.filterInstanceGroups(ig) {
+			validation.collectGateFailures(ctx, v.httpClient, v.k8sClient, ig.Spec.RollingUpdate.ValidationGates, ig)
+		}
+	}
+
 	return validation, nil
 }
 
@@ -70,6 +70,9 @@ const (
 	// EtcdLeasesGRPC is the GRPC port used by etcd-manager, for the leases etcd
 	EtcdLeasesGRPC = 4006
 
+	// KopsControllerMetricsPort is the port where kops-controller serves metrics, on the loopback interface by default.
+	KopsControllerMetricsPort = 4007
+
 	// DNSControllerGossipWeaveMesh was the port where dns-controller listened for the weave-mesh-backed gossip.
 	//
 	// Deprecated: gossip DNS support was removed in kOps 1.37; retained so the port is not reused.
@@ -128,9 +128,20 @@ type ApplyClusterCmd struct {
 	// that is re-mapped.
 	LifecycleOverrides map[string]fi.Lifecycle
 
+	// TaskSelectors limits changes to the tasks matching any of the selectors.
+	// Other tasks are treated as ExistsAndWarnIfChanges; dependencies of the selected tasks must exist.
+	TaskSelectors []*TaskSelector
+
 	// GetAssets is whether this is called just to obtain the list of assets.
 	GetAssets bool
 
+	// DryRunOutput is where the dry-run target prints its report of changes; defaults to stdout.
+	DryRunOutput io.Writer
+
+	// Plan, if set, restricts the apply to the changes in a plan saved by an earlier dry-run.
+	// Any other change fails the apply before it is made.
+	Plan *fi.SavedPlan
+
 	// TaskMap is the map of tasks that we built (output)
 	TaskMap map[string]fi.CloudupTask
 
@@ -820,6 +831,9 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 
 	case TargetDryRun:
 		var out io.Writer = os.Stdout
+		if c.DryRunOutput != nil {
+			out = c.DryRunOutput
+		}
 		checkExisting := true
 		if c.GetAssets {
 			out = io.Discard
@@ -844,11 +858,21 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 		}
 	}
 
+	if len(c.TaskSelectors) != 0 {
+		if err := applyTaskSelectors(c.TaskMap, c.TaskSelectors, c.InstanceGroups, c.DryRun); err != nil {
+			return nil, err
+		}
+	}
+
 	context, err := fi.NewCloudupContext(ctx, deletionProcessingMode, target, cluster, cloud, keyStore, secretStore, configBase, c.TaskMap)
 	if err != nil {
 		return nil, fmt.Errorf("error building context: %v", err)
 	}
 
+	if c.Plan != nil {
+		context.EnforcePlan(c.Plan)
+	}
+
 	var options fi.RunTasksOptions
 	if c.RunTasksOptions != nil {
 		options = *c.RunTasksOptions
@@ -861,6 +885,10 @@ func (c *ApplyClusterCmd) Run(ctx context.Context) (*ApplyResults, error) {
 		return nil, fmt.Errorf("error running tasks: %v", err)
 	}
 
+	for _, key := range context.PlannedChangesNotMade() {
+		klog.Warningf("planned change to %s was not made, as it is no longer needed", key)
+	}
+
 	if !cluster.PublishesDNSRecords() {
 		shouldPrecreateDNS = false
 	}
@@ -0,0 +1,80 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awstasks
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestPlanFields(t *testing.T) {
+	userData := "#!/bin/bash\nexport S3_SECRET_ACCESS_KEY=hunter2\n"
+	policy := `{"Statement":[{"Action":"s3:GetObject","Effect":"Allow","Resource":"*"}]}`
+
+	launchTemplate := &LaunchTemplate{
+		Name:       new("nodes.example.com"),
+		Lifecycle:  fi.LifecycleSync,
+		HTTPTokens: new(ec2types.LaunchTemplateHttpTokensStateRequired),
+		UserData:   fi.NewStringResource(userData),
+	}
+	rolePolicy := &IAMRolePolicy{
+		Name:           new("nodes.example.com"),
+		Lifecycle:      fi.LifecycleSync,
+		PolicyDocument: fi.NewStringResource(policy),
+	}
+	tasks := map[string]fi.CloudupTask{
+		"LaunchTemplate/nodes.example.com": launchTemplate,
+		"IAMRolePolicy/nodes.example.com":  rolePolicy,
+	}
+
+	target := fi.NewCloudupDryRunTarget(assets.NewAssetBuilder(vfs.Context, nil, false), true, io.Discard)
+	launchTemplateChanges := &LaunchTemplate{}
+	fi.BuildChanges((*LaunchTemplate)(nil), launchTemplate, launchTemplateChanges)
+	require.NoError(t, target.Render((*LaunchTemplate)(nil), launchTemplate, launchTemplateChanges))
+	rolePolicyChanges := &IAMRolePolicy{}
+	fi.BuildChanges((*IAMRolePolicy)(nil), rolePolicy, rolePolicyChanges)
+	require.NoError(t, target.Render((*IAMRolePolicy)(nil), rolePolicy, rolePolicyChanges))
+
+	plan, err := target.Plan(tasks)
+	require.NoError(t, err)
+	fields := make(map[string]string)
+	for _, task := range plan.Tasks {
+		for _, change := range task.Changes {
+			fields[task.Key+"."+change.Field] = change.New
+		}
+	}
+
+	// Field names are not a reason to redact: HTTPTokens is the instance metadata setting
+	assert.Equal(t, "required", fields["LaunchTemplate/nodes.example.com.HTTPTokens"])
+	// User data may hold credentials, so only its hash is shown
+	assert.Regexp(t, "^"+fi.ResourceHashPrefix, fields["LaunchTemplate/nodes.example.com.UserData"])
+	// Policies are shown so that they can be reviewed
+	assert.Equal(t, policy, fields["IAMRolePolicy/nodes.example.com.PolicyDocument"])
+
+	var report bytes.Buffer
+	require.NoError(t, target.PrintReport(tasks, &report))
+	assert.Contains(t, report.String(), "HTTPTokens")
+	assert.NotContains(t, report.String(), "hunter2")
+}
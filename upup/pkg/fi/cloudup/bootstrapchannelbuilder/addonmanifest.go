@@ -93,6 +93,14 @@ func (a *AddonManifest) Normalize(c *fi.CloudupContext) error {
 		}
 	}
 
+	// Patches are applied before remapping, so that images they set are remapped too
+	if a.modelContext != nil {
+		manifestBytes, err = addonmanifests.PatchAddonManifest(fi.ValueOf(a.addonSpec.Name), a.modelContext.Cluster.Spec.AddonPatches, manifestBytes)
+		if err != nil {
+			return fmt.Errorf("error patching addon %q: %w", fi.ValueOf(a.Name), err)
+		}
+	}
+
 	if !a.skipRemap {
 		manifestBytes, err = addonmanifests.RemapAddonManifest(a.addonSpec, a.modelContext, a.assetBuilder, manifestBytes, a.serviceAccounts)
 		if err != nil {
@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	channelsapi "k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/assets"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -125,7 +126,7 @@ func TestAddonCollectImagesSkipsRenderForRawSources(t *testing.T) {
 		SkipRender: true,
 	}
 
-	if err := addon.CollectImages(assetBuilder, renderer); err != nil {
+	if err := addon.CollectImages(assetBuilder, renderer, nil); err != nil {
 		t.Fatalf("collecting raw addon images: %v", err)
 	}
 	if renderer.calls != 0 {
@@ -143,7 +144,7 @@ func TestAddonCollectImagesRendersTemplateSources(t *testing.T) {
 		Source: fi.NewBytesResource([]byte("{{ template }}")),
 	}
 
-	if err := addon.CollectImages(assetBuilder, renderer); err != nil {
+	if err := addon.CollectImages(assetBuilder, renderer, nil); err != nil {
 		t.Fatalf("collecting template addon images: %v", err)
 	}
 	if renderer.calls != 1 {
@@ -158,3 +159,30 @@ func testAddonSpec(name string) *channelsapi.AddonSpec {
 		Manifest: new(name + ".yaml"),
 	}
 }
+
+func TestAddonCollectImagesAppliesPatches(t *testing.T) {
+	assetBuilder := assets.NewAssetBuilder(nil, nil, false)
+	addon := &Addon{
+		Spec:       testAddonSpec("patched-addon"),
+		Source:     fi.NewBytesResource([]byte("apiVersion: v1\nkind: Pod\nmetadata:\n  name: patched\nspec:\n  containers:\n  - name: container\n    image: registry.k8s.io/pause:3.9\n")),
+		SkipRender: true,
+	}
+	patches := []kops.AddonPatchSpec{
+		{
+			Addon:    "patched-addon",
+			Target:   kops.AddonPatchTarget{Kind: "Pod", Name: "patched"},
+			JSON6902: `[{"op": "replace", "path": "/spec/containers/0/image", "value": "registry.k8s.io/pause:3.10"}]`,
+		},
+	}
+
+	if err := addon.CollectImages(assetBuilder, nil, patches); err != nil {
+		t.Fatalf("collecting patched addon images: %v", err)
+	}
+	var images []string
+	for _, image := range assetBuilder.ImageAssets() {
+		images = append(images, image.CanonicalLocation)
+	}
+	if len(images) != 1 || images[0] != "registry.k8s.io/pause:3.10" {
+		t.Fatalf("images = %v, want the patched image", images)
+	}
+}
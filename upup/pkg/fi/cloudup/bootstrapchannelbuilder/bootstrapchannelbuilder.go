@@ -18,7 +18,9 @@ package bootstrapchannelbuilder
 
 import (
 	"fmt"
+	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
@@ -115,6 +117,10 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		return err
 	}
 
+	if err := b.addChartAddons(c, addons); err != nil {
+		return err
+	}
+
 	for _, addon := range addons.Items {
 		// Addons with a preset source do not have a template.
 		if addon.Source != nil {
@@ -183,12 +189,18 @@ func (b *BootstrapChannelBuilder) Build(c *fi.CloudupModelBuilderContext) error
 		addon.SkipRender = true
 	}
 
+	for i, patch := range b.Cluster.Spec.AddonPatches {
+		if addons.find(patch.Addon) == nil {
+			return fmt.Errorf("spec.addonPatches[%d] patches addon %q, which is not installed in this cluster", i, patch.Addon)
+		}
+	}
+
 	preRegisterAddonImages := b.shouldPreRegisterAddonImages()
 
 	var addonTasks []*AddonManifest
 	for _, addon := range addons.Items {
 		if preRegisterAddonImages {
-			if err := addon.CollectImages(b.assetBuilder, b.addonRenderer); err != nil {
+			if err := addon.CollectImages(b.assetBuilder, b.addonRenderer, b.Cluster.Spec.AddonPatches); err != nil {
 				klog.Warningf("unable to pre-register addon images for %q: %v", addonKey(addon.Spec), err)
 			}
 		}
@@ -266,6 +278,44 @@ func (a *AddonList) AddWithSource(spec *channelsapi.AddonSpec, source fi.Resourc
 	return addon
 }
 
+// dependencyHealthTimeout is how long channels waits for an addon that other addons depend on to become healthy
+const dependencyHealthTimeout = 10 * time.Minute
+
+// addDependencies declares the dependencies between the addons we manage,
+// so that channels applies them in order and waits for each dependency to become healthy.
+func (a *AddonList) addDependencies() {
+	if certManager := a.find("certmanager.io"); certManager != nil {
+		// Addons that need PKI use a cert-manager Issuer and Certificates
+		dependedOn := false
+		for _, addon := range a.Items {
+			if addon.Spec.NeedsPKI {
+				addon.Spec.DependsOn = append(addon.Spec.DependsOn, "certmanager.io")
+				dependedOn = true
+			}
+		}
+		if dependedOn {
+			a.waitForHealthy(certManager)
+		}
+	}
+}
+
+// waitForHealthy sets a healthTimeout on an addon that other addons depend on,
+// as channels only waits for dependencies that set one.
+func (a *AddonList) waitForHealthy(addon *Addon) {
+	if addon.Spec.HealthTimeout == nil {
+		addon.Spec.HealthTimeout = &metav1.Duration{Duration: dependencyHealthTimeout}
+	}
+}
+
+func (a *AddonList) find(name string) *Addon {
+	for _, addon := range a.Items {
+		if fi.ValueOf(addon.Spec.Name) == name {
+			return addon
+		}
+	}
+	return nil
+}
+
 type Addon struct {
 	// Spec is the spec that will (eventually) be passed to the kops-channels static pod.
 	Spec *channelsapi.AddonSpec
@@ -287,7 +337,7 @@ type Addon struct {
 // the resulting YAML for image references to pre-register with the builder.
 // Best-effort only (used for AWS WarmPool image prewarm): a failure here
 // warns rather than breaks the build.
-func (a *Addon) CollectImages(assetBuilder *assets.AssetBuilder, renderer AddonTemplateRenderer) error {
+func (a *Addon) CollectImages(assetBuilder *assets.AssetBuilder, renderer AddonTemplateRenderer, patches []kops.AddonPatchSpec) error {
 	if a == nil || assetBuilder == nil || a.Source == nil {
 		return nil
 	}
@@ -302,6 +352,10 @@ func (a *Addon) CollectImages(assetBuilder *assets.AssetBuilder, renderer AddonT
 			return fmt.Errorf("rendering addon %q for image discovery: %w", addonKey(a.Spec), err)
 		}
 	}
+	manifestBytes, err = addonmanifests.PatchAddonManifest(fi.ValueOf(a.Spec.Name), patches, manifestBytes)
+	if err != nil {
+		return fmt.Errorf("patching addon %q for image discovery: %w", addonKey(a.Spec), err)
+	}
 	_, err = assetBuilder.RemapManifest(manifestBytes)
 	return err
 }
@@ -1205,6 +1259,8 @@ func (b *BootstrapChannelBuilder) buildAddons(c *fi.CloudupModelBuilderContext)
 		}
 	}
 
+	addons.addDependencies()
+
 	serviceAccounts := make(map[types.NamespacedName]iam.Subject)
 
 	if b.Cluster.GetCloudProvider() == kops.CloudProviderAWS && b.Cluster.Spec.KubeAPIServer.ServiceAccountIssuer != nil {
@@ -0,0 +1,58 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapchannelbuilder
+
+import (
+	"reflect"
+	"testing"
+
+	channelsapi "k8s.io/kops/channels/pkg/api"
+)
+
+func TestAddDependencies(t *testing.T) {
+	addons := &AddonList{}
+	certManager := addons.Add(&channelsapi.AddonSpec{Name: new("certmanager.io")})
+	metricsServer := addons.Add(&channelsapi.AddonSpec{Name: new("metrics-server.addons.k8s.io"), NeedsPKI: true})
+	coreDNS := addons.Add(&channelsapi.AddonSpec{Name: new("coredns.addons.k8s.io")})
+
+	addons.addDependencies()
+
+	if got, want := metricsServer.Spec.DependsOn, []string{"certmanager.io"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected metrics-server to depend on %v, got %v", want, got)
+	}
+	if len(coreDNS.Spec.DependsOn) != 0 {
+		t.Errorf("expected coredns to have no dependencies, got %v", coreDNS.Spec.DependsOn)
+	}
+	// channels only waits for dependencies that set a healthTimeout
+	if certManager.Spec.HealthTimeout == nil || certManager.Spec.HealthTimeout.Duration != dependencyHealthTimeout {
+		t.Errorf("expected cert-manager to have a healthTimeout of %v, got %v", dependencyHealthTimeout, certManager.Spec.HealthTimeout)
+	}
+	if coreDNS.Spec.HealthTimeout != nil {
+		t.Errorf("expected coredns to have no healthTimeout, got %v", coreDNS.Spec.HealthTimeout)
+	}
+}
+
+func TestAddDependenciesWithoutDependents(t *testing.T) {
+	addons := &AddonList{}
+	certManager := addons.Add(&channelsapi.AddonSpec{Name: new("certmanager.io")})
+
+	addons.addDependencies()
+
+	if certManager.Spec.HealthTimeout != nil {
+		t.Errorf("expected cert-manager to have no healthTimeout when nothing depends on it, got %v", certManager.Spec.HealthTimeout)
+	}
+}
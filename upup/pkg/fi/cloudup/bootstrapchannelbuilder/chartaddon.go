@@ -0,0 +1,136 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bootstrapchannelbuilder
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+	channelsapi "k8s.io/kops/channels/pkg/api"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/helm"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/fitasks"
+	"k8s.io/kops/upup/pkg/fi/utils"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// chartNamespace is the namespace charts are installed in if none is specified
+const chartNamespace = "kube-system"
+
+// addChartAddons renders the charts of the cluster addons, and adds them to the channel.
+// Chart archives are vendored into the state store, so that later updates do not need the chart repository.
+func (b *BootstrapChannelBuilder) addChartAddons(c *fi.CloudupModelBuilderContext, addons *AddonList) error {
+	for i := range b.Cluster.Spec.Addons {
+		spec := b.Cluster.Spec.Addons[i].Chart
+		if spec == nil {
+			continue
+		}
+
+		chart, err := b.loadChart(c, spec)
+		if err != nil {
+			return err
+		}
+
+		values := make(map[string]interface{})
+		if spec.Values != "" {
+			if err := utils.YamlUnmarshal([]byte(spec.Values), &values); err != nil {
+				return fmt.Errorf("parsing values of chart %q: %w", spec.Name, err)
+			}
+		}
+
+		releaseName := spec.ReleaseName
+		if releaseName == "" {
+			releaseName = spec.Name
+		}
+		namespace := spec.Namespace
+		if namespace == "" {
+			namespace = chartNamespace
+		}
+
+		manifest, err := helm.Render(chart, helm.RenderOptions{
+			ReleaseName:     releaseName,
+			Namespace:       namespace,
+			Values:          values,
+			KubeVersion:     b.Cluster.Spec.KubernetesVersion,
+			CreateNamespace: true,
+		})
+		if err != nil {
+			return fmt.Errorf("rendering chart %q: %w", spec.Name, err)
+		}
+
+		key := releaseName + ".helm.kops.k8s.io"
+		addon := addons.Add(&channelsapi.AddonSpec{
+			Name:     new(key),
+			Selector: map[string]string{"k8s-addon": key},
+			Manifest: new(key + "/" + spec.Version + ".yaml"),
+			Id:       spec.Version,
+		})
+		addon.Source = fi.NewBytesResource(manifest)
+		addon.SkipRender = true
+		addon.BuildPrune = true
+	}
+	return nil
+}
+
+// loadChart loads a chart from a local directory, from the copy vendored in the state store,
+// or from its repository, in that order of preference.
+func (b *BootstrapChannelBuilder) loadChart(c *fi.CloudupModelBuilderContext, spec *kops.HelmChartSpec) (*helm.Chart, error) {
+	source := helm.ChartSource{
+		Repository: spec.Repository,
+		Name:       spec.Name,
+		Version:    spec.Version,
+	}
+
+	if dir, ok := source.LocalDir(); ok {
+		return helm.LoadDir(dir)
+	}
+
+	location := "addons/charts/" + source.ArchiveName()
+	configBase, err := vfs.Context.BuildVfsPath(b.Cluster.Spec.ConfigStore.Base)
+	if err != nil {
+		return nil, fmt.Errorf("parsing configStore.base %q: %w", b.Cluster.Spec.ConfigStore.Base, err)
+	}
+
+	archive, err := configBase.Join(location).ReadFile(c.Context())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("reading vendored chart %s: %w", source, err)
+		}
+		klog.Infof("fetching chart %s", source)
+		archive, err = helm.FetchArchive(c.Context(), vfs.Context, source)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	chart, err := helm.LoadArchive(archive)
+	if err != nil {
+		return nil, fmt.Errorf("loading chart %s: %w", source, err)
+	}
+
+	c.AddTask(&fitasks.ManagedFile{
+		Name:      new("chart-" + source.ArchiveName()),
+		Lifecycle: b.Lifecycle,
+		Location:  new(location),
+		Contents:  fi.NewBytesResource(archive),
+	})
+
+	return chart, nil
+}
@@ -46,6 +46,8 @@ func TestBootstrapChannelBuilder_BuildTasks(t *testing.T) {
 	h.SetupMockAWS()
 
 	runChannelBuilderTest(t, "simple", []string{"kops-controller.addons.k8s.io-k8s-1.16"})
+	// kops-controller serves its metrics on the node IP
+	runChannelBuilderTest(t, "kops-controller-metrics", []string{"kops-controller.addons.k8s.io-k8s-1.16"})
 	// Clusters with dns=none get an empty dns-controller manifest, so leftover resources are pruned
 	runChannelBuilderTest(t, "dns-none", []string{"dns-controller.addons.k8s.io-k8s-1.12"})
 	// Use cilium networking, proxy
@@ -57,6 +59,10 @@ func TestBootstrapChannelBuilder_BuildTasks(t *testing.T) {
 	runChannelBuilderTest(t, "metrics-server/insecure-1.19", []string{"metrics-server.addons.k8s.io-k8s-1.11"})
 	runChannelBuilderTest(t, "metrics-server/secure-1.19", []string{"metrics-server.addons.k8s.io-k8s-1.11"})
 	runChannelBuilderTest(t, "coredns", []string{"coredns.addons.k8s.io-k8s-1.12"})
+	// Patches from the cluster spec are applied to addon manifests
+	runChannelBuilderTest(t, "addon-patches", []string{"coredns.addons.k8s.io-k8s-1.12"})
+	// Charts are rendered into the channel
+	runChannelBuilderTest(t, "helm-chart", []string{"hello.helm.kops.k8s.io-0.1.0"})
 }
 
 func TestBootstrapChannelBuilder_ServiceAccountIAM(t *testing.T) {
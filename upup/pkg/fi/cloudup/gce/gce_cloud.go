@@ -29,6 +29,7 @@ import (
 
 	"cloud.google.com/go/storage"
 	"golang.org/x/oauth2/google"
+	"google.golang.org/api/cloudkms/v1"
 	"google.golang.org/api/cloudresourcemanager/v1"
 	compute "google.golang.org/api/compute/v1"
 	oauth2 "google.golang.org/api/oauth2/v2"
@@ -56,6 +57,9 @@ type GCECloud interface {
 
 	// CloudResourceManager returns the client for the cloudresourcemanager API
 	CloudResourceManager() *cloudresourcemanager.Service
+
+	// CloudKMS returns the client for the Cloud KMS API
+	CloudKMS() *cloudkms.Service
 }
 
 // MutexForProjectIAM returns a mutex to prevent local concurrent operations on project IAM.
@@ -72,6 +76,9 @@ type gceCloudImplementation struct {
 	// cloudResourceManager is the client for the cloudresourcemanager API
 	cloudResourceManager *cloudresourcemanager.Service
 
+	// cloudKMS is the client for the Cloud KMS API
+	cloudKMS *cloudkms.Service
+
 	region  string
 	project string
 
@@ -169,6 +176,12 @@ func NewGCECloud(region string, project string, labels map[string]string) (GCECl
 	}
 	c.cloudResourceManager = cloudResourceManager
 
+	cloudKMS, err := cloudkms.NewService(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error building Cloud KMS API client: %w", err)
+	}
+	c.cloudKMS = cloudKMS
+
 	CacheGCECloudInstance(region, project, c)
 
 	{
@@ -230,6 +243,11 @@ func (c *gceCloudImplementation) CloudResourceManager() *cloudresourcemanager.Se
 	return c.cloudResourceManager
 }
 
+// CloudKMS returns the client for the Cloud KMS API
+func (c *gceCloudImplementation) CloudKMS() *cloudkms.Service {
+	return c.cloudKMS
+}
+
 // Region returns private struct element region.
 func (c *gceCloudImplementation) Region() string {
 	return c.region
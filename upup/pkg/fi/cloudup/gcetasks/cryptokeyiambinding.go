@@ -0,0 +1,170 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gcetasks
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/api/cloudkms/v1"
+	"k8s.io/klog/v2"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/gce"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraform"
+	"k8s.io/kops/upup/pkg/fi/cloudup/terraformWriter"
+)
+
+// CryptoKeyIAMBinding represents an IAM rule on a Cloud KMS crypto key
+// +kops:fitask
+type CryptoKeyIAMBinding struct {
+	Name      *string
+	Lifecycle fi.Lifecycle
+
+	// CryptoKey is the name of the key, projects/<project>/locations/<location>/keyRings/<ring>/cryptoKeys/<key>
+	CryptoKey            *string
+	MemberServiceAccount *ServiceAccount
+	Role                 *string
+}
+
+var _ fi.CompareWithID = (*CryptoKeyIAMBinding)(nil)
+
+func (e *CryptoKeyIAMBinding) CompareWithID() *string {
+	return e.Name
+}
+
+func (e *CryptoKeyIAMBinding) Find(c *fi.CloudupContext) (*CryptoKeyIAMBinding, error) {
+	ctx := context.TODO()
+
+	cloud := c.T.Cloud.(gce.GCECloud)
+
+	cryptoKey := fi.ValueOf(e.CryptoKey)
+	member := "serviceAccount:" + fi.ValueOf(e.MemberServiceAccount.Email)
+	role := fi.ValueOf(e.Role)
+
+	klog.V(2).Infof("Checking IAM for crypto key %q", cryptoKey)
+	policy, err := cloud.CloudKMS().Projects.Locations.KeyRings.CryptoKeys.GetIamPolicy(cryptoKey).OptionsRequestedPolicyVersion(3).Context(ctx).Do()
+	if err != nil {
+		if gce.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error checking IAM for crypto key %s: %w", cryptoKey, err)
+	}
+
+	changed := patchKMSPolicy(policy, member, role)
+	if changed {
+		return nil, nil
+	}
+
+	actual := &CryptoKeyIAMBinding{}
+	actual.CryptoKey = e.CryptoKey
+	actual.MemberServiceAccount = e.MemberServiceAccount
+	actual.Role = e.Role
+
+	// Ignore "system" fields
+	actual.Name = e.Name
+	actual.Lifecycle = e.Lifecycle
+
+	return actual, nil
+}
+
+func (e *CryptoKeyIAMBinding) Run(c *fi.CloudupContext) error {
+	return fi.CloudupDefaultDeltaRunMethod(e, c)
+}
+
+func (_ *CryptoKeyIAMBinding) CheckChanges(a, e, changes *CryptoKeyIAMBinding) error {
+	if fi.ValueOf(e.CryptoKey) == "" {
+		return fi.RequiredField("CryptoKey")
+	}
+	if e.MemberServiceAccount == nil {
+		return fi.RequiredField("MemberServiceAccount")
+	}
+	if fi.ValueOf(e.MemberServiceAccount.Email) == "" {
+		return fi.RequiredField("MemberServiceAccount.Email")
+	}
+	if fi.ValueOf(e.Role) == "" {
+		return fi.RequiredField("Role")
+	}
+	return nil
+}
+
+func (_ *CryptoKeyIAMBinding) RenderGCE(t *gce.GCEAPITarget, a, e, changes *CryptoKeyIAMBinding) error {
+	ctx := context.TODO()
+
+	cryptoKey := fi.ValueOf(e.CryptoKey)
+	member := "serviceAccount:" + fi.ValueOf(e.MemberServiceAccount.Email)
+	role := fi.ValueOf(e.Role)
+
+	policy, err := t.Cloud.CloudKMS().Projects.Locations.KeyRings.CryptoKeys.GetIamPolicy(cryptoKey).OptionsRequestedPolicyVersion(3).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("error getting IAM policy for crypto key %s: %w", cryptoKey, err)
+	}
+
+	changed := patchKMSPolicy(policy, member, role)
+
+	if !changed {
+		klog.Warningf("did not need to change policy (concurrent change?)")
+		return nil
+	}
+
+	klog.V(2).Infof("updating IAM for crypto key %s", cryptoKey)
+	if _, err := t.Cloud.CloudKMS().Projects.Locations.KeyRings.CryptoKeys.SetIamPolicy(cryptoKey, &cloudkms.SetIamPolicyRequest{Policy: policy}).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("error updating IAM for crypto key %s: %w", cryptoKey, err)
+	}
+
+	return nil
+}
+
+// terraformCryptoKeyIAMMember is the model for a terraform google_kms_crypto_key_iam_member rule
+type terraformCryptoKeyIAMMember struct {
+	CryptoKeyID string                   `cty:"crypto_key_id"`
+	Role        string                   `cty:"role"`
+	Member      *terraformWriter.Literal `cty:"member"`
+}
+
+func (_ *CryptoKeyIAMBinding) RenderTerraform(t *terraform.TerraformTarget, a, e, changes *CryptoKeyIAMBinding) error {
+	tf := &terraformCryptoKeyIAMMember{
+		CryptoKeyID: fi.ValueOf(e.CryptoKey),
+		Role:        fi.ValueOf(e.Role),
+		Member:      e.MemberServiceAccount.TerraformLink_Member(),
+	}
+
+	return t.RenderResource("google_kms_crypto_key_iam_member", *e.Name, tf)
+}
+
+func patchKMSPolicy(policy *cloudkms.Policy, wantMember string, wantRole string) bool {
+	for _, binding := range policy.Bindings {
+		if binding.Condition != nil {
+			continue
+		}
+		if binding.Role != wantRole {
+			continue
+		}
+		for _, member := range binding.Members {
+			if member == wantMember {
+				return false
+			}
+		}
+		binding.Members = append(binding.Members, wantMember)
+		return true
+	}
+
+	policy.Bindings = append(policy.Bindings, &cloudkms.Binding{
+		Members: []string{wantMember},
+		Role:    wantRole,
+	})
+	return true
+}
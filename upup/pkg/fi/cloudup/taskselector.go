@@ -0,0 +1,178 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+)
+
+// TaskSelector selects tasks by type, name and instance group.
+// All the specified conditions must match.
+type TaskSelector struct {
+	// Type matches the task type, such as SecurityGroupRule or LaunchTemplate
+	Type string
+	// Name is a regular expression that must match the whole task name
+	Name *regexp.Regexp
+	// InstanceGroup matches tasks whose name is derived from the name of the instance group
+	InstanceGroup string
+
+	selector string
+}
+
+// ParseTaskSelector parses a selector such as "type=LaunchTemplate,instancegroup=gpu-a"
+func ParseTaskSelector(s string) (*TaskSelector, error) {
+	selector := &TaskSelector{selector: s}
+	for _, term := range strings.Split(s, ",") {
+		key, value, ok := strings.Cut(term, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid task selector %q: expected key=value terms separated by commas", s)
+		}
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "type":
+			selector.Type = value
+		case "name":
+			re, err := regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid name in task selector %q: %w", s, err)
+			}
+			selector.Name = re
+		case "instancegroup":
+			selector.InstanceGroup = value
+		default:
+			return nil, fmt.Errorf("invalid task selector %q: unknown key %q, expected one of type, name or instancegroup", s, key)
+		}
+	}
+	return selector, nil
+}
+
+func (s *TaskSelector) String() string {
+	return s.selector
+}
+
+// matches returns true if the task matches all the conditions of the selector
+func (s *TaskSelector) matches(key string, task fi.CloudupTask, instanceGroup string) bool {
+	if s.Type != "" && !strings.EqualFold(s.Type, fi.TypeNameForTask(task)) {
+		return false
+	}
+	_, name, _ := strings.Cut(key, "/")
+	if s.Name != nil && !s.Name.MatchString(name) {
+		return false
+	}
+	if s.InstanceGroup != "" && s.InstanceGroup != instanceGroup {
+		return false
+	}
+	return true
+}
+
+// instanceGroupForTaskName returns the instance group that the task name is derived from, if any.
+// Task names are typically the instance group name, optionally followed by "." or "-" and the cluster name,
+// or prefixed by a qualifier and "-". If several instance groups match, the longest name wins.
+func instanceGroupForTaskName(name string, instanceGroups []*kops.InstanceGroup) string {
+	match := ""
+	for _, ig := range instanceGroups {
+		igName := ig.ObjectMeta.Name
+		if len(igName) <= len(match) {
+			continue
+		}
+		if name == igName || strings.HasPrefix(name, igName+".") || strings.HasPrefix(name, igName+"-") || strings.HasSuffix(name, "-"+igName) {
+			match = igName
+		}
+	}
+	return match
+}
+
+// applyTaskSelectors limits the changes that will be made to the tasks matching any of the selectors.
+// Tasks that do not match are changed to ExistsAndWarnIfChanges, except for the dependencies of selected tasks,
+// which must exist and match (ExistsAndValidates) when we are making changes.
+func applyTaskSelectors(taskMap map[string]fi.CloudupTask, selectors []*TaskSelector, instanceGroups []*kops.InstanceGroup, dryRun bool) error {
+	selected := make(map[string]bool)
+	for key, task := range taskMap {
+		instanceGroup := instanceGroupForTaskName(strings.TrimPrefix(key, fi.TypeNameForTask(task)+"/"), instanceGroups)
+		for _, selector := range selectors {
+			if selector.matches(key, task, instanceGroup) {
+				selected[key] = true
+				break
+			}
+		}
+	}
+	if len(selected) == 0 {
+		var s []string
+		for _, selector := range selectors {
+			s = append(s, fmt.Sprintf("%q", selector))
+		}
+		return fmt.Errorf("no tasks matched the task selectors %s", strings.Join(s, ", "))
+	}
+
+	// Find the (transitive) dependencies of the selected tasks
+	dependencies := fi.FindTaskDependencies(taskMap)
+	required := make(map[string]bool)
+	var queue []string
+	for key := range selected {
+		queue = append(queue, key)
+	}
+	for len(queue) != 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, dep := range dependencies[key] {
+			if !selected[dep] && !required[dep] {
+				required[dep] = true
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	dependencyLifecycle := fi.LifecycleExistsAndValidates
+	if dryRun {
+		dependencyLifecycle = fi.LifecycleExistsAndWarnIfChanges
+	}
+
+	var keys []string
+	for key := range selected {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	klog.Infof("Selected %d tasks: %s", len(keys), strings.Join(keys, ", "))
+
+	for key, task := range taskMap {
+		if selected[key] {
+			continue
+		}
+		hl, ok := task.(fi.HasLifecycle)
+		if !ok {
+			klog.Warningf("task %s does not implement HasLifecycle; it cannot be excluded by task selectors", key)
+			continue
+		}
+		switch hl.GetLifecycle() {
+		case fi.LifecycleSync, fi.LifecycleWarnIfInsufficientAccess:
+			if required[key] {
+				klog.V(2).Infof("task %s is a dependency of a selected task, setting lifecycle %s", key, dependencyLifecycle)
+				hl.SetLifecycle(dependencyLifecycle)
+			} else {
+				hl.SetLifecycle(fi.LifecycleExistsAndWarnIfChanges)
+			}
+		}
+	}
+
+	return nil
+}
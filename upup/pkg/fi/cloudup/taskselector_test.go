@@ -0,0 +1,103 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloudup
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/upup/pkg/fi/cloudup/awstasks"
+)
+
+func TestParseTaskSelector(t *testing.T) {
+	selector, err := ParseTaskSelector("type=SecurityGroupRule,name=nodes.*,instancegroup=gpu-a")
+	require.NoError(t, err)
+	assert.Equal(t, "SecurityGroupRule", selector.Type)
+	assert.Equal(t, "gpu-a", selector.InstanceGroup)
+	assert.True(t, selector.Name.MatchString("nodes-to-master"))
+	assert.False(t, selector.Name.MatchString("from-nodes"), "name should be anchored")
+
+	for _, s := range []string{"", "type", "type=", "kind=Foo", "name=("} {
+		_, err := ParseTaskSelector(s)
+		assert.Error(t, err, "selector %q", s)
+	}
+}
+
+func TestInstanceGroupForTaskName(t *testing.T) {
+	igs := []*kops.InstanceGroup{
+		{ObjectMeta: metav1.ObjectMeta{Name: "nodes"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "nodes-gpu"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "control-plane-us-east-1a"}},
+	}
+	grid := map[string]string{
+		"nodes.example.com":                "nodes",
+		"nodes-gpu.example.com":            "nodes-gpu",
+		"control-plane-us-east-1a.masters": "control-plane-us-east-1a",
+		"from-nodes-gpu":                   "nodes-gpu",
+		"masters.example.com":              "",
+	}
+	for name, expected := range grid {
+		assert.Equal(t, expected, instanceGroupForTaskName(name, igs), "task name %q", name)
+	}
+}
+
+func TestApplyTaskSelectors(t *testing.T) {
+	vpc := &awstasks.VPC{Name: new("vpc"), Lifecycle: fi.LifecycleSync}
+	nodesGroup := &awstasks.SecurityGroup{Name: new("nodes.example.com"), Lifecycle: fi.LifecycleSync, VPC: vpc}
+	mastersGroup := &awstasks.SecurityGroup{Name: new("masters.example.com"), Lifecycle: fi.LifecycleSync, VPC: vpc}
+	nodesRule := &awstasks.SecurityGroupRule{Name: new("nodes-egress"), Lifecycle: fi.LifecycleSync, SecurityGroup: nodesGroup}
+	mastersRule := &awstasks.SecurityGroupRule{Name: new("masters-egress"), Lifecycle: fi.LifecycleSync, SecurityGroup: mastersGroup}
+	ignoredRule := &awstasks.SecurityGroupRule{Name: new("nodes-ignored"), Lifecycle: fi.LifecycleIgnore, SecurityGroup: nodesGroup}
+
+	newTaskMap := func() map[string]fi.CloudupTask {
+		taskMap := make(map[string]fi.CloudupTask)
+		for _, task := range []fi.CloudupTask{vpc, nodesGroup, mastersGroup, nodesRule, mastersRule, ignoredRule} {
+			taskMap[fi.TypeNameForTask(task)+"/"+*task.(fi.HasName).GetName()] = task
+		}
+		return taskMap
+	}
+
+	selector, err := ParseTaskSelector("type=SecurityGroupRule,name=nodes-.*")
+	require.NoError(t, err)
+
+	for _, dryRun := range []bool{false, true} {
+		for _, task := range []fi.HasLifecycle{vpc, nodesGroup, mastersGroup, nodesRule, mastersRule} {
+			task.SetLifecycle(fi.LifecycleSync)
+		}
+
+		require.NoError(t, applyTaskSelectors(newTaskMap(), []*TaskSelector{selector}, nil, dryRun))
+
+		dependencyLifecycle := fi.LifecycleExistsAndValidates
+		if dryRun {
+			dependencyLifecycle = fi.LifecycleExistsAndWarnIfChanges
+		}
+		assert.Equal(t, fi.LifecycleSync, nodesRule.Lifecycle, "selected task")
+		assert.Equal(t, dependencyLifecycle, nodesGroup.Lifecycle, "direct dependency")
+		assert.Equal(t, dependencyLifecycle, vpc.Lifecycle, "transitive dependency")
+		assert.Equal(t, fi.LifecycleExistsAndWarnIfChanges, mastersGroup.Lifecycle, "unselected task")
+		assert.Equal(t, fi.LifecycleExistsAndWarnIfChanges, mastersRule.Lifecycle, "unselected task")
+		assert.Equal(t, fi.LifecycleIgnore, ignoredRule.Lifecycle, "ignored task should not be changed")
+	}
+
+	noMatch, err := ParseTaskSelector("type=LaunchTemplate")
+	require.NoError(t, err)
+	assert.Error(t, applyTaskSelectors(newTaskMap(), []*TaskSelector{noMatch}, nil, false))
+}
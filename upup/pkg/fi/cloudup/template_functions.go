@@ -52,6 +52,7 @@ import (
 	"k8s.io/kops/pkg/apis/kops"
 	apiModel "k8s.io/kops/pkg/apis/kops/model"
 	"k8s.io/kops/pkg/apis/kops/util"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
 	"k8s.io/kops/pkg/bootstrap/awsbootstrap"
 	"k8s.io/kops/pkg/bootstrap/pkibootstrap"
 	"k8s.io/kops/pkg/featureflag"
@@ -78,6 +79,7 @@ import (
 	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway"
 	"k8s.io/kops/upup/pkg/fi/cloudup/scaleway/scalewaymetadata"
 	"k8s.io/kops/util/pkg/env"
+	"k8s.io/kops/util/pkg/vfs"
 	"sigs.k8s.io/yaml"
 )
 
@@ -214,6 +216,11 @@ func (tf *TemplateFunctions) AddTo(dest template.FuncMap, secretStore fi.SecretS
 
 	dest["KopsControllerArgv"] = tf.KopsControllerArgv
 	dest["KopsControllerConfig"] = tf.KopsControllerConfig
+	dest["KopsControllerStateStoreHostPaths"] = tf.KopsControllerStateStoreHostPaths
+	dest["KopsControllerStateStoreGroups"] = tf.KopsControllerStateStoreGroups
+	dest["KopsControllerVaultSecretIDFile"] = tf.KopsControllerVaultSecretIDFile
+	dest["KopsControllerExposeMetrics"] = tf.KopsControllerExposeMetrics
+	dest["KopsControllerMetricsPort"] = func() int { return wellknownports.KopsControllerMetricsPort }
 	dest["DnsControllerArgv"] = tf.DNSControllerArgv
 	dest["ExternalDnsArgv"] = tf.ExternalDNSArgv
 	dest["CloudControllerConfigArgv"] = tf.CloudControllerConfigArgv
@@ -828,27 +835,14 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 	}
 
 	{
-		certNames := []string{"kubelet", "kubelet-server"}
-		signingCAs := []string{fi.CertificateIDCA}
-		if apiModel.UseCiliumEtcd(cluster) {
-			certNames = append(certNames, "etcd-client-cilium")
-			signingCAs = append(signingCAs, "etcd-clients-ca-cilium")
-		}
-		if cluster.Spec.KubeProxy.Enabled == nil || *cluster.Spec.KubeProxy.Enabled {
-			certNames = append(certNames, "kube-proxy")
-		}
-		if cluster.Spec.Networking.KubeRouter != nil {
-			certNames = append(certNames, "kube-router")
-		}
-
 		pkiDir := "/etc/kubernetes/kops-controller/pki"
 		config.Server = &kopscontrollerconfig.ServerOptions{
 			Listen:                fmt.Sprintf(":%d", wellknownports.KopsControllerPort),
 			ServerCertificatePath: path.Join(pkiDir, "kops-controller.crt"),
 			ServerKeyPath:         path.Join(pkiDir, "kops-controller.key"),
 			CABasePath:            pkiDir,
-			SigningCAs:            signingCAs,
-			CertNames:             certNames,
+			SigningCAs:            apiModel.KopsControllerSigningCAs(cluster),
+			CertNames:             apiModel.KopsControllerCertNames(cluster),
 		}
 
 		if featureflag.Metal.Enabled() {
@@ -922,6 +916,32 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 		default:
 			return "", fmt.Errorf("unsupported cloud provider %s", cluster.GetCloudProvider())
 		}
+
+		if cluster.Spec.NodeAttestation != nil {
+			attestation, err := buildAttestationOptions(cluster.Spec.NodeAttestation)
+			if err != nil {
+				return "", err
+			}
+			config.Server.Provider.Attestation = attestation
+		}
+
+		if cluster.Spec.KopsController != nil && cluster.Spec.KopsController.Bootstrap != nil {
+			bootstrap := cluster.Spec.KopsController.Bootstrap
+			config.Server.Bootstrap = &kopscontrollerconfig.BootstrapLimitsOptions{
+				RequestsPerMinute:     bootstrap.RequestsPerMinute,
+				Burst:                 bootstrap.Burst,
+				NodeRequestsPerMinute: bootstrap.NodeRequestsPerMinute,
+				NodeBurst:             bootstrap.NodeBurst,
+				MinReissueInterval:    bootstrap.MinReissueInterval,
+			}
+		}
+	}
+
+	if cluster.Spec.KopsController != nil && cluster.Spec.KopsController.Metrics != nil {
+		metrics := cluster.Spec.KopsController.Metrics
+		config.MetricsAddress = fmt.Sprintf("127.0.0.1:%d", wellknownports.KopsControllerMetricsPort)
+		config.MetricsOnHostIP = fi.ValueOf(metrics.Expose)
+		config.CertificateExpiryHorizon = metrics.CertificateExpiryHorizon
 	}
 
 	if cluster.Spec.IsKopsControllerIPAM() {
@@ -937,6 +957,34 @@ func (tf *TemplateFunctions) KopsControllerConfig() (string, error) {
 	return string(b), nil
 }
 
+// buildAttestationOptions converts the cluster's nodeAttestation into kops-controller verifier options.
+func buildAttestationOptions(spec *kops.NodeAttestationSpec) (*attestbootstrap.Options, error) {
+	options := &attestbootstrap.Options{
+		MaxTimeSkew: 300,
+	}
+	if spec.TPM != nil {
+		options.TPM = &attestbootstrap.TPMOptions{}
+		for k, v := range spec.TPM.PCRs {
+			index, err := strconv.ParseUint(k, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("parsing PCR index %q: %w", k, err)
+			}
+			if options.TPM.PCRs == nil {
+				options.TPM.PCRs = make(map[uint32]string)
+			}
+			options.TPM.PCRs[uint32(index)] = v
+		}
+	}
+	if spec.SPIFFE != nil {
+		options.SPIFFE = &attestbootstrap.SPIFFEOptions{
+			TrustDomain: spec.SPIFFE.TrustDomain,
+			TrustBundle: spec.SPIFFE.TrustBundle,
+			PathPrefix:  spec.SPIFFE.PathPrefix,
+		}
+	}
+	return options, nil
+}
+
 // KopsControllerArgv returns the args to kops-controller
 func (tf *TemplateFunctions) KopsControllerArgv() ([]string, error) {
 	var argv []string
@@ -1037,10 +1085,94 @@ func (tf *TemplateFunctions) ProxyEnv() map[string]string {
 	return envs
 }
 
+// KopsControllerStateStoreHostPaths returns the directories of a local filesystem state store that
+// kops-controller reads from, which must be mounted from the control plane hosts.
+func (tf *TemplateFunctions) KopsControllerStateStoreHostPaths() ([]string, error) {
+	var dirs []string
+	configStore := tf.Cluster.Spec.ConfigStore
+	for _, location := range []string{configStore.Base, configStore.Keypairs, configStore.Secrets} {
+		if location == "" {
+			continue
+		}
+		p, err := vfs.Context.BuildVfsPath(location)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing state store path %q: %w", location, err)
+		}
+		if _, ok := p.(*vfs.FSPath); !ok {
+			continue
+		}
+		dir := path.Clean(p.Path())
+		if !path.IsAbs(dir) {
+			return nil, fmt.Errorf("local state store path %q must be absolute", location)
+		}
+
+		// We don't need to mount a directory that is already mounted as part of its parent
+		covered := false
+		for i, other := range dirs {
+			if dir == other || strings.HasPrefix(dir, other+"/") {
+				covered = true
+			} else if strings.HasPrefix(other, dir+"/") {
+				dirs[i] = dir
+				covered = true
+			}
+		}
+		if !covered {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
+
+// KopsControllerStateStoreGroups returns the groups of the directories of a local filesystem state store,
+// which kops-controller joins so that it can read the group-readable files.
+// The directories are shared with the control plane hosts, so they have the same group IDs there.
+func (tf *TemplateFunctions) KopsControllerStateStoreGroups() ([]int64, error) {
+	dirs, err := tf.KopsControllerStateStoreHostPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []int64
+	for _, dir := range dirs {
+		gid, err := vfs.NewFSPath(dir).GroupID()
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(groups, gid) {
+			groups = append(groups, gid)
+		}
+	}
+	return groups, nil
+}
+
+// KopsControllerVaultSecretIDFile returns the host file with the Vault AppRole secret ID that is mounted into kops-controller,
+// or an empty string if the cluster doesn't use Vault.
+func (tf *TemplateFunctions) KopsControllerVaultSecretIDFile() string {
+	if !tf.Cluster.UsesVault() {
+		return ""
+	}
+	return vfs.VaultSecretIDFile
+}
+
+// KopsControllerExposeMetrics returns true if kops-controller serves its metrics on the IP address of the control plane node.
+func (tf *TemplateFunctions) KopsControllerExposeMetrics() bool {
+	kopsController := tf.Cluster.Spec.KopsController
+	return kopsController != nil && kopsController.Metrics != nil && fi.ValueOf(kopsController.Metrics.Expose)
+}
+
 // KopsControllerEnv builds the env vars for the kops-controller component
-func (tf *TemplateFunctions) KopsControllerEnv() []corev1.EnvVar {
+func (tf *TemplateFunctions) KopsControllerEnv() ([]corev1.EnvVar, error) {
 	envMap := env.BuildSystemComponentEnvVars(&tf.Cluster.Spec)
 
+	// kops-controller reads the keypairs and secrets from Vault if they are stored there
+	if tf.Cluster.UsesVault() {
+		vaultEnv, err := vfs.VaultControlPlaneEnv()
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(envMap, vaultEnv)
+	}
+
 	// kops-controller needs the KOPS_RUN_TOO_NEW_VERSION env var to run newer versions of kubernetes
 	// (if building bootstrap configuration on the fly)
 	if v := os.Getenv("KOPS_RUN_TOO_NEW_VERSION"); v != "" {
@@ -1052,7 +1184,7 @@ func (tf *TemplateFunctions) KopsControllerEnv() []corev1.EnvVar {
 		envMap["KOPS_BASE_URL"] = v
 	}
 
-	return envMap.ToEnvVars()
+	return envMap.ToEnvVars(), nil
 }
 
 // OpenStackCCMTag returns OpenStack external cloud controller manager current image
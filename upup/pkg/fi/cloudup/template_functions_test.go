@@ -19,13 +19,16 @@ package cloudup
 import (
 	"bytes"
 	"fmt"
+	"os"
 	"reflect"
+	"runtime"
 	"testing"
 	stdtemplate "text/template"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	gcemock "k8s.io/kops/cloudmock/gce"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/bootstrap/attestbootstrap"
 	"k8s.io/kops/pkg/featureflag"
 	"k8s.io/kops/third_party/forked/text/template"
 	"k8s.io/kops/upup/pkg/fi"
@@ -449,6 +452,170 @@ func TestHasHighlyAvailableControlPlane(t *testing.T) {
 	}
 }
 
+func TestKopsControllerStateStoreHostPaths(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		keypairs string
+		secrets  string
+		expected []string
+	}{
+		{
+			name:    "Object storage",
+			base:    "s3://bucket/cluster.example.com",
+			secrets: "s3://bucket/cluster.example.com/secrets",
+		},
+		{
+			name:     "Local state store",
+			base:     "/srv/kops/cluster.example.com",
+			secrets:  "/srv/kops/cluster.example.com/secrets",
+			expected: []string{"/srv/kops/cluster.example.com"},
+		},
+		{
+			name:     "Local state store with file scheme",
+			base:     "file:///srv/kops/cluster.example.com/",
+			secrets:  "file:///srv/kops/cluster.example.com/secrets",
+			expected: []string{"/srv/kops/cluster.example.com"},
+		},
+		{
+			name:     "Secrets outside the local state store",
+			base:     "/srv/kops/cluster.example.com",
+			secrets:  "/srv/secrets/cluster.example.com",
+			expected: []string{"/srv/kops/cluster.example.com", "/srv/secrets/cluster.example.com"},
+		},
+		{
+			name:     "Only secrets in a local directory",
+			base:     "s3://bucket/cluster.example.com",
+			secrets:  "/srv/secrets/cluster.example.com",
+			expected: []string{"/srv/secrets/cluster.example.com"},
+		},
+		{
+			name:     "Keypairs outside the local state store",
+			base:     "/srv/kops/cluster.example.com",
+			keypairs: "/srv/pki/cluster.example.com",
+			secrets:  "/srv/kops/cluster.example.com/secrets",
+			expected: []string{"/srv/kops/cluster.example.com", "/srv/pki/cluster.example.com"},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			tf := &TemplateFunctions{}
+			tf.Cluster = &kops.Cluster{}
+			tf.Cluster.Spec.ConfigStore.Base = tc.base
+			tf.Cluster.Spec.ConfigStore.Keypairs = tc.keypairs
+			tf.Cluster.Spec.ConfigStore.Secrets = tc.secrets
+
+			actual, err := tf.KopsControllerStateStoreHostPaths()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(actual, tc.expected) {
+				t.Errorf("expected %v, got %v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestKopsControllerStateStoreGroups(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows has no numeric group IDs")
+	}
+
+	dir := t.TempDir()
+	tf := &TemplateFunctions{}
+	tf.Cluster = &kops.Cluster{}
+	tf.Cluster.Spec.ConfigStore.Base = "s3://bucket/cluster.example.com"
+	groups, err := tf.KopsControllerStateStoreGroups()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups) != 0 {
+		t.Errorf("expected no groups for object storage, got %v", groups)
+	}
+
+	tf.Cluster.Spec.ConfigStore.Base = dir
+	tf.Cluster.Spec.ConfigStore.Secrets = dir + "/secrets"
+	groups, err = tf.KopsControllerStateStoreGroups()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected := []int64{int64(os.Getegid())}; !reflect.DeepEqual(groups, expected) {
+		t.Errorf("expected %v, got %v", expected, groups)
+	}
+}
+
+func TestKopsControllerVault(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "https://vault.example.com:8200")
+	t.Setenv("VAULT_ROLE_ID", "kops-role")
+	t.Setenv("VAULT_SECRET_ID", "kops-secret")
+	t.Setenv("VAULT_CACERT", "")
+
+	tf := &TemplateFunctions{}
+	tf.Cluster = &kops.Cluster{}
+	tf.Cluster.Spec.ConfigStore.Base = "s3://bucket/cluster.example.com"
+	if file := tf.KopsControllerVaultSecretIDFile(); file != "" {
+		t.Errorf("expected no vault secret ID file without vault, got %q", file)
+	}
+
+	tf.Cluster.Spec.ConfigStore.Keypairs = "vault://secret/kops/pki"
+	if file := tf.KopsControllerVaultSecretIDFile(); file != "/etc/kubernetes/vault/secret-id" {
+		t.Errorf("unexpected vault secret ID file %q", file)
+	}
+
+	envVars, err := tf.KopsControllerEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	env := make(map[string]string)
+	for _, envVar := range envVars {
+		env[envVar.Name] = envVar.Value
+	}
+	if env["VAULT_ROLE_ID"] != "kops-role" || env["VAULT_SECRET_ID_FILE"] != "/etc/kubernetes/vault/secret-id" {
+		t.Errorf("kops-controller did not get the vault settings: %v", env)
+	}
+	if _, found := env["VAULT_SECRET_ID"]; found {
+		t.Errorf("the vault secret ID should not be in the kops-controller manifest: %v", env)
+	}
+}
+
+func TestBuildAttestationOptions(t *testing.T) {
+	spec := &kops.NodeAttestationSpec{
+		TPM: &kops.TPMAttestationSpec{
+			PCRs: map[string]string{"7": "ab"},
+		},
+		SPIFFE: &kops.SPIFFEAttestationSpec{
+			TrustDomain: "example.org",
+			TrustBundle: "bundle",
+			PathPrefix:  "/clusters/dev",
+		},
+	}
+
+	actual, err := buildAttestationOptions(spec)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected := &attestbootstrap.Options{
+		MaxTimeSkew: 300,
+		TPM: &attestbootstrap.TPMOptions{
+			PCRs: map[uint32]string{7: "ab"},
+		},
+		SPIFFE: &attestbootstrap.SPIFFEOptions{
+			TrustDomain: "example.org",
+			TrustBundle: "bundle",
+			PathPrefix:  "/clusters/dev",
+		},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %+v, got %+v", expected, actual)
+	}
+
+	spec.TPM.PCRs = map[string]string{"seven": "ab"}
+	if _, err := buildAttestationOptions(spec); err == nil {
+		t.Errorf("expected error for non-numeric PCR index")
+	}
+}
+
 func TestTemplateFunctions_TaskHelpers(t *testing.T) {
 	tf := &TemplateFunctions{}
 	tf.Cluster = &kops.Cluster{}
@@ -40,6 +40,9 @@ type Context[T SubContext] struct {
 
 	deletionProcessingMode DeletionProcessingMode
 
+	// plan, if set, restricts the changes we make to those in a saved plan
+	plan *planEnforcer
+
 	T T
 }
 
@@ -120,6 +123,22 @@ func NewCloudupContext(ctx context.Context, deletionProcessingMode DeletionProce
 	return newContext[CloudupSubContext](ctx, deletionProcessingMode, target, sub, tasks)
 }
 
+// EnforcePlan restricts the changes made by RunTasks to those in the plan.
+// Each change is checked against the plan after the task finds the existing object and before it is changed,
+// so any other change fails the run before it is made.
+func (c *Context[T]) EnforcePlan(plan *SavedPlan) {
+	c.plan = &planEnforcer{plan: plan}
+}
+
+// PlannedChangesNotMade returns the keys of the changes in the plan passed to EnforcePlan that were not made,
+// typically because they were no longer needed.
+func (c *Context[T]) PlannedChangesNotMade() []string {
+	if c.plan == nil {
+		return nil
+	}
+	return c.plan.notMade()
+}
+
 func (c *Context[T]) AllTasks() map[string]Task[T] {
 	return c.tasks
 }
@@ -191,6 +210,16 @@ func (c *Context[T]) Render(a, e, changes Task[T]) error {
 		return c.Target.(*DryRunTarget[T]).Render(a, e, changes)
 	}
 
+	if c.plan != nil {
+		actual, err := savedPlanTask(a, e, changes, reflect.ValueOf(a).IsNil())
+		if err != nil {
+			return err
+		}
+		if err := c.plan.check(buildTaskKey(e), actual); err != nil {
+			return err
+		}
+	}
+
 	v := reflect.ValueOf(e)
 	vType := v.Type()
 
@@ -112,7 +112,7 @@ func defaultDeltaRunMethod[T SubContext](e Task[T], c *Context[T]) error {
 		}
 		for _, deletion := range deletions {
 			if _, ok := c.Target.(*DryRunTarget[T]); ok {
-				if err := c.Target.(*DryRunTarget[T]).RecordDeletion(deletion); err != nil {
+				if err := c.Target.(*DryRunTarget[T]).recordDeletion(deletion, lifecycle); err != nil {
 					return err
 				}
 			} else {
@@ -127,6 +127,11 @@ func defaultDeltaRunMethod[T SubContext](e Task[T], c *Context[T]) error {
 						klog.Fatalf("unhandled deletionProcessingMode %v", c.deletionProcessingMode)
 					}
 				}
+				if c.plan != nil {
+					if err := c.plan.check(deletionKey(deletion), savedPlanDeletion(deletion)); err != nil {
+						return err
+					}
+				}
 				if err := deletion.Delete(c.Target); err != nil {
 					return err
 				}
@@ -40,6 +40,9 @@ type DryRunTarget[T SubContext] struct {
 	changes   []*render[T]
 	deletions []Deletion[T]
 
+	// deletionLifecycles holds the lifecycle of the task that produced each deletion, keyed by deletionKey
+	deletionLifecycles map[string]Lifecycle
+
 	// The destination to which the final report will be printed on Finish()
 	out io.Writer
 
@@ -120,14 +123,29 @@ func (t *DryRunTarget[T]) Render(a, e, changes Task[T]) error {
 }
 
 func (t *DryRunTarget[T]) RecordDeletion(deletion Deletion[T]) error {
+	return t.recordDeletion(deletion, "")
+}
+
+// recordDeletion records a deletion, along with the lifecycle of the task that produced it
+func (t *DryRunTarget[T]) recordDeletion(deletion Deletion[T], lifecycle Lifecycle) error {
 	t.mutex.Lock()
 	defer t.mutex.Unlock()
 
 	t.deletions = append(t.deletions, deletion)
+	if lifecycle != "" {
+		if t.deletionLifecycles == nil {
+			t.deletionLifecycles = make(map[string]Lifecycle)
+		}
+		t.deletionLifecycles[deletionKey(deletion)] = lifecycle
+	}
 
 	return nil
 }
 
+func deletionKey[T SubContext](deletion Deletion[T]) string {
+	return deletion.TaskName() + "/" + deletion.Item()
+}
+
 func idForTask[T SubContext](taskMap map[string]Task[T], t Task[T]) string {
 	for k, v := range taskMap {
 		if v == t {
@@ -168,56 +186,11 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 				taskName := getTaskName(r.changes)
 				fmt.Fprintf(b, "  %s/%s\n", taskName, idForTask(taskMap, r.e))
 
-				changes := reflect.ValueOf(r.changes)
-				if changes.Kind() == reflect.Ptr && !changes.IsNil() {
-					changes = changes.Elem()
-				}
-
-				if changes.Kind() == reflect.Struct {
-					for i := 0; i < changes.NumField(); i++ {
-
-						field := changes.Field(i)
-
-						fieldName := changes.Type().Field(i).Name
-						if changes.Type().Field(i).PkgPath != "" {
-							// Not exported
-							continue
-						}
-
-						fieldValue := reflectutils.ValueAsString(field)
-
-						shouldPrint := true
-						if fieldName == "Name" {
-							// The field name is already printed above, no need to repeat it.
-							shouldPrint = false
-						}
-						if fieldName == "Lifecycle" {
-							// Lifecycle is a "system" field; no need to show it
-							shouldPrint = false
-						}
-						if fieldValue == "<nil>" || fieldValue == "<resource>" {
-							// Uninformative
-							shouldPrint = false
-						}
-						if fieldValue == "id:<nil>" {
-							// Uninformative, but we can often print the name instead
-							name := ""
-							if field.CanInterface() {
-								hasName, ok := field.Interface().(HasName)
-								if ok {
-									name = ValueOf(hasName.GetName())
-								}
-							}
-							if name != "" {
-								fieldValue = "name:" + name
-							} else {
-								shouldPrint = false
-							}
-						}
-						if shouldPrint {
-							fmt.Fprintf(b, "  \t%-20s\t%s\n", fieldName, fieldValue)
-						}
+				for _, change := range buildCreateList(r.changes) {
+					if change.hideInReport {
+						continue
 					}
+					fmt.Fprintf(b, "  \t%-20s\t%s\n", change.FieldName, change.Description)
 				}
 
 				fmt.Fprintf(b, "\n")
@@ -315,6 +288,97 @@ func (t *DryRunTarget[T]) PrintReport(taskMap map[string]Task[T], out io.Writer)
 type change struct {
 	FieldName   string
 	Description string
+
+	// Old and New are the actual and expected values of the field; the full contents are used for resources
+	Old string
+	New string
+
+	// hideInReport is true for fields that are too verbose to be shown in the human-readable report
+	hideInReport bool
+
+	// resource is true if Old and New are the contents of resources
+	resource bool
+
+	// redacted is true if the field is tagged to never be shown
+	redacted bool
+}
+
+// buildCreateList returns the fields that will be set when creating an object
+func buildCreateList[T SubContext](changes Task[T]) []change {
+	var changeList []change
+
+	valC := reflect.ValueOf(changes)
+	if valC.Kind() == reflect.Ptr && !valC.IsNil() {
+		valC = valC.Elem()
+	}
+
+	if valC.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < valC.NumField(); i++ {
+		field := valC.Field(i)
+
+		fieldName := valC.Type().Field(i).Name
+		if valC.Type().Field(i).PkgPath != "" {
+			// Not exported
+			continue
+		}
+
+		fieldValue := reflectutils.ValueAsString(field)
+		newValue := fieldValue
+		hideInReport := false
+		resource := false
+
+		shouldPrint := true
+		if fieldName == "Name" {
+			// The field name is already printed above, no need to repeat it.
+			shouldPrint = false
+		}
+		if fieldName == "Lifecycle" {
+			// Lifecycle is a "system" field; no need to show it
+			shouldPrint = false
+		}
+		if fieldValue == "<nil>" {
+			// Uninformative
+			shouldPrint = false
+		}
+		if fieldValue == "<resource>" {
+			// Uninformative in the report, but the contents are included in the plan
+			if res, ok := tryResourceAsString(field); ok {
+				newValue = res
+				hideInReport = true
+				resource = true
+			} else {
+				shouldPrint = false
+			}
+		}
+		if fieldValue == "id:<nil>" {
+			// Uninformative, but we can often print the name instead
+			name := ""
+			if field.CanInterface() {
+				hasName, ok := field.Interface().(HasName)
+				if ok {
+					name = ValueOf(hasName.GetName())
+				}
+			}
+			if name != "" {
+				fieldValue = "name:" + name
+				newValue = fieldValue
+			} else {
+				shouldPrint = false
+			}
+		}
+		redacted := isRedactedField(valC.Type().Field(i))
+		if redacted {
+			fieldValue = RedactedValue
+		}
+		if shouldPrint {
+			changeList = append(changeList, change{FieldName: fieldName, Description: fieldValue, New: newValue, hideInReport: hideInReport, resource: resource, redacted: redacted})
+		}
+	}
+
+	return changeList
 }
 
 func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
@@ -373,6 +437,9 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 
 			description := ""
 			ignored := false
+			resource := false
+			oldValue := reflectutils.ValueAsString(fieldValA)
+			newValue := reflectutils.ValueAsString(fieldValE)
 			if fieldValE.CanInterface() {
 
 				switch fieldValE.Interface().(type) {
@@ -383,17 +450,24 @@ func buildChangeList[T SubContext](a, e, changes Task[T]) ([]change, error) {
 					resE, okE := tryResourceAsString(fieldValE)
 					if okA && okE {
 						description = diff.FormatDiff(resA, resE)
+						oldValue = resA
+						newValue = resE
+						resource = true
 					}
 				}
 
 				if !ignored && description == "" {
-					description = fmt.Sprintf(" %v -> %v", reflectutils.ValueAsString(fieldValA), reflectutils.ValueAsString(fieldValE))
+					description = fmt.Sprintf(" %v -> %v", oldValue, newValue)
 				}
 			}
 			if ignored {
 				continue
 			}
-			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description})
+			redacted := isRedactedField(valC.Type().Field(i))
+			if redacted {
+				description = fmt.Sprintf(" %v -> %v", RedactedValue, RedactedValue)
+			}
+			changeList = append(changeList, change{FieldName: valC.Type().Field(i).Name, Description: description, Old: oldValue, New: newValue, resource: resource, redacted: redacted})
 		}
 	} else {
 		return nil, fmt.Errorf("unhandled change type: %v", valC.Type())
@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -40,11 +41,19 @@ type taskState[T SubContext] struct {
 	deadline     time.Time
 	lastError    error
 	dependencies []*taskState[T]
+
+	// attempts, startTime and endTime describe the most recent attempt to run the task
+	attempts  int
+	startTime time.Time
+	endTime   time.Time
 }
 
 type RunTasksOptions struct {
 	MaxTaskDuration         time.Duration
 	WaitAfterAllTasksFailed time.Duration
+
+	// EventSink, if set, receives a TaskEvent for every attempt to run a task
+	EventSink TaskEventSink
 }
 
 func (o *RunTasksOptions) InitDefaults() {
@@ -86,6 +95,12 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 	}
 
 	for {
+		// Stop before starting another round of tasks if the context was cancelled,
+		// for example because the state store lock was lost.
+		if err := ctx.Err(); err != nil {
+			return context.Cause(ctx)
+		}
+
 		var canRun []*taskState[T]
 		doneCount := 0
 		for _, ts := range taskStates {
@@ -104,6 +119,7 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 				if ts.deadline.IsZero() {
 					ts.deadline = time.Now().Add(e.options.MaxTaskDuration)
 				} else if time.Now().After(ts.deadline) {
+					e.emitEvent(ts, TaskResultDeadlineExceeded, ts.lastError)
 					return fmt.Errorf("deadline exceeded executing task %v. Example error: %v", ts.key, ts.lastError)
 				}
 				canRun = append(canRun, ts)
@@ -128,6 +144,7 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 				//  print warning message and continue like the task succeeded
 				if _, ok := err.(*ExistsAndWarnIfChangesError); ok {
 					klog.Warning(err.Error())
+					e.emitEvent(ts, TaskResultWarning, err)
 					ts.done = true
 					ts.lastError = nil
 					progress = true
@@ -138,8 +155,10 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 				var tryAgainLater *TryAgainLaterError
 				if errors.As(err, &tryAgainLater) {
 					klog.V(2).Infof("Task %q not ready: %v", ts.key, err)
+					e.emitEvent(ts, TaskResultTryAgainLater, err)
 				} else {
 					klog.Warningf("error running task %q (%v remaining to succeed): %v", ts.key, remaining, err)
+					e.emitEvent(ts, TaskResultError, err)
 				}
 				errs = append(errs, err)
 				ts.lastError = err
@@ -147,6 +166,7 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 				ts.done = true
 				ts.lastError = nil
 				progress = true
+				e.emitEvent(ts, TaskResultSucceeded, nil)
 			}
 		}
 
@@ -182,6 +202,7 @@ func (e *executor[T]) RunTasks(ctx context.Context, taskMap map[string]Task[T])
 	for _, ts := range taskStates {
 		if !ts.done {
 			notDone = append(notDone, ts.key)
+			e.emitEvent(ts, TaskResultBlocked, ts.lastError)
 		}
 	}
 	if len(notDone) != 0 {
@@ -214,6 +235,12 @@ func (e *executor[T]) forkJoin(ctx context.Context, tasks []*taskState[T]) []err
 
 			klog.V(2).Infof("Executing task %q: %v\n", ts.key, ts.task)
 
+			ts.attempts++
+			ts.startTime = time.Now()
+			defer func() {
+				ts.endTime = time.Now()
+			}()
+
 			if taskNormalize, ok := ts.task.(TaskNormalize[T]); ok {
 				if err := taskNormalize.Normalize(e.context); err != nil {
 					results[index] = err
@@ -233,3 +260,33 @@ func (e *executor[T]) forkJoin(ctx context.Context, tasks []*taskState[T]) []err
 
 	return results
 }
+
+// emitEvent reports the outcome of the most recent attempt to run a task to the EventSink, if one is configured
+func (e *executor[T]) emitEvent(ts *taskState[T], result TaskResult, err error) {
+	if e.options.EventSink == nil {
+		return
+	}
+
+	event := &TaskEvent{
+		Key:     ts.key,
+		Attempt: ts.attempts,
+		Result:  result,
+	}
+	for _, dep := range ts.dependencies {
+		event.Dependencies = append(event.Dependencies, dep.key)
+	}
+	sort.Strings(event.Dependencies)
+	if ts.attempts != 0 {
+		startTime, endTime := ts.startTime, ts.endTime
+		event.StartTime = &startTime
+		event.EndTime = &endTime
+	}
+	if err != nil {
+		event.Error = err.Error()
+		var tryAgainLater *TryAgainLaterError
+		if errors.As(err, &tryAgainLater) {
+			event.Reason = tryAgainLater.msg
+		}
+	}
+	e.options.EventSink.TaskEvent(event)
+}
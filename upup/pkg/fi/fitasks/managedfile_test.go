@@ -0,0 +1,89 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fitasks
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/kops/pkg/assets"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+// TestManagedFilePlan checks that the contents of managed files, which can hold credentials, are not shown in a plan.
+func TestManagedFilePlan(t *testing.T) {
+	const secret = "AWS_SECRET_ACCESS_KEY=wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"
+
+	target := fi.NewCloudupDryRunTarget(assets.NewAssetBuilder(vfs.Context, nil, false), true, io.Discard)
+
+	created := &ManagedFile{
+		Name:      new("created"),
+		Lifecycle: fi.LifecycleSync,
+		Location:  new("created.env"),
+		Contents:  fi.NewStringResource(secret + "\n"),
+	}
+	updatedA := &ManagedFile{
+		Name:      new("updated"),
+		Lifecycle: fi.LifecycleSync,
+		Location:  new("updated.env"),
+		Contents:  fi.NewStringResource("AWS_REGION=us-east-1\n"),
+	}
+	updatedE := &ManagedFile{
+		Name:      new("updated"),
+		Lifecycle: fi.LifecycleSync,
+		Location:  new("updated.env"),
+		Contents:  fi.NewStringResource("AWS_REGION=us-east-1\n" + secret + "\n"),
+	}
+
+	changes := &ManagedFile{}
+	fi.BuildChanges((*ManagedFile)(nil), created, changes)
+	require.NoError(t, target.Render((*ManagedFile)(nil), created, changes))
+
+	changes = &ManagedFile{}
+	fi.BuildChanges(updatedA, updatedE, changes)
+	require.NoError(t, target.Render(updatedA, updatedE, changes))
+
+	plan, err := target.Plan(map[string]fi.CloudupTask{
+		"ManagedFile/created": created,
+		"ManagedFile/updated": updatedE,
+	})
+	require.NoError(t, err)
+
+	b, err := json.Marshal(plan)
+	require.NoError(t, err)
+	assert.NotContains(t, string(b), "wJalrXUtnFEMI")
+
+	var contents []fi.PlanFieldChange
+	for _, task := range plan.Tasks {
+		for _, c := range task.Changes {
+			if c.Field == "Contents" {
+				contents = append(contents, c)
+			}
+		}
+	}
+	require.Len(t, contents, 2)
+	assert.Empty(t, contents[0].Old)
+	assert.True(t, strings.HasPrefix(contents[0].New, fi.ResourceHashPrefix), "created contents should be hashed: %q", contents[0].New)
+	assert.True(t, strings.HasPrefix(contents[1].Old, fi.ResourceHashPrefix), "old contents should be hashed: %q", contents[1].Old)
+	assert.True(t, strings.HasPrefix(contents[1].New, fi.ResourceHashPrefix), "new contents should be hashed: %q", contents[1].New)
+	assert.NotEqual(t, contents[1].Old, contents[1].New)
+}
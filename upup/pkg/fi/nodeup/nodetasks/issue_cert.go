@@ -29,6 +29,7 @@ import (
 	"time"
 
 	"k8s.io/klog/v2"
+	"k8s.io/kops/pkg/certinventory"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/upup/pkg/fi"
 )
@@ -142,14 +143,14 @@ func (e *IssueCert) Run(c *fi.NodeupContext) error {
 	} else {
 		klog.Warningf("cannot skew certificate lifetime: failed to get interface addresses: %v", err)
 	}
-	validHours := (455 * 24) + (hash.Sum32() % (30 * 24))
+	skewHours := hash.Sum32() % certinventory.NodeCertificateValiditySkewHours
 
 	req := &pki.IssueCertRequest{
 		Signer:         e.Signer,
 		Type:           e.Type,
 		Subject:        e.Subject.toPKIXName(),
 		AlternateNames: e.AlternateNames,
-		Validity:       time.Hour * time.Duration(validHours),
+		Validity:       certinventory.NodeCertificateMinValidity + time.Hour*time.Duration(skewHours),
 	}
 
 	keystore, err := newStaticKeystore(ctx, e.Signer, e.KeypairID, c.T.Keystore)
@@ -0,0 +1,309 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PlanAction is the action that applying a plan would take for a task
+type PlanAction string
+
+const (
+	PlanActionCreate PlanAction = "create"
+	PlanActionUpdate PlanAction = "update"
+	PlanActionDelete PlanAction = "delete"
+)
+
+// RedactedValue replaces the value of fields that may hold secrets or private keys
+const RedactedValue = "<redacted>"
+
+// ResourceHashPrefix prefixes the hash that replaces the contents of a resource in a plan
+const ResourceHashPrefix = "sha256:"
+
+// Plan is a machine-readable description of the changes found by a DryRunTarget
+type Plan struct {
+	Tasks []PlanTask `json:"tasks"`
+}
+
+// PlanTask describes the change to a single task
+type PlanTask struct {
+	// Key identifies the task, as type/name
+	Key string `json:"key"`
+	// Action is whether the object will be created, updated or deleted
+	Action PlanAction `json:"action"`
+	// Lifecycle is the lifecycle of the task, or of the task that produced a deletion
+	Lifecycle Lifecycle `json:"lifecycle,omitempty"`
+	// Deferred is true for deletions that are only performed with --prune
+	Deferred bool `json:"deferred,omitempty"`
+	// Changes lists the fields that will be changed
+	Changes []PlanFieldChange `json:"changes,omitempty"`
+}
+
+// PlanFieldChange describes the change to a single field of a task
+type PlanFieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// SavedPlan is the result of a dry-run, saved so that exactly the reviewed changes can be applied later.
+// Tasks that are not listed are expected to have no changes.
+type SavedPlan struct {
+	// KopsVersion is the version of kOps that created the plan
+	KopsVersion string `json:"kopsVersion"`
+	// SpecHash is a hash of the cluster and instance group specs, and of the options that affect the changes
+	SpecHash string `json:"specHash"`
+	// Tasks records the expected change for each task, keyed by task key
+	Tasks map[string]SavedPlanTask `json:"tasks"`
+	// Plan is a human-reviewable description of the changes, with secrets redacted
+	Plan *Plan `json:"plan,omitempty"`
+}
+
+// SavedPlanTask is the expected change for a single task
+type SavedPlanTask struct {
+	// Action is whether the object will be created, updated or deleted
+	Action PlanAction `json:"action"`
+	// Fields are the names of the changed fields
+	Fields []string `json:"fields,omitempty"`
+	// Hash is a hash of the actual and expected values of the changed fields
+	Hash string `json:"hash"`
+}
+
+// SavedPlan builds a SavedPlan from the changes that were recorded.
+// The caller is responsible for populating KopsVersion and SpecHash.
+func (t *DryRunTarget[T]) SavedPlan(taskMap map[string]Task[T]) (*SavedPlan, error) {
+	plan, err := t.Plan(taskMap)
+	if err != nil {
+		return nil, err
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	saved := &SavedPlan{
+		Tasks: make(map[string]SavedPlanTask),
+		Plan:  plan,
+	}
+
+	for _, r := range t.changes {
+		task, err := savedPlanTask(r.a, r.e, r.changes, r.aIsNil)
+		if err != nil {
+			return nil, err
+		}
+		saved.Tasks[buildTaskKey(r.e)] = task
+	}
+
+	for _, d := range t.deletions {
+		saved.Tasks[deletionKey(d)] = savedPlanDeletion(d)
+	}
+
+	return saved, nil
+}
+
+// taskIDs matches the IDs printed for references to other tasks by PrintCompareWithID.
+// Tasks created during an apply only have an ID once they are created, so we compare references by name.
+var taskIDs = regexp.MustCompile(`(name:[^\s,\]}]+) id:[^\s,\]}]+`)
+
+// savedPlanTask builds the expected change for a task, as recorded in a SavedPlan.
+func savedPlanTask[T SubContext](a, e, changes Task[T], aIsNil bool) (SavedPlanTask, error) {
+	task := SavedPlanTask{}
+	var changeList []change
+	if aIsNil {
+		task.Action = PlanActionCreate
+		changeList = buildCreateList(changes)
+	} else {
+		task.Action = PlanActionUpdate
+		var err error
+		changeList, err = buildChangeList(a, e, changes)
+		if err != nil {
+			return task, fmt.Errorf("building changes for %s: %w", buildTaskKey(e), err)
+		}
+	}
+
+	h := sha256.New()
+	for _, c := range changeList {
+		task.Fields = append(task.Fields, c.FieldName)
+		fmt.Fprintf(h, "%s\x00%s\x00%s\x00", c.FieldName, taskIDs.ReplaceAllString(c.Old, "$1"), taskIDs.ReplaceAllString(c.New, "$1"))
+	}
+	task.Hash = hex.EncodeToString(h.Sum(nil))
+	return task, nil
+}
+
+// savedPlanDeletion builds the expected deletion, as recorded in a SavedPlan.
+func savedPlanDeletion[T SubContext](d Deletion[T]) SavedPlanTask {
+	h := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%t", deletionKey(d), d.DeferDeletion())))
+	return SavedPlanTask{
+		Action: PlanActionDelete,
+		Hash:   hex.EncodeToString(h[:]),
+	}
+}
+
+// planEnforcer checks each change against a SavedPlan immediately before it is made,
+// so that only the reviewed changes are applied even if the cloud has changed since the plan was made.
+type planEnforcer struct {
+	plan *SavedPlan
+
+	mutex sync.Mutex
+	made  map[string]bool
+}
+
+// check returns an error unless the change to the task with the given key is the planned one.
+func (p *planEnforcer) check(key string, actual SavedPlanTask) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	planned, found := p.plan.Tasks[key]
+	switch {
+	case !found:
+		return fmt.Errorf("refusing to %s %s, which is not in the plan; please create a new plan", actual.Action, key)
+	case planned.Action != actual.Action:
+		return fmt.Errorf("refusing to %s %s, which the plan would %s; please create a new plan", actual.Action, key, planned.Action)
+	case planned.Hash != actual.Hash:
+		return fmt.Errorf("refusing to %s %s: planned changes to [%s], but the changes are now to [%s]; please create a new plan",
+			actual.Action, key, strings.Join(planned.Fields, ","), strings.Join(actual.Fields, ","))
+	}
+	if p.made == nil {
+		p.made = make(map[string]bool)
+	}
+	p.made[key] = true
+	return nil
+}
+
+// notMade returns the keys of the planned changes that were not made.
+func (p *planEnforcer) notMade() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	var keys []string
+	for key := range p.plan.Tasks {
+		if !p.made[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// planTagName is the struct tag that marks task fields whose values must never be shown, e.g. `plan:"redact"`.
+// Resources are hashed rather than shown (see planResourceFields), so the tag is only needed on other fields that hold secrets.
+const planTagName = "plan"
+
+// isRedactedField returns true if the task field is tagged to be redacted in plans and reports
+func isRedactedField(field reflect.StructField) bool {
+	return field.Tag.Get(planTagName) == "redact"
+}
+
+// planResourceFields are the resource fields, as TaskType.Field, whose contents are shown in a plan.
+// Other resources, such as user data and managed files, may hold credentials, so only a hash of their contents is shown.
+var planResourceFields = map[string]bool{
+	"IAMRole.RolePolicyDocument":   true,
+	"IAMRolePolicy.PolicyDocument": true,
+	"SQS.Policy":                   true,
+	"SSHKey.PublicKey":             true,
+}
+
+// planResourceValue returns the value to show in a plan for the contents of a resource
+func planResourceValue(taskName string, fieldName string, value string) string {
+	if value == "" || planResourceFields[taskName+"."+fieldName] {
+		return value
+	}
+	hash := sha256.Sum256([]byte(value))
+	return ResourceHashPrefix + hex.EncodeToString(hash[:])
+}
+
+// redactFieldValue returns the value to show in a plan for a field, redacting secrets and private keys
+func redactFieldValue(c change, value string) string {
+	if value == "" {
+		return value
+	}
+	if c.redacted || strings.Contains(value, "PRIVATE KEY-----") {
+		return RedactedValue
+	}
+	return value
+}
+
+// Plan builds a machine-readable description of the changes that were recorded, with sensitive values redacted.
+// Tasks are sorted by key, with deletions listed last.
+func (t *DryRunTarget[T]) Plan(taskMap map[string]Task[T]) (*Plan, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	plan := &Plan{}
+
+	var changes []*render[T]
+	changes = append(changes, t.changes...)
+	sort.Sort(ByTaskKey[T](changes))
+
+	for _, r := range changes {
+		task := PlanTask{
+			Key: getTaskName(r.changes) + "/" + idForTask(taskMap, r.e),
+		}
+		if hl, ok := r.e.(HasLifecycle); ok {
+			task.Lifecycle = hl.GetLifecycle()
+		}
+
+		var changeList []change
+		if r.aIsNil {
+			task.Action = PlanActionCreate
+			changeList = buildCreateList(r.changes)
+		} else {
+			task.Action = PlanActionUpdate
+			var err error
+			changeList, err = buildChangeList(r.a, r.e, r.changes)
+			if err != nil {
+				return nil, fmt.Errorf("building changes for %s: %w", task.Key, err)
+			}
+		}
+
+		for _, c := range changeList {
+			oldValue, newValue := c.Old, c.New
+			if c.resource {
+				oldValue = planResourceValue(getTaskName(r.changes), c.FieldName, oldValue)
+				newValue = planResourceValue(getTaskName(r.changes), c.FieldName, newValue)
+			}
+			task.Changes = append(task.Changes, PlanFieldChange{
+				Field: c.FieldName,
+				Old:   redactFieldValue(c, oldValue),
+				New:   redactFieldValue(c, newValue),
+			})
+		}
+		plan.Tasks = append(plan.Tasks, task)
+	}
+
+	var deletions []Deletion[T]
+	deletions = append(deletions, t.deletions...)
+	sort.Stable(DeletionByTaskName[T](deletions))
+
+	for _, d := range deletions {
+		plan.Tasks = append(plan.Tasks, PlanTask{
+			Key:       deletionKey(d),
+			Action:    PlanActionDelete,
+			Lifecycle: t.deletionLifecycles[deletionKey(d)],
+			Deferred:  d.DeferDeletion(),
+		})
+	}
+
+	return plan, nil
+}
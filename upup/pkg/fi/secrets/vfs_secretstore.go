@@ -25,6 +25,7 @@ import (
 
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/upup/pkg/fi"
 	"k8s.io/kops/util/pkg/vfs"
 	"k8s.io/kops/util/pkg/vfs/acls"
@@ -78,7 +79,7 @@ func (c *VFSSecretStore) MirrorTo(ctx context.Context, basedir vfs.Path) error {
 
 		klog.Infof("mirroring secret %s -> %s", name, p)
 
-		err = createSecret(ctx, secret, p, acl, true)
+		err = createSecret(ctx, secret, p, acl, c.encryptionKey(), true)
 		if err != nil {
 			return fmt.Errorf("error writing secret %q for mirror: %v", name, err)
 		}
@@ -129,7 +130,7 @@ func (c *VFSSecretStore) GetOrCreateSecret(ctx context.Context, id string, secre
 			return nil, false, err
 		}
 
-		err = createSecret(ctx, secret, p, acl, false)
+		err = createSecret(ctx, secret, p, acl, c.encryptionKey(), false)
 		if err != nil {
 			if os.IsExist(err) && i == 0 {
 				klog.Infof("Got already-exists error when writing secret; likely due to concurrent creation.  Will retry")
@@ -163,7 +164,7 @@ func (c *VFSSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret
 		return nil, err
 	}
 
-	err = createSecret(ctx, secret, p, acl, true)
+	err = createSecret(ctx, secret, p, acl, c.encryptionKey(), true)
 	if err != nil {
 		return nil, fmt.Errorf("unable to write secret: %v", err)
 	}
@@ -176,13 +177,29 @@ func (c *VFSSecretStore) ReplaceSecret(id string, secret *fi.Secret) (*fi.Secret
 	return s, nil
 }
 
-// createSecret will create the Secret, overwriting an existing secret if replace is true
-func createSecret(ctx context.Context, s *fi.Secret, p vfs.Path, acl vfs.ACL, replace bool) error {
+// encryptionKey returns the URI of the key used to encrypt secrets, or "" if they are stored in plaintext
+func (c *VFSSecretStore) encryptionKey() string {
+	if c.cluster == nil {
+		return ""
+	}
+	return c.cluster.Spec.ConfigStore.EncryptionKey
+}
+
+// createSecret will create the Secret, overwriting an existing secret if replace is true.
+// The secret is encrypted if encryptionKey is set.
+func createSecret(ctx context.Context, s *fi.Secret, p vfs.Path, acl vfs.ACL, encryptionKey string, replace bool) error {
 	data, err := json.Marshal(s)
 	if err != nil {
 		return fmt.Errorf("error serializing secret: %v", err)
 	}
 
+	if encryptionKey != "" {
+		data, err = envelope.Encrypt(ctx, encryptionKey, data)
+		if err != nil {
+			return fmt.Errorf("error encrypting secret: %w", err)
+		}
+	}
+
 	rs := bytes.NewReader(data)
 	if replace {
 		return p.WriteFile(ctx, rs, acl)
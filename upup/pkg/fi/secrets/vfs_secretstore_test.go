@@ -0,0 +1,73 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
+	"k8s.io/kops/upup/pkg/fi"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestVFSSecretStoreEncryption(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests/secrets")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	key, err := envelope.GenerateLocalKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.ConfigStore.EncryptionKey = "local://" + keyPath
+	store := NewVFSSecretStore(cluster, basePath)
+
+	if _, _, err := store.GetOrCreateSecret(ctx, "admin", &fi.Secret{Data: []byte("hunter2")}); err != nil {
+		t.Fatalf("error creating secret: %v", err)
+	}
+
+	data, err := basePath.Join("admin").ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if !envelope.IsEncrypted(data) {
+		t.Errorf("secret was not encrypted: %s", data)
+	}
+
+	// A reader decrypts the secret without being configured with the key
+	secret, err := NewVFSSecretStoreReader(basePath).Secret("admin")
+	if err != nil {
+		t.Fatalf("error reading secret: %v", err)
+	}
+	if string(secret.Data) != "hunter2" {
+		t.Errorf("unexpected secret data %q", secret.Data)
+	}
+}
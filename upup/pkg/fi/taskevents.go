@@ -0,0 +1,95 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// TaskResult is the outcome of a single attempt to run a task
+type TaskResult string
+
+const (
+	// TaskResultSucceeded means the task ran successfully; this is a final result
+	TaskResultSucceeded TaskResult = "Succeeded"
+	// TaskResultWarning means the task had changes, but its lifecycle is ExistsAndWarnIfChanges; this is a final result
+	TaskResultWarning TaskResult = "Warning"
+	// TaskResultTryAgainLater means the task is not yet ready and will be retried
+	TaskResultTryAgainLater TaskResult = "TryAgainLater"
+	// TaskResultError means the task failed and will be retried
+	TaskResultError TaskResult = "Error"
+	// TaskResultDeadlineExceeded means the task did not succeed within MaxTaskDuration; this is a final result
+	TaskResultDeadlineExceeded TaskResult = "DeadlineExceeded"
+	// TaskResultBlocked means the task never ran, because its dependencies never completed; this is a final result
+	TaskResultBlocked TaskResult = "Blocked"
+)
+
+// TaskEvent is a machine-readable record of an attempt to run a task
+type TaskEvent struct {
+	// Key is the key of the task in the task map
+	Key string `json:"key"`
+	// Dependencies are the keys of the tasks this task depends on
+	Dependencies []string `json:"dependencies,omitempty"`
+	// Attempt is the number of times the task has been run, starting at 1
+	Attempt int `json:"attempt"`
+	// StartTime is when the attempt started; it is unset if the task never ran
+	StartTime *time.Time `json:"startTime,omitempty"`
+	// EndTime is when the attempt finished; it is unset if the task never ran
+	EndTime *time.Time `json:"endTime,omitempty"`
+	// Result is the outcome of the attempt
+	Result TaskResult `json:"result"`
+	// Reason is the reason reported by a TryAgainLaterError
+	Reason string `json:"reason,omitempty"`
+	// Error is the error reported by the task, if any
+	Error string `json:"error,omitempty"`
+}
+
+// TaskEventSink receives a TaskEvent for every attempt to run a task.
+// Implementations must be safe for concurrent use.
+type TaskEventSink interface {
+	TaskEvent(event *TaskEvent)
+}
+
+// JSONTaskEventSink writes task events as newline-delimited JSON
+type JSONTaskEventSink struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+}
+
+var _ TaskEventSink = &JSONTaskEventSink{}
+
+// NewJSONTaskEventSink builds a TaskEventSink that writes one JSON object per line to w
+func NewJSONTaskEventSink(w io.Writer) *JSONTaskEventSink {
+	return &JSONTaskEventSink{
+		encoder: json.NewEncoder(w),
+	}
+}
+
+// TaskEvent implements TaskEventSink
+func (s *JSONTaskEventSink) TaskEvent(event *TaskEvent) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if err := s.encoder.Encode(event); err != nil {
+		klog.Warningf("error writing event for task %q: %v", event.Key, err)
+	}
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// eventTestTask is a task that is not ready until it has been run notReadyAttempts times
+type eventTestTask struct {
+	dependencies     []CloudupTask
+	notReadyAttempts int
+	attempts         int
+}
+
+var (
+	_ CloudupTask            = &eventTestTask{}
+	_ CloudupHasDependencies = &eventTestTask{}
+)
+
+func (t *eventTestTask) Run(_ *CloudupContext) error {
+	t.attempts++
+	if t.attempts <= t.notReadyAttempts {
+		return NewTryAgainLaterError("waiting for test")
+	}
+	return nil
+}
+
+func (t *eventTestTask) GetDependencies(map[string]CloudupTask) []CloudupTask {
+	return t.dependencies
+}
+
+func TestRunTasksEmitsEvents(t *testing.T) {
+	var out bytes.Buffer
+
+	network := &eventTestTask{notReadyAttempts: 1}
+	instance := &eventTestTask{dependencies: []CloudupTask{network}}
+	taskMap := map[string]CloudupTask{
+		"Network/test":  network,
+		"Instance/test": instance,
+	}
+
+	options := RunTasksOptions{}
+	options.InitDefaults()
+	options.WaitAfterAllTasksFailed = 0
+	options.EventSink = NewJSONTaskEventSink(&out)
+
+	e := &executor[CloudupSubContext]{
+		context: &CloudupContext{},
+		options: options,
+	}
+	require.NoError(t, e.RunTasks(context.TODO(), taskMap))
+
+	var events []TaskEvent
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var event TaskEvent
+		require.NoError(t, json.Unmarshal([]byte(line), &event), "line %q", line)
+		events = append(events, event)
+	}
+	require.Len(t, events, 3)
+
+	assert.Equal(t, "Network/test", events[0].Key)
+	assert.Equal(t, 1, events[0].Attempt)
+	assert.Equal(t, TaskResultTryAgainLater, events[0].Result)
+	assert.Equal(t, "waiting for test", events[0].Reason)
+	require.NotNil(t, events[0].StartTime)
+	require.NotNil(t, events[0].EndTime)
+	assert.False(t, events[0].EndTime.Before(*events[0].StartTime))
+
+	assert.Equal(t, "Network/test", events[1].Key)
+	assert.Equal(t, 2, events[1].Attempt)
+	assert.Equal(t, TaskResultSucceeded, events[1].Result)
+	assert.Empty(t, events[1].Reason)
+
+	assert.Equal(t, "Instance/test", events[2].Key)
+	assert.Equal(t, []string{"Network/test"}, events[2].Dependencies)
+	assert.Equal(t, 1, events[2].Attempt)
+	assert.Equal(t, TaskResultSucceeded, events[2].Result)
+}
@@ -29,6 +29,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/sshcredentials"
 	"k8s.io/kops/util/pkg/vfs"
@@ -127,6 +128,10 @@ func writeKeysetBundle(ctx context.Context, cluster *kops.Cluster, p vfs.Path, n
 		return err
 	}
 
+	if err := encryptPrivateMaterial(ctx, cluster, o); err != nil {
+		return err
+	}
+
 	objectData, err := serializeKeysetBundle(o)
 	if err != nil {
 		return err
@@ -139,6 +144,26 @@ func writeKeysetBundle(ctx context.Context, cluster *kops.Cluster, p vfs.Path, n
 	return p.WriteFile(ctx, bytes.NewReader(objectData), acl)
 }
 
+// encryptPrivateMaterial encrypts the private keys of the keyset, if the cluster has a state store encryption key.
+// The certificates are not encrypted, so that they can be read without access to the key.
+func encryptPrivateMaterial(ctx context.Context, cluster *kops.Cluster, o *kops.Keyset) error {
+	if cluster == nil || cluster.Spec.ConfigStore.EncryptionKey == "" {
+		return nil
+	}
+	for i := range o.Spec.Keys {
+		key := &o.Spec.Keys[i]
+		if len(key.PrivateMaterial) == 0 {
+			continue
+		}
+		encrypted, err := envelope.Encrypt(ctx, cluster.Spec.ConfigStore.EncryptionKey, key.PrivateMaterial)
+		if err != nil {
+			return fmt.Errorf("error encrypting private key %s/%s: %w", o.Name, key.Id, err)
+		}
+		key.PrivateMaterial = encrypted
+	}
+	return nil
+}
+
 // serializeKeysetBundle converts a Keyset bundle to yaml, for writing to VFS.
 func serializeKeysetBundle(o *kops.Keyset) ([]byte, error) {
 	var objectData bytes.Buffer
@@ -18,12 +18,17 @@ package fi
 
 import (
 	"context"
+	"crypto/x509/pkix"
 	"math/big"
 	"math/rand"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/util/pkg/vfs"
 )
@@ -157,3 +162,76 @@ spec:
 		}
 	}
 }
+
+func TestVFSCAStoreEncryption(t *testing.T) {
+	ctx := context.TODO()
+
+	vfs.Context.ResetMemfsContext(true)
+	basePath, err := vfs.Context.BuildVfsPath("memfs://tests")
+	if err != nil {
+		t.Fatalf("error building vfspath: %v", err)
+	}
+
+	key, err := envelope.GenerateLocalKey()
+	if err != nil {
+		t.Fatalf("error generating key: %v", err)
+	}
+	keyPath := filepath.Join(t.TempDir(), "key")
+	if err := os.WriteFile(keyPath, key, 0600); err != nil {
+		t.Fatalf("error writing key: %v", err)
+	}
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.ConfigStore.EncryptionKey = "local://" + keyPath
+	s := NewVFSCAStore(cluster, basePath)
+
+	cert, privateKey, _, err := pki.IssueCert(ctx, &pki.IssueCertRequest{
+		Type:    "ca",
+		Subject: pkix.Name{CommonName: "kubernetes"},
+		Serial:  big.NewInt(1),
+	}, nil)
+	if err != nil {
+		t.Fatalf("error issuing certificate: %v", err)
+	}
+	keyset, err := NewKeyset(cert, privateKey)
+	if err != nil {
+		t.Fatalf("error building keyset: %v", err)
+	}
+	if err := s.StoreKeyset(ctx, "kubernetes-ca", keyset); err != nil {
+		t.Fatalf("error storing keyset: %v", err)
+	}
+
+	// The private key is encrypted, the certificate is not
+	data, err := basePath.Join("private", "kubernetes-ca", "keyset.yaml").ReadFile(ctx)
+	if err != nil {
+		t.Fatalf("error reading keyset: %v", err)
+	}
+	o, _, err := s.parseKeysetYaml(data)
+	if err != nil {
+		t.Fatalf("error parsing keyset: %v", err)
+	}
+	if !envelope.IsEncrypted(o.Spec.Keys[0].PrivateMaterial) {
+		t.Errorf("private material was not encrypted")
+	}
+	if _, err := pki.ParsePEMCertificate(o.Spec.Keys[0].PublicMaterial); err != nil {
+		t.Errorf("public material was not readable: %v", err)
+	}
+
+	// A reader decrypts the private key without being configured with the key
+	reader := NewVFSKeystoreReader(basePath)
+	_, loadedKey, err := reader.FindPrimaryKeypair(ctx, "kubernetes-ca")
+	if err != nil {
+		t.Fatalf("error reading keypair: %v", err)
+	}
+	expected, err := privateKey.AsString()
+	if err != nil {
+		t.Fatalf("error serializing private key: %v", err)
+	}
+	actual, err := loadedKey.AsString()
+	if err != nil {
+		t.Fatalf("error serializing private key: %v", err)
+	}
+	if actual != expected {
+		t.Errorf("decrypted private key does not match")
+	}
+}
@@ -25,6 +25,7 @@ import (
 	"k8s.io/klog/v2"
 	"k8s.io/kops/pkg/apis/kops"
 	"k8s.io/kops/pkg/apis/kops/v1alpha2"
+	"k8s.io/kops/pkg/envelope"
 	"k8s.io/kops/pkg/kopscodecs"
 	"k8s.io/kops/pkg/pki"
 	"k8s.io/kops/util/pkg/vfs"
@@ -93,6 +94,10 @@ func (c *VFSKeystoreReader) loadKeyset(ctx context.Context, p vfs.Path) (*Keyset
 		return nil, fmt.Errorf("error parsing bundle %q: %v", p, err)
 	}
 
+	if err := decryptPrivateMaterial(ctx, o); err != nil {
+		return nil, fmt.Errorf("error decrypting bundle %q: %w", p, err)
+	}
+
 	keyset, err := parseKeyset(o)
 	if err != nil {
 		return nil, fmt.Errorf("error mapping bundle %q: %v", p, err)
@@ -102,6 +107,22 @@ func (c *VFSKeystoreReader) loadKeyset(ctx context.Context, p vfs.Path) (*Keyset
 	return keyset, nil
 }
 
+// decryptPrivateMaterial decrypts the private keys of the keyset that were encrypted with a state store encryption key
+func decryptPrivateMaterial(ctx context.Context, o *kops.Keyset) error {
+	for i := range o.Spec.Keys {
+		key := &o.Spec.Keys[i]
+		if !envelope.IsEncrypted(key.PrivateMaterial) {
+			continue
+		}
+		decrypted, err := envelope.Decrypt(ctx, key.PrivateMaterial)
+		if err != nil {
+			return fmt.Errorf("decrypting private key %s: %w", key.Id, err)
+		}
+		key.PrivateMaterial = decrypted
+	}
+	return nil
+}
+
 var legacyKeysetMappings = map[string]string{
 	// The strange name is because kOps prior to 1.19 used the api-server TLS key for this.
 	"service-account": "master",
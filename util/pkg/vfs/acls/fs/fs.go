@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+	"k8s.io/kops/util/pkg/vfs/acls"
+)
+
+// kopsControllerFileMode is the mode of the files that kops-controller reads from a local state store.
+// kops-controller runs as a different user, with the groups of the state store directories as supplemental groups.
+const kopsControllerFileMode = 0o640
+
+// fsAclStrategy is the AclStrategy for files written to the local filesystem
+type fsAclStrategy struct{}
+
+var _ acls.ACLStrategy = &fsAclStrategy{}
+
+// GetACL returns the ACL to use if this is a local filesystem path.
+// Only the files that kops-controller reads are group-readable: the cluster configuration,
+// and the dockerconfig secret that it passes to nodes.
+// Everything else, in particular the private keys and the other secrets, keeps the default owner-only mode.
+func (s *fsAclStrategy) GetACL(ctx context.Context, p vfs.Path, cluster *kops.Cluster) (vfs.ACL, error) {
+	if _, ok := p.(*vfs.FSPath); !ok || cluster == nil {
+		return nil, nil
+	}
+
+	configStore := cluster.Spec.ConfigStore
+	if configStore.Base == "" {
+		return nil, nil
+	}
+	keypairs := configStore.Keypairs
+	if keypairs == "" {
+		keypairs = path.Join(configStore.Base, "pki")
+	}
+	secrets := configStore.Secrets
+	if secrets == "" {
+		secrets = path.Join(configStore.Base, "secrets")
+	}
+
+	location := localPath(p.Path())
+	if location == path.Join(localPath(secrets), "dockerconfig") {
+		return &vfs.FSAcl{FileMode: kopsControllerFileMode}, nil
+	}
+	if !isUnder(location, configStore.Base) || isUnder(location, keypairs) || isUnder(location, secrets) {
+		return nil, nil
+	}
+	return &vfs.FSAcl{FileMode: kopsControllerFileMode}, nil
+}
+
+// isUnder returns true if location is dir, or inside dir.
+func isUnder(location, dir string) bool {
+	dir = localPath(dir)
+	return location == dir || strings.HasPrefix(location, dir+"/")
+}
+
+// localPath returns the clean filesystem path of a location, which may have a file:// prefix.
+func localPath(location string) string {
+	return path.Clean(strings.TrimPrefix(location, "file://"))
+}
+
+func Register() {
+	acls.RegisterPlugin("k8s.io/kops/acl/fs", &fsAclStrategy{})
+}
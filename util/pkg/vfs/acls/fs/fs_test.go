@@ -0,0 +1,74 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fs
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/kops/pkg/apis/kops"
+	"k8s.io/kops/util/pkg/vfs"
+)
+
+func TestGetACL(t *testing.T) {
+	ctx := context.TODO()
+
+	cluster := &kops.Cluster{}
+	cluster.Spec.ConfigStore.Base = "/srv/kops/cluster.example.com"
+	cluster.Spec.ConfigStore.Keypairs = "/srv/kops/cluster.example.com/pki"
+	cluster.Spec.ConfigStore.Secrets = "file:///srv/kops/cluster.example.com/secrets"
+
+	grid := []struct {
+		path     vfs.Path
+		readable bool
+	}{
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/config"), readable: true},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/instancegroup/nodes"), readable: true},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/igconfig/node/nodes/nodeupconfig.yaml"), readable: true},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/secrets/dockerconfig"), readable: true},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/secrets/admin"), readable: false},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/pki/private/kubernetes-ca/keyset.yaml"), readable: false},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com/pki/issued/kubernetes-ca/keyset.yaml"), readable: false},
+		{path: vfs.NewFSPath("/srv/kops/other.example.com/config"), readable: false},
+		{path: vfs.NewFSPath("/srv/kops/cluster.example.com-backup/config"), readable: false},
+	}
+
+	strategy := &fsAclStrategy{}
+	for _, g := range grid {
+		t.Run(g.path.Path(), func(t *testing.T) {
+			acl, err := strategy.GetACL(ctx, g.path, cluster)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !g.readable {
+				if acl != nil {
+					t.Errorf("expected the default owner-only mode, got %v", acl)
+				}
+				return
+			}
+			fsACL, ok := acl.(*vfs.FSAcl)
+			if !ok || fsACL.FileMode != 0o640 {
+				t.Errorf("expected a group-readable file, got %v", acl)
+			}
+		})
+	}
+
+	acl, err := strategy.GetACL(ctx, vfs.NewFSPath("/srv/kops/cluster.example.com/config"), &kops.Cluster{})
+	if err != nil || acl != nil {
+		t.Errorf("expected no acl for a cluster without a config base, got %v, %v", acl, err)
+	}
+}
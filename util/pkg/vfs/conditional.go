@@ -0,0 +1,52 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrPreconditionFailed is returned by a conditional write when the file was changed by another writer
+var ErrPreconditionFailed = errors.New("file was changed by another writer")
+
+// HasConditionalWrite is implemented by Paths whose backend can atomically write a file
+// only if it has not changed since it was read, so that the file can be used for coordination.
+// Versions are opaque strings, such as an S3 ETag or a GCS generation.
+type HasConditionalWrite interface {
+	// ReadFileWithVersion returns the contents and version of the file.
+	// If the file does not exist, err = os.ErrNotExist
+	ReadFileWithVersion(ctx context.Context) ([]byte, string, error)
+
+	// WriteFileIfVersion writes the file only if its version matches, returning the new version.
+	// An empty version means that the file must not exist.
+	// If the file has a different version, err = ErrPreconditionFailed
+	WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error)
+
+	// RemoveIfVersion deletes the file only if its version matches.
+	// If the file has a different version, err = ErrPreconditionFailed
+	RemoveIfVersion(ctx context.Context, version string) error
+}
+
+var (
+	_ HasConditionalWrite = &MemFSPath{}
+	_ HasConditionalWrite = &S3Path{}
+	_ HasConditionalWrite = &GSPath{}
+	_ HasConditionalWrite = &FSPath{}
+	_ HasConditionalWrite = &VaultPath{}
+)
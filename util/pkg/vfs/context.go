@@ -61,6 +61,9 @@ type vfsContextState struct {
 	swiftClient *gophercloud.ServiceClient
 
 	azureClients map[string]*azblob.Client
+
+	// vaultClient is the client for the HashiCorp Vault API, if initialized
+	vaultClient *VaultClient
 }
 
 // Context holds the global VFS state.
@@ -219,6 +222,10 @@ func (c *VFSContext) BuildVfsPath(p string) (Path, error) {
 		return c.buildSCWPath(p)
 	}
 
+	if strings.HasPrefix(p, "vault://") {
+		return c.buildVaultPath(p)
+	}
+
 	return nil, fmt.Errorf("unknown / unhandled path type: %q", p)
 }
 
@@ -619,6 +626,41 @@ func (c *VFSContext) getAzureBlobClient(ctx context.Context, account string) (*a
 	return client, nil
 }
 
+func (c *VFSContext) buildVaultPath(p string) (*VaultPath, error) {
+	u, err := url.Parse(p)
+	if err != nil {
+		return nil, fmt.Errorf("invalid vault path: %q", p)
+	}
+
+	if u.Scheme != "vault" {
+		return nil, fmt.Errorf("invalid vault path: %q", p)
+	}
+
+	mount := strings.TrimSuffix(u.Host, "/")
+	if mount == "" {
+		return nil, fmt.Errorf("no secrets engine mount specified in %q; expected vault://<mount>/<path>", p)
+	}
+
+	return NewVaultPath(c, mount, u.Path), nil
+}
+
+// getVaultClient returns the client for the HashiCorp Vault API, caching it for future reuse.
+func (c *VFSContext) getVaultClient(ctx context.Context) (*VaultClient, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.vaultClient != nil {
+		return c.vaultClient, nil
+	}
+
+	client, err := NewVaultClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.vaultClient = client
+	return client, nil
+}
+
 func (c *VFSContext) buildSCWPath(p string) (*S3Path, error) {
 	endpoint := os.Getenv("S3_ENDPOINT")
 	if endpoint == "" {
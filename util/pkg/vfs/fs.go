@@ -17,13 +17,16 @@ limitations under the License.
 package vfs
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
-	"sync"
+	"strings"
 	"syscall"
 
 	"k8s.io/klog/v2"
@@ -51,57 +54,98 @@ func (p *FSPath) Join(relativePath ...string) Path {
 	return &FSPath{location: joined}
 }
 
+// fsTempFilePrefix is the prefix of the temporary files used for atomic writes;
+// they are excluded from directory listings.
+const fsTempFilePrefix = ".kops-tmp-"
+
+// fsFileMode is the mode of files written to the local filesystem, unless an FSAcl says otherwise.
+const fsFileMode os.FileMode = 0o600
+
+// FSAcl is an ACL implementation for files on the local filesystem
+type FSAcl struct {
+	FileMode os.FileMode
+}
+
+func (a *FSAcl) String() string {
+	return fmt.Sprintf("{FileMode:%v}", a.FileMode)
+}
+
+// fileMode returns the mode for a file written with the given ACL.
+func fileMode(acl ACL) os.FileMode {
+	if fsACL, ok := acl.(*FSAcl); ok && fsACL != nil {
+		return fsACL.FileMode
+	}
+	return fsFileMode
+}
+
 func (p *FSPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	tempfile, err := p.writeTempFile(data, fileMode(acl))
+	if err != nil {
+		return err
+	}
+
+	err = os.Rename(tempfile, p.location)
+	if err != nil {
+		err = fmt.Errorf("error during file write of %q: rename failed: %v", p.location, err)
+	} else {
+		err = syncDir(filepath.Dir(p.location))
+	}
+
+	if err == nil {
+		return nil
+	}
+
+	// Something went wrong; try to remove the temp file
+	if removeErr := os.Remove(tempfile); removeErr != nil && !os.IsNotExist(removeErr) {
+		klog.Warningf("unable to remove temp file %q: %v", tempfile, removeErr)
+	}
+
+	return err
+}
+
+// writeTempFile writes the data to a temporary file in the same directory as the file,
+// and flushes it to disk, so that it can be atomically moved into place.
+func (p *FSPath) writeTempFile(data io.ReadSeeker, mode os.FileMode) (string, error) {
 	dir := filepath.Dir(p.location)
 	err := os.MkdirAll(dir, 0o755)
 	if err != nil {
-		return fmt.Errorf("error creating directories %q: %v", dir, err)
+		return "", fmt.Errorf("error creating directories %q: %v", dir, err)
 	}
 
-	f, err := os.CreateTemp(dir, "tmp")
+	f, err := os.CreateTemp(dir, fsTempFilePrefix)
 	if err != nil {
-		return fmt.Errorf("error creating temp file in %q: %v", dir, err)
+		return "", fmt.Errorf("error creating temp file in %q: %v", dir, err)
 	}
 
-	// Note from here on in we have to close f and delete or rename the temp file
+	// Note from here on in we have to close f and delete the temp file on error
 	tempfile := f.Name()
 
 	_, err = io.Copy(f, data)
-
-	if closeErr := f.Close(); err == nil {
-		err = closeErr
-	}
-
 	if err == nil {
-		err = os.Rename(tempfile, p.location)
-		if err != nil {
-			err = fmt.Errorf("error during file write of %q: rename failed: %v", p.location, err)
-		}
+		err = f.Chmod(mode)
 	}
-
 	if err == nil {
-		return nil
+		err = f.Sync()
 	}
 
-	// Something went wrong; try to remove the temp file
-	if removeErr := os.Remove(tempfile); removeErr != nil {
-		klog.Warningf("unable to remove temp file %q: %v", tempfile, removeErr)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
 	}
 
-	return err
+	if err != nil {
+		if removeErr := os.Remove(tempfile); removeErr != nil {
+			klog.Warningf("unable to remove temp file %q: %v", tempfile, removeErr)
+		}
+		return "", fmt.Errorf("error writing temp file for %q: %w", p.location, err)
+	}
+	return tempfile, nil
 }
 
-// To prevent concurrent creates on the same file while maintaining atomicity of writes,
-// we take a process-wide lock during the operation.
-// Not a great approach, but fine for a single process (with low concurrency)
-// TODO: should we take a file lock or equivalent here?  Can we use RENAME_NOREPLACE ?
-var createFileLock sync.Mutex
-
+// CreateFile writes the file only if it does not already exist.
+// The file is moved into place with a hard link, which fails if the file exists,
+// so this is safe against concurrent writers, including other processes.
 func (p *FSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
-	createFileLock.Lock()
-	defer createFileLock.Unlock()
-
-	// Check if exists
+	// Check if exists, to avoid writing the data if we can
 	_, err := os.Stat(p.location)
 	if err == nil {
 		return os.ErrExist
@@ -111,7 +155,23 @@ func (p *FSPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) er
 		return err
 	}
 
-	return p.WriteFile(ctx, data, acl)
+	tempfile, err := p.writeTempFile(data, fileMode(acl))
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if removeErr := os.Remove(tempfile); removeErr != nil {
+			klog.Warningf("unable to remove temp file %q: %v", tempfile, removeErr)
+		}
+	}()
+
+	if err := os.Link(tempfile, p.location); err != nil {
+		if os.IsExist(err) {
+			return os.ErrExist
+		}
+		return fmt.Errorf("error during file create of %q: link failed: %w", p.location, err)
+	}
+	return syncDir(filepath.Dir(p.location))
 }
 
 // ReadFile implements Path::ReadFile
@@ -144,6 +204,9 @@ func (p *FSPath) ReadDir() ([]Path, error) {
 	}
 	var paths []Path
 	for _, f := range files {
+		if strings.HasPrefix(f.Name(), fsTempFilePrefix) {
+			continue
+		}
 		paths = append(paths, NewFSPath(filepath.Join(p.location, f.Name())))
 	}
 	return paths, nil
@@ -166,6 +229,9 @@ func readTree(base string, dest *[]Path) error {
 		return err
 	}
 	for _, f := range files {
+		if strings.HasPrefix(f.Name(), fsTempFilePrefix) {
+			continue
+		}
 		p := filepath.Join(base, f.Name())
 		if f.IsDir() {
 			err = readTree(p, dest)
@@ -224,3 +290,81 @@ func (p *FSPath) Hash(a hashing.HashAlgorithm) (*hashing.Hash, error) {
 
 	return a.HashFile(p.location)
 }
+
+// ReadFileWithVersion implements HasConditionalWrite::ReadFileWithVersion
+// The version of a local file is the hash of its contents.
+func (p *FSPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, fsVersion(data), nil
+}
+
+// WriteFileIfVersion implements HasConditionalWrite::WriteFileIfVersion
+func (p *FSPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	dir := filepath.Dir(p.location)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("error creating directories %q: %v", dir, err)
+	}
+
+	unlock, err := lockDir(dir)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if err := p.checkVersion(ctx, version); err != nil {
+		return "", err
+	}
+
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return "", fmt.Errorf("error reading data for %q: %w", p.location, err)
+	}
+	if err := p.WriteFile(ctx, bytes.NewReader(b), acl); err != nil {
+		return "", err
+	}
+	return fsVersion(b), nil
+}
+
+// RemoveIfVersion implements HasConditionalWrite::RemoveIfVersion
+func (p *FSPath) RemoveIfVersion(ctx context.Context, version string) error {
+	unlock, err := lockDir(filepath.Dir(p.location))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if _, err := os.Stat(p.location); err != nil {
+		return err
+	}
+	if err := p.checkVersion(ctx, version); err != nil {
+		return err
+	}
+	return p.Remove(ctx)
+}
+
+// checkVersion returns ErrPreconditionFailed if the file does not have the expected version.
+// The caller must hold the lock on the directory.
+func (p *FSPath) checkVersion(ctx context.Context, version string) error {
+	data, err := p.ReadFile(ctx)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if version == "" {
+				return nil
+			}
+			return ErrPreconditionFailed
+		}
+		return err
+	}
+	if fsVersion(data) != version {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+func fsVersion(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
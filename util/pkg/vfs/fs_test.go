@@ -18,8 +18,12 @@ package vfs
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
 	"testing"
 
 	"k8s.io/kops/pkg/testutils/testcontext"
@@ -139,3 +143,155 @@ func TestWriteTo(t *testing.T) {
 		}
 	}
 }
+
+func TestCreateFileConcurrent(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	fspath := NewFSPath(filepath.Join(t.TempDir(), "lock"))
+
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	created := 0
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := fspath.CreateFile(ctx, bytes.NewReader([]byte(fmt.Sprintf("writer %d", i))), nil)
+			if err == nil {
+				mutex.Lock()
+				created++
+				mutex.Unlock()
+			} else if err != os.ErrExist {
+				t.Errorf("unexpected error from CreateFile: %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Errorf("expected exactly one CreateFile to succeed, got %d", created)
+	}
+}
+
+func TestWriteFileLeavesNoTempFiles(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	dir := t.TempDir()
+	base := NewFSPath(dir)
+
+	if err := base.Join("config").WriteFile(ctx, bytes.NewReader([]byte("v1")), nil); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	if err := base.Join("config").WriteFile(ctx, bytes.NewReader([]byte("v2")), nil); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	if err := base.Join("pki", "ca").CreateFile(ctx, bytes.NewReader([]byte("ca")), nil); err != nil {
+		t.Fatalf("error creating file: %v", err)
+	}
+
+	// A temp file left behind by a crashed writer is not listed
+	if err := os.WriteFile(filepath.Join(dir, fsTempFilePrefix+"123"), []byte("partial"), 0o600); err != nil {
+		t.Fatalf("error writing temp file: %v", err)
+	}
+
+	children, err := base.ReadDir()
+	if err != nil {
+		t.Fatalf("error reading dir: %v", err)
+	}
+	if len(children) != 2 {
+		t.Errorf("expected config and pki in dir, got %v", children)
+	}
+
+	tree, err := base.ReadTree(ctx)
+	if err != nil {
+		t.Fatalf("error reading tree: %v", err)
+	}
+	if len(tree) != 2 {
+		t.Errorf("expected config and pki/ca in tree, got %v", tree)
+	}
+
+	stat, err := os.Stat(filepath.Join(dir, "config"))
+	if err != nil {
+		t.Fatalf("error reading file info: %v", err)
+	}
+	if stat.Mode().Perm() != fsFileMode {
+		t.Errorf("expected file mode %v, got %v", fsFileMode, stat.Mode().Perm())
+	}
+}
+
+func TestWriteFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("windows has no group permissions")
+	}
+
+	ctx := testcontext.ForTest(t)
+	base := NewFSPath(t.TempDir())
+
+	acl := &FSAcl{FileMode: 0o640}
+	if err := base.Join("config").WriteFile(ctx, bytes.NewReader([]byte("config")), acl); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+	if err := base.Join("instancegroup", "nodes").CreateFile(ctx, bytes.NewReader([]byte("nodes")), acl); err != nil {
+		t.Fatalf("error creating file: %v", err)
+	}
+	if err := base.Join("secrets", "admin").WriteFile(ctx, bytes.NewReader([]byte("admin")), nil); err != nil {
+		t.Fatalf("error writing file: %v", err)
+	}
+
+	for name, expected := range map[string]os.FileMode{
+		"config":              0o640,
+		"instancegroup/nodes": 0o640,
+		"secrets/admin":       0o600,
+	} {
+		stat, err := os.Stat(filepath.Join(base.Path(), name))
+		if err != nil {
+			t.Fatalf("error reading file info: %v", err)
+		}
+		if stat.Mode().Perm() != expected {
+			t.Errorf("expected file mode %v for %s, got %v", expected, name, stat.Mode().Perm())
+		}
+	}
+}
+
+func TestFSPathConditionalWrite(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+	fspath := NewFSPath(filepath.Join(t.TempDir(), "cluster", "lock"))
+
+	if _, _, err := fspath.ReadFileWithVersion(ctx); !os.IsNotExist(err) {
+		t.Fatalf("expected not-exist error, got %v", err)
+	}
+
+	v1, err := fspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 1")), nil, "")
+	if err != nil {
+		t.Fatalf("error creating file: %v", err)
+	}
+
+	if _, err := fspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 2")), nil, ""); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("expected precondition failure creating existing file, got %v", err)
+	}
+
+	data, version, err := fspath.ReadFileWithVersion(ctx)
+	if err != nil {
+		t.Fatalf("error reading file: %v", err)
+	}
+	if string(data) != "holder 1" || version != v1 {
+		t.Errorf("unexpected contents %q version %q", data, version)
+	}
+
+	v2, err := fspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 1 renewed")), nil, v1)
+	if err != nil {
+		t.Fatalf("error updating file: %v", err)
+	}
+
+	if _, err := fspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 2")), nil, v1); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("expected precondition failure with stale version, got %v", err)
+	}
+	if err := fspath.RemoveIfVersion(ctx, v1); !errors.Is(err, ErrPreconditionFailed) {
+		t.Errorf("expected precondition failure removing with stale version, got %v", err)
+	}
+
+	if err := fspath.RemoveIfVersion(ctx, v2); err != nil {
+		t.Fatalf("error removing file: %v", err)
+	}
+	if _, err := fspath.ReadFile(ctx); !os.IsNotExist(err) {
+		t.Errorf("expected file to be removed, got %v", err)
+	}
+}
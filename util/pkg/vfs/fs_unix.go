@@ -0,0 +1,67 @@
+//go:build !windows
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// syncDir flushes a directory to disk, so that a file created or renamed into it survives a crash.
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return fmt.Errorf("error opening directory %q: %w", dir, err)
+	}
+	defer f.Close()
+
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("error syncing directory %q: %w", dir, err)
+	}
+	return nil
+}
+
+// lockDir takes an exclusive advisory lock on a directory, which is shared with other processes.
+func lockDir(dir string) (func(), error) {
+	f, err := os.Open(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error opening directory %q: %w", dir, err)
+	}
+
+	if err := unix.Flock(int(f.Fd()), unix.LOCK_EX); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("error locking directory %q: %w", dir, err)
+	}
+
+	return func() {
+		// Closing the file releases the lock
+		f.Close()
+	}, nil
+}
+
+// GroupID returns the ID of the group that owns the file or directory.
+func (p *FSPath) GroupID() (int64, error) {
+	var stat unix.Stat_t
+	if err := unix.Stat(p.location, &stat); err != nil {
+		return 0, fmt.Errorf("error reading file info for %q: %w", p.location, err)
+	}
+	return int64(stat.Gid), nil
+}
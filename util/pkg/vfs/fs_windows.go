@@ -0,0 +1,44 @@
+//go:build windows
+
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"fmt"
+	"sync"
+)
+
+// syncDir is a no-op on windows, which cannot flush a directory.
+func syncDir(dir string) error {
+	return nil
+}
+
+// fsLock serializes conditional writes on windows.
+// It is only effective within a single process.
+var fsLock sync.Mutex
+
+// lockDir takes a process-wide lock.
+func lockDir(dir string) (func(), error) {
+	fsLock.Lock()
+	return fsLock.Unlock, nil
+}
+
+// GroupID is not supported on windows, which has no numeric group IDs.
+func (p *FSPath) GroupID() (int64, error) {
+	return 0, fmt.Errorf("cannot determine the group of %q on windows", p.location)
+}
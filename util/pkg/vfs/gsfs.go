@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -528,6 +529,109 @@ func (s *GSPath) TerraformLink(name string) *terraformWriter.Literal {
 	return terraformWriter.LiteralProperty("google_storage_bucket_object", name, "output_name")
 }
 
+// ReadFileWithVersion implements HasConditionalWrite::ReadFileWithVersion, using the generation as the version
+func (p *GSPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	klog.V(4).Infof("Reading file %q", p)
+
+	client, err := p.getStorageClient(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r, err := client.Bucket(p.bucket).Object(p.key).NewReader(ctx)
+	if err != nil {
+		if isGCSNotFound(err) {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	defer r.Close()
+
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return b, strconv.FormatInt(r.Attrs.Generation, 10), nil
+}
+
+// WriteFileIfVersion implements HasConditionalWrite::WriteFileIfVersion, using generation preconditions
+func (p *GSPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	conditions, err := gcsConditionsForVersion(version)
+	if err != nil {
+		return "", err
+	}
+
+	var objectACL []storage.ACLRule
+	if acl != nil {
+		gsACL, ok := acl.(*GSAcl)
+		if !ok {
+			return "", fmt.Errorf("write to %s with ACL of unexpected type %T", p, acl)
+		}
+		objectACL = gsACL.Acl
+	}
+
+	klog.V(4).Infof("Writing file %q if it has generation %q", p, version)
+
+	client, err := p.getStorageClient(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	w := client.Bucket(p.bucket).Object(p.key).If(conditions).NewWriter(ctx)
+	w.ACL = objectACL
+	if _, err := io.Copy(w, data); err != nil {
+		w.Close()
+		return "", fmt.Errorf("error writing %s: %v", p, err)
+	}
+	if err := w.Close(); err != nil {
+		if isGCSPreconditionFailed(err) {
+			return "", ErrPreconditionFailed
+		}
+		return "", fmt.Errorf("error writing %s: %v", p, err)
+	}
+	return strconv.FormatInt(w.Attrs().Generation, 10), nil
+}
+
+// RemoveIfVersion implements HasConditionalWrite::RemoveIfVersion, using generation preconditions
+func (p *GSPath) RemoveIfVersion(ctx context.Context, version string) error {
+	conditions, err := gcsConditionsForVersion(version)
+	if err != nil {
+		return err
+	}
+
+	client, err := p.getStorageClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Bucket(p.bucket).Object(p.key).If(conditions).Delete(ctx); err != nil {
+		if isGCSPreconditionFailed(err) {
+			return ErrPreconditionFailed
+		}
+		if isGCSNotFound(err) {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error deleting %s: %w", p, err)
+	}
+	return nil
+}
+
+func gcsConditionsForVersion(version string) (storage.Conditions, error) {
+	if version == "" {
+		return storage.Conditions{DoesNotExist: true}, nil
+	}
+	generation, err := strconv.ParseInt(version, 10, 64)
+	if err != nil {
+		return storage.Conditions{}, fmt.Errorf("invalid GCS generation %q: %w", version, err)
+	}
+	return storage.Conditions{GenerationMatch: generation}, nil
+}
+
+func isGCSPreconditionFailed(err error) bool {
+	var ae *googleapi.Error
+	return errors.As(err, &ae) && ae.Code == http.StatusPreconditionFailed
+}
+
 func isGCSNotFound(err error) bool {
 	if err == nil {
 		return false
@@ -22,6 +22,7 @@ import (
 	"io"
 	"os"
 	"path"
+	"strconv"
 	"strings"
 	"sync"
 
@@ -36,6 +37,8 @@ type MemFSPath struct {
 	mutex    sync.Mutex
 	contents []byte
 	children map[string]*MemFSPath
+	// generation is incremented on every write, and is used as the version for conditional writes
+	generation int64
 }
 
 var (
@@ -112,6 +115,7 @@ func (p *MemFSPath) WriteFile(ctx context.Context, r io.ReadSeeker, acl ACL) err
 	}
 	p.contents = data
 	p.acl = acl
+	p.generation++
 	return nil
 }
 
@@ -188,6 +192,58 @@ func (p *MemFSPath) Remove(ctx context.Context) error {
 	return nil
 }
 
+// ReadFileWithVersion implements HasConditionalWrite::ReadFileWithVersion
+func (p *MemFSPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return nil, "", os.ErrNotExist
+	}
+	return p.contents, strconv.FormatInt(p.generation, 10), nil
+}
+
+// WriteFileIfVersion implements HasConditionalWrite::WriteFileIfVersion
+func (p *MemFSPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if err := p.checkVersion(version); err != nil {
+		return "", err
+	}
+	if err := p.WriteFile(ctx, data, acl); err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(p.generation, 10), nil
+}
+
+// RemoveIfVersion implements HasConditionalWrite::RemoveIfVersion
+func (p *MemFSPath) RemoveIfVersion(ctx context.Context, version string) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	if p.contents == nil {
+		return os.ErrNotExist
+	}
+	if err := p.checkVersion(version); err != nil {
+		return err
+	}
+	return p.Remove(ctx)
+}
+
+func (p *MemFSPath) checkVersion(version string) error {
+	if version == "" {
+		if p.contents != nil {
+			return ErrPreconditionFailed
+		}
+		return nil
+	}
+	if p.contents == nil || version != strconv.FormatInt(p.generation, 10) {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
 func (p *MemFSPath) RemoveAll(ctx context.Context) error {
 	tree, err := p.ReadTree(ctx)
 	if err != nil {
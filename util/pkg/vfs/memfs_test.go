@@ -65,6 +65,57 @@ func TestMemFsCreateFile(t *testing.T) {
 	}
 }
 
+func TestMemFsConditionalWrite(t *testing.T) {
+	ctx := testcontext.ForTest(t)
+
+	memfspath := NewMemFSPath(NewMemFSContext(), "/root/lock")
+
+	if _, _, err := memfspath.ReadFileWithVersion(ctx); !os.IsNotExist(err) {
+		t.Fatalf("Expected os.ErrNotExist reading missing file, got: %v", err)
+	}
+
+	v1, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("one")), nil, "")
+	if err != nil {
+		t.Fatalf("Failed creating file: %v", err)
+	}
+
+	// Creating the file again should fail
+	if _, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("other")), nil, ""); err != ErrPreconditionFailed {
+		t.Errorf("Expected ErrPreconditionFailed creating existing file, got: %v", err)
+	}
+
+	v2, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("two")), nil, v1)
+	if err != nil {
+		t.Fatalf("Failed replacing file: %v", err)
+	}
+	if v2 == v1 {
+		t.Errorf("Expected version to change on write, got %q", v2)
+	}
+
+	// Writing with a stale version should fail
+	if _, err := memfspath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("stale")), nil, v1); err != ErrPreconditionFailed {
+		t.Errorf("Expected ErrPreconditionFailed writing with stale version, got: %v", err)
+	}
+	if err := memfspath.RemoveIfVersion(ctx, v1); err != ErrPreconditionFailed {
+		t.Errorf("Expected ErrPreconditionFailed removing with stale version, got: %v", err)
+	}
+
+	data, version, err := memfspath.ReadFileWithVersion(ctx)
+	if err != nil {
+		t.Fatalf("Failed reading file: %v", err)
+	}
+	if string(data) != "two" || version != v2 {
+		t.Errorf("Expected contents %q with version %q, got %q with version %q", "two", v2, data, version)
+	}
+
+	if err := memfspath.RemoveIfVersion(ctx, v2); err != nil {
+		t.Errorf("Failed removing file: %v", err)
+	}
+	if _, err := memfspath.ReadFile(ctx); !os.IsNotExist(err) {
+		t.Errorf("Expected file to be removed, got: %v", err)
+	}
+}
+
 func TestMemFsReadDir(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -319,15 +319,19 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 	ctx, span := tracer.Start(ctx, "S3Path::WriteFile", trace.WithAttributes(attribute.String("path", p.String())))
 	defer span.End()
 
+	_, err := p.putObject(ctx, &s3.PutObjectInput{Body: data}, aclObj)
+	return err
+}
+
+// putObject writes the file, with the request fields (such as preconditions) that are already set
+func (p *S3Path) putObject(ctx context.Context, request *s3.PutObjectInput, aclObj ACL) (*s3.PutObjectOutput, error) {
 	client, err := p.client(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	klog.V(4).Infof("Writing file %q", p)
 
-	request := &s3.PutObjectInput{}
-	request.Body = data
 	request.Bucket = aws.String(p.bucket)
 	request.Key = aws.String(p.key)
 
@@ -336,7 +340,7 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 
 	acl, err := p.getRequestACL(aclObj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if acl != nil {
 		request.ACL = *acl
@@ -346,17 +350,98 @@ func (p *S3Path) WriteFile(ctx context.Context, data io.ReadSeeker, aclObj ACL)
 
 	klog.V(8).Infof("Calling S3 PutObject Bucket=%q Key=%q SSE=%q ACL=%q", p.bucket, p.key, sseLog, request.ACL)
 
-	_, err = client.PutObject(ctx, request)
+	response, err := client.PutObject(ctx, request)
 	if err != nil {
+		if isS3PreconditionFailed(err) {
+			return nil, ErrPreconditionFailed
+		}
 		if len(request.ACL) > 0 {
-			return fmt.Errorf("error writing %s (with ACL=%q): %v", p, request.ACL, err)
+			return nil, fmt.Errorf("error writing %s (with ACL=%q): %v", p, request.ACL, err)
 		}
-		return fmt.Errorf("error writing %s: %v", p, err)
+		return nil, fmt.Errorf("error writing %s: %v", p, err)
+	}
+
+	return response, nil
+}
+
+// ReadFileWithVersion implements HasConditionalWrite::ReadFileWithVersion, using the ETag as the version
+func (p *S3Path) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return nil, "", err
 	}
 
+	klog.V(4).Infof("Reading file %q", p)
+
+	response, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(p.bucket),
+		Key:    aws.String(p.key),
+	})
+	if err != nil {
+		if AWSErrorCode(err) == "NoSuchKey" {
+			return nil, "", os.ErrNotExist
+		}
+		return nil, "", fmt.Errorf("error fetching %s: %v", p, err)
+	}
+	defer response.Body.Close()
+
+	b, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("error reading %s: %v", p, err)
+	}
+	return b, aws.ToString(response.ETag), nil
+}
+
+// WriteFileIfVersion implements HasConditionalWrite::WriteFileIfVersion, using S3 conditional writes
+func (p *S3Path) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	request := &s3.PutObjectInput{Body: data}
+	if version == "" {
+		request.IfNoneMatch = aws.String("*")
+	} else {
+		request.IfMatch = aws.String(version)
+	}
+	response, err := p.putObject(ctx, request, acl)
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(response.ETag), nil
+}
+
+// RemoveIfVersion implements HasConditionalWrite::RemoveIfVersion, using S3 conditional deletes
+func (p *S3Path) RemoveIfVersion(ctx context.Context, version string) error {
+	client, err := p.client(ctx)
+	if err != nil {
+		return err
+	}
+
+	klog.V(8).Infof("removing file %s if it has ETag %s", p, version)
+
+	_, err = client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket:  aws.String(p.bucket),
+		Key:     aws.String(p.key),
+		IfMatch: aws.String(version),
+	})
+	if err != nil {
+		if isS3PreconditionFailed(err) {
+			return ErrPreconditionFailed
+		}
+		if AWSErrorCode(err) == "NoSuchKey" {
+			return os.ErrNotExist
+		}
+		return fmt.Errorf("error deleting %s: %v", p, err)
+	}
 	return nil
 }
 
+// isS3PreconditionFailed returns true if a conditional request failed because the object was changed
+func isS3PreconditionFailed(err error) bool {
+	switch AWSErrorCode(err) {
+	case "PreconditionFailed", "ConditionalRequestConflict":
+		return true
+	}
+	return false
+}
+
 // To prevent concurrent creates on the same file while maintaining atomicity of writes,
 // we take a process-wide lock during the operation.
 // Not a great approach, but fine for a single process (with low concurrency)
@@ -0,0 +1,684 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// vaultRequestTimeout bounds each request to the Vault API
+const vaultRequestTimeout = 30 * time.Second
+
+// vaultContentKey is the key of the KV v2 secret data under which we store the (base64-encoded) file contents
+const vaultContentKey = "content"
+
+// VaultPath is a path in the VFS space backed by a HashiCorp Vault KV v2 secrets engine.
+// Each file is stored as a secret, with the contents base64-encoded under the "content" key.
+type VaultPath struct {
+	vfsContext *VFSContext
+	mount      string
+	key        string
+}
+
+var (
+	_ Path                = &VaultPath{}
+	_ WriterToWithContext = &VaultPath{}
+)
+
+// NewVaultPath returns a new VaultPath.
+func NewVaultPath(vfsContext *VFSContext, mount string, key string) *VaultPath {
+	return &VaultPath{
+		vfsContext: vfsContext,
+		mount:      strings.Trim(mount, "/"),
+		key:        strings.Trim(key, "/"),
+	}
+}
+
+// Mount returns the mount point of the KV v2 secrets engine.
+func (p *VaultPath) Mount() string {
+	return p.mount
+}
+
+// Key returns the path of the secret within the secrets engine.
+func (p *VaultPath) Key() string {
+	return p.key
+}
+
+// Base returns the base name (last element).
+func (p *VaultPath) Base() string {
+	return path.Base(p.key)
+}
+
+// Path returns a string representing the full path.
+func (p *VaultPath) Path() string {
+	return "vault://" + p.mount + "/" + p.key
+}
+
+// String implements fmt.Stringer; returns Path() so %s renders the full URL.
+func (p *VaultPath) String() string {
+	return p.Path()
+}
+
+// Join returns a new path that joins the current path and given relative paths.
+func (p *VaultPath) Join(relativePath ...string) Path {
+	args := []string{p.key}
+	args = append(args, relativePath...)
+	joined := path.Join(args...)
+	return &VaultPath{
+		vfsContext: p.vfsContext,
+		mount:      p.mount,
+		key:        strings.TrimPrefix(joined, "/"),
+	}
+}
+
+// ReadFile returns the contents of the secret.
+func (p *VaultPath) ReadFile(ctx context.Context) ([]byte, error) {
+	klog.V(8).Infof("Reading file: %s", p)
+
+	data, _, err := p.readSecret(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readSecret reads the current version of the secret, returning the file contents and the version.
+func (p *VaultPath) readSecret(ctx context.Context) ([]byte, int, error) {
+	client, err := p.vfsContext.getVaultClient(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var response struct {
+		Data struct {
+			Data     map[string]string `json:"data"`
+			Metadata struct {
+				Version int `json:"version"`
+			} `json:"metadata"`
+		} `json:"data"`
+	}
+	if err := client.Do(ctx, http.MethodGet, p.apiPath("data"), nil, &response); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, 0, os.ErrNotExist
+		}
+		return nil, 0, fmt.Errorf("error reading %s: %w", p, err)
+	}
+
+	if len(response.Data.Data) == 0 {
+		// An empty secret is the tombstone left by RemoveIfVersion
+		return nil, response.Data.Metadata.Version, os.ErrNotExist
+	}
+	content, ok := response.Data.Data[vaultContentKey]
+	if !ok {
+		return nil, 0, fmt.Errorf("secret %s was not written by kOps: no %q key", p, vaultContentKey)
+	}
+	data, err := base64.StdEncoding.DecodeString(content)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error decoding %s: %w", p, err)
+	}
+	return data, response.Data.Metadata.Version, nil
+}
+
+// WriteTo writes the contents of the secret to the writer.
+func (p *VaultPath) WriteTo(out io.Writer) (int64, error) {
+	ctx := context.TODO()
+	return p.WriteToWithContext(ctx, out)
+}
+
+// WriteToWithContext writes the contents of the secret to the writer, with the given context.
+func (p *VaultPath) WriteToWithContext(ctx context.Context, out io.Writer) (int64, error) {
+	b, err := p.ReadFile(ctx)
+	if err != nil {
+		return 0, err
+	}
+	n, err := out.Write(b)
+	return int64(n), err
+}
+
+// WriteFile writes a new version of the secret.
+func (p *VaultPath) WriteFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	klog.V(8).Infof("Writing file: %s", p)
+
+	_, err := p.writeSecret(ctx, data, nil)
+	return err
+}
+
+// CreateFile writes the secret only if it does not already exist.
+// It relies on the check-and-set support of KV v2, so it is safe against concurrent writers.
+func (p *VaultPath) CreateFile(ctx context.Context, data io.ReadSeeker, acl ACL) error {
+	klog.V(8).Infof("Creating file: %s", p)
+
+	cas := 0
+	_, err := p.writeSecret(ctx, data, &cas)
+	if isVaultCASError(err) {
+		return os.ErrExist
+	}
+	return err
+}
+
+// ReadFileWithVersion implements HasConditionalWrite::ReadFileWithVersion
+// The version is the version number of the secret in the KV v2 secrets engine.
+func (p *VaultPath) ReadFileWithVersion(ctx context.Context) ([]byte, string, error) {
+	data, version, err := p.readSecret(ctx)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, strconv.Itoa(version), nil
+}
+
+// WriteFileIfVersion implements HasConditionalWrite::WriteFileIfVersion
+func (p *VaultPath) WriteFileIfVersion(ctx context.Context, data io.ReadSeeker, acl ACL, version string) (string, error) {
+	klog.V(8).Infof("Writing file %s if it has version %q", p, version)
+
+	cas := 0
+	if version != "" {
+		n, err := strconv.Atoi(version)
+		if err != nil {
+			return "", fmt.Errorf("invalid version %q for %s", version, p)
+		}
+		cas = n
+	} else {
+		// The secret may have been removed by RemoveIfVersion, which leaves a tombstone behind
+		if _, tombstone, err := p.readSecret(ctx); errors.Is(err, os.ErrNotExist) {
+			cas = tombstone
+		} else if err == nil {
+			return "", ErrPreconditionFailed
+		} else {
+			return "", err
+		}
+	}
+
+	newVersion, err := p.writeSecret(ctx, data, &cas)
+	if err != nil {
+		if isVaultCASError(err) {
+			return "", ErrPreconditionFailed
+		}
+		return "", err
+	}
+	return strconv.Itoa(newVersion), nil
+}
+
+// RemoveIfVersion implements HasConditionalWrite::RemoveIfVersion
+// KV v2 can't delete a secret conditionally, so we replace it with an empty tombstone using check-and-set.
+// We don't delete the tombstone afterwards: another client may already have written over it,
+// and the tombstone reads as a missing file.
+func (p *VaultPath) RemoveIfVersion(ctx context.Context, version string) error {
+	klog.V(8).Infof("Removing file %s if it has version %q", p, version)
+
+	cas, err := strconv.Atoi(version)
+	if err != nil {
+		return fmt.Errorf("invalid version %q for %s", version, p)
+	}
+	if _, _, err := p.readSecret(ctx); err != nil {
+		return err
+	}
+
+	client, err := p.vfsContext.getVaultClient(ctx)
+	if err != nil {
+		return err
+	}
+	request := map[string]any{
+		"data":    map[string]string{},
+		"options": map[string]any{"cas": cas},
+	}
+	if err := client.Do(ctx, http.MethodPost, p.apiPath("data"), request, nil); err != nil {
+		if isVaultCASError(err) {
+			return ErrPreconditionFailed
+		}
+		return fmt.Errorf("error deleting %s: %w", p, err)
+	}
+	return nil
+}
+
+// writeSecret writes a new version of the secret, returning the version number.
+// If cas is not nil, the write only succeeds if the current version of the secret matches;
+// a version of 0 means that the secret must not exist.
+func (p *VaultPath) writeSecret(ctx context.Context, data io.ReadSeeker, cas *int) (int, error) {
+	client, err := p.vfsContext.getVaultClient(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	b, err := io.ReadAll(data)
+	if err != nil {
+		return 0, fmt.Errorf("error reading data for %s: %w", p, err)
+	}
+
+	request := map[string]any{
+		"data": map[string]string{
+			vaultContentKey: base64.StdEncoding.EncodeToString(b),
+		},
+	}
+	if cas != nil {
+		request["options"] = map[string]any{
+			"cas": *cas,
+		}
+	}
+	var response struct {
+		Data struct {
+			Version int `json:"version"`
+		} `json:"data"`
+	}
+	if err := client.Do(ctx, http.MethodPost, p.apiPath("data"), request, &response); err != nil {
+		return 0, fmt.Errorf("error writing %s: %w", p, err)
+	}
+	return response.Data.Version, nil
+}
+
+// Remove deletes the secret, including all of its versions.
+func (p *VaultPath) Remove(ctx context.Context) error {
+	klog.V(8).Infof("Removing file: %s", p)
+
+	client, err := p.vfsContext.getVaultClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := client.Do(ctx, http.MethodDelete, p.apiPath("metadata"), nil, nil); err != nil {
+		return fmt.Errorf("error deleting %s: %w", p, err)
+	}
+	return nil
+}
+
+// RemoveAll deletes all secrets under the current path.
+func (p *VaultPath) RemoveAll(ctx context.Context) error {
+	tree, err := p.ReadTree(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, child := range tree {
+		if err := child.Remove(ctx); err != nil {
+			return fmt.Errorf("removing file %s: %w", child, err)
+		}
+	}
+	return nil
+}
+
+// RemoveAllVersions deletes all secrets under the current path.
+// Remove already deletes every version of a secret, so this is the same as RemoveAll.
+func (p *VaultPath) RemoveAllVersions(ctx context.Context) error {
+	return p.RemoveAll(ctx)
+}
+
+// ReadDir lists the secrets and subdirectories directly under the current path.
+func (p *VaultPath) ReadDir() ([]Path, error) {
+	ctx := context.TODO()
+
+	keys, err := p.listKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []Path
+	for _, key := range keys {
+		paths = append(paths, p.Join(strings.TrimSuffix(key, "/")))
+	}
+	klog.V(8).Infof("Listed files in %v: %v", p, paths)
+	return paths, nil
+}
+
+// ReadTree lists all secrets (recursively) under the current path.
+func (p *VaultPath) ReadTree(ctx context.Context) ([]Path, error) {
+	var paths []Path
+	if err := p.readTree(ctx, &paths); err != nil {
+		return nil, err
+	}
+	return paths, nil
+}
+
+func (p *VaultPath) readTree(ctx context.Context, dest *[]Path) error {
+	keys, err := p.listKeys(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		child := p.Join(strings.TrimSuffix(key, "/")).(*VaultPath)
+		if strings.HasSuffix(key, "/") {
+			if err := child.readTree(ctx, dest); err != nil {
+				return err
+			}
+		} else {
+			*dest = append(*dest, child)
+		}
+	}
+	return nil
+}
+
+// listKeys lists the keys directly under the current path; subdirectories have a trailing "/".
+func (p *VaultPath) listKeys(ctx context.Context) ([]string, error) {
+	client, err := p.vfsContext.getVaultClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	err = client.Do(ctx, "LIST", p.apiPath("metadata")+"/", nil, &response)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// Vault returns a 404 for a path without any secrets
+			return nil, nil
+		}
+		return nil, fmt.Errorf("error listing %s: %w", p, err)
+	}
+	return response.Data.Keys, nil
+}
+
+// apiPath returns the path of the KV v2 API endpoint for this secret, e.g. <mount>/data/<key>.
+func (p *VaultPath) apiPath(endpoint string) string {
+	s := p.mount + "/" + endpoint
+	if p.key != "" {
+		s += "/" + p.key
+	}
+	return s
+}
+
+// VaultClient is a minimal client for the Vault HTTP API, shared with the Vault transit key provider of the state store encryption.
+type VaultClient struct {
+	httpClient *http.Client
+	address    string
+	namespace  string
+
+	// roleID and secretID are the AppRole credentials, used to (re-)login when the token expires
+	roleID       string
+	secretID     string
+	approleMount string
+
+	mutex sync.Mutex
+	token string
+}
+
+// vaultError is an error response from the Vault API.
+type vaultError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *vaultError) Error() string {
+	return fmt.Sprintf("vault returned status %d: %s", e.StatusCode, strings.Join(e.Errors, "; "))
+}
+
+// Is allows errors.Is(err, os.ErrNotExist) to match a 404 response.
+func (e *vaultError) Is(target error) bool {
+	return target == os.ErrNotExist && e.StatusCode == http.StatusNotFound
+}
+
+// isVaultCASError returns true if the error is a check-and-set failure.
+func isVaultCASError(err error) bool {
+	var e *vaultError
+	if !errors.As(err, &e) || e.StatusCode != http.StatusBadRequest {
+		return false
+	}
+	for _, s := range e.Errors {
+		if strings.Contains(s, "check-and-set") {
+			return true
+		}
+	}
+	return false
+}
+
+// VaultSecretIDFile is the file on the control plane hosts that holds the AppRole secret ID.
+// It is provisioned by the cluster operator, so that the secret ID is never part of the user-data or the state store.
+const VaultSecretIDFile = "/etc/kubernetes/vault/secret-id"
+
+// NewVaultClient builds a vault client from the standard Vault environment variables.
+// It authenticates with VAULT_TOKEN if set, otherwise with the AppRole credentials in
+// VAULT_ROLE_ID and VAULT_SECRET_ID (using the auth method mounted at VAULT_APPROLE_MOUNT, default "approle").
+// If VAULT_SECRET_ID is not set, the secret ID is read from the file named by VAULT_SECRET_ID_FILE.
+func NewVaultClient(ctx context.Context) (*VaultClient, error) {
+	address := os.Getenv("VAULT_ADDR")
+	if address == "" {
+		return nil, fmt.Errorf("VAULT_ADDR must be set to use Vault")
+	}
+
+	httpClient, err := newVaultHTTPClient()
+	if err != nil {
+		return nil, err
+	}
+
+	c := &VaultClient{
+		httpClient:   httpClient,
+		address:      strings.TrimSuffix(address, "/"),
+		namespace:    os.Getenv("VAULT_NAMESPACE"),
+		token:        os.Getenv("VAULT_TOKEN"),
+		roleID:       os.Getenv("VAULT_ROLE_ID"),
+		secretID:     os.Getenv("VAULT_SECRET_ID"),
+		approleMount: os.Getenv("VAULT_APPROLE_MOUNT"),
+	}
+	if c.approleMount == "" {
+		c.approleMount = "approle"
+	}
+
+	if c.token == "" {
+		if c.roleID == "" {
+			return nil, fmt.Errorf("either VAULT_TOKEN or VAULT_ROLE_ID must be set to use Vault")
+		}
+		if secretIDFile := os.Getenv("VAULT_SECRET_ID_FILE"); c.secretID == "" && secretIDFile != "" {
+			b, err := os.ReadFile(secretIDFile)
+			if err != nil {
+				return nil, fmt.Errorf("error reading VAULT_SECRET_ID_FILE %q: %w", secretIDFile, err)
+			}
+			c.secretID = strings.TrimSpace(string(b))
+		}
+		if err := c.login(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// VaultControlPlaneEnv returns the environment variables that give the control plane access to Vault,
+// taken from the environment of the kops CLI.
+// The control plane must be able to log in again after a reboot, so it is given an AppRole role ID rather than a token,
+// and the CA certificates in VAULT_CACERT are passed inline in VAULT_CACERT_BASE64, as the file only exists locally.
+// The secret ID is not included; the control plane reads it from VaultSecretIDFile.
+func VaultControlPlaneEnv() (map[string]string, error) {
+	if os.Getenv("VAULT_ADDR") == "" {
+		return nil, fmt.Errorf("VAULT_ADDR must be set for a cluster that stores its keypairs, secrets or encryption key in Vault")
+	}
+	if os.Getenv("VAULT_ROLE_ID") == "" {
+		return nil, fmt.Errorf("VAULT_ROLE_ID must be set for a cluster that stores its keypairs, secrets or encryption key in Vault, so that the control plane can log in with AppRole")
+	}
+
+	env := make(map[string]string)
+	for _, name := range []string{"VAULT_ADDR", "VAULT_NAMESPACE", "VAULT_ROLE_ID", "VAULT_APPROLE_MOUNT", "VAULT_SKIP_VERIFY", "VAULT_CACERT_BASE64"} {
+		if v := os.Getenv(name); v != "" {
+			env[name] = v
+		}
+	}
+	env["VAULT_SECRET_ID_FILE"] = VaultSecretIDFile
+	if caCert := os.Getenv("VAULT_CACERT"); caCert != "" {
+		b, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading VAULT_CACERT %q: %w", caCert, err)
+		}
+		env["VAULT_CACERT_BASE64"] = base64.StdEncoding.EncodeToString(b)
+	}
+	return env, nil
+}
+
+// newVaultHTTPClient builds the HTTP client for the Vault API, trusting the CA certificates in VAULT_CACERT
+// (or inline in VAULT_CACERT_BASE64) if set, and skipping TLS verification if VAULT_SKIP_VERIFY is true.
+func newVaultHTTPClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{}
+
+	if caCert := os.Getenv("VAULT_CACERT"); caCert != "" {
+		b, err := os.ReadFile(caCert)
+		if err != nil {
+			return nil, fmt.Errorf("error reading VAULT_CACERT %q: %w", caCert, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in VAULT_CACERT %q", caCert)
+		}
+		tlsConfig.RootCAs = pool
+	} else if caCertBase64 := os.Getenv("VAULT_CACERT_BASE64"); caCertBase64 != "" {
+		b, err := base64.StdEncoding.DecodeString(caCertBase64)
+		if err != nil {
+			return nil, fmt.Errorf("error decoding VAULT_CACERT_BASE64: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(b) {
+			return nil, fmt.Errorf("no certificates found in VAULT_CACERT_BASE64")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if skipVerify := os.Getenv("VAULT_SKIP_VERIFY"); skipVerify != "" {
+		skip, err := strconv.ParseBool(skipVerify)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VAULT_SKIP_VERIFY %q: %w", skipVerify, err)
+		}
+		if skip {
+			klog.Warningf("VAULT_SKIP_VERIFY is set; not verifying the TLS certificate of the vault server")
+		}
+		tlsConfig.InsecureSkipVerify = skip
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	return &http.Client{
+		Transport: transport,
+		Timeout:   vaultRequestTimeout,
+	}, nil
+}
+
+// login obtains a token using AppRole authentication.
+func (c *VaultClient) login(ctx context.Context) error {
+	klog.V(2).Infof("logging in to vault with approle %q", c.roleID)
+
+	request := map[string]string{
+		"role_id":   c.roleID,
+		"secret_id": c.secretID,
+	}
+	var response struct {
+		Auth struct {
+			ClientToken string `json:"client_token"`
+		} `json:"auth"`
+	}
+	if err := c.doWithToken(ctx, "", http.MethodPost, "auth/"+c.approleMount+"/login", request, &response); err != nil {
+		return fmt.Errorf("error logging in to vault with approle: %w", err)
+	}
+	if response.Auth.ClientToken == "" {
+		return fmt.Errorf("vault approle login did not return a token")
+	}
+
+	c.mutex.Lock()
+	c.token = response.Auth.ClientToken
+	c.mutex.Unlock()
+	return nil
+}
+
+// Do performs a request against the Vault API, logging in again if an AppRole token has expired.
+func (c *VaultClient) Do(ctx context.Context, method string, apiPath string, request any, response any) error {
+	c.mutex.Lock()
+	token := c.token
+	c.mutex.Unlock()
+
+	err := c.doWithToken(ctx, token, method, apiPath, request, response)
+	if e, ok := err.(*vaultError); ok && e.StatusCode == http.StatusForbidden && c.roleID != "" {
+		if err := c.login(ctx); err != nil {
+			return err
+		}
+		c.mutex.Lock()
+		token = c.token
+		c.mutex.Unlock()
+		err = c.doWithToken(ctx, token, method, apiPath, request, response)
+	}
+	return err
+}
+
+func (c *VaultClient) doWithToken(ctx context.Context, token string, method string, apiPath string, request any, response any) error {
+	var body io.Reader
+	if request != nil {
+		b, err := json.Marshal(request)
+		if err != nil {
+			return fmt.Errorf("error building request: %w", err)
+		}
+		body = bytes.NewReader(b)
+	}
+
+	u := c.address + "/v1/" + apiPath
+	klog.V(8).Infof("Performing vault request: %s %s", method, u)
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if c.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", c.namespace)
+	}
+	if request != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		e := &vaultError{StatusCode: resp.StatusCode}
+		var errorResponse struct {
+			Errors []string `json:"errors"`
+		}
+		if err := json.Unmarshal(b, &errorResponse); err == nil {
+			e.Errors = errorResponse.Errors
+		}
+		if len(e.Errors) == 0 {
+			e.Errors = []string{strconv.Quote(string(b))}
+		}
+		return e
+	}
+
+	if response != nil && len(b) != 0 {
+		if err := json.Unmarshal(b, response); err != nil {
+			return fmt.Errorf("error parsing response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,510 @@
+/*
+Copyright 2026 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package vfs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeVault is an in-process fake of the parts of the Vault HTTP API used by VaultPath:
+// a KV v2 secrets engine mounted at "secret", and AppRole login.
+type fakeVault struct {
+	mutex   sync.Mutex
+	tokens  map[string]bool
+	secrets map[string]*fakeVaultSecret
+
+	roleID   string
+	secretID string
+	logins   int
+
+	// afterRequest, if set, is called after each request has been handled
+	afterRequest func(r *http.Request)
+}
+
+type fakeVaultSecret struct {
+	data    map[string]string
+	version int
+}
+
+func newFakeVault(t *testing.T) *fakeVault {
+	f := &fakeVault{
+		tokens:   map[string]bool{"root-token": true},
+		secrets:  make(map[string]*fakeVaultSecret),
+		roleID:   "kops-role",
+		secretID: "kops-secret",
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+
+	t.Setenv("VAULT_ADDR", server.URL)
+	t.Setenv("VAULT_TOKEN", "")
+	t.Setenv("VAULT_ROLE_ID", "")
+	t.Setenv("VAULT_SECRET_ID", "")
+	t.Setenv("VAULT_SECRET_ID_FILE", "")
+	t.Setenv("VAULT_NAMESPACE", "")
+	t.Setenv("VAULT_APPROLE_MOUNT", "")
+	t.Setenv("VAULT_CACERT", "")
+	t.Setenv("VAULT_CACERT_BASE64", "")
+	t.Setenv("VAULT_SKIP_VERIFY", "")
+	return f
+}
+
+func (f *fakeVault) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.afterRequest != nil {
+		defer f.afterRequest(r)
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	writeError := func(code int, msg string) {
+		w.WriteHeader(code)
+		json.NewEncoder(w).Encode(map[string]any{"errors": []string{msg}})
+	}
+
+	if r.URL.Path == "/v1/auth/approle/login" && r.Method == http.MethodPost {
+		var req struct {
+			RoleID   string `json:"role_id"`
+			SecretID string `json:"secret_id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.RoleID != f.roleID || req.SecretID != f.secretID {
+			writeError(http.StatusBadRequest, "invalid role or secret ID")
+			return
+		}
+		f.logins++
+		token := fmt.Sprintf("approle-token-%d", f.logins)
+		f.tokens[token] = true
+		json.NewEncoder(w).Encode(map[string]any{"auth": map[string]any{"client_token": token}})
+		return
+	}
+
+	if !f.tokens[r.Header.Get("X-Vault-Token")] {
+		writeError(http.StatusForbidden, "permission denied")
+		return
+	}
+
+	switch {
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/data/"):
+		key := strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")
+		switch r.Method {
+		case http.MethodGet:
+			secret := f.secrets[key]
+			if secret == nil {
+				writeError(http.StatusNotFound, "")
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": map[string]any{
+					"data":     secret.data,
+					"metadata": map[string]any{"version": secret.version},
+				},
+			})
+		case http.MethodPost, http.MethodPut:
+			var req struct {
+				Data    map[string]string `json:"data"`
+				Options struct {
+					CAS *int `json:"cas"`
+				} `json:"options"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				writeError(http.StatusBadRequest, err.Error())
+				return
+			}
+			secret := f.secrets[key]
+			version := 0
+			if secret != nil {
+				version = secret.version
+			}
+			if req.Options.CAS != nil && *req.Options.CAS != version {
+				writeError(http.StatusBadRequest, "check-and-set parameter did not match the current version")
+				return
+			}
+			f.secrets[key] = &fakeVaultSecret{data: req.Data, version: version + 1}
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"version": version + 1}})
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	case strings.HasPrefix(r.URL.Path, "/v1/secret/metadata/"):
+		key := strings.TrimPrefix(r.URL.Path, "/v1/secret/metadata/")
+		switch r.Method {
+		case "LIST":
+			keys := make(map[string]bool)
+			for k := range f.secrets {
+				if !strings.HasPrefix(k, key) {
+					continue
+				}
+				rest := strings.TrimPrefix(k, key)
+				if i := strings.Index(rest, "/"); i != -1 {
+					rest = rest[:i+1]
+				}
+				keys[rest] = true
+			}
+			if len(keys) == 0 {
+				writeError(http.StatusNotFound, "")
+				return
+			}
+			var list []string
+			for k := range keys {
+				list = append(list, k)
+			}
+			sort.Strings(list)
+			json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"keys": list}})
+		case http.MethodDelete:
+			delete(f.secrets, key)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+
+	default:
+		writeError(http.StatusNotFound, "no handler for route")
+	}
+}
+
+func pathStrings(paths []Path) []string {
+	var s []string
+	for _, p := range paths {
+		s = append(s, p.Path())
+	}
+	sort.Strings(s)
+	return s
+}
+
+func TestVaultPath(t *testing.T) {
+	ctx := context.TODO()
+	newFakeVault(t)
+	t.Setenv("VAULT_TOKEN", "root-token")
+
+	vfsContext := NewVFSContext()
+	base, err := vfsContext.BuildVfsPath("vault://secret/kops/cluster.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "vault://secret/kops/cluster.example.com", base.Path())
+
+	config := base.Join("config")
+	_, err = config.ReadFile(ctx)
+	assert.True(t, os.IsNotExist(err), "expected not-exist error, got %v", err)
+
+	require.NoError(t, config.WriteFile(ctx, bytes.NewReader([]byte("config-v1")), nil))
+	data, err := config.ReadFile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "config-v1", string(data))
+
+	require.NoError(t, config.WriteFile(ctx, bytes.NewReader([]byte("config-v2")), nil))
+	data, err = config.ReadFile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "config-v2", string(data))
+
+	// Binary contents survive the round trip
+	binary := []byte{0, 1, 2, 0xff, '\n'}
+	key := base.Join("pki", "private", "ca", "keyset.yaml")
+	require.NoError(t, key.CreateFile(ctx, bytes.NewReader(binary), nil))
+	data, err = key.ReadFile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binary, data)
+
+	err = key.CreateFile(ctx, bytes.NewReader([]byte("other")), nil)
+	assert.True(t, os.IsExist(err), "expected exists error, got %v", err)
+	data, err = key.ReadFile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, binary, data)
+
+	require.NoError(t, base.Join("instancegroup", "nodes").WriteFile(ctx, bytes.NewReader([]byte("nodes")), nil))
+
+	dir, err := base.ReadDir()
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"vault://secret/kops/cluster.example.com/config",
+		"vault://secret/kops/cluster.example.com/instancegroup",
+		"vault://secret/kops/cluster.example.com/pki",
+	}, pathStrings(dir))
+
+	tree, err := base.ReadTree(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"vault://secret/kops/cluster.example.com/config",
+		"vault://secret/kops/cluster.example.com/instancegroup/nodes",
+		"vault://secret/kops/cluster.example.com/pki/private/ca/keyset.yaml",
+	}, pathStrings(tree))
+
+	empty, err := base.Join("missing").ReadDir()
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	require.NoError(t, config.Remove(ctx))
+	_, err = config.ReadFile(ctx)
+	assert.True(t, os.IsNotExist(err), "expected not-exist error, got %v", err)
+
+	require.NoError(t, base.RemoveAll(ctx))
+	tree, err = base.ReadTree(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, tree)
+}
+
+func TestVaultPathConditionalWrite(t *testing.T) {
+	ctx := context.TODO()
+	fake := newFakeVault(t)
+	t.Setenv("VAULT_TOKEN", "root-token")
+
+	vfsContext := NewVFSContext()
+	p, err := vfsContext.BuildVfsPath("vault://secret/kops/cluster.example.com/lock")
+	require.NoError(t, err)
+	vaultPath := p.(*VaultPath)
+
+	_, _, err = vaultPath.ReadFileWithVersion(ctx)
+	assert.True(t, os.IsNotExist(err), "expected not-exist error, got %v", err)
+
+	v1, err := vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 1")), nil, "")
+	require.NoError(t, err)
+
+	_, err = vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 2")), nil, "")
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+
+	data, version, err := vaultPath.ReadFileWithVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "holder 1", string(data))
+	assert.Equal(t, v1, version)
+
+	v2, err := vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 1 renewed")), nil, v1)
+	require.NoError(t, err)
+
+	_, err = vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 2")), nil, v1)
+	assert.ErrorIs(t, err, ErrPreconditionFailed)
+	assert.ErrorIs(t, vaultPath.RemoveIfVersion(ctx, v1), ErrPreconditionFailed)
+
+	require.NoError(t, vaultPath.RemoveIfVersion(ctx, v2))
+	_, err = vaultPath.ReadFile(ctx)
+	assert.True(t, os.IsNotExist(err), "expected not-exist error, got %v", err)
+
+	// The tombstone left behind by RemoveIfVersion reads as a missing file
+	fake.mutex.Lock()
+	fake.secrets["kops/cluster.example.com/lock"] = &fakeVaultSecret{data: map[string]string{}, version: 7}
+	fake.mutex.Unlock()
+	_, _, err = vaultPath.ReadFileWithVersion(ctx)
+	assert.True(t, os.IsNotExist(err), "expected not-exist error, got %v", err)
+	v3, err := vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 3")), nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "8", v3)
+}
+
+func TestVaultPathRemoveIfVersionRace(t *testing.T) {
+	ctx := context.TODO()
+	fake := newFakeVault(t)
+	t.Setenv("VAULT_TOKEN", "root-token")
+
+	vfsContext := NewVFSContext()
+	p, err := vfsContext.BuildVfsPath("vault://secret/kops/cluster.example.com/lock")
+	require.NoError(t, err)
+	vaultPath := p.(*VaultPath)
+
+	v1, err := vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 1")), nil, "")
+	require.NoError(t, err)
+
+	// Another client takes the lock as soon as holder 1 has written the tombstone
+	var acquired bool
+	var acquireErr error
+	fake.afterRequest = func(r *http.Request) {
+		if acquired || r.Method != http.MethodPost || !strings.HasPrefix(r.URL.Path, "/v1/secret/data/") {
+			return
+		}
+		acquired = true
+		_, acquireErr = vaultPath.WriteFileIfVersion(ctx, bytes.NewReader([]byte("holder 2")), nil, "")
+	}
+
+	require.NoError(t, vaultPath.RemoveIfVersion(ctx, v1))
+	require.True(t, acquired)
+	require.NoError(t, acquireErr)
+
+	data, _, err := vaultPath.ReadFileWithVersion(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "holder 2", string(data))
+}
+
+func TestVaultHTTPClient(t *testing.T) {
+	t.Setenv("VAULT_CACERT", "")
+	t.Setenv("VAULT_CACERT_BASE64", "")
+	t.Setenv("VAULT_SKIP_VERIFY", "")
+	client, err := newVaultHTTPClient()
+	require.NoError(t, err)
+	assert.Equal(t, vaultRequestTimeout, client.Timeout)
+	transport := client.Transport.(*http.Transport)
+	assert.Nil(t, transport.TLSClientConfig.RootCAs)
+	assert.False(t, transport.TLSClientConfig.InsecureSkipVerify)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	t.Setenv("VAULT_CACERT", caCert)
+	client, err = newVaultHTTPClient()
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	t.Setenv("VAULT_CACERT", "")
+	t.Setenv("VAULT_SKIP_VERIFY", "true")
+	client, err = newVaultHTTPClient()
+	require.NoError(t, err)
+	assert.True(t, client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify)
+
+	t.Setenv("VAULT_SKIP_VERIFY", "maybe")
+	_, err = newVaultHTTPClient()
+	assert.ErrorContains(t, err, "invalid VAULT_SKIP_VERIFY")
+
+	t.Setenv("VAULT_SKIP_VERIFY", "")
+	t.Setenv("VAULT_CACERT", filepath.Join(t.TempDir(), "missing.crt"))
+	_, err = newVaultHTTPClient()
+	assert.ErrorContains(t, err, "error reading VAULT_CACERT")
+}
+
+func TestVaultControlPlaneEnv(t *testing.T) {
+	t.Setenv("VAULT_ADDR", "https://vault.example.com:8200")
+	t.Setenv("VAULT_TOKEN", "cli-token")
+	t.Setenv("VAULT_ROLE_ID", "")
+	t.Setenv("VAULT_SECRET_ID", "")
+	t.Setenv("VAULT_SECRET_ID_FILE", "")
+	t.Setenv("VAULT_NAMESPACE", "")
+	t.Setenv("VAULT_APPROLE_MOUNT", "")
+	t.Setenv("VAULT_CACERT", "")
+	t.Setenv("VAULT_CACERT_BASE64", "")
+	t.Setenv("VAULT_SKIP_VERIFY", "")
+
+	_, err := VaultControlPlaneEnv()
+	assert.ErrorContains(t, err, "VAULT_ROLE_ID must be set")
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caCert := filepath.Join(t.TempDir(), "ca.crt")
+	require.NoError(t, os.WriteFile(caCert, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0o600))
+
+	t.Setenv("VAULT_ROLE_ID", "kops-role")
+	t.Setenv("VAULT_SECRET_ID", "kops-secret")
+	t.Setenv("VAULT_NAMESPACE", "team")
+	t.Setenv("VAULT_CACERT", caCert)
+	env, err := VaultControlPlaneEnv()
+	require.NoError(t, err)
+	assert.Equal(t, "https://vault.example.com:8200", env["VAULT_ADDR"])
+	assert.Equal(t, "team", env["VAULT_NAMESPACE"])
+	assert.Equal(t, "kops-role", env["VAULT_ROLE_ID"])
+	assert.Equal(t, VaultSecretIDFile, env["VAULT_SECRET_ID_FILE"])
+	assert.NotContains(t, env, "VAULT_SECRET_ID")
+	assert.NotContains(t, env, "VAULT_TOKEN")
+	assert.NotContains(t, env, "VAULT_CACERT")
+
+	// The control plane trusts the inline certificates
+	t.Setenv("VAULT_CACERT", "")
+	t.Setenv("VAULT_CACERT_BASE64", env["VAULT_CACERT_BASE64"])
+	client, err := newVaultHTTPClient()
+	require.NoError(t, err)
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	t.Setenv("VAULT_CACERT_BASE64", "not base64!")
+	_, err = newVaultHTTPClient()
+	assert.ErrorContains(t, err, "error decoding VAULT_CACERT_BASE64")
+}
+
+func TestVaultPathAppRole(t *testing.T) {
+	ctx := context.TODO()
+	fake := newFakeVault(t)
+	t.Setenv("VAULT_ROLE_ID", "kops-role")
+	t.Setenv("VAULT_SECRET_ID", "kops-secret")
+
+	vfsContext := NewVFSContext()
+	p, err := vfsContext.BuildVfsPath("vault://secret/kops/config")
+	require.NoError(t, err)
+
+	require.NoError(t, p.WriteFile(ctx, bytes.NewReader([]byte("hello")), nil))
+	assert.Equal(t, 1, fake.logins)
+
+	// Expire the token; the client logs in again
+	fake.mutex.Lock()
+	fake.tokens = map[string]bool{}
+	fake.mutex.Unlock()
+
+	data, err := p.ReadFile(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	assert.Equal(t, 2, fake.logins)
+}
+
+func TestVaultPathAppRoleSecretIDFile(t *testing.T) {
+	ctx := context.TODO()
+	fake := newFakeVault(t)
+	t.Setenv("VAULT_ROLE_ID", "kops-role")
+	secretIDFile := filepath.Join(t.TempDir(), "secret-id")
+	t.Setenv("VAULT_SECRET_ID_FILE", secretIDFile)
+
+	vfsContext := NewVFSContext()
+	p, err := vfsContext.BuildVfsPath("vault://secret/kops/config")
+	require.NoError(t, err)
+
+	_, err = p.ReadFile(ctx)
+	assert.ErrorContains(t, err, "error reading VAULT_SECRET_ID_FILE")
+
+	require.NoError(t, os.WriteFile(secretIDFile, []byte("kops-secret\n"), 0o400))
+	vfsContext = NewVFSContext()
+	p, err = vfsContext.BuildVfsPath("vault://secret/kops/config")
+	require.NoError(t, err)
+
+	require.NoError(t, p.WriteFile(ctx, bytes.NewReader([]byte("hello")), nil))
+	assert.Equal(t, 1, fake.logins)
+}
+
+func TestVaultPathNoCredentials(t *testing.T) {
+	newFakeVault(t)
+
+	vfsContext := NewVFSContext()
+	p, err := vfsContext.BuildVfsPath("vault://secret/kops/config")
+	require.NoError(t, err)
+
+	_, err = p.ReadFile(context.TODO())
+	assert.ErrorContains(t, err, "VAULT_TOKEN or VAULT_ROLE_ID must be set")
+}
+
+func TestBuildVaultPath(t *testing.T) {
+	vfsContext := NewVFSContext()
+
+	p, err := vfsContext.BuildVfsPath("vault://kv/clusters/example/")
+	require.NoError(t, err)
+	vaultPath := p.(*VaultPath)
+	assert.Equal(t, "kv", vaultPath.Mount())
+	assert.Equal(t, "clusters/example", vaultPath.Key())
+	assert.Equal(t, "vault://kv/clusters/example/secrets/admin", p.Join("secrets", "admin").Path())
+	assert.False(t, IsClusterReadable(p))
+
+	_, err = vfsContext.BuildVfsPath("vault:///clusters/example")
+	assert.Error(t, err)
+}
@@ -128,6 +128,10 @@ func IsClusterReadable(p Path) bool {
 	case *SSHPath:
 		return false
 
+	case *VaultPath:
+		// Only the control plane is given vault credentials
+		return false
+
 	case *MemFSPath:
 		return false
 